@@ -0,0 +1,171 @@
+// Copyright 2024 Fantom Foundation
+// This file is part of Norma System Testing Infrastructure for Sonic.
+//
+// Norma is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// Norma is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with Norma. If not, see <http://www.gnu.org/licenses/>.
+
+package checking
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/0xsoniclabs/norma/driver"
+	"github.com/0xsoniclabs/norma/driver/monitoring"
+	"github.com/0xsoniclabs/norma/driver/rpc"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+)
+
+// defaultTimestampBound is how far the timestamp of a node's latest block
+// may deviate from real time unless configured otherwise.
+const defaultTimestampBound = 10 * time.Second
+
+func init() {
+	RegisterNetworkCheck("blockTimestamps",
+		func(net driver.Network, monitor *monitoring.Monitor) Checker {
+			return &blockTimestampsChecker{net: net, bound: defaultTimestampBound}
+		})
+}
+
+// blockTimestampsChecker is a Checker verifying that block timestamps follow
+// real time even if the clocks of some nodes do not. The timestamps of the
+// blocks of the first reachable healthy node must never decrease, and the
+// latest block of every healthy node must not be further than the bound from
+// real time, as measured by the clock of the host.
+//
+// The latest block of an idle network is old, so the check is only
+// meaningful while the network is producing blocks.
+type blockTimestampsChecker struct {
+	net   driver.Network
+	bound time.Duration
+	// now returns the real time; it is replaced in tests.
+	now func() time.Time
+}
+
+func (c *blockTimestampsChecker) Configure(config CheckerConfig) Checker {
+	res := *c
+	if b, exist := config["bound"]; exist {
+		res.bound = time.Duration(b.(int64))
+	}
+	return &res
+}
+
+func (c *blockTimestampsChecker) Check(ctx context.Context) error {
+	nodes := c.net.GetActiveNodes()
+	slog.Info("checking block timestamps for nodes", "count", len(nodes))
+
+	clients := make([]rpc.Client, 0, len(nodes))
+	labels := make([]string, 0, len(nodes))
+	defer func() {
+		for _, client := range clients {
+			client.Close()
+		}
+	}()
+	for _, n := range nodes {
+		if n.IsExpectedFailure() {
+			continue
+		}
+		client, err := n.DialRpc(ctx)
+		if err != nil {
+			return fmt.Errorf("failed to dial RPC for node %s; %v", n.GetLabel(), err)
+		}
+		clients = append(clients, client)
+		labels = append(labels, n.GetLabel())
+	}
+	if len(clients) == 0 {
+		return fmt.Errorf("unable to check block timestamps: no reachable node")
+	}
+
+	// All nodes agree on their blocks, which blockHashes verifies, so one
+	// node is enough to check the order of the timestamps.
+	head, err := clients[0].BlockNumber(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to get block height of node %s; %w", labels[0], err)
+	}
+	if err := checkTimestampOrder(clients[0], head); err != nil {
+		return fmt.Errorf("%w (node %s)", err, labels[0])
+	}
+
+	for i, client := range clients {
+		if err := c.checkLatestBlock(ctx, client); err != nil {
+			return fmt.Errorf("%w (node %s)", err, labels[i])
+		}
+	}
+	return nil
+}
+
+// checkTimestampOrder verifies that the timestamps of the blocks up to the
+// given one never decrease. The genesis block is skipped, as its timestamp
+// is set by the genesis rather than by the network.
+func checkTimestampOrder(client rpc.Client, head uint64) error {
+	var previous time.Time
+	for number := uint64(1); number <= head; number++ {
+		block, err := getBlockTimestamp(client, number)
+		if err != nil {
+			return err
+		}
+		if block.Before(previous) {
+			return fmt.Errorf("timestamp of block %d is %v, before that of its parent, %v",
+				number, block, previous)
+		}
+		previous = block
+	}
+	return nil
+}
+
+// checkLatestBlock verifies that the latest block of a node is within the
+// bound of real time.
+func (c *blockTimestampsChecker) checkLatestBlock(ctx context.Context, client rpc.Client) error {
+	head, err := client.BlockNumber(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to get block height; %w", err)
+	}
+	block, err := getBlockTimestamp(client, head)
+	if err != nil {
+		return err
+	}
+	now := time.Now
+	if c.now != nil {
+		now = c.now
+	}
+	deviation := block.Sub(now())
+	if deviation > c.bound || deviation < -c.bound {
+		return fmt.Errorf("timestamp of block %d deviates %v from real time, more than %v",
+			head, deviation, c.bound)
+	}
+	return nil
+}
+
+// blockTimestamp holds the timestamp fields of a block. Sonic adds the time
+// in nanoseconds to the Ethereum timestamp, which only has seconds.
+type blockTimestamp struct {
+	Timestamp     hexutil.Uint64  `json:"timestamp"`
+	TimestampNano *hexutil.Uint64 `json:"timestampNano"`
+}
+
+func getBlockTimestamp(client rpc.Client, number uint64) (time.Time, error) {
+	var block *blockTimestamp
+	err := client.Call(&block, "eth_getBlockByNumber", hexutil.EncodeUint64(number), false)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("failed to get block %d from RPC; %v", number, err)
+	}
+	if block == nil {
+		return time.Time{}, fmt.Errorf("block %d is not available", number)
+	}
+	if block.TimestampNano != nil {
+		return time.Unix(0, int64(*block.TimestampNano)), nil
+	}
+	return time.Unix(int64(block.Timestamp), 0), nil
+}
@@ -0,0 +1,122 @@
+// Copyright 2024 Fantom Foundation
+// This file is part of Norma System Testing Infrastructure for Sonic.
+//
+// Norma is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// Norma is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with Norma. If not, see <http://www.gnu.org/licenses/>.
+
+package checking
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/0xsoniclabs/norma/driver"
+	"github.com/0xsoniclabs/norma/driver/rpc"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"go.uber.org/mock/gomock"
+)
+
+// timestampNetwork builds a mocked network of nodes sharing a chain whose
+// blocks have the given timestamps, the last one being the head.
+func timestampNetwork(t *testing.T, timestamps ...time.Time) driver.Network {
+	t.Helper()
+	ctrl := gomock.NewController(t)
+
+	nodes := make([]driver.Node, 0, 2)
+	for _, label := range []string{"A", "B"} {
+		client := rpc.NewMockClient(ctrl)
+		client.EXPECT().Close().AnyTimes()
+		client.EXPECT().BlockNumber(gomock.Any()).AnyTimes().
+			Return(uint64(len(timestamps)-1), nil)
+		client.EXPECT().Call(
+			gomock.Any(), "eth_getBlockByNumber", gomock.Any(), false,
+		).AnyTimes().DoAndReturn(func(result any, _ string, args ...any) error {
+			number, err := hexutil.DecodeUint64(args[0].(string))
+			if err != nil {
+				return err
+			}
+			nanos := hexutil.Uint64(timestamps[number].UnixNano())
+			*result.(**blockTimestamp) = &blockTimestamp{
+				Timestamp:     hexutil.Uint64(timestamps[number].Unix()),
+				TimestampNano: &nanos,
+			}
+			return nil
+		})
+
+		node := driver.NewMockNode(ctrl)
+		node.EXPECT().GetLabel().AnyTimes().Return(label)
+		node.EXPECT().IsExpectedFailure().AnyTimes().Return(false)
+		node.EXPECT().DialRpc(gomock.Any()).AnyTimes().Return(client, nil)
+		nodes = append(nodes, node)
+	}
+
+	net := driver.NewMockNetwork(ctrl)
+	net.EXPECT().GetActiveNodes().AnyTimes().Return(nodes)
+	return net
+}
+
+func TestBlockTimestampsChecker_AcceptsOrderedTimestampsCloseToRealTime(t *testing.T) {
+	now := time.Unix(1000, 0)
+	net := timestampNetwork(t,
+		time.Unix(0, 0), // the genesis is not checked
+		now.Add(-3*time.Second),
+		now.Add(-2*time.Second),
+		now.Add(-2*time.Second),
+		now.Add(-time.Second),
+	)
+	checker := &blockTimestampsChecker{net: net, bound: defaultTimestampBound,
+		now: func() time.Time { return now }}
+
+	if err := checker.Check(t.Context()); err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+}
+
+func TestBlockTimestampsChecker_DetectsDecreasingTimestamps(t *testing.T) {
+	now := time.Unix(1000, 0)
+	net := timestampNetwork(t,
+		time.Unix(0, 0),
+		now.Add(-2*time.Second),
+		now.Add(-3*time.Second),
+		now.Add(-time.Second),
+	)
+	checker := &blockTimestampsChecker{net: net, bound: defaultTimestampBound,
+		now: func() time.Time { return now }}
+
+	err := checker.Check(t.Context())
+	if err == nil || !strings.Contains(err.Error(), "before that of its parent") {
+		t.Errorf("expected decreasing timestamps to be reported, got %v", err)
+	}
+}
+
+func TestBlockTimestampsChecker_DetectsDeviationFromRealTime(t *testing.T) {
+	now := time.Unix(1000, 0)
+	tests := map[string]time.Duration{
+		"ahead":  5 * time.Second,
+		"behind": -5 * time.Second,
+	}
+	for name, deviation := range tests {
+		t.Run(name, func(t *testing.T) {
+			net := timestampNetwork(t, time.Unix(0, 0), now.Add(deviation))
+			checker := (&blockTimestampsChecker{net: net, bound: defaultTimestampBound,
+				now: func() time.Time { return now }}).
+				Configure(CheckerConfig{"bound": int64(4 * time.Second)})
+
+			err := checker.Check(t.Context())
+			if err == nil || !strings.Contains(err.Error(), "deviates") {
+				t.Errorf("expected deviation to be reported, got %v", err)
+			}
+		})
+	}
+}
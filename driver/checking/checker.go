@@ -122,6 +122,7 @@ func (config *CheckerConfig) Check() error {
 		"failing":   isBool,
 		"tolerance": isPositiveInt,
 		"duration":  isNonNegativeInt64,
+		"bound":     isNonNegativeInt64,
 		"ceiling":   isPositiveInt,
 		"slack":     isPositiveInt,
 		"rules":     isRulesPatch,
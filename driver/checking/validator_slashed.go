@@ -0,0 +1,269 @@
+// Copyright 2024 Fantom Foundation
+// This file is part of Norma System Testing Infrastructure for Sonic.
+//
+// Norma is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// Norma is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with Norma. If not, see <http://www.gnu.org/licenses/>.
+
+package checking
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"maps"
+	"math/big"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/0xsoniclabs/norma/driver"
+	"github.com/0xsoniclabs/norma/driver/monitoring"
+	"github.com/0xsoniclabs/norma/driver/rpc"
+	"github.com/0xsoniclabs/norma/load/contracts/abi"
+	"github.com/0xsoniclabs/sonic/opera/contracts/sfc"
+)
+
+// doubleSignBit is the flag the SFC contract sets in the status of a
+// validator deactivated for double-signing.
+const doubleSignBit = 1 << 7
+
+// defaultSlashingTimeout bounds how long Check waits for the validators to be
+// slashed. Equivocation is only penalized once the evidence is part of a
+// block, which takes a moment after the conflicting events were emitted.
+const defaultSlashingTimeout = 60 * time.Second
+
+// slashingBlockTimeout bounds how long Check waits for a block produced after
+// the validators were found slashed.
+const slashingBlockTimeout = 30 * time.Second
+
+// validatorSlashedPollInterval is the delay between polls. Var so tests can
+// shorten it.
+var validatorSlashedPollInterval = 500 * time.Millisecond
+
+func init() {
+	RegisterNetworkCheck("validatorSlashed",
+		func(net driver.Network, _ *monitoring.Monitor) Checker {
+			return newValidatorSlashedChecker(net)
+		})
+}
+
+func newValidatorSlashedChecker(net driver.Network) *validatorSlashedChecker {
+	return &validatorSlashedChecker{
+		net:       net,
+		getStatus: getValidatorStatus,
+		timeout:   defaultSlashingTimeout,
+	}
+}
+
+// validatorSlashedChecker verifies that the validators of the nodes listed in
+// `slashedNodes` were caught double-signing and penalized for it, and that
+// the rest of the network carries on producing blocks without them.
+//
+// A validator counts as slashed if the SFC contract both flags it as a
+// double-signer in its status and reports it as slashed. The contract is read
+// through a node other than the listed ones, or the twin nodes signing with
+// their keys, so a cheater's own view of the chain cannot decide the check.
+type validatorSlashedChecker struct {
+	net          driver.Network
+	slashedNodes []string // node labels expected to be slashed
+	// getStatus reads the status of a validator from the SFC contract.
+	// Overridable for tests.
+	getStatus func(rpc.Client, int) (validatorStatus, error)
+	// timeout bounds the wait for the slashing. Zero means a single attempt.
+	timeout time.Duration
+}
+
+// validatorStatus is what the SFC contract reports about a validator.
+type validatorStatus struct {
+	flags   uint64 // status bits, zero for an active validator
+	slashed bool   // the contract's own verdict on the validator
+}
+
+func (c *validatorSlashedChecker) Configure(config CheckerConfig) Checker {
+	res := *c
+	if v, ok := config["slashedNodes"]; ok {
+		switch list := v.(type) {
+		case []string:
+			res.slashedNodes = list
+		case []any:
+			res.slashedNodes = nil
+			for _, item := range list {
+				if s, ok := item.(string); ok {
+					res.slashedNodes = append(res.slashedNodes, s)
+				}
+			}
+		}
+	}
+	if d, exist := config["duration"]; exist {
+		res.timeout = time.Duration(d.(int64))
+	}
+	return &res
+}
+
+func (c *validatorSlashedChecker) Check(ctx context.Context) error {
+	if len(c.slashedNodes) == 0 {
+		return fmt.Errorf("slashedNodes must not be empty")
+	}
+	nodes := c.net.GetActiveNodes()
+	if len(nodes) == 0 {
+		return fmt.Errorf("no active nodes")
+	}
+	cheaters, err := resolveSlashedValidators(nodes, c.slashedNodes)
+	if err != nil {
+		return err
+	}
+
+	client, err := dialFirstReachable(ctx, honestNodes(nodes, cheaters))
+	if err != nil {
+		return err
+	}
+	defer client.Close()
+
+	deadline := time.Now().Add(c.timeout)
+	ticker := time.NewTicker(validatorSlashedPollInterval)
+	defer ticker.Stop()
+	for {
+		err := c.verifySlashed(client, cheaters)
+		if err == nil {
+			break
+		}
+		// A non-positive timeout leaves the deadline in the past, which makes
+		// this the single attempt such a checker is configured for.
+		if !time.Now().Before(deadline) {
+			return err
+		}
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+		}
+	}
+	slog.Info("validators slashed for double-signing", "validators", cheaters)
+
+	return awaitNextBlock(ctx, client, slashingBlockTimeout)
+}
+
+// verifySlashed fails unless every listed validator is slashed for
+// double-signing.
+func (c *validatorSlashedChecker) verifySlashed(
+	client rpc.Client, cheaters map[int]string,
+) error {
+	unpunished := []string{}
+	for _, id := range slices.Sorted(maps.Keys(cheaters)) {
+		status, err := c.getStatus(client, id)
+		if err != nil {
+			return fmt.Errorf("failed to read status of validator %d: %w", id, err)
+		}
+		if status.flags&doubleSignBit == 0 || !status.slashed {
+			unpunished = append(unpunished, fmt.Sprintf(
+				"%s (validator %d, status %#x, slashed %t)",
+				cheaters[id], id, status.flags, status.slashed,
+			))
+		}
+	}
+	if len(unpunished) == 0 {
+		return nil
+	}
+	return fmt.Errorf(
+		"%d of %d validators were not slashed for double-signing: %s",
+		len(unpunished), len(cheaters), strings.Join(unpunished, ", "),
+	)
+}
+
+// resolveSlashedValidators maps the validator id of every listed node to its
+// label. Every label must name an active validator node.
+func resolveSlashedValidators(
+	nodes []driver.Node, slashedNodes []string,
+) (map[int]string, error) {
+	byLabel := make(map[string]int, len(nodes))
+	for _, n := range nodes {
+		if id := n.GetValidatorId(); id != nil {
+			byLabel[n.GetLabel()] = *id
+		}
+	}
+	cheaters := make(map[int]string, len(slashedNodes))
+	for _, label := range slashedNodes {
+		id, ok := byLabel[label]
+		if !ok {
+			return nil, fmt.Errorf(
+				"slashedNodes: no active validator with label %q", label,
+			)
+		}
+		cheaters[id] = label
+	}
+	return cheaters, nil
+}
+
+// honestNodes returns the nodes not signing as one of the given validators.
+func honestNodes(nodes []driver.Node, cheaters map[int]string) []driver.Node {
+	res := make([]driver.Node, 0, len(nodes))
+	for _, n := range nodes {
+		if id := n.GetValidatorId(); id != nil {
+			if _, cheating := cheaters[*id]; cheating {
+				continue
+			}
+		}
+		res = append(res, n)
+	}
+	return res
+}
+
+// awaitNextBlock waits until the node behind the client reports a block
+// beyond its current head.
+func awaitNextBlock(ctx context.Context, client rpc.Client, timeout time.Duration) error {
+	head, err := client.BlockNumber(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to get block height: %w", err)
+	}
+	deadline := time.Now().Add(timeout)
+	ticker := time.NewTicker(validatorSlashedPollInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+		}
+		current, err := client.BlockNumber(ctx)
+		if err != nil {
+			return fmt.Errorf("failed to get block height: %w", err)
+		}
+		if current > head {
+			return nil
+		}
+		if !time.Now().Before(deadline) {
+			return fmt.Errorf("no block produced after block %d within %v", head, timeout)
+		}
+	}
+}
+
+// getValidatorStatus reads the status of a validator from the SFC contract.
+func getValidatorStatus(client rpc.Client, id int) (validatorStatus, error) {
+	contract, err := abi.NewSFCCaller(sfc.ContractAddress, client)
+	if err != nil {
+		return validatorStatus{}, fmt.Errorf("failed to get SFC contract representation; %w", err)
+	}
+	validatorId := big.NewInt(int64(id))
+	validator, err := contract.GetValidator(nil, validatorId)
+	if err != nil {
+		return validatorStatus{}, fmt.Errorf("failed to get validator: %w", err)
+	}
+	slashed, err := contract.IsSlashed(nil, validatorId)
+	if err != nil {
+		return validatorStatus{}, fmt.Errorf("failed to get slashing status: %w", err)
+	}
+	return validatorStatus{
+		flags:   validator.Status.Uint64(),
+		slashed: slashed,
+	}, nil
+}
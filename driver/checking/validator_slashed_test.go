@@ -0,0 +1,173 @@
+// Copyright 2024 Fantom Foundation
+// This file is part of Norma System Testing Infrastructure for Sonic.
+//
+// Norma is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// Norma is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with Norma. If not, see <http://www.gnu.org/licenses/>.
+
+package checking
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/0xsoniclabs/norma/driver"
+	"github.com/0xsoniclabs/norma/driver/rpc"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+)
+
+func TestValidatorSlashedChecker_Configure_ReadsSlashedNodesAndDuration(t *testing.T) {
+	checker := newValidatorSlashedChecker(nil).Configure(CheckerConfig{
+		"slashedNodes": []any{"val-1", "val-2"},
+		"duration":     int64(5 * time.Second),
+	}).(*validatorSlashedChecker)
+	require.Equal(t, []string{"val-1", "val-2"}, checker.slashedNodes)
+	require.Equal(t, 5*time.Second, checker.timeout)
+	require.NotNil(t, checker.getStatus)
+}
+
+func TestValidatorSlashedChecker_Fails_WithoutSlashedNodes(t *testing.T) {
+	err := newValidatorSlashedChecker(nil).Check(context.Background())
+	require.ErrorContains(t, err, "slashedNodes must not be empty")
+}
+
+func TestValidatorSlashedChecker_Fails_ForUnknownNode(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	node := driver.NewMockNode(ctrl)
+	id := 1
+	node.EXPECT().GetValidatorId().Return(&id).AnyTimes()
+	node.EXPECT().GetLabel().Return("val-1").AnyTimes()
+	net := driver.NewMockNetwork(ctrl)
+	net.EXPECT().GetActiveNodes().Return([]driver.Node{node})
+
+	checker := newValidatorSlashedChecker(net)
+	checker.slashedNodes = []string{"val-9"}
+	err := checker.Check(context.Background())
+	require.ErrorContains(t, err, `no active validator with label "val-9"`)
+}
+
+func TestValidatorSlashedChecker_Passes_OnceTheCheaterIsSlashed(t *testing.T) {
+	pollSlashingFast(t)
+	ctrl := gomock.NewController(t)
+	checker, client := slashingCheckerWithCheater(ctrl)
+	checker.timeout = time.Minute
+
+	reads := 0
+	checker.getStatus = func(_ rpc.Client, id int) (validatorStatus, error) {
+		require.Equal(t, 2, id)
+		reads++
+		if reads < 3 {
+			return validatorStatus{}, nil
+		}
+		return validatorStatus{flags: doubleSignBit, slashed: true}, nil
+	}
+	gomock.InOrder(
+		client.EXPECT().BlockNumber(gomock.Any()).Return(uint64(10), nil),
+		client.EXPECT().BlockNumber(gomock.Any()).Return(uint64(10), nil),
+		client.EXPECT().BlockNumber(gomock.Any()).Return(uint64(11), nil),
+	)
+
+	require.NoError(t, checker.Check(context.Background()))
+	require.Equal(t, 3, reads)
+}
+
+func TestValidatorSlashedChecker_Fails_WhenTheCheaterIsNotPunished(t *testing.T) {
+	tests := map[string]validatorStatus{
+		"active":                 {},
+		"offline, not slashed":   {flags: 1 << 3},
+		"flagged, not slashed":   {flags: doubleSignBit},
+		"slashed without a flag": {slashed: true},
+	}
+	for name, status := range tests {
+		t.Run(name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			checker, _ := slashingCheckerWithCheater(ctrl)
+			checker.getStatus = func(rpc.Client, int) (validatorStatus, error) {
+				return status, nil
+			}
+
+			err := checker.Check(context.Background())
+			require.ErrorContains(t, err, "1 of 1 validators were not slashed")
+			require.ErrorContains(t, err, "val-2 (validator 2")
+		})
+	}
+}
+
+func TestValidatorSlashedChecker_Fails_WhenNoBlockFollowsTheSlashing(t *testing.T) {
+	pollSlashingFast(t)
+	ctrl := gomock.NewController(t)
+	checker, client := slashingCheckerWithCheater(ctrl)
+	checker.getStatus = func(rpc.Client, int) (validatorStatus, error) {
+		return validatorStatus{flags: doubleSignBit, slashed: true}, nil
+	}
+	client.EXPECT().BlockNumber(gomock.Any()).Return(uint64(10), nil).MinTimes(2)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	err := checker.Check(ctx)
+	require.ErrorIs(t, err, context.DeadlineExceeded)
+}
+
+func TestValidatorSlashedChecker_PropagatesStatusReadFailure(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	checker, _ := slashingCheckerWithCheater(ctrl)
+	checker.getStatus = func(rpc.Client, int) (validatorStatus, error) {
+		return validatorStatus{}, fmt.Errorf("SFC unreachable")
+	}
+
+	err := checker.Check(context.Background())
+	require.ErrorContains(t, err, "SFC unreachable")
+}
+
+// slashingCheckerWithCheater returns a checker expecting validator 2 to be
+// slashed, in a network of an honest validator 1 and validator 2 running on
+// two nodes. Only the honest node may be dialed.
+func slashingCheckerWithCheater(
+	ctrl *gomock.Controller,
+) (*validatorSlashedChecker, *rpc.MockClient) {
+	client := rpc.NewMockClient(ctrl)
+	client.EXPECT().Close()
+
+	honestId, cheaterId := 1, 2
+	honest := driver.NewMockNode(ctrl)
+	honest.EXPECT().GetValidatorId().Return(&honestId).AnyTimes()
+	honest.EXPECT().GetLabel().Return("val-1").AnyTimes()
+	honest.EXPECT().IsExpectedFailure().Return(false).AnyTimes()
+	honest.EXPECT().DialRpc(gomock.Any()).Return(client, nil)
+
+	nodes := []driver.Node{honest}
+	for _, label := range []string{"cheater-val-2", "val-2"} {
+		node := driver.NewMockNode(ctrl)
+		node.EXPECT().GetValidatorId().Return(&cheaterId).AnyTimes()
+		node.EXPECT().GetLabel().Return(label).AnyTimes()
+		nodes = append(nodes, node)
+	}
+
+	net := driver.NewMockNetwork(ctrl)
+	net.EXPECT().GetActiveNodes().Return(nodes)
+
+	checker := newValidatorSlashedChecker(net)
+	checker.slashedNodes = []string{"val-2"}
+	checker.timeout = 0
+	return checker, client
+}
+
+// pollSlashingFast shortens the poll interval for the duration of a test.
+func pollSlashingFast(t *testing.T) {
+	t.Helper()
+	original := validatorSlashedPollInterval
+	validatorSlashedPollInterval = time.Millisecond
+	t.Cleanup(func() { validatorSlashedPollInterval = original })
+}
@@ -66,7 +66,7 @@ func clientVersion(ctx context.Context, imageRef string) (string, error) {
 		return "", fmt.Errorf("failed to ask image %q for its client version: %w\n%s",
 			imageRef, err, strings.TrimSpace(string(output)))
 	}
-	version, found := parseReportedVersion(output)
+	version, found := ParseReportedVersion(output)
 	if !found {
 		return "", fmt.Errorf("image %q reported no client version:\n%s",
 			imageRef, strings.TrimSpace(string(output)))
@@ -78,8 +78,8 @@ func clientVersion(ctx context.Context, imageRef string) (string, error) {
 // documented to be machine readable and has carried this line since v2.0.0.
 var versionLine = regexp.MustCompile(`(?m)^Version:[ \t]*(\S+)`)
 
-// parseReportedVersion extracts the client version from that output.
-func parseReportedVersion(output []byte) (string, bool) {
+// ParseReportedVersion extracts the client version from that output.
+func ParseReportedVersion(output []byte) (string, bool) {
 	match := versionLine.FindSubmatch(output)
 	if match == nil {
 		return "", false
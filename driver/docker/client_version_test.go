@@ -44,7 +44,7 @@ func TestParseReportedVersion_ReadsTheVersionLine(t *testing.T) {
 
 	for name, test := range tests {
 		t.Run(name, func(t *testing.T) {
-			got, found := parseReportedVersion([]byte(test.output))
+			got, found := ParseReportedVersion([]byte(test.output))
 			if !found {
 				t.Fatalf("no version found in %q", test.output)
 			}
@@ -65,7 +65,7 @@ func TestParseReportedVersion_ReportsOutputWithoutAVersion(t *testing.T) {
 
 	for name, output := range tests {
 		t.Run(name, func(t *testing.T) {
-			if version, found := parseReportedVersion([]byte(output)); found {
+			if version, found := ParseReportedVersion([]byte(output)); found {
 				t.Fatalf("unexpected version %q in %q", version, output)
 			}
 		})
@@ -23,6 +23,7 @@ import (
 	"fmt"
 	"io"
 	"log/slog"
+	"math"
 	"math/rand"
 	"os"
 	"path/filepath"
@@ -82,14 +83,18 @@ type Container struct {
 	config  *ContainerConfig
 	stopped bool
 	cleaned bool
+	paused  bool
 
 	// execLogs carries the output of background execs. It is only used
 	// once a background exec has been started; until then the container's
 	// own stdout (via ContainerLogs) remains the log source.
 	execLogs *logBroadcaster
+	// streamLogs carries the output of background execs published on a
+	// named stream, see ExecOptions.Stream, by stream name.
+	streamLogs map[string]*logBroadcaster
 	// execLogPath is the host path of the most recent background exec log.
 	execLogPath string
-	// execLogMutex guards execLogs and execLogPath.
+	// execLogMutex guards execLogs, streamLogs and execLogPath.
 	execLogMutex sync.Mutex
 }
 
@@ -157,6 +162,31 @@ type ContainerConfig struct {
 	GenesisFileBind *string  // mount genesis file on host to /genesis.json:ro in container
 	KeystoreBinding *string  // mount keystore dir on host to /datadir/keystore:ro in container
 	LogsDir         *string  // host directory for exec output logs
+	Resources       Resources
+}
+
+// Resources limits the host resources a container may use, enforced through
+// its cgroup. A zero field leaves the respective resource unlimited when the
+// container is started, and unchanged when its limits are updated.
+type Resources struct {
+	CPUs        float64 // number of CPUs, may be fractional
+	Memory      uint64  // memory in bytes, without swap
+	BlkioWeight uint16  // relative block IO weight, 10 to 1000
+}
+
+// toDocker converts the limits into their Docker API representation.
+func (r Resources) toDocker() container.Resources {
+	res := container.Resources{
+		NanoCPUs:    int64(math.Round(r.CPUs * 1e9)),
+		BlkioWeight: r.BlkioWeight,
+	}
+	if r.Memory > 0 {
+		// A swap limit equal to the memory limit disables swapping, which
+		// would otherwise blur the effect of the limit.
+		res.Memory = int64(r.Memory)
+		res.MemorySwap = int64(r.Memory)
+	}
+	return res
 }
 
 // NewClient creates a new client facilitating the creation of Docker
@@ -252,9 +282,10 @@ func (c *Client) Start(ctx context.Context, config *ContainerConfig) (*Container
 		},
 		StopTimeout: &stopTimeout,
 	}, &container.HostConfig{
-		Init:   &init,
-		CapAdd: []string{"NET_ADMIN"},
-		Binds:  binds,
+		Init:      &init,
+		CapAdd:    []string{"NET_ADMIN"},
+		Binds:     binds,
+		Resources: config.Resources.toDocker(),
 	}, nil, nil, config.Hostname)
 	if err != nil {
 		return nil, err
@@ -365,6 +396,13 @@ func (c *Container) Stop(ctx context.Context) error {
 		return nil
 	}
 	c.stopped = true
+	// A frozen container cannot handle the shutdown signal, so it has to be
+	// thawed first to be stopped gracefully.
+	if c.paused {
+		if err := c.Unpause(ctx); err != nil {
+			return err
+		}
+	}
 	timeout := int(c.config.ShutdownTimeout.Seconds())
 	err := c.client.cli.ContainerStop(ctx, c.id, container.StopOptions{
 		Signal: string(SigInt), Timeout: &timeout})
@@ -374,6 +412,11 @@ func (c *Container) Stop(ctx context.Context) error {
 	if logs := c.logSource(); logs != nil {
 		logs.close()
 	}
+	c.execLogMutex.Lock()
+	for _, logs := range c.streamLogs {
+		logs.close()
+	}
+	c.execLogMutex.Unlock()
 	return err
 }
 
@@ -499,6 +542,18 @@ func (c *Container) StreamLog(ctx context.Context) (io.ReadCloser, error) {
 	return demuxContainerLog(reader), nil
 }
 
+// StreamLogOf returns a follow-mode reader over the output of the background
+// execs published on the given stream, see ExecOptions.Stream. Unlike
+// StreamLog, it never falls back to the container's stdout, so the reader
+// may attach before the first exec of the stream has been started. The empty
+// stream is the one StreamLog serves.
+func (c *Container) StreamLogOf(ctx context.Context, stream string) (io.ReadCloser, error) {
+	if stream == "" {
+		return c.StreamLog(ctx)
+	}
+	return c.logBroadcasterForExec(stream).subscribe(ctx), nil
+}
+
 // demuxContainerLog decodes docker's stream multiplexing protocol, which
 // ContainerLogs uses whenever the container was created without a TTY.
 // Without this, every frame's 8-byte binary header would end up inline in
@@ -545,6 +600,44 @@ func (c *Container) SendSignal(ctx context.Context, signal Signal) error {
 	return c.client.cli.ContainerKill(ctx, c.id, string(signal))
 }
 
+// Pause freezes all processes of the container, including those started by
+// Exec, without terminating them. Unlike SIGSTOP, the processes cannot tell
+// they were frozen and continue with their in-memory state once thawed.
+// While paused, the container accepts no execs.
+func (c *Container) Pause(ctx context.Context) error {
+	if err := c.client.cli.ContainerPause(ctx, c.id); err != nil {
+		return fmt.Errorf("failed to pause container: %w", err)
+	}
+	c.paused = true
+	return nil
+}
+
+// Unpause thaws the processes frozen by Pause.
+func (c *Container) Unpause(ctx context.Context) error {
+	if err := c.client.cli.ContainerUnpause(ctx, c.id); err != nil {
+		return fmt.Errorf("failed to unpause container: %w", err)
+	}
+	c.paused = false
+	return nil
+}
+
+// IsPaused returns true if the container is frozen by Pause.
+func (c *Container) IsPaused() bool {
+	return c.paused
+}
+
+// UpdateResources changes the resource limits of the running container.
+// Limits left zero stay as they are.
+func (c *Container) UpdateResources(ctx context.Context, resources Resources) error {
+	_, err := c.client.cli.ContainerUpdate(ctx, c.id, container.UpdateConfig{
+		Resources: resources.toDocker(),
+	})
+	if err != nil {
+		return fmt.Errorf("failed to update container resources: %w", err)
+	}
+	return nil
+}
+
 // Exec executes a command in the container.
 // This method is blocking until the command has finished.
 // The output of the command is returned as a string (stdout + stderr).
@@ -562,6 +655,11 @@ type ExecOptions struct {
 	// process output to a timestamped file named
 	// <hostname>_<LogName>_<timestamp>.log in that directory.
 	LogName string
+	// Stream, if set, separates the output of a background exec from that
+	// of the execs on other streams, so several clients sharing a container
+	// each have a log of their own; see StreamLogOf. It also replaces the
+	// hostname in the name of the log file.
+	Stream string
 }
 
 // ExecWithOptions executes a command in the container and blocks until it
@@ -586,7 +684,7 @@ func (c *Container) ExecWithOptions(
 	}
 
 	if opts.LogName != "" && c.config.LogsDir != nil {
-		if _, writeErr := c.writeExecLog(opts.LogName, output.Bytes()); writeErr != nil {
+		if _, writeErr := c.writeExecLog(opts.Stream, opts.LogName, output.Bytes()); writeErr != nil {
 			slog.Warn("failed to write exec log",
 				"name", opts.LogName,
 				"error", writeErr)
@@ -609,8 +707,8 @@ func (c *Container) ExecWithOptions(
 // ExecBackground starts a long-running command in the container without
 // waiting for it to finish. Its output is split into lines and forwarded
 // both to a host-side log file (when opts.LogName and LogsDir are set)
-// and to the container's log broadcaster, which is what StreamLog serves
-// from once a background exec exists.
+// and to the log broadcaster of its stream, which is what StreamLog serves
+// from once a background exec exists on the default stream.
 //
 // The returned handle's Done channel is closed after the process exited,
 // its output was drained and its exit status was recorded.
@@ -619,12 +717,12 @@ func (c *Container) ExecBackground(
 	cmd []string,
 	opts ExecOptions,
 ) (*ExecHandle, error) {
-	logs := c.logBroadcasterForExec()
+	logs := c.logBroadcasterForExec(opts.Stream)
 
 	var logFile *os.File
 	logPath := ""
 	if opts.LogName != "" && c.config.LogsDir != nil {
-		f, path, err := c.createExecLogFile(opts.LogName)
+		f, path, err := c.createExecLogFile(opts.Stream, opts.LogName)
 		if err != nil {
 			return nil, fmt.Errorf("failed to create exec log file: %w", err)
 		}
@@ -738,12 +836,24 @@ func (c *Container) startExec(
 	return execResp.ID, resp, nil
 }
 
-// logBroadcasterForExec returns the container's log broadcaster,
-// creating it on first use. Its existence is what switches StreamLog
-// over from the container's stdout to the exec output.
-func (c *Container) logBroadcasterForExec() *logBroadcaster {
+// logBroadcasterForExec returns the log broadcaster of the given stream,
+// creating it on first use. The existence of the default stream's
+// broadcaster is what switches StreamLog over from the container's stdout
+// to the exec output.
+func (c *Container) logBroadcasterForExec(stream string) *logBroadcaster {
 	c.execLogMutex.Lock()
 	defer c.execLogMutex.Unlock()
+	if stream != "" {
+		if c.streamLogs == nil {
+			c.streamLogs = map[string]*logBroadcaster{}
+		}
+		logs, found := c.streamLogs[stream]
+		if !found {
+			logs = newLogBroadcaster()
+			c.streamLogs[stream] = logs
+		}
+		return logs
+	}
 	if c.execLogs == nil {
 		c.execLogs = newLogBroadcaster()
 	}
@@ -760,8 +870,8 @@ func (c *Container) logSource() *logBroadcaster {
 
 // writeExecLog writes output data to a timestamped log file and returns
 // its path.
-func (c *Container) writeExecLog(name string, data []byte) (string, error) {
-	f, path, err := c.createExecLogFile(name)
+func (c *Container) writeExecLog(stream, name string, data []byte) (string, error) {
+	f, path, err := c.createExecLogFile(stream, name)
 	if err != nil {
 		return "", err
 	}
@@ -772,10 +882,15 @@ func (c *Container) writeExecLog(name string, data []byte) (string, error) {
 }
 
 // createExecLogFile creates a new timestamped log file in the
-// configured LogsDir and returns it along with its path.
-func (c *Container) createExecLogFile(name string) (*os.File, string, error) {
+// configured LogsDir and returns it along with its path. The file is named
+// after the stream, if any, and the container's hostname otherwise.
+func (c *Container) createExecLogFile(stream, name string) (*os.File, string, error) {
+	source := c.config.Hostname
+	if stream != "" {
+		source = stream
+	}
 	ts := time.Now().UTC().Format("20060102T150405.000Z")
-	filename := fmt.Sprintf("%s_%s_%s.log", c.config.Hostname, name, ts)
+	filename := fmt.Sprintf("%s_%s_%s.log", source, name, ts)
 	path := filepath.Join(*c.config.LogsDir, filename)
 	f, err := os.Create(path) //#nosec G304 -- path is constructed internally
 	if err != nil {
@@ -240,6 +240,79 @@ func TestContainer_SendSignal(t *testing.T) {
 	}
 }
 
+func TestContainer_PauseAndUnpause(t *testing.T) {
+	cli, cont := startRunningContainer(t, nil)
+	if err := cont.Pause(t.Context()); err != nil {
+		t.Fatalf("error: %v", err)
+	}
+	info, err := cli.cli.ContainerInspect(t.Context(), cont.id)
+	if err != nil {
+		t.Fatalf("error: %v", err)
+	}
+	if !info.State.Paused || !cont.IsPaused() {
+		t.Errorf("expected container to be paused")
+	}
+
+	if err := cont.Unpause(t.Context()); err != nil {
+		t.Fatalf("error: %v", err)
+	}
+	info, err = cli.cli.ContainerInspect(t.Context(), cont.id)
+	if err != nil {
+		t.Fatalf("error: %v", err)
+	}
+	if info.State.Paused || cont.IsPaused() {
+		t.Errorf("expected container to be running again")
+	}
+}
+
+func TestContainer_StopThawsPausedContainer(t *testing.T) {
+	_, cont := startRunningContainer(t, nil)
+	if err := cont.Pause(t.Context()); err != nil {
+		t.Fatalf("error: %v", err)
+	}
+	if err := cont.Stop(t.Context()); err != nil {
+		t.Fatalf("failed to stop paused container: %v", err)
+	}
+	if cont.IsPaused() {
+		t.Errorf("expected stopped container not to be paused")
+	}
+}
+
+func TestResources_ToDockerDisablesSwapUnderMemoryLimit(t *testing.T) {
+	got := Resources{CPUs: 1.5, Memory: 1 << 30, BlkioWeight: 300}.toDocker()
+	if got.NanoCPUs != 1_500_000_000 {
+		t.Errorf("unexpected NanoCPUs, got %d", got.NanoCPUs)
+	}
+	if got.Memory != 1<<30 || got.MemorySwap != 1<<30 {
+		t.Errorf("unexpected memory limits, got %d and swap %d", got.Memory, got.MemorySwap)
+	}
+	if got.BlkioWeight != 300 {
+		t.Errorf("unexpected BlkioWeight, got %d", got.BlkioWeight)
+	}
+
+	got = Resources{}.toDocker()
+	if got.NanoCPUs != 0 || got.Memory != 0 || got.MemorySwap != 0 || got.BlkioWeight != 0 {
+		t.Errorf("expected no limits, got %+v", got)
+	}
+}
+
+func TestContainer_UpdateResources(t *testing.T) {
+	cli, cont := startRunningContainer(t, nil)
+	if err := cont.UpdateResources(t.Context(), Resources{CPUs: 0.5, Memory: 64 << 20}); err != nil {
+		t.Fatalf("error: %v", err)
+	}
+	info, err := cli.cli.ContainerInspect(t.Context(), cont.id)
+	if err != nil {
+		t.Fatalf("error: %v", err)
+	}
+	if got := info.HostConfig.NanoCPUs; got != 500_000_000 {
+		t.Errorf("unexpected NanoCPUs, got %d", got)
+	}
+	if got := info.HostConfig.Memory; got != 64<<20 {
+		t.Errorf("unexpected memory limit, got %d", got)
+	}
+}
+
 func TestNetwork_Cleanup(t *testing.T) {
 	cli, net := createNetwork(t)
 
@@ -67,6 +67,15 @@ func SonicLocalPath() string {
 	return sonicLocalPath
 }
 
+// clockImageRepository is the repository of the client images whose client is
+// built with the clock offset hook of driver/node/clockhook. Its references
+// name the client sources the way the ones of "sonic" do, e.g.
+// "sonic-clock:local" builds the sources of "sonic:local".
+const clockImageRepository = "sonic-clock"
+
+// clockImageTarget is the Dockerfile target building the clock images.
+const clockImageTarget = "clock"
+
 // imageBuildKind describes how an image should be materialized when it is not
 // already available locally.
 type imageBuildKind int
@@ -87,10 +96,12 @@ const (
 // reference.
 //
 // clientSrc is passed to docker build as the value of the "client-src" build
-// context expected by the repository Dockerfile.
+// context expected by the repository Dockerfile, and target selects the
+// Dockerfile target to build; empty for the default one.
 type imageBuildPlan struct {
 	kind      imageBuildKind
 	clientSrc string
+	target    string
 }
 
 // EnsureImages makes sure the given image refs are locally available.
@@ -110,6 +121,8 @@ type imageBuildPlan struct {
 //     SetSonicLocalPath / SonicLocalPath, default DefaultSonicLocalPath)
 //   - sonic:<tag>: from sonicRepositoryURL#<tag>
 //   - sonic:<commit hash>: from sonicRepositoryURL#<commit hash>
+//   - sonic-clock[:<tag>]: as the sonic image of the same tag, with the clock
+//     offset hook
 //
 // Other images are pulled if missing.
 //
@@ -150,7 +163,7 @@ func EnsureImages(ctx context.Context, imageRefs []string, buildRoot string) err
 		switch plan.kind {
 		case imageBuildSonicRemote, imageBuildSonicLocal:
 			slog.Info("building image", "ref", ref, "clientSrc", plan.clientSrc)
-			if err := buildImage(ctx, buildRoot, ref, plan.clientSrc); err != nil {
+			if err := buildImage(ctx, buildRoot, ref, plan.clientSrc, plan.target); err != nil {
 				return err
 			}
 		case imageBuildNone:
@@ -209,14 +222,18 @@ func pullImage(ctx context.Context, cli *Client, imageRef string) error {
 // buildRoot.
 //
 // The build passes "client-src=<clientSrc>" as an additional build context,
-// matching the Dockerfile convention used by Norma. BuildKit is enabled
-// explicitly via environment variable to keep behavior aligned with existing
-// developer workflows.
+// matching the Dockerfile convention used by Norma, and builds the given
+// target, or the default one if empty. BuildKit is enabled explicitly via
+// environment variable to keep behavior aligned with existing developer
+// workflows.
 //
 // On failure, the function returns the docker command error along with captured
 // combined stdout/stderr output to aid diagnostics.
-func buildImage(ctx context.Context, buildRoot, imageRef, clientSrc string) error {
+func buildImage(ctx context.Context, buildRoot, imageRef, clientSrc, target string) error {
 	args := []string{"build", "--build-context", fmt.Sprintf("client-src=%s", clientSrc), ".", "-t", imageRef}
+	if target != "" {
+		args = append(args, "--target", target)
+	}
 	cmd := exec.CommandContext(ctx, "docker", args...)
 	cmd.Dir = buildRoot
 	cmd.Env = append(os.Environ(), "DOCKER_BUILDKIT=1")
@@ -236,10 +253,19 @@ func buildImage(ctx context.Context, buildRoot, imageRef, clientSrc string) erro
 //     DefaultSonicLocalPath)
 //   - "sonic:<tag>" => remote build from sonicRepositoryURL#<tag>
 //   - "sonic:<commit hash>" => remote build from sonicRepositoryURL#<commit hash>
+//   - "sonic-clock[:<tag>]" => as "sonic[:<tag>]", built for clockImageTarget
 //   - everything else => no build strategy (pull)
 //
 // The returned plan is consumed by EnsureImages.
 func planImage(imageRef string) imageBuildPlan {
+	if tag, found := strings.CutPrefix(imageRef, clockImageRepository); found &&
+		(tag == "" || strings.HasPrefix(tag, ":")) {
+		plan := planImage("sonic" + tag)
+		if plan.kind != imageBuildNone {
+			plan.target = clockImageTarget
+		}
+		return plan
+	}
 	if imageRef == "sonic" {
 		return imageBuildPlan{kind: imageBuildSonicRemote, clientSrc: sonicRepositoryURL}
 	}
@@ -266,8 +292,8 @@ func planImage(imageRef string) imageBuildPlan {
 // image reference.
 //
 // This is true for Sonic image refs handled via local or remote source build
-// contexts (e.g. sonic, sonic:local, sonic:<tag-or-commit>). For all other
-// refs, EnsureImages will pull instead.
+// contexts (e.g. sonic, sonic:local, sonic:<tag-or-commit>, and the same for
+// sonic-clock). For all other refs, EnsureImages will pull instead.
 func WillBuildImage(imageRef string) bool {
 	plan := planImage(imageRef)
 	return plan.kind == imageBuildSonicRemote || plan.kind == imageBuildSonicLocal
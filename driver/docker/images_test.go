@@ -53,6 +53,31 @@ func TestPlanImage(t *testing.T) {
 				clientSrc: sonicRepositoryURL + "#v2.1.2",
 			},
 		},
+		{
+			name:     "local clock image",
+			imageRef: "sonic-clock:local",
+			want: imageBuildPlan{
+				kind:      imageBuildSonicLocal,
+				clientSrc: "sonic",
+				target:    "clock",
+			},
+		},
+		{
+			name:     "tagged remote clock image",
+			imageRef: "sonic-clock:v2.1.2",
+			want: imageBuildPlan{
+				kind:      imageBuildSonicRemote,
+				clientSrc: sonicRepositoryURL + "#v2.1.2",
+				target:    "clock",
+			},
+		},
+		{
+			name:     "image of another repository with the same prefix is pull-only plan",
+			imageRef: "sonic-clockwork:local",
+			want: imageBuildPlan{
+				kind: imageBuildNone,
+			},
+		},
 		{
 			name:     "non sonic image is pull-only plan",
 			imageRef: "alpine",
@@ -260,7 +285,7 @@ func TestBuildImage_Builds_SonicLocal(t *testing.T) {
 		t.Skipf("local sonic sources not available at %s: %v", filepath.Join(buildRoot, "sonic"), err)
 	}
 
-	if err := buildImage(t.Context(), buildRoot, "sonic:testlocal", "sonic"); err != nil {
+	if err := buildImage(t.Context(), buildRoot, "sonic:testlocal", "sonic", ""); err != nil {
 		t.Fatalf("failed to build sonic:testlocal image: %v", err)
 	}
 
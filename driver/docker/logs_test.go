@@ -226,6 +226,29 @@ func TestLogSubscription_CloseUnsubscribesAndIsIdempotent(t *testing.T) {
 	}
 }
 
+func TestContainer_StreamLogOfServesOnlyTheOutputOfItsStream(t *testing.T) {
+	c := &Container{config: &ContainerConfig{Hostname: "pack"}}
+	first, err := c.StreamLogOf(t.Context(), "first")
+	if err != nil {
+		t.Fatalf("failed to stream log: %v", err)
+	}
+	defer func() { _ = first.Close() }()
+
+	publishLines(c.logBroadcasterForExec("first"), "one")
+	publishLines(c.logBroadcasterForExec("second"), "two")
+	publishLines(c.logBroadcasterForExec(""), "default")
+	c.execLogMutex.Lock()
+	for _, logs := range c.streamLogs {
+		logs.close()
+	}
+	c.execLogMutex.Unlock()
+
+	got := readLines(t, first)
+	if len(got) != 1 || got[0] != "one" {
+		t.Errorf("unexpected lines, got %v, want [one]", got)
+	}
+}
+
 func TestLineSplitter_EmitsCompleteLinesAcrossWrites(t *testing.T) {
 	var got []string
 	w := &lineSplitter{emit: func(line []byte) {
@@ -0,0 +1,425 @@
+// Copyright 2024 Fantom Foundation
+// This file is part of Norma System Testing Infrastructure for Sonic.
+//
+// Norma is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// Norma is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with Norma. If not, see <http://www.gnu.org/licenses/>.
+
+package executor
+
+import (
+	"cmp"
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"maps"
+	"math/rand"
+	"slices"
+	"time"
+
+	"github.com/0xsoniclabs/norma/driver"
+	"github.com/0xsoniclabs/norma/driver/network"
+	"github.com/0xsoniclabs/norma/driver/node"
+	"github.com/0xsoniclabs/norma/driver/parser"
+)
+
+// Bounds of the random times between the disruptions of a chaos step and of
+// how long each of them lasts. Vars so tests can shorten them.
+var (
+	chaosMinGap  = 5 * time.Second
+	chaosMaxGap  = 20 * time.Second
+	chaosMinHold = 10 * time.Second
+	chaosMaxHold = 30 * time.Second
+)
+
+// chaosTarget is a node a chaos step may disrupt, along with the stake of
+// its validator in the current epoch, if it runs one.
+type chaosTarget struct {
+	name      string
+	validator bool
+	stake     uint64
+}
+
+// chaosAction is a disruption planned by a chaos step: one of the actions
+// of the step applied to a target at an offset from the start of the step,
+// and undone after hold.
+type chaosAction struct {
+	at, hold time.Duration
+	kind     string
+	target   chaosTarget
+}
+
+// planChaos draws the disruptions of a chaos step lasting window from the
+// given seed. Every disruption hits a single target not disrupted already,
+// and at most maxValidators of them, holding at most maxStake together, hit
+// validators at the same time. Only one target is partitioned off at a time,
+// and never while another one is paused, as a frozen node could not take
+// part in the cut. All disruptions end within the window. The plan depends
+// on nothing but its arguments; as the stakes are read from the network,
+// execChaos logs them along with the seed, so that a run can be replayed.
+func planChaos(
+	seed int64,
+	window time.Duration,
+	targets []chaosTarget,
+	kinds []string,
+	maxValidators int,
+	maxStake uint64,
+) []chaosAction {
+	rng := rand.New(rand.NewSource(seed))
+	between := func(lo, hi time.Duration) time.Duration {
+		return lo + time.Duration(rng.Int63n(int64(hi-lo)+1))
+	}
+
+	var plan []chaosAction
+	for at := between(chaosMinGap, chaosMaxGap); at < window; at += between(chaosMinGap, chaosMaxGap) {
+		affected := map[string]bool{}
+		validators := 0
+		var stake uint64
+		partitioned, paused := false, false
+		for _, action := range plan {
+			if action.at+action.hold <= at {
+				continue
+			}
+			affected[action.target.name] = true
+			if action.target.validator {
+				validators++
+				stake += action.target.stake
+			}
+			partitioned = partitioned || action.kind == parser.ChaosPartition
+			paused = paused || action.kind == parser.ChaosPause
+		}
+
+		var eligible []chaosTarget
+		for _, target := range targets {
+			if affected[target.name] {
+				continue
+			}
+			if !target.validator || (validators < maxValidators && stake+target.stake <= maxStake) {
+				eligible = append(eligible, target)
+			}
+		}
+		allowed := slices.DeleteFunc(slices.Clone(kinds), func(kind string) bool {
+			return (kind == parser.ChaosPartition && (partitioned || paused)) ||
+				(kind == parser.ChaosPause && partitioned)
+		})
+		if len(eligible) == 0 || len(allowed) == 0 {
+			continue
+		}
+
+		plan = append(plan, chaosAction{
+			at:     at,
+			kind:   allowed[rng.Intn(len(allowed))],
+			target: eligible[rng.Intn(len(eligible))],
+			hold:   min(between(chaosMinHold, chaosMaxHold), window-at),
+		})
+	}
+	return plan
+}
+
+// execChaos disrupts the targets of a chaos step as planned from its seed,
+// and undoes every disruption before it returns, also when it fails. Each
+// disruption and its undoing is recorded in the step timings, under a name
+// that carries the seed.
+func execChaos(
+	ctx context.Context,
+	step *parser.Step,
+	net driver.Network,
+	state *runState,
+) error {
+	seed := time.Now().UnixNano()
+	if step.Seed != nil {
+		seed = *step.Seed
+	}
+	kinds := step.ChaosActions
+	if len(kinds) == 0 {
+		kinds = parser.AllChaosActions
+	}
+
+	var targets []chaosTarget
+	for _, name := range step.Targets {
+		instances := findNodeInstances(state, name)
+		if len(instances) == 0 {
+			return fmt.Errorf("node %q not found in active nodes", name)
+		}
+		for _, instance := range instances {
+			if slices.ContainsFunc(targets, func(t chaosTarget) bool { return t.name == instance.name }) {
+				continue
+			}
+			_, validator := state.validatorIds[instance.name]
+			targets = append(targets, chaosTarget{name: instance.name, validator: validator})
+		}
+	}
+
+	maxValidators := 1
+	if step.MaxAffected != nil {
+		maxValidators = *step.MaxAffected
+	}
+	// Validators are only disrupted as far as the stake of the epoch allows,
+	// which is read from the network unless no validator is targeted.
+	var maxStake uint64
+	if slices.ContainsFunc(targets, func(t chaosTarget) bool { return t.validator }) {
+		stakes, err := readValidatorStakes(net)
+		if err != nil {
+			return fmt.Errorf("failed to read the stakes of the validators; %w", err)
+		}
+		maxStake = disruptableStake(stakes, upValidators(state))
+		for i := range targets {
+			if targets[i].validator {
+				targets[i].stake = stakes[state.validatorIds[targets[i].name]]
+			}
+		}
+	}
+
+	plan := planChaos(seed, step.Duration, targets, kinds, maxValidators, maxStake)
+	targetStakes := map[string]uint64{}
+	for _, target := range targets {
+		if target.validator {
+			targetStakes[target.name] = target.stake
+		}
+	}
+	slog.Info("starting chaos", "seed", seed, "disruptions", len(plan),
+		"duration", step.Duration, "max_validators", maxValidators, "max_stake", maxStake,
+		"stakes", targetStakes)
+
+	// Disruptions ending at the time another one starts are undone first.
+	type event struct {
+		at    time.Duration
+		index int
+		undo  bool
+	}
+	events := make([]event, 0, 2*len(plan))
+	for i, action := range plan {
+		events = append(events,
+			event{at: action.at, index: i},
+			event{at: action.at + action.hold, index: i, undo: true})
+	}
+	slices.SortStableFunc(events, func(a, b event) int {
+		if c := cmp.Compare(a.at, b.at); c != 0 {
+			return c
+		}
+		if a.undo != b.undo {
+			if a.undo {
+				return -1
+			}
+			return 1
+		}
+		return 0
+	})
+
+	start := time.Now()
+	inPlace := map[int]bool{}
+	var err error
+	for _, e := range events {
+		if err = sleepUntil(ctx, start.Add(e.at)); err != nil {
+			break
+		}
+		action := plan[e.index]
+		if e.undo {
+			err = recordChaos(state, seed, e.index, "undo "+action.kind, action.target.name, func() error {
+				return undoChaos(ctx, action, net, state)
+			})
+			delete(inPlace, e.index)
+		} else {
+			err = recordChaos(state, seed, e.index, action.kind, action.target.name, func() error {
+				return injectChaos(ctx, action, net, state)
+			})
+			if err == nil {
+				inPlace[e.index] = true
+			}
+		}
+		if err != nil {
+			break
+		}
+	}
+	if err == nil {
+		return sleepUntil(ctx, start.Add(step.Duration))
+	}
+
+	// Leave no node frozen, stopped or cut off behind a failure, even if the
+	// failure is the end of the context.
+	cleanupCtx := context.WithoutCancel(ctx)
+	for _, i := range slices.Sorted(maps.Keys(inPlace)) {
+		action := plan[i]
+		err = errors.Join(err, recordChaos(state, seed, i, "undo "+action.kind, action.target.name, func() error {
+			return undoChaos(cleanupCtx, action, net, state)
+		}))
+	}
+	return err
+}
+
+// readValidatorStakes reads the stakes of the validators of the current
+// epoch, in S, by validator id.
+func readValidatorStakes(net driver.Network) (map[int]uint64, error) {
+	client, err := net.DialRandomRpc()
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect to RPC; %w", err)
+	}
+	defer client.Close()
+	return network.GetValidatorStakes(client)
+}
+
+// stateReporter is a node that reports the state of its client, as
+// node.OperaNode does.
+type stateReporter interface {
+	GetState() node.NodeState
+}
+
+// upValidators returns the ids of the validators that take part in
+// producing blocks: the ones whose client runs, neither frozen nor stopped,
+// and which are not cut off by a partition. Nodes not reporting the state of
+// their client are taken to be down.
+func upValidators(state *runState) map[int]bool {
+	up := map[int]bool{}
+	for name, n := range state.nodes {
+		id, validator := state.validatorIds[name]
+		if !validator {
+			continue
+		}
+		if _, partitioned := state.partitioned[name]; partitioned {
+			continue
+		}
+		if reporter, ok := n.(stateReporter); ok && reporter.GetState() == node.NodeStateRunning {
+			up[id] = true
+		}
+	}
+	return up
+}
+
+// disruptableStake returns the stake of the validators that may be disrupted
+// at the same time, given the stakes of the validators of the epoch and the
+// ones running. Blocks are only produced while validators holding more than
+// two thirds of the stake of the epoch are up, so the running validators
+// may lose less than the stake they hold beyond two thirds of it.
+func disruptableStake(stakes map[int]uint64, running map[int]bool) uint64 {
+	var total, up uint64
+	for id, stake := range stakes {
+		total += stake
+		if running[id] {
+			up += stake
+		}
+	}
+	// Disrupting a stake d leaves blocks produced while 3*(up-d) > 2*total.
+	if 3*up <= 2*total {
+		return 0
+	}
+	return (3*up - 2*total - 1) / 3
+}
+
+// injectChaos puts a planned disruption in place.
+func injectChaos(ctx context.Context, action chaosAction, net driver.Network, state *runState) error {
+	target, opera, err := chaosNode(state, action.target.name)
+	if err != nil {
+		return err
+	}
+	switch action.kind {
+	case parser.ChaosKillHeal:
+		net.SuspendNode(target)
+		return opera.ForceStopSonicd(ctx)
+	case parser.ChaosRestart:
+		net.SuspendNode(target)
+		return opera.StopSonicd(ctx)
+	case parser.ChaosPause:
+		net.SuspendNode(target)
+		if err := opera.PauseSonicd(ctx); err != nil {
+			net.ResumeNode(target)
+			return err
+		}
+		return nil
+	case parser.ChaosPartition:
+		others := make([]driver.Node, 0, len(state.nodes))
+		for _, name := range slices.Sorted(maps.Keys(state.nodes)) {
+			if name != action.target.name {
+				others = append(others, state.nodes[name])
+			}
+		}
+		return net.PartitionNodes(ctx, [][]driver.Node{{target}, others})
+	default:
+		return fmt.Errorf("unknown chaos action %q", action.kind)
+	}
+}
+
+// undoChaos lifts a disruption put in place by injectChaos. Stopped clients
+// are restarted in place, so the node keeps its data and its validator.
+func undoChaos(ctx context.Context, action chaosAction, net driver.Network, state *runState) error {
+	target, opera, err := chaosNode(state, action.target.name)
+	if err != nil {
+		return err
+	}
+	restart := &parser.Step{Identifier: action.target.name, NodeType: "observer"}
+	if action.target.validator {
+		restart.NodeType = "validator"
+	}
+	switch action.kind {
+	case parser.ChaosKillHeal:
+		healCtx, cancel := context.WithTimeout(ctx, healDbTimeout)
+		defer cancel()
+		if err := opera.HealSonicd(healCtx); err != nil {
+			return err
+		}
+		return restartNodeInPlace(ctx, restart, net, target)
+	case parser.ChaosRestart:
+		return restartNodeInPlace(ctx, restart, net, target)
+	case parser.ChaosPause:
+		if err := opera.ResumeSonicd(ctx); err != nil {
+			return err
+		}
+		net.ResumeNode(target)
+		return nil
+	case parser.ChaosPartition:
+		return net.HealPartition(ctx)
+	default:
+		return fmt.Errorf("unknown chaos action %q", action.kind)
+	}
+}
+
+// chaosNode returns the active node of the given name, which has to be an
+// OperaNode to be disrupted.
+func chaosNode(state *runState, name string) (driver.Node, *node.OperaNode, error) {
+	target, ok := state.nodes[name]
+	if !ok {
+		return nil, nil, fmt.Errorf("node %q not found in active nodes", name)
+	}
+	opera, ok := target.(*node.OperaNode)
+	if !ok {
+		return nil, nil, fmt.Errorf("node %q is not an OperaNode", name)
+	}
+	return target, opera, nil
+}
+
+// recordChaos runs one disruption or its undoing and records it in the step
+// timings, if they are captured.
+func recordChaos(state *runState, seed int64, index int, what, target string, run func() error) error {
+	name := fmt.Sprintf("chaos seed %d #%d: %s %s", seed, index+1, what, target)
+	slog.Info("chaos", "action", name)
+	start := time.Now()
+	err := run()
+	if state.onEvent != nil {
+		state.onEvent(EventExecution{Name: name, Start: start, End: time.Now()})
+	}
+	if err != nil {
+		return fmt.Errorf("%s: %w", name, err)
+	}
+	return nil
+}
+
+// sleepUntil waits until the given time, or until the context ends.
+func sleepUntil(ctx context.Context, deadline time.Time) error {
+	timer := time.NewTimer(time.Until(deadline))
+	defer timer.Stop()
+	select {
+	case <-timer.C:
+		return nil
+	case <-ctx.Done():
+		return fmt.Errorf("context cancelled during chaos: %w", ctx.Err())
+	}
+}
@@ -0,0 +1,222 @@
+// Copyright 2024 Fantom Foundation
+// This file is part of Norma System Testing Infrastructure for Sonic.
+//
+// Norma is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// Norma is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with Norma. If not, see <http://www.gnu.org/licenses/>.
+
+package executor
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/0xsoniclabs/norma/driver"
+	"github.com/0xsoniclabs/norma/driver/node"
+	"github.com/0xsoniclabs/norma/driver/parser"
+	"go.uber.org/mock/gomock"
+)
+
+func TestPlanChaos_IsDeterministicForASeed(t *testing.T) {
+	targets := []chaosTarget{{"a", true, 1}, {"b", true, 1}, {"c", false, 0}}
+	first := planChaos(42, 10*time.Minute, targets, parser.AllChaosActions, 1, 1)
+	second := planChaos(42, 10*time.Minute, targets, parser.AllChaosActions, 1, 1)
+	if len(first) == 0 {
+		t.Fatalf("expected disruptions to be planned")
+	}
+	if !reflect.DeepEqual(first, second) {
+		t.Fatalf("plans of the same seed differ\nfirst:  %v\nsecond: %v", first, second)
+	}
+	other := planChaos(43, 10*time.Minute, targets, parser.AllChaosActions, 1, 1)
+	if reflect.DeepEqual(first, other) {
+		t.Errorf("plans of different seeds are identical: %v", first)
+	}
+}
+
+func TestPlanChaos_RespectsBounds(t *testing.T) {
+	targets := []chaosTarget{{"a", true, 1}, {"b", true, 1}, {"c", true, 1}, {"d", false, 0}, {"e", false, 0}}
+	window := 10 * time.Minute
+	for seed := range int64(50) {
+		plan := planChaos(seed, window, targets, parser.AllChaosActions, 2, 2)
+		for i, action := range plan {
+			if action.at+action.hold > window {
+				t.Fatalf("seed %d: disruption %d ends after the window: %v", seed, i, action)
+			}
+			if action.hold <= 0 {
+				t.Fatalf("seed %d: disruption %d has no duration: %v", seed, i, action)
+			}
+			validators, partitions, pauses := 0, 0, 0
+			affected := map[string]bool{}
+			for _, other := range plan[:i+1] {
+				if other.at+other.hold <= action.at {
+					continue
+				}
+				if affected[other.target.name] {
+					t.Fatalf("seed %d: node %s is disrupted twice at %v", seed, other.target.name, action.at)
+				}
+				affected[other.target.name] = true
+				if other.target.validator {
+					validators++
+				}
+				switch other.kind {
+				case parser.ChaosPartition:
+					partitions++
+				case parser.ChaosPause:
+					pauses++
+				}
+			}
+			if validators > 2 {
+				t.Fatalf("seed %d: %d validators disrupted at %v", seed, validators, action.at)
+			}
+			if partitions > 1 || (partitions > 0 && pauses > 0) {
+				t.Fatalf("seed %d: %d partitions and %d pauses at %v", seed, partitions, pauses, action.at)
+			}
+		}
+	}
+}
+
+func TestPlanChaos_LeavesValidatorsAloneWithoutQuorumToSpare(t *testing.T) {
+	targets := []chaosTarget{{"val", true, 1}, {"obs", false, 0}}
+	plan := planChaos(1, 10*time.Minute, targets, []string{parser.ChaosPause}, 0, 0)
+	if len(plan) == 0 {
+		t.Fatalf("expected the observer to be disrupted")
+	}
+	for _, action := range plan {
+		if action.target.validator || action.kind != parser.ChaosPause {
+			t.Fatalf("unexpected disruption %v", action)
+		}
+	}
+}
+
+func TestPlanChaos_BoundsTheStakeOfDisruptedValidators(t *testing.T) {
+	targets := []chaosTarget{{"heavy", true, 10}, {"a", true, 1}, {"b", true, 1}, {"c", true, 1}}
+	window := 10 * time.Minute
+	for seed := range int64(50) {
+		plan := planChaos(seed, window, targets, parser.AllChaosActions, 3, 2)
+		for i, action := range plan {
+			if action.target.name == "heavy" {
+				t.Fatalf("seed %d: validator holding more than the stake to spare is disrupted: %v", seed, action)
+			}
+			var stake uint64
+			for _, other := range plan[:i+1] {
+				if other.at+other.hold > action.at {
+					stake += other.target.stake
+				}
+			}
+			if stake > 2 {
+				t.Fatalf("seed %d: validators holding %d are disrupted at %v", seed, stake, action.at)
+			}
+		}
+	}
+}
+
+func TestDisruptableStake_LeavesMoreThanTwoThirdsOfTheStakeUp(t *testing.T) {
+	equal := map[int]uint64{1: 1, 2: 1, 3: 1, 4: 1}
+	all := map[int]bool{1: true, 2: true, 3: true, 4: true}
+	tests := map[string]struct {
+		stakes  map[int]uint64
+		running map[int]bool
+		want    uint64
+	}{
+		"four equal validators":   {equal, all, 1},
+		"three equal validators":  {map[int]uint64{1: 1, 2: 1, 3: 1}, all, 0},
+		"one of four stopped":     {equal, map[int]bool{1: true, 2: true, 3: true}, 0},
+		"one heavy validator":     {map[int]uint64{1: 10, 2: 1, 3: 1, 4: 1}, all, 4},
+		"heavy validator stopped": {map[int]uint64{1: 10, 2: 1, 3: 1, 4: 1}, map[int]bool{2: true, 3: true, 4: true}, 0},
+		"no validators":           {map[int]uint64{}, all, 0},
+	}
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			if got := disruptableStake(test.stakes, test.running); got != test.want {
+				t.Errorf("unexpected disruptable stake, got %d, want %d", got, test.want)
+			}
+		})
+	}
+}
+
+// clientStateNode is a node reporting the given state of its client.
+type clientStateNode struct {
+	driver.Node
+	state node.NodeState
+}
+
+func (n clientStateNode) GetState() node.NodeState {
+	return n.state
+}
+
+func TestUpValidators_LeavesOutValidatorsNotTakingPart(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	state := &runState{
+		nodes: map[string]driver.Node{
+			"running":     clientStateNode{state: node.NodeStateRunning},
+			"paused":      clientStateNode{state: node.NodeStatePaused},
+			"killed":      clientStateNode{state: node.NodeStateKilled},
+			"partitioned": clientStateNode{state: node.NodeStateRunning},
+			"unknown":     driver.NewMockNode(ctrl),
+			"observer":    clientStateNode{state: node.NodeStateRunning},
+		},
+		validatorIds: map[string]int{
+			"running": 1, "paused": 2, "killed": 3, "partitioned": 4, "unknown": 5, "stopped": 6,
+		},
+		partitioned: map[string]driver.Node{"partitioned": nil, "observer": nil},
+	}
+	want := map[int]bool{1: true}
+	if got := upValidators(state); !reflect.DeepEqual(got, want) {
+		t.Errorf("unexpected validators up, got %v, want %v", got, want)
+	}
+}
+
+func TestExecChaos_UnknownTargetIsReported(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	net := driver.NewMockNetwork(ctrl)
+
+	step := &parser.Step{Function: parser.FuncChaos, Duration: time.Second, Targets: []string{"missing"}}
+	err := execChaos(t.Context(), step, net, &runState{nodes: map[string]driver.Node{}})
+	if err == nil || !strings.Contains(err.Error(), `node "missing" not found`) {
+		t.Fatalf("expected unknown node error, got %v", err)
+	}
+}
+
+func TestExecChaos_RecordsFailedDisruptionWithSeed(t *testing.T) {
+	defer func(minGap, maxGap, minHold, maxHold time.Duration) {
+		chaosMinGap, chaosMaxGap, chaosMinHold, chaosMaxHold = minGap, maxGap, minHold, maxHold
+	}(chaosMinGap, chaosMaxGap, chaosMinHold, chaosMaxHold)
+	chaosMinGap, chaosMaxGap = time.Millisecond, time.Millisecond
+	chaosMinHold, chaosMaxHold = time.Millisecond, time.Millisecond
+
+	ctrl := gomock.NewController(t)
+	net := driver.NewMockNetwork(ctrl)
+	var events []EventExecution
+	state := &runState{
+		nodes:        map[string]driver.Node{"obs": driver.NewMockNode(ctrl)},
+		validatorIds: map[string]int{},
+		onEvent:      func(e EventExecution) { events = append(events, e) },
+	}
+
+	seed := int64(7)
+	step := &parser.Step{
+		Function:     parser.FuncChaos,
+		Duration:     time.Second,
+		Seed:         &seed,
+		Targets:      []string{"obs"},
+		ChaosActions: []string{parser.ChaosPause},
+	}
+	err := execChaos(t.Context(), step, net, state)
+	if err == nil || !strings.Contains(err.Error(), "is not an OperaNode") {
+		t.Fatalf("expected the disruption to fail, got %v", err)
+	}
+	if len(events) != 1 || events[0].Name != "chaos seed 7 #1: pause obs" {
+		t.Fatalf("unexpected events %v", events)
+	}
+}
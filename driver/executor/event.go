@@ -23,4 +23,7 @@ import "time"
 type EventExecution struct {
 	Name       string
 	Start, End time.Time
+	// Error is the error of a step that failed as expected; empty for all
+	// other events.
+	Error string
 }
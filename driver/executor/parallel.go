@@ -0,0 +1,221 @@
+// Copyright 2024 Fantom Foundation
+// This file is part of Norma System Testing Infrastructure for Sonic.
+//
+// Norma is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// Norma is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with Norma. If not, see <http://www.gnu.org/licenses/>.
+
+package executor
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"fmt"
+	"log/slog"
+	"maps"
+	"sync"
+	"time"
+
+	"github.com/0xsoniclabs/norma/driver"
+	"github.com/0xsoniclabs/norma/driver/checking"
+	"github.com/0xsoniclabs/norma/driver/parser"
+	"github.com/ethereum/go-ethereum/common"
+	"golang.org/x/sync/errgroup"
+)
+
+// execParallel runs the branches of a parallel step at the same time and
+// returns once all of them have finished. The first branch to fail cancels
+// the others.
+//
+// Each branch runs on a copy of the run state, and the changes it made are
+// merged back once all branches are done, whether they succeeded or not, so
+// that the nodes and applications started by a branch are known to the
+// steps after it and cleaned up with the rest. The parser makes sure no two
+// branches act on the same node, application or delegator, so their changes
+// do not collide. The run serializes the transactions sent by the treasury
+// account, as concurrent ones would be given the same nonce.
+func execParallel(
+	ctx context.Context,
+	step *parser.Step,
+	net driver.Network,
+	checks checking.Checks,
+	registry validatorRegistry,
+	state *runState,
+) error {
+	events := &sync.Mutex{}
+	forks := make([]*runState, len(step.Branches))
+	g, gctx := errgroup.WithContext(ctx)
+	for i := range step.Branches {
+		branch := &step.Branches[i]
+		fork := state.fork()
+		if state.onEvent != nil {
+			fork.onEvent = func(event EventExecution) {
+				events.Lock()
+				defer events.Unlock()
+				state.onEvent(event)
+			}
+		}
+		forks[i] = fork
+		g.Go(func() error {
+			name := fmt.Sprintf("parallel branch %d: %s", i+1, describeStep(branch))
+			slog.Info("executing parallel branch", "branch", i+1, "function", branch.Function, "identifier", branch.Identifier)
+			start := time.Now()
+			err := executeStep(gctx, branch, net, checks, registry, fork)
+			observed, err := checkExpectedError(gctx, branch, err)
+			if fork.onEvent != nil {
+				fork.onEvent(EventExecution{Name: name, Start: start, End: time.Now(), Error: observed})
+			}
+			if err != nil {
+				return fmt.Errorf("branch %d (%s %s): %w", i+1, branch.Function, branch.Identifier, err)
+			}
+			return nil
+		})
+	}
+	err := g.Wait()
+
+	base := state.fork()
+	for _, fork := range forks {
+		state.merge(base, fork)
+	}
+	return err
+}
+
+// fork returns a copy of the run state a parallel branch may change without
+// affecting the others.
+func (s *runState) fork() *runState {
+	res := *s
+	res.nodes = maps.Clone(s.nodes)
+	res.apps = maps.Clone(s.apps)
+	res.nodeHistory = maps.Clone(s.nodeHistory)
+	res.validatorIds = maps.Clone(s.validatorIds)
+	res.delegators = maps.Clone(s.delegators)
+	res.expectedStakes = maps.Clone(s.expectedStakes)
+	res.partitioned = maps.Clone(s.partitioned)
+	res.configs = maps.Clone(s.configs)
+	return &res
+}
+
+// merge applies the changes a fork made to the run state it was forked
+// from, as it was then, to this state.
+func (s *runState) merge(base, fork *runState) {
+	mergeChanges(s.nodes, base.nodes, fork.nodes)
+	mergeChanges(s.apps, base.apps, fork.apps)
+	mergeChanges(s.nodeHistory, base.nodeHistory, fork.nodeHistory)
+	mergeChanges(s.validatorIds, base.validatorIds, fork.validatorIds)
+	mergeChanges(s.delegators, base.delegators, fork.delegators)
+	mergeChanges(s.expectedStakes, base.expectedStakes, fork.expectedStakes)
+	mergeChanges(s.partitioned, base.partitioned, fork.partitioned)
+	mergeChanges(s.configs, base.configs, fork.configs)
+}
+
+// mergeChanges applies the entries added, updated and removed in changed
+// relative to base to dst.
+func mergeChanges[K comparable, V comparable](dst, base, changed map[K]V) {
+	for key, value := range changed {
+		if old, found := base[key]; !found || old != value {
+			dst[key] = value
+		}
+	}
+	for key := range base {
+		if _, found := changed[key]; !found {
+			delete(dst, key)
+		}
+	}
+}
+
+// serializedNetwork is a network on which the transactions the treasury
+// account sends to apply rules, seal epochs and set up applications are
+// sent one at a time.
+type serializedNetwork struct {
+	driver.Network
+	mutex *sync.Mutex
+}
+
+func (n serializedNetwork) ApplyNetworkRules(ctx context.Context, rules driver.NetworkRules) error {
+	n.mutex.Lock()
+	defer n.mutex.Unlock()
+	return n.Network.ApplyNetworkRules(ctx, rules)
+}
+
+func (n serializedNetwork) AdvanceEpoch(ctx context.Context, epochIncrement int) error {
+	n.mutex.Lock()
+	defer n.mutex.Unlock()
+	return n.Network.AdvanceEpoch(ctx, epochIncrement)
+}
+
+func (n serializedNetwork) CreateApplication(ctx context.Context, config *driver.ApplicationConfig) (driver.Application, error) {
+	n.mutex.Lock()
+	defer n.mutex.Unlock()
+	return n.Network.CreateApplication(ctx, config)
+}
+
+// serializedRegistry is a validator registry whose operations run one at a
+// time, as registering a validator relies on the ID of the last one and
+// most operations send transactions from the treasury account.
+type serializedRegistry struct {
+	registry validatorRegistry
+	mutex    *sync.Mutex
+}
+
+// newSerializedRegistry serializes the operations of the given registry with
+// the given lock. A registry sealing epochs through a network is made to
+// seal them through the network serialized with the same lock, so only the
+// sealing holds it while the registry waits for the validator set.
+func newSerializedRegistry(registry validatorRegistry, mutex *sync.Mutex) serializedRegistry {
+	if r, ok := registry.(*netBasedValidatorRegistry); ok {
+		registry = &netBasedValidatorRegistry{net: serializedNetwork{Network: r.net, mutex: mutex}}
+	}
+	return serializedRegistry{registry: registry, mutex: mutex}
+}
+
+func (r serializedRegistry) registerNewValidator(ctx context.Context, stake uint64) (int, error) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	return r.registry.registerNewValidator(ctx, stake)
+}
+
+// ensureValidatorsActive does not hold the lock, as it may wait for blocks
+// and for a sealed epoch to reach the validator set for a while; the epoch
+// is sealed through the serialized network instead.
+func (r serializedRegistry) ensureValidatorsActive(ctx context.Context, validatorIds []int) error {
+	return r.registry.ensureValidatorsActive(ctx, validatorIds)
+}
+
+func (r serializedRegistry) unregisterValidator(ctx context.Context, validatorId int, stake uint64) error {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	return r.registry.unregisterValidator(ctx, validatorId, stake)
+}
+
+func (r serializedRegistry) delegate(ctx context.Context, validatorId int, stake uint64, delegator *ecdsa.PrivateKey) error {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	return r.registry.delegate(ctx, validatorId, stake, delegator)
+}
+
+func (r serializedRegistry) undelegateAs(ctx context.Context, validatorId int, stake uint64, delegator *ecdsa.PrivateKey) error {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	return r.registry.undelegateAs(ctx, validatorId, stake, delegator)
+}
+
+func (r serializedRegistry) fundDelegator(ctx context.Context, delegator common.Address, amount uint64) error {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	return r.registry.fundDelegator(ctx, delegator, amount)
+}
+
+func (r serializedRegistry) getDelegatorStake(delegator common.Address, validatorId int) (uint64, error) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	return r.registry.getDelegatorStake(delegator, validatorId)
+}
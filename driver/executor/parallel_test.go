@@ -0,0 +1,185 @@
+// Copyright 2024 Fantom Foundation
+// This file is part of Norma System Testing Infrastructure for Sonic.
+//
+// Norma is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// Norma is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with Norma. If not, see <http://www.gnu.org/licenses/>.
+
+package executor
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"slices"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/0xsoniclabs/norma/driver"
+	"github.com/0xsoniclabs/norma/driver/parser"
+	"go.uber.org/mock/gomock"
+)
+
+func TestExecuteStep_ParallelMergesTheChangesOfAllBranches(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	net := driver.NewMockNetwork(ctrl)
+	registry := NewMockvalidatorRegistry(ctrl)
+	loadA := driver.NewMockApplication(ctrl)
+	loadB := driver.NewMockApplication(ctrl)
+	loadC := driver.NewMockApplication(ctrl)
+
+	loadA.EXPECT().Stop().Return(nil)
+	loadB.EXPECT().Stop().Return(fmt.Errorf("injected"))
+
+	var events []string
+	state := &runState{
+		apps:           map[string]driver.Application{"loadA": loadA, "loadB": loadB, "loadC": loadC},
+		nodes:          map[string]driver.Node{},
+		nodeHistory:    map[string]bool{},
+		validatorIds:   map[string]int{},
+		delegators:     map[string]*delegatorAccount{},
+		expectedStakes: map[stakeKey]uint64{},
+		partitioned:    map[string]driver.Node{},
+		configs:        map[string]driver.NodeConfig{},
+		onEvent: func(event EventExecution) {
+			events = append(events, event.Name)
+		},
+	}
+	step := &parser.Step{Function: parser.FuncParallel, Branches: []parser.Step{
+		{Function: parser.FuncStopApp, Identifier: "loadA"},
+		{Function: parser.FuncStopApp, Identifier: "loadB"},
+		{Function: parser.FuncWaitFor, Duration: 10 * time.Millisecond},
+	}}
+
+	err := executeStep(t.Context(), step, net, nil, registry, state)
+	if err == nil || !strings.Contains(err.Error(), "branch 2 (stopApp loadB)") {
+		t.Fatalf("expected the failure of the second branch, got %v", err)
+	}
+
+	// The application stopped by the first branch is gone, while the one
+	// that failed to stop is still known, as is the one not acted on.
+	want := map[string]driver.Application{"loadB": loadB, "loadC": loadC}
+	if !reflect.DeepEqual(state.apps, want) {
+		t.Errorf("unexpected applications after parallel step, got %v, want %v", state.apps, want)
+	}
+
+	slices.Sort(events)
+	wantEvents := []string{
+		"parallel branch 1: stopApp loadA",
+		"parallel branch 2: stopApp loadB",
+		"parallel branch 3: waitFor",
+	}
+	if !slices.Equal(events, wantEvents) {
+		t.Errorf("unexpected events, got %v, want %v", events, wantEvents)
+	}
+}
+
+func TestMergeChanges_AppliesAddedUpdatedAndRemovedEntries(t *testing.T) {
+	dst := map[string]int{"kept": 1, "updated": 2, "removed": 3, "other": 4}
+	base := map[string]int{"kept": 1, "updated": 2, "removed": 3}
+	changed := map[string]int{"kept": 1, "updated": 5, "added": 6}
+
+	mergeChanges(dst, base, changed)
+
+	want := map[string]int{"kept": 1, "updated": 5, "added": 6, "other": 4}
+	if !reflect.DeepEqual(dst, want) {
+		t.Errorf("unexpected merge result, got %v, want %v", dst, want)
+	}
+}
+
+func TestRequiresBlockProductionCheck_ParallelStepRequiresItIfAnyBranchDoes(t *testing.T) {
+	waiting := parser.Step{Function: parser.FuncParallel, Branches: []parser.Step{
+		{Function: parser.FuncWaitFor},
+		{Function: parser.FuncAdvanceEpoch},
+	}}
+	if !requiresBlockProductionCheck(waiting) {
+		t.Errorf("parallel step with an advanceEpoch branch should wait for blocks")
+	}
+
+	idle := parser.Step{Function: parser.FuncParallel, Branches: []parser.Step{
+		{Function: parser.FuncWaitFor},
+		{Function: parser.FuncStopNode, Identifier: "A"},
+	}}
+	if requiresBlockProductionCheck(idle) {
+		t.Errorf("parallel step without a branch requiring it should not wait for blocks")
+	}
+}
+
+func TestSerializedNetwork_SendsTreasuryTransactionsOneAtATime(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	inner := driver.NewMockNetwork(ctrl)
+
+	var running, overlaps atomic.Int32
+	enter := func(context.Context, int) error {
+		if running.Add(1) > 1 {
+			overlaps.Add(1)
+		}
+		time.Sleep(5 * time.Millisecond)
+		running.Add(-1)
+		return nil
+	}
+	inner.EXPECT().AdvanceEpoch(gomock.Any(), 1).DoAndReturn(enter).Times(4)
+
+	net := serializedNetwork{Network: inner, mutex: &sync.Mutex{}}
+	var wg sync.WaitGroup
+	for range 4 {
+		wg.Go(func() {
+			if err := net.AdvanceEpoch(t.Context(), 1); err != nil {
+				t.Errorf("unexpected error: %v", err)
+			}
+		})
+	}
+	wg.Wait()
+	if got := overlaps.Load(); got != 0 {
+		t.Errorf("epochs were advanced concurrently %d times", got)
+	}
+}
+
+func TestSerializedRegistry_WaitsForValidatorsWithoutTheLock(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	inner := NewMockvalidatorRegistry(ctrl)
+
+	mutex := &sync.Mutex{}
+	inner.EXPECT().ensureValidatorsActive(gomock.Any(), []int{1}).DoAndReturn(
+		func(context.Context, []int) error {
+			if !mutex.TryLock() {
+				t.Errorf("the lock is held while waiting for the validators")
+				return nil
+			}
+			mutex.Unlock()
+			return nil
+		})
+
+	registry := newSerializedRegistry(inner, mutex)
+	if err := registry.ensureValidatorsActive(t.Context(), []int{1}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}
+
+func TestNewSerializedRegistry_SealsEpochsThroughTheSerializedNetwork(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	net := driver.NewMockNetwork(ctrl)
+
+	mutex := &sync.Mutex{}
+	registry := newSerializedRegistry(&netBasedValidatorRegistry{net: net}, mutex)
+	inner, ok := registry.registry.(*netBasedValidatorRegistry)
+	if !ok {
+		t.Fatalf("unexpected registry %T", registry.registry)
+	}
+	serialized, ok := inner.net.(serializedNetwork)
+	if !ok || serialized.Network != net || serialized.mutex != mutex {
+		t.Errorf("epochs are not sealed through the serialized network, got %#v", inner.net)
+	}
+}
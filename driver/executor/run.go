@@ -22,8 +22,10 @@ import (
 	"errors"
 	"fmt"
 	"log/slog"
+	"slices"
 	"strconv"
 	"strings"
+	"sync"
 	"time"
 
 	"github.com/0xsoniclabs/norma/driver"
@@ -38,7 +40,8 @@ import (
 
 // Run executes a scenario on the given network.
 // Steps are executed one by one in order. The context can be used to abort
-// execution, and a default timeout is enforced as a deadline.
+// execution, and the timeout of the scenario, or a default one if it sets
+// none, is enforced as a deadline.
 func Run(
 	ctx context.Context,
 	network driver.Network,
@@ -53,6 +56,8 @@ func Run(
 		&netBasedValidatorRegistry{net: network},
 		nil,
 		nil,
+		"",
+		"",
 	)
 }
 
@@ -60,13 +65,14 @@ func Run(
 // returns wall-clock start/end intervals for every executed step.
 // genesisValidatorIds maps node labels to their pre-assigned validator IDs
 // (from genesis configuration); these nodes are started without on-chain
-// registration.
+// registration. Snapshot steps save the network into outputDir.
 func RunAndCaptureEventExecution(
 	ctx context.Context,
 	network driver.Network,
 	scenario *parser.Scenario,
 	checks checking.Checks,
 	genesisValidatorIds map[string]int,
+	outputDir string,
 ) ([]EventExecution, error) {
 	executions := make([]EventExecution, 0, len(scenario.Steps))
 	err := runWithObserver(
@@ -79,12 +85,44 @@ func RunAndCaptureEventExecution(
 			executions = append(executions, execution)
 		},
 		genesisValidatorIds,
+		outputDir,
+		"",
+	)
+	return executions, err
+}
+
+// RestoreAndCaptureEventExecution continues a scenario from the snapshot in
+// snapshotDir, taken by one of its snapshot steps, on an empty network. The
+// nodes are restarted on copies of the data directories in the snapshot,
+// and the steps after the snapshot step are executed as by
+// RunAndCaptureEventExecution.
+func RestoreAndCaptureEventExecution(
+	ctx context.Context,
+	network driver.Network,
+	scenario *parser.Scenario,
+	checks checking.Checks,
+	snapshotDir string,
+	outputDir string,
+) ([]EventExecution, error) {
+	executions := make([]EventExecution, 0, len(scenario.Steps))
+	err := runWithObserver(
+		ctx,
+		network,
+		scenario,
+		checks,
+		&netBasedValidatorRegistry{net: network},
+		func(execution EventExecution) {
+			executions = append(executions, execution)
+		},
+		nil,
+		outputDir,
+		snapshotDir,
 	)
 	return executions, err
 }
 
 // defaultScenarioTimeout is the maximum time a scenario is
-// allowed to run before being aborted.
+// allowed to run before being aborted, unless it sets a timeout of its own.
 const defaultScenarioTimeout = 10 * time.Minute
 
 // healDbTimeout caps how long a single `sonictool heal` invocation may
@@ -110,6 +148,8 @@ func run(
 		registry,
 		nil,
 		nil,
+		"",
+		"",
 	)
 }
 
@@ -121,13 +161,44 @@ func runWithObserver(
 	registry validatorRegistry,
 	onStepExecuted func(EventExecution),
 	genesisValidatorIds map[string]int,
+	outputDir string,
+	snapshotDir string,
 ) error {
 	if err := scenario.Check(); err != nil {
 		return err
 	}
 
-	ctx, cancel := context.WithTimeout(ctx, defaultScenarioTimeout)
+	timeout := scenario.Timeout
+	if timeout == 0 {
+		timeout = defaultScenarioTimeout
+	}
+	deadline := time.Now().Add(timeout)
+	ctx, cancel := context.WithDeadline(ctx, deadline)
 	defer cancel()
+	// remaining describes the time left of the timeout when the scenario is
+	// aborted, to tell a scenario running out of time from one cancelled.
+	remaining := func() string {
+		return fmt.Sprintf("%v of its %v timeout left", max(time.Until(deadline), 0).Round(time.Second), timeout)
+	}
+
+	// Steps may run at the same time, in the branches of parallel steps and
+	// in the background of schedule steps. The events they report are
+	// recorded one at a time, as are the transactions the treasury account
+	// sends for them, as concurrent ones would be given the same nonce.
+	treasury := &sync.Mutex{}
+	network = serializedNetwork{Network: network, mutex: treasury}
+	registry = newSerializedRegistry(registry, treasury)
+	if onStepExecuted != nil {
+		record := onStepExecuted
+		events := &sync.Mutex{}
+		onStepExecuted = func(event EventExecution) {
+			events.Lock()
+			defer events.Unlock()
+			record(event)
+		}
+	}
+	scheduled := newBackground(ctx)
+	defer scheduled.stop()
 
 	state := &runState{
 		nodes:          make(map[string]driver.Node),
@@ -136,18 +207,58 @@ func runWithObserver(
 		validatorIds:   make(map[string]int),
 		delegators:     make(map[string]*delegatorAccount),
 		expectedStakes: make(map[stakeKey]uint64),
+		partitioned:    make(map[string]driver.Node),
+		configs:        make(map[string]driver.NodeConfig),
+		scenario:       scenario,
+		outputDir:      outputDir,
+		onEvent:        onStepExecuted,
+		background:     scheduled,
 	}
 	for label, id := range genesisValidatorIds {
 		state.validatorIds[label] = id
 	}
 
+	// A restored network continues with the step after the snapshot step.
+	first := 0
+	if snapshotDir != "" {
+		start := time.Now()
+		taken, err := restoreSnapshot(ctx, network, snapshotDir, state)
+		if err != nil {
+			return fmt.Errorf("failed to restore snapshot %s: %w", snapshotDir, err)
+		}
+		if onStepExecuted != nil {
+			onStepExecuted(EventExecution{
+				Name:  fmt.Sprintf("restore %s", formatStepExecutionName(taken, &scenario.Steps[taken-1])),
+				Start: start,
+				End:   time.Now(),
+			})
+		}
+		if err := waitForBlockProduction(ctx, network); err != nil {
+			return fmt.Errorf("network unstable after restoring snapshot %s: %w", snapshotDir, err)
+		}
+		first = taken
+	}
+
+	// Scheduled steps are awaited before the end checks.
+	join := len(scenario.Steps) - scenario.EndChecks
 	for i, step := range scenario.Steps {
+		if i < first {
+			continue
+		}
 		select {
 		case <-ctx.Done():
-			slog.Warn("scenario aborted", "step", i+1, "reason", ctx.Err())
-			return fmt.Errorf("scenario aborted at step %d (%s): %w", i+1, step.Function, ctx.Err())
+			slog.Warn("scenario aborted", "step", i+1, "reason", ctx.Err(), "remaining", remaining())
+			return fmt.Errorf("scenario aborted at step %d (%s) with %s: %w", i+1, step.Function, remaining(), ctx.Err())
 		default:
 		}
+		if i == join {
+			if err := scheduled.join(state); err != nil {
+				return err
+			}
+		}
+		if err := scheduled.failure(); err != nil {
+			return fmt.Errorf("scenario aborted at step %d (%s): %w", i+1, step.Function, err)
+		}
 
 		slog.Info("executing step",
 			"step", i+1,
@@ -157,15 +268,21 @@ func runWithObserver(
 
 		start := time.Now()
 		err := executeStep(ctx, &step, network, checks, registry, state)
+		observed, err := checkExpectedError(ctx, &step, err)
 		end := time.Now()
 		if onStepExecuted != nil {
 			onStepExecuted(EventExecution{
 				Name:  formatStepExecutionName(i+1, &step),
 				Start: start,
 				End:   end,
+				Error: observed,
 			})
 		}
 
+		if err != nil && ctx.Err() != nil {
+			slog.Warn("scenario aborted", "step", i+1, "reason", ctx.Err(), "remaining", remaining())
+			return fmt.Errorf("scenario aborted during step %d (%s %s) with %s: %w", i+1, step.Function, step.Identifier, remaining(), err)
+		}
 		if err != nil {
 			slog.Error("step failed",
 				"step", i+1,
@@ -194,15 +311,35 @@ func runWithObserver(
 		}
 	}
 
+	if err := scheduled.join(state); err != nil {
+		return err
+	}
+
 	slog.Info("scenario completed successfully")
 	return nil
 }
 
 func formatStepExecutionName(stepNum int, step *parser.Step) string {
+	prefix := fmt.Sprintf("step %d", stepNum)
+	if len(step.Iterations) > 0 {
+		// Repeated steps share their function and often their identifier,
+		// so the iteration tells their timings apart.
+		iterations := make([]string, len(step.Iterations))
+		for i, iteration := range step.Iterations {
+			iterations[i] = strconv.Itoa(iteration)
+		}
+		prefix += fmt.Sprintf(" (iteration %s)", strings.Join(iterations, "."))
+	}
+	return fmt.Sprintf("%s: %s", prefix, describeStep(step))
+}
+
+// describeStep names a step by its function and, if it has one, the
+// identifier it acts on.
+func describeStep(step *parser.Step) string {
 	if step.Identifier == "" {
-		return fmt.Sprintf("step %d: %s", stepNum, step.Function)
+		return string(step.Function)
 	}
-	return fmt.Sprintf("step %d: %s %s", stepNum, step.Function, step.Identifier)
+	return fmt.Sprintf("%s %s", step.Function, step.Identifier)
 }
 
 // runState tracks runtime state during execution.
@@ -225,6 +362,25 @@ type runState struct {
 	// verifyStakes step to compare against on-chain state. Fully
 	// undelegated pairs stay tracked with an expected stake of 0.
 	expectedStakes map[stakeKey]uint64
+	// partitioned holds the nodes cut off from each other by the active
+	// partition step, keyed by name; it is empty when there is none.
+	partitioned map[string]driver.Node
+	// configs holds the configuration every active node was created with,
+	// kept up to date by the steps changing it, for the steps that replace
+	// a node's container.
+	configs map[string]driver.NodeConfig
+	// scenario is the scenario being run, for the steps referring to its
+	// top-level sections.
+	scenario *parser.Scenario
+	// outputDir is the output directory of the run, where snapshot steps
+	// save the network. Snapshots cannot be taken if it is empty.
+	outputDir string
+	// onEvent, if set, records events within a step in the step timings,
+	// as the chaos step does for every disruption.
+	onEvent func(EventExecution)
+	// background runs the steps scheduled by schedule steps; nil if the
+	// state is not the one of a run.
+	background *background
 }
 
 // stakeKey identifies a tracked (delegator, validator) stake pair.
@@ -241,7 +397,7 @@ type delegatorAccount struct {
 	address    common.Address
 }
 
-// executeStep dispatches a single step to the appropriate handler.
+// executeStep executes a single step, within its timeout if it has one.
 func executeStep(
 	ctx context.Context,
 	step *parser.Step,
@@ -249,6 +405,51 @@ func executeStep(
 	checks checking.Checks,
 	registry validatorRegistry,
 	state *runState,
+) error {
+	if step.Timeout <= 0 {
+		return dispatchStep(ctx, step, net, checks, registry, state)
+	}
+	stepCtx, cancel := context.WithTimeout(ctx, step.Timeout)
+	defer cancel()
+	err := dispatchStep(stepCtx, step, net, checks, registry, state)
+	// A step cut short by the end of the scenario is reported as such by
+	// the caller rather than as timed out.
+	if err != nil && ctx.Err() == nil && errors.Is(stepCtx.Err(), context.DeadlineExceeded) {
+		return fmt.Errorf("step timed out after %v: %w", step.Timeout, err)
+	}
+	return err
+}
+
+// checkExpectedError checks the outcome of a step against the error it is
+// expected to fail with, if any. A step failing as expected succeeds, and the
+// error it failed with is returned as observed. Errors of a cancelled context
+// are returned as they are, as no step can expect the scenario to be aborted.
+func checkExpectedError(ctx context.Context, step *parser.Step, err error) (string, error) {
+	if step.ExpectError == nil || ctx.Err() != nil {
+		return "", err
+	}
+	if err == nil {
+		return "", fmt.Errorf("expected %v, but the step succeeded", step.ExpectError)
+	}
+	if !step.ExpectError.Matches(err) {
+		return "", fmt.Errorf("expected %v, but the step failed with: %w", step.ExpectError, err)
+	}
+	slog.Info("step failed as expected",
+		"function", step.Function,
+		"identifier", step.Identifier,
+		"error", err,
+	)
+	return err.Error(), nil
+}
+
+// dispatchStep dispatches a single step to the appropriate handler.
+func dispatchStep(
+	ctx context.Context,
+	step *parser.Step,
+	net driver.Network,
+	checks checking.Checks,
+	registry validatorRegistry,
+	state *runState,
 ) error {
 	switch step.Function {
 	case parser.FuncStartNode:
@@ -259,6 +460,38 @@ func executeStep(
 		return execKillSonic(ctx, step, net, state)
 	case parser.FuncHealDb:
 		return execHealDb(ctx, step, state)
+	case parser.FuncPartition:
+		return execPartition(ctx, step, net, state)
+	case parser.FuncHeal:
+		return execHeal(ctx, net, state)
+	case parser.FuncSetLink:
+		return execSetLink(ctx, step, net, state)
+	case parser.FuncThrottleNode:
+		return execThrottleNode(ctx, step, net, state)
+	case parser.FuncUnthrottleNode:
+		return execUnthrottleNode(ctx, step, net, state)
+	case parser.FuncPauseNode:
+		return execPauseNode(ctx, step, net, state)
+	case parser.FuncResumeNode:
+		return execResumeNode(ctx, step, net, state)
+	case parser.FuncSetClockSkew:
+		return execSetClockSkew(ctx, step, state)
+	case parser.FuncUpdateResources:
+		return execUpdateResources(ctx, step, state)
+	case parser.FuncConnect:
+		return execConnect(ctx, step, net, state)
+	case parser.FuncDisconnect:
+		return execDisconnect(ctx, step, net, state)
+	case parser.FuncChaos:
+		return execChaos(ctx, step, net, state)
+	case parser.FuncUpgradeNode:
+		return execUpgradeNode(ctx, step, net, state)
+	case parser.FuncSnapshot:
+		return execSnapshot(ctx, step, net, state)
+	case parser.FuncParallel:
+		return execParallel(ctx, step, net, checks, registry, state)
+	case parser.FuncSchedule:
+		return execSchedule(step, net, checks, registry, state)
 	case parser.FuncDelegate:
 		return execDelegate(ctx, step, registry, state)
 	case parser.FuncUndelegate:
@@ -471,20 +704,31 @@ func execStartNode(
 		}
 	}
 
+	configs := make([]driver.NodeConfig, instances)
+	for instance := range instances {
+		configs[instance] = driver.NodeConfig{
+			Name:           instanceNames[instance],
+			Failing:        step.Failing,
+			Image:          image,
+			Validator:      isValidator,
+			ValidatorId:    validatorIds[instance],
+			Cheater:        step.Cheater,
+			DataVolume:     dataVolumeOf(state, step, instanceNames[instance]),
+			ExtraArguments: step.ExtraArguments,
+			ClockOffset:    clockOffsetOf(state, step),
+			Resources:      resourcesOf(step),
+			Container:      containerOf(step, instance),
+			Memory:         ClientMemoryOf(step),
+		}
+	}
+
 	// Create nodes in parallel — all on-chain state has been settled above.
 	g, _ := errgroup.WithContext(ctx)
 	for instance := range instances {
 		instance := instance
 		g.Go(func() error {
-			node, err := net.CreateNode(&driver.NodeConfig{
-				Name:           instanceNames[instance],
-				Failing:        step.Failing,
-				Image:          image,
-				Validator:      isValidator,
-				ValidatorId:    validatorIds[instance],
-				DataVolume:     dataVolumePtr(step.DataVolume),
-				ExtraArguments: step.ExtraArguments,
-			})
+			config := configs[instance]
+			node, err := net.CreateNode(&config)
 			if err != nil {
 				return fmt.Errorf(
 					"failed to create node %s: %w",
@@ -503,8 +747,9 @@ func execStartNode(
 	}
 
 	var newNodes []driver.Node
-	for _, r := range results {
+	for instance, r := range results {
 		state.nodes[r.instanceName] = r.node
+		state.configs[r.instanceName] = configs[instance]
 		state.nodeHistory[r.instanceName] = true
 		if r.validatorId != nil {
 			state.validatorIds[r.instanceName] = *r.validatorId
@@ -565,21 +810,19 @@ func waitForNodeSync(ctx context.Context, node driver.Node, targetBlock uint64)
 	deadline := time.Now().Add(syncTimeout)
 
 	for time.Now().Before(deadline) {
-		select {
-		case <-ctx.Done():
-			return ctx.Err()
-		default:
-		}
-
 		block, err := client.BlockNumber(ctx)
+		if err == nil && block >= targetBlock {
+			return nil
+		}
+		period := 200 * time.Millisecond
 		if err != nil {
-			time.Sleep(500 * time.Millisecond)
-			continue
+			period = 500 * time.Millisecond
 		}
-		if block >= targetBlock {
-			return nil
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(period):
 		}
-		time.Sleep(200 * time.Millisecond)
 	}
 	return fmt.Errorf("node did not sync to block %d within %s", targetBlock, syncTimeout)
 }
@@ -592,29 +835,7 @@ func execStopNode(
 	state *runState,
 ) error {
 	base := step.Identifier
-	prefix := base + "-"
-
-	type stopTarget struct {
-		name string
-		node driver.Node
-	}
-	targets := make([]stopTarget, 0)
-	for name, node := range state.nodes {
-		if name == base {
-			targets = append(targets, stopTarget{name: name, node: node})
-		} else if strings.HasPrefix(name, prefix) {
-			// Only match instances with a numeric suffix (e.g. "base-0",
-			// "base-1"), not unrelated nodes that share the prefix
-			// (e.g. "base-extra").
-			suffix := name[len(prefix):]
-			if _, err := strconv.Atoi(suffix); err == nil {
-				targets = append(targets, stopTarget{
-					name: name, node: node,
-				})
-			}
-		}
-	}
-
+	targets := findNodeInstances(state, base)
 	if len(targets) == 0 {
 		return fmt.Errorf("node %q not found in active nodes", base)
 	}
@@ -642,8 +863,93 @@ func execStopNode(
 
 	for _, target := range targets {
 		delete(state.nodes, target.name)
+		delete(state.partitioned, target.name)
+		delete(state.configs, target.name)
+	}
+
+	return nil
+}
+
+// nodeInstance is an active node together with the name it is tracked
+// under in the run state.
+type nodeInstance struct {
+	name string
+	node driver.Node
+}
+
+// findNodeInstances returns the active nodes a step refers to by the given
+// name: the node of that name, and all instances of a node started with
+// several instances, sorted by name.
+func findNodeInstances(state *runState, base string) []nodeInstance {
+	prefix := base + "-"
+	res := make([]nodeInstance, 0)
+	for name, node := range state.nodes {
+		if name == base {
+			res = append(res, nodeInstance{name: name, node: node})
+		} else if strings.HasPrefix(name, prefix) {
+			// Only match instances with a numeric suffix (e.g. "base-0",
+			// "base-1"), not unrelated nodes that share the prefix
+			// (e.g. "base-extra").
+			suffix := name[len(prefix):]
+			if _, err := strconv.Atoi(suffix); err == nil {
+				res = append(res, nodeInstance{name: name, node: node})
+			}
+		}
 	}
+	slices.SortFunc(res, func(a, b nodeInstance) int {
+		return strings.Compare(a.name, b.name)
+	})
+	return res
+}
 
+// execPartition splits the network into the isolated groups of nodes the
+// step lists. The nodes stay in state.nodes and remain reachable for
+// monitoring and checks; only their connections to each other are cut.
+func execPartition(
+	ctx context.Context,
+	step *parser.Step,
+	net driver.Network,
+	state *runState,
+) error {
+	if len(state.partitioned) > 0 {
+		return fmt.Errorf("network is already partitioned")
+	}
+
+	partitioned := make(map[string]driver.Node)
+	groups := make([][]driver.Node, 0, len(step.Groups))
+	for i, names := range step.Groups {
+		group := make([]driver.Node, 0, len(names))
+		for _, name := range names {
+			instances := findNodeInstances(state, name)
+			if len(instances) == 0 {
+				return fmt.Errorf("partition group %d: node %q not found in active nodes", i+1, name)
+			}
+			for _, instance := range instances {
+				group = append(group, instance.node)
+				partitioned[instance.name] = instance.node
+			}
+		}
+		groups = append(groups, group)
+	}
+
+	if err := net.PartitionNodes(ctx, groups); err != nil {
+		return fmt.Errorf("failed to partition network: %w", err)
+	}
+	state.partitioned = partitioned
+	return nil
+}
+
+// execHeal restores the connectivity cut by the preceding partition step.
+// Whether the separated nodes converge again is left to the checks of the
+// scenario; the between-step wait only asserts that blocks are produced.
+func execHeal(ctx context.Context, net driver.Network, state *runState) error {
+	if len(state.partitioned) == 0 {
+		return fmt.Errorf("network is not partitioned")
+	}
+	if err := net.HealPartition(ctx); err != nil {
+		return fmt.Errorf("failed to heal partition: %w", err)
+	}
+	state.partitioned = make(map[string]driver.Node)
 	return nil
 }
 
@@ -910,6 +1216,483 @@ func execHealDb(
 	return opera.HealSonicd(healCtx)
 }
 
+// execSetLink applies the link of a setLink step on top of the links
+// configured so far.
+func execSetLink(
+	ctx context.Context,
+	step *parser.Step,
+	net driver.Network,
+	state *runState,
+) error {
+	if step.Link == nil {
+		return fmt.Errorf("setLink requires a link")
+	}
+	if err := net.SetLink(ctx, toLinkRule(state.scenario, *step.Link)); err != nil {
+		return fmt.Errorf("failed to set link between %v: %w", step.Link.Between, err)
+	}
+	return nil
+}
+
+// NetworkLinks returns the link rules of the Links section of a scenario, to
+// be put in place before its first node starts.
+func NetworkLinks(scenario *parser.Scenario) []driver.LinkRule {
+	rules := make([]driver.LinkRule, 0, len(scenario.Links))
+	for _, link := range scenario.Links {
+		rules = append(rules, toLinkRule(scenario, link))
+	}
+	return rules
+}
+
+// toLinkRule converts a scenario link into a rule, resolving the region
+// names among its ends to the nodes in those regions.
+func toLinkRule(scenario *parser.Scenario, link parser.Link) driver.LinkRule {
+	rule := driver.LinkRule{
+		Link: driver.LinkConfig{
+			Latency: link.Latency,
+			Jitter:  link.Jitter,
+			Loss:    link.Loss,
+			Reorder: link.Reorder,
+		},
+	}
+	if len(link.Between) != 2 {
+		return rule
+	}
+	resolve := func(name string) []string { return []string{name} }
+	if scenario != nil {
+		resolve = scenario.ResolveLinkEnd
+	}
+	rule.A = resolve(link.Between[0])
+	rule.B = resolve(link.Between[1])
+	return rule
+}
+
+// NetworkTopology returns the topology of a scenario, resolving the region
+// names in its adjacency list to the nodes in those regions.
+func NetworkTopology(scenario *parser.Scenario) driver.Topology {
+	topology := driver.Topology{
+		Kind: driver.TopologyKind(scenario.Topology.Kind),
+		Hub:  scenario.Topology.Hub,
+	}
+	if scenario.Topology.Adjacency == nil {
+		return topology
+	}
+	topology.Adjacency = map[string][]string{}
+	for name, neighbors := range scenario.Topology.Adjacency {
+		var resolved []string
+		for _, neighbor := range neighbors {
+			resolved = append(resolved, scenario.ResolveLinkEnd(neighbor)...)
+		}
+		for _, member := range scenario.ResolveLinkEnd(name) {
+			topology.Adjacency[member] = append(topology.Adjacency[member], resolved...)
+		}
+	}
+	return topology
+}
+
+// execConnect peers every instance of the one node of a connect step with
+// every instance of the other.
+func execConnect(
+	ctx context.Context,
+	step *parser.Step,
+	net driver.Network,
+	state *runState,
+) error {
+	return forEachPeerPair(step, state, func(a, b nodeInstance) error {
+		slog.Info("connecting nodes", "node", a.name, "peer", b.name)
+		if err := net.ConnectNodes(ctx, a.node, b.node); err != nil {
+			return fmt.Errorf("failed to connect %s and %s: %w", a.name, b.name, err)
+		}
+		return nil
+	})
+}
+
+// execDisconnect drops the peerings between the instances of the two nodes
+// of a disconnect step.
+func execDisconnect(
+	ctx context.Context,
+	step *parser.Step,
+	net driver.Network,
+	state *runState,
+) error {
+	return forEachPeerPair(step, state, func(a, b nodeInstance) error {
+		slog.Info("disconnecting nodes", "node", a.name, "peer", b.name)
+		if err := net.DisconnectNodes(ctx, a.node, b.node); err != nil {
+			return fmt.Errorf("failed to disconnect %s and %s: %w", a.name, b.name, err)
+		}
+		return nil
+	})
+}
+
+// forEachPeerPair calls apply for every pair of an instance of the first and
+// an instance of the second node of a connect or disconnect step.
+func forEachPeerPair(
+	step *parser.Step,
+	state *runState,
+	apply func(a, b nodeInstance) error,
+) error {
+	if len(step.Peers) != 2 {
+		return fmt.Errorf("%s requires exactly two nodes, got %d", step.Function, len(step.Peers))
+	}
+	ends := make([][]nodeInstance, 0, 2)
+	for _, name := range step.Peers {
+		instances := findNodeInstances(state, name)
+		if len(instances) == 0 {
+			return fmt.Errorf("node %q not found in active nodes", name)
+		}
+		ends = append(ends, instances)
+	}
+	for _, a := range ends[0] {
+		for _, b := range ends[1] {
+			if err := apply(a, b); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// execThrottleNode limits the bandwidth of the node the step names, or of
+// all its instances.
+func execThrottleNode(
+	ctx context.Context,
+	step *parser.Step,
+	net driver.Network,
+	state *runState,
+) error {
+	instances := findNodeInstances(state, step.Identifier)
+	if len(instances) == 0 {
+		return fmt.Errorf("node %q not found in active nodes", step.Identifier)
+	}
+	config := driver.ThrottleConfig{
+		Egress:  uint64(step.Egress),
+		Ingress: uint64(step.Ingress),
+		Burst:   uint64(step.Burst),
+	}
+	for _, instance := range instances {
+		slog.Info("throttling node", "name", instance.name,
+			"egress", step.Egress, "ingress", step.Ingress)
+		if err := net.ThrottleNode(ctx, instance.node, config); err != nil {
+			return fmt.Errorf("failed to throttle node %s: %w", instance.name, err)
+		}
+	}
+	return nil
+}
+
+// execUnthrottleNode lifts the bandwidth limits of the node the step names,
+// or of all its instances.
+func execUnthrottleNode(
+	ctx context.Context,
+	step *parser.Step,
+	net driver.Network,
+	state *runState,
+) error {
+	instances := findNodeInstances(state, step.Identifier)
+	if len(instances) == 0 {
+		return fmt.Errorf("node %q not found in active nodes", step.Identifier)
+	}
+	for _, instance := range instances {
+		slog.Info("unthrottling node", "name", instance.name)
+		if err := net.UnthrottleNode(ctx, instance.node); err != nil {
+			return fmt.Errorf("failed to unthrottle node %s: %w", instance.name, err)
+		}
+	}
+	return nil
+}
+
+// execPauseNode freezes the node the step names, or all its instances. The
+// nodes stay in state.nodes, but monitoring is told to stop polling them
+// until they are resumed, as their endpoints do not answer while frozen.
+func execPauseNode(
+	ctx context.Context,
+	step *parser.Step,
+	net driver.Network,
+	state *runState,
+) error {
+	instances := findNodeInstances(state, step.Identifier)
+	if len(instances) == 0 {
+		return fmt.Errorf("node %q not found in active nodes", step.Identifier)
+	}
+	for _, instance := range instances {
+		opera, ok := instance.node.(*node.OperaNode)
+		if !ok {
+			return fmt.Errorf("node %q is not an OperaNode", instance.name)
+		}
+		slog.Info("pausing node", "name", instance.name)
+		net.SuspendNode(instance.node)
+		if err := opera.PauseSonicd(ctx); err != nil {
+			net.ResumeNode(instance.node)
+			return fmt.Errorf("failed to pause node %s: %w", instance.name, err)
+		}
+	}
+	return nil
+}
+
+// execResumeNode thaws the node the step names, or all its instances, and
+// hands them back to monitoring.
+func execResumeNode(
+	ctx context.Context,
+	step *parser.Step,
+	net driver.Network,
+	state *runState,
+) error {
+	instances := findNodeInstances(state, step.Identifier)
+	if len(instances) == 0 {
+		return fmt.Errorf("node %q not found in active nodes", step.Identifier)
+	}
+	for _, instance := range instances {
+		opera, ok := instance.node.(*node.OperaNode)
+		if !ok {
+			return fmt.Errorf("node %q is not an OperaNode", instance.name)
+		}
+		slog.Info("resuming node", "name", instance.name)
+		if err := opera.ResumeSonicd(ctx); err != nil {
+			return fmt.Errorf("failed to resume node %s: %w", instance.name, err)
+		}
+		net.ResumeNode(instance.node)
+	}
+	return nil
+}
+
+// clockOffsetOf returns the clock offset to start the nodes of a startNode
+// step with. A node's clock can only be offset at runtime if it was started
+// with a controllable clock, so nodes that a later setClockSkew step names
+// start with a zero offset if the step does not give one.
+func clockOffsetOf(state *runState, step *parser.Step) *time.Duration {
+	if step.ClockOffset != nil {
+		offset := *step.ClockOffset
+		return &offset
+	}
+	if namedByStep(state, parser.FuncSetClockSkew, step.Identifier) {
+		return new(time.Duration)
+	}
+	return nil
+}
+
+// namedByStep reports whether a step of the given function anywhere in the
+// scenario names the node of the given name or one of its instances.
+func namedByStep(state *runState, function parser.StepFunction, name string) bool {
+	if state.scenario == nil {
+		return false
+	}
+	for _, step := range state.scenario.Steps {
+		for _, other := range step.Steps() {
+			if other.Function != function {
+				continue
+			}
+			if other.Identifier == name ||
+				strings.HasPrefix(other.Identifier, name+"-") {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// execSetClockSkew changes the clock offset of the node the step names, or
+// of all its instances.
+func execSetClockSkew(
+	ctx context.Context,
+	step *parser.Step,
+	state *runState,
+) error {
+	if step.ClockOffset == nil {
+		return fmt.Errorf("setClockSkew requires an offset")
+	}
+	instances := findNodeInstances(state, step.Identifier)
+	if len(instances) == 0 {
+		return fmt.Errorf("node %q not found in active nodes", step.Identifier)
+	}
+	for _, instance := range instances {
+		opera, ok := instance.node.(*node.OperaNode)
+		if !ok {
+			return fmt.Errorf("node %q is not an OperaNode", instance.name)
+		}
+		slog.Info("setting clock skew", "name", instance.name, "offset", *step.ClockOffset)
+		if err := opera.SetClockOffset(ctx, *step.ClockOffset); err != nil {
+			return fmt.Errorf("failed to set clock skew of node %s: %w", instance.name, err)
+		}
+	}
+	return nil
+}
+
+// resourcesOf returns the resource limits a step gives.
+func resourcesOf(step *parser.Step) driver.Resources {
+	return driver.Resources{
+		CPUs:        step.CPUs,
+		Memory:      uint64(step.Memory),
+		BlkioWeight: step.BlkioWeight,
+	}
+}
+
+// containerOf returns the name of the container the client of the given
+// instance of a startNode step shares with the clients of its neighbouring
+// instances, or the empty string if the step does not pack its clients.
+func containerOf(step *parser.Step, instance int) string {
+	if step.ClientsPerContainer == nil || *step.ClientsPerContainer <= 1 {
+		return ""
+	}
+	return fmt.Sprintf("%s-pack-%d", step.Identifier, instance / *step.ClientsPerContainer)
+}
+
+// ClientMemoryOf returns the client memory settings of a startNode step.
+func ClientMemoryOf(step *parser.Step) driver.ClientMemory {
+	return driver.ClientMemory{
+		LiveCache:  uint64(step.LiveCache),
+		Cache:      uint64(step.Cache),
+		GoMemLimit: uint64(step.GoMemLimit),
+	}
+}
+
+// execUpdateResources changes the resource limits of the node the step
+// names, or of all its instances. Limits the step does not give are kept.
+func execUpdateResources(
+	ctx context.Context,
+	step *parser.Step,
+	state *runState,
+) error {
+	instances := findNodeInstances(state, step.Identifier)
+	if len(instances) == 0 {
+		return fmt.Errorf("node %q not found in active nodes", step.Identifier)
+	}
+	resources := resourcesOf(step)
+	for _, instance := range instances {
+		opera, ok := instance.node.(*node.OperaNode)
+		if !ok {
+			return fmt.Errorf("node %q is not an OperaNode", instance.name)
+		}
+		slog.Info("updating resources", "name", instance.name,
+			"cpus", resources.CPUs, "memory", resources.Memory, "blkioWeight", resources.BlkioWeight)
+		if err := opera.UpdateResources(ctx, resources); err != nil {
+			return fmt.Errorf("failed to update resources of node %s: %w", instance.name, err)
+		}
+		if config, ok := state.configs[instance.name]; ok {
+			config.Resources = mergeResources(config.Resources, resources)
+			state.configs[instance.name] = config
+		}
+	}
+	return nil
+}
+
+// mergeResources returns the limits of current with the ones update gives
+// replaced, as updating the resources of a node does.
+func mergeResources(current, update driver.Resources) driver.Resources {
+	if update.CPUs != 0 {
+		current.CPUs = update.CPUs
+	}
+	if update.Memory != 0 {
+		current.Memory = update.Memory
+	}
+	if update.BlkioWeight != 0 {
+		current.BlkioWeight = update.BlkioWeight
+	}
+	return current
+}
+
+// execUpgradeNode replaces the client of the node the step names, or of all
+// its instances one after the other, with the client of the step's image.
+func execUpgradeNode(
+	ctx context.Context,
+	step *parser.Step,
+	net driver.Network,
+	state *runState,
+) error {
+	if step.ImageName == "" {
+		return fmt.Errorf("upgradeNode requires an imageName")
+	}
+	instances := findNodeInstances(state, step.Identifier)
+	if len(instances) == 0 {
+		return fmt.Errorf("node %q not found in active nodes", step.Identifier)
+	}
+	for i, instance := range instances {
+		if i > 0 && step.Interval > 0 {
+			slog.Info("waiting before next upgrade", "duration", step.Interval)
+			select {
+			case <-time.After(step.Interval):
+			case <-ctx.Done():
+				return fmt.Errorf("context cancelled during upgradeNode: %w", ctx.Err())
+			}
+		}
+		if err := upgradeNodeInstance(ctx, instance, step.ImageName, net, state); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// upgradeNodeInstance stops the client of a node gracefully and starts the
+// client of the given image in a new container on the same data directory,
+// with the same validator ID. It returns once the new client has caught up
+// with the network.
+func upgradeNodeInstance(
+	ctx context.Context,
+	instance nodeInstance,
+	image string,
+	net driver.Network,
+	state *runState,
+) error {
+	name := instance.name
+	opera, ok := instance.node.(*node.OperaNode)
+	if !ok {
+		return fmt.Errorf("node %q is not an OperaNode", name)
+	}
+	config, ok := state.configs[name]
+	if !ok {
+		return fmt.Errorf("node %q has no known configuration to upgrade", name)
+	}
+	if config.DataVolume == nil {
+		return fmt.Errorf("node %q keeps no data directory on the host to upgrade", name)
+	}
+
+	targetBlock, err := getNetworkBlockHeight(ctx, net)
+	if err != nil {
+		if !errors.Is(err, driver.ErrEmptyNetwork) {
+			return fmt.Errorf(
+				"failed to determine sync target for upgrade of %s: %w", name, err)
+		}
+		targetBlock = 0
+	}
+	slog.Info("upgrading node", "node", name, "from", config.Image, "to", image,
+		"target_block", targetBlock)
+
+	// The client is stopped before the container goes, so the database the
+	// new client opens was closed cleanly.
+	if err := net.RemoveNode(instance.node); err != nil {
+		return fmt.Errorf("failed to remove node %s: %w", name, err)
+	}
+	delete(state.nodes, name)
+	if err := opera.StopSonicd(ctx); err != nil {
+		return fmt.Errorf("failed to stop sonicd on %s: %w", name, err)
+	}
+	if err := opera.Stop(ctx); err != nil {
+		return fmt.Errorf("failed to stop node %s: %w", name, err)
+	}
+	if err := opera.Cleanup(ctx); err != nil {
+		return fmt.Errorf("failed to cleanup node %s: %w", name, err)
+	}
+
+	// The replacement keeps the clock the node runs on, and does not start
+	// another twin for a cheating validator, as the first one still runs.
+	config.Image = image
+	config.Cheater = false
+	if config.ClockOffset != nil {
+		offset := opera.ClockOffset()
+		config.ClockOffset = &offset
+	}
+	upgraded, err := net.CreateNode(&config)
+	if err != nil {
+		return fmt.Errorf("failed to create upgraded node %s: %w", name, err)
+	}
+	state.nodes[name] = upgraded
+	state.configs[name] = config
+
+	if err := waitForNodeSync(ctx, upgraded, targetBlock+1); err != nil {
+		return fmt.Errorf("upgraded node %s did not reach block %d: %w",
+			name, targetBlock+1, err)
+	}
+	slog.Info("node upgraded", "node", name, "image", image)
+	return nil
+}
+
 // execStopApp stops a running application.
 func execStopApp(step *parser.Step, state *runState) error {
 	app, ok := state.apps[step.Identifier]
@@ -940,11 +1723,13 @@ var checkFunctionToCheckerName = map[parser.StepFunction]string{
 	parser.FuncCheckBlockGasRate:     "blockGasRate",
 	parser.FuncCheckBlockHashes:      "blocksHashes",
 	parser.FuncCheckBlockHeights:     "blockHeight",
+	parser.FuncCheckBlockTimestamps:  "blockTimestamps",
 	parser.FuncCheckBlocksHalted:     "blocksHalted",
 	parser.FuncCheckBlocksProduced:   "blocksRolling",
 	parser.FuncCheckEventThrottled:   "eventThrottled",
 	parser.FuncCheckNetworkRules:     "networkRules",
 	parser.FuncCheckValidatorsActive: "validatorsActive",
+	parser.FuncCheckValidatorSlashed: "validatorSlashed",
 }
 
 // execCheck runs a named checker with configuration from the check spec.
@@ -977,6 +1762,9 @@ func execCheck(ctx context.Context, checkerName string, spec *parser.CheckSpec,
 		// production progressed during the observation window.
 		config["duration"] = int64(*spec.Duration)
 	}
+	if spec.Bound != nil {
+		config["bound"] = int64(*spec.Bound)
+	}
 	if spec.Ceiling != nil {
 		config["ceiling"] = int(*spec.Ceiling)
 	}
@@ -986,6 +1774,9 @@ func execCheck(ctx context.Context, checkerName string, spec *parser.CheckSpec,
 	if len(spec.ThrottledNodes) > 0 {
 		config["throttledNodes"] = spec.ThrottledNodes
 	}
+	if len(spec.SlashedNodes) > 0 {
+		config["slashedNodes"] = spec.SlashedNodes
+	}
 
 	if len(config) > 0 {
 		checker = checking.NewFailingChecker(checker).Configure(config)
@@ -994,6 +1785,23 @@ func execCheck(ctx context.Context, checkerName string, spec *parser.CheckSpec,
 	return checker.Check(ctx)
 }
 
+// dataVolumeOf returns the data volume to start an instance of a startNode
+// step on. An upgrade replaces the container of a node but has to keep its
+// database, and a snapshot copies the databases of all nodes, so nodes that
+// an upgradeNode step names anywhere in the scenario, and all nodes of a
+// scenario taking snapshots, keep their data directory on the host, in a
+// volume named after the instance, if the step does not give one.
+func dataVolumeOf(state *runState, step *parser.Step, instanceName string) *string {
+	if step.DataVolume != "" {
+		return dataVolumePtr(step.DataVolume)
+	}
+	if namedByStep(state, parser.FuncUpgradeNode, step.Identifier) ||
+		takesSnapshots(state) {
+		return dataVolumePtr(instanceName + "-datadir")
+	}
+	return nil
+}
+
 // dataVolumePtr returns a *string for a non-empty DataVolume, nil otherwise.
 func dataVolumePtr(s string) *string {
 	if s == "" {
@@ -1005,13 +1813,23 @@ func dataVolumePtr(s string) *string {
 // requiresBlockProductionCheck returns true for steps after which we should
 // verify the network is still producing blocks.
 func requiresBlockProductionCheck(step parser.Step) bool {
+	// A step failing as expected may have left its work half done.
+	if step.ExpectError != nil {
+		return false
+	}
 	switch step.Function {
 	case parser.FuncStartNode:
 		return !step.Failing
-	case parser.FuncRunApp, parser.FuncUpdateRules, parser.FuncAdvanceEpoch:
+	case parser.FuncParallel:
+		return slices.ContainsFunc(step.Branches, requiresBlockProductionCheck)
+	case parser.FuncRunApp, parser.FuncUpdateRules, parser.FuncAdvanceEpoch,
+		parser.FuncHeal, parser.FuncUnthrottleNode, parser.FuncResumeNode,
+		parser.FuncChaos, parser.FuncUpgradeNode, parser.FuncSnapshot:
 		return true
 	default:
-		// stopNode, undelegate, waitFor, waitForEpoch, stopApp, checks — skip
+		// stopNode, undelegate, waitFor, waitForEpoch, stopApp, checks,
+		// partition, setLink, throttleNode, pauseNode, setClockSkew,
+		// updateResources, connect, disconnect — skip
 		return false
 	}
 }
@@ -1034,11 +1852,6 @@ func waitForBlockProduction(ctx context.Context, net driver.Network) error {
 	}
 
 	for {
-		select {
-		case <-ctx.Done():
-			return fmt.Errorf("context cancelled while waiting for block production: %w", ctx.Err())
-		default:
-		}
 		block, err := client.BlockNumber(ctx)
 		if err != nil {
 			return fmt.Errorf("failed to get block number: %w", err)
@@ -1046,7 +1859,11 @@ func waitForBlockProduction(ctx context.Context, net driver.Network) error {
 		if block > baseline {
 			return nil
 		}
-		time.Sleep(100 * time.Millisecond)
+		select {
+		case <-ctx.Done():
+			return fmt.Errorf("context cancelled while waiting for block production: %w", ctx.Err())
+		case <-time.After(100 * time.Millisecond):
+		}
 	}
 }
 
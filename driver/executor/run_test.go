@@ -19,8 +19,10 @@ package executor
 import (
 	"context"
 	"crypto/ecdsa"
+	"errors"
 	"fmt"
 	"math/big"
+	"reflect"
 	"slices"
 	"strings"
 	"testing"
@@ -29,6 +31,7 @@ import (
 	"github.com/0xsoniclabs/norma/driver"
 	"github.com/0xsoniclabs/norma/driver/checking"
 	"github.com/0xsoniclabs/norma/driver/parser"
+	"github.com/0xsoniclabs/norma/driver/rpc"
 	"github.com/0xsoniclabs/norma/genesis"
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/crypto"
@@ -468,6 +471,88 @@ func TestRun_AdvanceEpoch(t *testing.T) {
 	}
 }
 
+func TestRun_StepFailingAsExpectedRecordsItsError(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	net := driver.NewMockNetwork(ctrl)
+
+	// No block production wait follows, as the step failed.
+	net.EXPECT().AdvanceEpoch(gomock.Any(), 1).Return(fmt.Errorf("epoch sealing rejected"))
+
+	scenario := parser.Scenario{
+		Name:        "Rejected",
+		Description: "Test scenario.",
+		Steps: []parser.Step{
+			{Function: parser.FuncAdvanceEpoch, ExpectError: &parser.ErrorExpectation{Text: "rejected"}},
+		},
+	}
+
+	var events []EventExecution
+	err := runWithObserver(t.Context(), net, &scenario, nil, nil,
+		func(event EventExecution) { events = append(events, event) }, nil, "", "")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(events) != 1 || events[0].Error != "epoch sealing rejected" {
+		t.Errorf("expected the observed error to be recorded, got %v", events)
+	}
+}
+
+func TestCheckExpectedError_InvertsTheOutcomeOfSteps(t *testing.T) {
+	injected := fmt.Errorf("injected")
+	cases := map[string]struct {
+		expectation *parser.ErrorExpectation
+		err         error
+		observed    string
+		errSubstr   string
+	}{
+		"no expectation, success": {},
+		"no expectation, failure": {
+			err:       injected,
+			errSubstr: "injected",
+		},
+		"expected error": {
+			expectation: &parser.ErrorExpectation{Text: "inject"},
+			err:         injected,
+			observed:    "injected",
+		},
+		"other error": {
+			expectation: &parser.ErrorExpectation{Text: "rejected"},
+			err:         injected,
+			errSubstr:   `expected an error containing "rejected", but the step failed with: injected`,
+		},
+		"success": {
+			expectation: &parser.ErrorExpectation{},
+			errSubstr:   "expected an error, but the step succeeded",
+		},
+	}
+	for name, c := range cases {
+		t.Run(name, func(t *testing.T) {
+			step := &parser.Step{Function: parser.FuncAdvanceEpoch, ExpectError: c.expectation}
+			observed, err := checkExpectedError(t.Context(), step, c.err)
+			if observed != c.observed {
+				t.Errorf("unexpected observed error, got %q, want %q", observed, c.observed)
+			}
+			if c.errSubstr == "" && err != nil {
+				t.Errorf("unexpected error: %v", err)
+			}
+			if c.errSubstr != "" && (err == nil || !strings.Contains(err.Error(), c.errSubstr)) {
+				t.Errorf("expected error to contain %q, got %v", c.errSubstr, err)
+			}
+		})
+	}
+}
+
+func TestCheckExpectedError_DoesNotExpectTheScenarioToBeAborted(t *testing.T) {
+	ctx, cancel := context.WithCancel(t.Context())
+	cancel()
+
+	step := &parser.Step{Function: parser.FuncWaitFor, ExpectError: &parser.ErrorExpectation{}}
+	observed, err := checkExpectedError(ctx, step, context.Canceled)
+	if observed != "" || !errors.Is(err, context.Canceled) {
+		t.Errorf("expected the abort to be reported, got %q and %v", observed, err)
+	}
+}
+
 func TestRun_Check(t *testing.T) {
 	ctrl := gomock.NewController(t)
 	net := driver.NewMockNetwork(ctrl)
@@ -517,6 +602,48 @@ func TestRun_ContextCancellation(t *testing.T) {
 	}
 }
 
+func TestRun_ScenarioTimeoutAbortsTheRun(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	net := driver.NewMockNetwork(ctrl)
+
+	scenario := parser.Scenario{
+		Name:        "Short",
+		Description: "Test scenario.",
+		Timeout:     20 * time.Millisecond,
+		Steps: []parser.Step{
+			{Function: parser.FuncWaitFor, Duration: time.Minute},
+		},
+	}
+
+	err := run(t.Context(), net, &scenario, nil, nil)
+	if err == nil {
+		t.Fatal("expected the scenario to time out")
+	}
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("expected a deadline error, got %v", err)
+	}
+	want := "scenario aborted during step 1 (waitFor ) with 0s of its 20ms timeout left"
+	if !strings.Contains(err.Error(), want) {
+		t.Errorf("expected error to contain %q, got %v", want, err)
+	}
+}
+
+func TestExecuteStep_StepTimeoutFailsTheStep(t *testing.T) {
+	step := &parser.Step{
+		Function: parser.FuncWaitFor,
+		Duration: time.Minute,
+		Timeout:  20 * time.Millisecond,
+	}
+
+	err := executeStep(t.Context(), step, nil, nil, nil, &runState{})
+	if err == nil {
+		t.Fatal("expected the step to time out")
+	}
+	if want := "step timed out after 20ms"; !strings.Contains(err.Error(), want) {
+		t.Errorf("expected error to contain %q, got %v", want, err)
+	}
+}
+
 func TestRun_MultiInstanceNode(t *testing.T) {
 	ctrl := gomock.NewController(t)
 	net := driver.NewMockNetwork(ctrl)
@@ -676,6 +803,586 @@ func TestExecStopNode_IgnoresNonNumericSuffix(t *testing.T) {
 	}
 }
 
+func TestExecuteStep_PartitionAndHeal(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	net := driver.NewMockNetwork(ctrl)
+	a := driver.NewMockNode(ctrl)
+	b0 := driver.NewMockNode(ctrl)
+	b1 := driver.NewMockNode(ctrl)
+	// DialRandomRpc returns error so waitForBlockProduction is skipped.
+	net.EXPECT().DialRandomRpc().Return(nil, fmt.Errorf("no nodes")).AnyTimes()
+
+	gomock.InOrder(
+		net.EXPECT().PartitionNodes(gomock.Any(), [][]driver.Node{{a}, {b0, b1}}).Return(nil),
+		net.EXPECT().HealPartition(gomock.Any()).Return(nil),
+	)
+
+	scenario := parser.Scenario{
+		Name:        "Partition",
+		Description: "Test scenario.",
+		Steps: []parser.Step{
+			{Function: parser.FuncPartition, Groups: [][]string{{"a"}, {"b"}}},
+			{Function: parser.FuncHeal},
+		},
+	}
+
+	state := &runState{
+		nodes: map[string]driver.Node{
+			"a":   a,
+			"b-1": b1,
+			"b-0": b0,
+		},
+		partitioned: map[string]driver.Node{},
+	}
+	for _, step := range scenario.Steps {
+		if err := executeStep(t.Context(), &step, net, nil, nil, state); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+	if len(state.partitioned) != 0 {
+		t.Fatalf("expected no partitioned nodes after heal, got %v", state.partitioned)
+	}
+}
+
+func TestExecPartition_UnknownNodeIsReported(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	net := driver.NewMockNetwork(ctrl)
+	a := driver.NewMockNode(ctrl)
+
+	state := &runState{
+		nodes:       map[string]driver.Node{"a": a},
+		partitioned: map[string]driver.Node{},
+	}
+	step := &parser.Step{
+		Function: parser.FuncPartition,
+		Groups:   [][]string{{"a"}, {"missing"}},
+	}
+
+	err := execPartition(t.Context(), step, net, state)
+	if err == nil || !strings.Contains(err.Error(), `node "missing" not found`) {
+		t.Fatalf("expected unknown node error, got %v", err)
+	}
+	if len(state.partitioned) != 0 {
+		t.Fatalf("expected no partitioned nodes, got %v", state.partitioned)
+	}
+}
+
+func TestExecPartition_FailureLeavesNetworkUnpartitioned(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	net := driver.NewMockNetwork(ctrl)
+	a := driver.NewMockNode(ctrl)
+	b := driver.NewMockNode(ctrl)
+
+	net.EXPECT().PartitionNodes(gomock.Any(), gomock.Any()).Return(fmt.Errorf("injected"))
+
+	state := &runState{
+		nodes:       map[string]driver.Node{"a": a, "b": b},
+		partitioned: map[string]driver.Node{},
+	}
+	step := &parser.Step{
+		Function: parser.FuncPartition,
+		Groups:   [][]string{{"a"}, {"b"}},
+	}
+
+	if err := execPartition(t.Context(), step, net, state); err == nil {
+		t.Fatalf("expected partition failure to be reported")
+	}
+	if len(state.partitioned) != 0 {
+		t.Fatalf("expected no partitioned nodes, got %v", state.partitioned)
+	}
+}
+
+func TestExecStopNode_ForgetsPartitionedNode(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	net := driver.NewMockNetwork(ctrl)
+	node := driver.NewMockNode(ctrl)
+
+	state := &runState{
+		nodes:       map[string]driver.Node{"a": node},
+		partitioned: map[string]driver.Node{"a": node},
+	}
+
+	net.EXPECT().RemoveNode(node).Return(nil)
+	node.EXPECT().Stop(gomock.Any()).Return(nil)
+	node.EXPECT().Cleanup(gomock.Any()).Return(nil)
+
+	step := &parser.Step{Function: parser.FuncStopNode, Identifier: "a"}
+	if err := execStopNode(t.Context(), step, net, state); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if _, ok := state.partitioned["a"]; ok {
+		t.Fatalf("expected stopped node to be removed from the partition")
+	}
+}
+
+func TestExecuteStep_SetLinkResolvesRegions(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	net := driver.NewMockNetwork(ctrl)
+
+	scenario := &parser.Scenario{
+		Regions: map[string][]string{"eu": {"val-eu-1", "val-eu-2"}},
+	}
+	step := &parser.Step{
+		Function: parser.FuncSetLink,
+		Link: &parser.Link{
+			Between: []string{"eu", "rpc"},
+			Latency: 80 * time.Millisecond,
+			Loss:    1,
+		},
+	}
+	net.EXPECT().SetLink(gomock.Any(), driver.LinkRule{
+		A:    []string{"val-eu-1", "val-eu-2"},
+		B:    []string{"rpc"},
+		Link: driver.LinkConfig{Latency: 80 * time.Millisecond, Loss: 1},
+	}).Return(nil)
+
+	state := &runState{scenario: scenario}
+	if err := executeStep(t.Context(), step, net, nil, nil, state); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}
+
+func TestExecSetLink_ReportsNetworkError(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	net := driver.NewMockNetwork(ctrl)
+	net.EXPECT().SetLink(gomock.Any(), gomock.Any()).Return(fmt.Errorf("injected"))
+
+	step := &parser.Step{
+		Function: parser.FuncSetLink,
+		Link:     &parser.Link{Between: []string{"a", "b"}},
+	}
+	err := execSetLink(t.Context(), step, net, &runState{})
+	if err == nil || !strings.Contains(err.Error(), "injected") {
+		t.Fatalf("expected network error, got %v", err)
+	}
+}
+
+func TestNetworkLinks_ConvertsScenarioLinksInOrder(t *testing.T) {
+	scenario := &parser.Scenario{
+		Regions: map[string][]string{"us": {"val-us"}},
+		Links: []parser.Link{
+			{Between: []string{"us", "a"}, Latency: 50 * time.Millisecond},
+			{Between: []string{"a", "b"}, Jitter: time.Millisecond},
+		},
+	}
+	got := NetworkLinks(scenario)
+	want := []driver.LinkRule{
+		{A: []string{"val-us"}, B: []string{"a"}, Link: driver.LinkConfig{Latency: 50 * time.Millisecond}},
+		{A: []string{"a"}, B: []string{"b"}, Link: driver.LinkConfig{Jitter: time.Millisecond}},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("unexpected rules\ngot:  %v\nwant: %v", got, want)
+	}
+}
+
+func TestNetworkTopology_ResolvesRegionsInAdjacencyList(t *testing.T) {
+	scenario := &parser.Scenario{
+		Regions: map[string][]string{"eu": {"val-eu-1", "val-eu-2"}},
+		Topology: parser.Topology{
+			Kind: parser.TopologyCustom,
+			Adjacency: map[string][]string{
+				"eu":  {"relay"},
+				"obs": {"eu", "rpc"},
+			},
+		},
+	}
+	got := NetworkTopology(scenario)
+	want := driver.Topology{
+		Kind: driver.TopologyCustom,
+		Adjacency: map[string][]string{
+			"val-eu-1": {"relay"},
+			"val-eu-2": {"relay"},
+			"obs":      {"val-eu-1", "val-eu-2", "rpc"},
+		},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("unexpected topology\ngot:  %v\nwant: %v", got, want)
+	}
+
+	star := NetworkTopology(&parser.Scenario{
+		Topology: parser.Topology{Kind: parser.TopologyStar, Hub: "relay"},
+	})
+	if want := (driver.Topology{Kind: driver.TopologyStar, Hub: "relay"}); !reflect.DeepEqual(star, want) {
+		t.Fatalf("unexpected topology\ngot:  %v\nwant: %v", star, want)
+	}
+}
+
+func TestExecuteStep_ConnectAndDisconnectAllInstances(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	net := driver.NewMockNetwork(ctrl)
+	v0 := driver.NewMockNode(ctrl)
+	v1 := driver.NewMockNode(ctrl)
+	obs := driver.NewMockNode(ctrl)
+
+	gomock.InOrder(
+		net.EXPECT().ConnectNodes(gomock.Any(), obs, v0).Return(nil),
+		net.EXPECT().ConnectNodes(gomock.Any(), obs, v1).Return(nil),
+		net.EXPECT().DisconnectNodes(gomock.Any(), v0, obs).Return(nil),
+		net.EXPECT().DisconnectNodes(gomock.Any(), v1, obs).Return(nil),
+	)
+
+	steps := []parser.Step{
+		{Function: parser.FuncConnect, Peers: []string{"obs", "val"}},
+		{Function: parser.FuncDisconnect, Peers: []string{"val", "obs"}},
+	}
+	state := &runState{
+		nodes: map[string]driver.Node{
+			"val-1": v1,
+			"val-0": v0,
+			"obs":   obs,
+		},
+	}
+	for _, step := range steps {
+		if err := executeStep(t.Context(), &step, net, nil, nil, state); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+}
+
+func TestExecConnect_ReportsUnknownNodeAndNetworkError(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	net := driver.NewMockNetwork(ctrl)
+	a := driver.NewMockNode(ctrl)
+	b := driver.NewMockNode(ctrl)
+	state := &runState{nodes: map[string]driver.Node{"a": a, "b": b}}
+
+	step := &parser.Step{Function: parser.FuncConnect, Peers: []string{"a", "missing"}}
+	err := execConnect(t.Context(), step, net, state)
+	if err == nil || !strings.Contains(err.Error(), `node "missing" not found`) {
+		t.Fatalf("expected unknown node error, got %v", err)
+	}
+
+	net.EXPECT().DisconnectNodes(gomock.Any(), a, b).Return(fmt.Errorf("injected"))
+	step = &parser.Step{Function: parser.FuncDisconnect, Peers: []string{"a", "b"}}
+	err = execDisconnect(t.Context(), step, net, state)
+	if err == nil || !strings.Contains(err.Error(), "injected") {
+		t.Fatalf("expected network error, got %v", err)
+	}
+}
+
+func TestExecuteStep_ThrottleAndUnthrottleAllInstances(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	net := driver.NewMockNetwork(ctrl)
+	v0 := driver.NewMockNode(ctrl)
+	v1 := driver.NewMockNode(ctrl)
+	other := driver.NewMockNode(ctrl)
+	// DialRandomRpc returns error so waitForBlockProduction is skipped.
+	net.EXPECT().DialRandomRpc().Return(nil, fmt.Errorf("no nodes")).AnyTimes()
+
+	config := driver.ThrottleConfig{Egress: 10_000_000, Burst: 32 * 1024}
+	gomock.InOrder(
+		net.EXPECT().ThrottleNode(gomock.Any(), v0, config).Return(nil),
+		net.EXPECT().ThrottleNode(gomock.Any(), v1, config).Return(nil),
+		net.EXPECT().UnthrottleNode(gomock.Any(), v0).Return(nil),
+		net.EXPECT().UnthrottleNode(gomock.Any(), v1).Return(nil),
+	)
+
+	steps := []parser.Step{
+		{Function: parser.FuncThrottleNode, Identifier: "val", Egress: 10_000_000, Burst: 32 * 1024},
+		{Function: parser.FuncUnthrottleNode, Identifier: "val"},
+	}
+	state := &runState{
+		nodes: map[string]driver.Node{
+			"val-1":     v1,
+			"val-0":     v0,
+			"validator": other,
+		},
+	}
+	for _, step := range steps {
+		if err := executeStep(t.Context(), &step, net, nil, nil, state); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+}
+
+func TestExecThrottleNode_UnknownNodeIsReported(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	net := driver.NewMockNetwork(ctrl)
+
+	step := &parser.Step{Function: parser.FuncThrottleNode, Identifier: "missing", Egress: 1000}
+	err := execThrottleNode(t.Context(), step, net, &runState{nodes: map[string]driver.Node{}})
+	if err == nil || !strings.Contains(err.Error(), `node "missing" not found`) {
+		t.Fatalf("expected unknown node error, got %v", err)
+	}
+}
+
+func TestExecPauseAndResumeNode_UnknownNodeIsReported(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	net := driver.NewMockNetwork(ctrl)
+	state := &runState{nodes: map[string]driver.Node{}}
+
+	pause := &parser.Step{Function: parser.FuncPauseNode, Identifier: "missing"}
+	if err := execPauseNode(t.Context(), pause, net, state); err == nil || !strings.Contains(err.Error(), `node "missing" not found`) {
+		t.Fatalf("expected unknown node error, got %v", err)
+	}
+	resume := &parser.Step{Function: parser.FuncResumeNode, Identifier: "missing"}
+	if err := execResumeNode(t.Context(), resume, net, state); err == nil || !strings.Contains(err.Error(), `node "missing" not found`) {
+		t.Fatalf("expected unknown node error, got %v", err)
+	}
+}
+
+// Only nodes hosted in a container can be frozen; others must be rejected
+// before monitoring is told the node is gone.
+func TestExecPauseNode_RejectsNodesWithoutContainer(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	net := driver.NewMockNetwork(ctrl)
+	state := &runState{nodes: map[string]driver.Node{"val": driver.NewMockNode(ctrl)}}
+
+	step := &parser.Step{Function: parser.FuncPauseNode, Identifier: "val"}
+	if err := execPauseNode(t.Context(), step, net, state); err == nil || !strings.Contains(err.Error(), "not an OperaNode") {
+		t.Fatalf("expected non-OperaNode error, got %v", err)
+	}
+}
+
+func TestClockOffsetOf_EnablesClockControlForSkewedNodes(t *testing.T) {
+	offset := 2 * time.Second
+	scenario := &parser.Scenario{Steps: []parser.Step{
+		{Function: parser.FuncSetClockSkew, Identifier: "val-1", ClockOffset: &offset},
+		{Function: parser.FuncSetClockSkew, Identifier: "group", ClockOffset: &offset},
+	}}
+	state := &runState{scenario: scenario}
+
+	tests := map[string]struct {
+		step parser.Step
+		want *time.Duration
+	}{
+		"explicit offset": {
+			step: parser.Step{Identifier: "rpc", ClockOffset: &offset},
+			want: &offset,
+		},
+		"skewed later":          {step: parser.Step{Identifier: "val-1"}, want: new(time.Duration)},
+		"instance skewed later": {step: parser.Step{Identifier: "val"}, want: new(time.Duration)},
+		"never skewed":          {step: parser.Step{Identifier: "rpc"}, want: nil},
+	}
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			got := clockOffsetOf(state, &test.step)
+			if (got == nil) != (test.want == nil) || (got != nil && *got != *test.want) {
+				t.Errorf("unexpected clock offset, got %v, want %v", got, test.want)
+			}
+		})
+	}
+}
+
+func TestExecSetClockSkew_RejectsUnknownAndUncontrollableNodes(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	offset := time.Second
+
+	step := &parser.Step{Function: parser.FuncSetClockSkew, Identifier: "missing", ClockOffset: &offset}
+	err := execSetClockSkew(t.Context(), step, &runState{nodes: map[string]driver.Node{}})
+	if err == nil || !strings.Contains(err.Error(), `node "missing" not found`) {
+		t.Fatalf("expected unknown node error, got %v", err)
+	}
+
+	state := &runState{nodes: map[string]driver.Node{"val": driver.NewMockNode(ctrl)}}
+	step = &parser.Step{Function: parser.FuncSetClockSkew, Identifier: "val", ClockOffset: &offset}
+	err = execSetClockSkew(t.Context(), step, state)
+	if err == nil || !strings.Contains(err.Error(), "not an OperaNode") {
+		t.Fatalf("expected non-OperaNode error, got %v", err)
+	}
+}
+
+func TestRun_StartNode_PassesResourceLimits(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	net := driver.NewMockNetwork(ctrl)
+	node := driver.NewMockNode(ctrl)
+
+	net.EXPECT().DialRandomRpc().Return(nil, fmt.Errorf("no nodes")).AnyTimes()
+	node.EXPECT().GetLabel().Return("rpc").AnyTimes()
+	node.EXPECT().DialRpc(gomock.Any()).Return(nil, fmt.Errorf("not ready")).AnyTimes()
+
+	want := driver.Resources{CPUs: 0.5, Memory: 1 << 30, BlkioWeight: 100}
+	net.EXPECT().CreateNode(gomock.Any()).Do(func(config *driver.NodeConfig) {
+		if config.Resources != want {
+			t.Errorf("unexpected resources, got %+v, want %+v", config.Resources, want)
+		}
+	}).Return(node, nil)
+
+	scenario := parser.Scenario{
+		Name:             "Resources",
+		Description:      "Test scenario.",
+		DisableEndChecks: true,
+		Steps: []parser.Step{
+			{
+				Function:    parser.FuncStartNode,
+				Identifier:  "rpc",
+				CPUs:        0.5,
+				Memory:      1 << 30,
+				BlkioWeight: 100,
+			},
+		},
+	}
+
+	if err := run(t.Context(), net, &scenario, nil, nil); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}
+
+func TestContainerOf_PacksNeighbouringInstancesTogether(t *testing.T) {
+	packing := 3
+	step := &parser.Step{Function: parser.FuncStartNode, Identifier: "obs", ClientsPerContainer: &packing}
+	want := []string{"obs-pack-0", "obs-pack-0", "obs-pack-0", "obs-pack-1", "obs-pack-1"}
+	for instance, container := range want {
+		if got := containerOf(step, instance); got != container {
+			t.Errorf("instance %d: unexpected container, got %q, want %q", instance, got, container)
+		}
+	}
+
+	one := 1
+	for _, packing := range []*int{nil, new(int), &one} {
+		step := &parser.Step{Function: parser.FuncStartNode, Identifier: "obs", ClientsPerContainer: packing}
+		if got := containerOf(step, 1); got != "" {
+			t.Errorf("unpacked instance got container %q", got)
+		}
+	}
+}
+
+func TestFormatStepExecutionName_IncludesTheIterationOfRepeatedSteps(t *testing.T) {
+	tests := map[string]struct {
+		step parser.Step
+		want string
+	}{
+		"plain":      {parser.Step{Function: parser.FuncAdvanceEpoch}, "step 3: advanceEpoch"},
+		"identifier": {parser.Step{Function: parser.FuncStartNode, Identifier: "val"}, "step 3: startNode val"},
+		"repeated":   {parser.Step{Function: parser.FuncStartNode, Identifier: "val", Iterations: []int{2}}, "step 3 (iteration 2): startNode val"},
+		"nested":     {parser.Step{Function: parser.FuncAdvanceEpoch, Iterations: []int{1, 0}}, "step 3 (iteration 1.0): advanceEpoch"},
+	}
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			if got := formatStepExecutionName(3, &test.step); got != test.want {
+				t.Errorf("unexpected name, got %q, want %q", got, test.want)
+			}
+		})
+	}
+}
+
+func TestClientMemoryOf_ForwardsTheMemorySettingsOfTheStep(t *testing.T) {
+	step := &parser.Step{LiveCache: 32 << 20, Cache: 64 << 20, GoMemLimit: 1 << 30}
+	want := driver.ClientMemory{LiveCache: 32 << 20, Cache: 64 << 20, GoMemLimit: 1 << 30}
+	if got := ClientMemoryOf(step); got != want {
+		t.Errorf("unexpected client memory, got %+v, want %+v", got, want)
+	}
+}
+
+func TestExecUpdateResources_RejectsUnknownAndNonOperaNodes(t *testing.T) {
+	ctrl := gomock.NewController(t)
+
+	step := &parser.Step{Function: parser.FuncUpdateResources, Identifier: "missing", CPUs: 1}
+	err := execUpdateResources(t.Context(), step, &runState{nodes: map[string]driver.Node{}})
+	if err == nil || !strings.Contains(err.Error(), `node "missing" not found`) {
+		t.Fatalf("expected unknown node error, got %v", err)
+	}
+
+	state := &runState{nodes: map[string]driver.Node{"val": driver.NewMockNode(ctrl)}}
+	step = &parser.Step{Function: parser.FuncUpdateResources, Identifier: "val", CPUs: 1}
+	err = execUpdateResources(t.Context(), step, state)
+	if err == nil || !strings.Contains(err.Error(), "not an OperaNode") {
+		t.Fatalf("expected non-OperaNode error, got %v", err)
+	}
+}
+
+func TestMergeResources_KeepsLimitsNotUpdated(t *testing.T) {
+	current := driver.Resources{CPUs: 2, Memory: 1 << 30, BlkioWeight: 500}
+	got := mergeResources(current, driver.Resources{CPUs: 0.5})
+	want := driver.Resources{CPUs: 0.5, Memory: 1 << 30, BlkioWeight: 500}
+	if got != want {
+		t.Errorf("unexpected resources, got %+v, want %+v", got, want)
+	}
+}
+
+func TestDataVolumeOf_KeepsDataOfUpgradedNodesOnTheHost(t *testing.T) {
+	scenario := &parser.Scenario{Steps: []parser.Step{
+		{Function: parser.FuncUpgradeNode, Identifier: "val-1", ImageName: "sonic:new"},
+		{Function: parser.FuncUpgradeNode, Identifier: "group", ImageName: "sonic:new"},
+	}}
+	state := &runState{scenario: scenario}
+
+	tests := map[string]struct {
+		step     parser.Step
+		instance string
+		want     string
+	}{
+		"explicit volume": {
+			step:     parser.Step{Identifier: "rpc", DataVolume: "rpc-data"},
+			instance: "rpc",
+			want:     "rpc-data",
+		},
+		"upgraded later":          {step: parser.Step{Identifier: "val-1"}, instance: "val-1", want: "val-1-datadir"},
+		"instance upgraded later": {step: parser.Step{Identifier: "val"}, instance: "val-1", want: "val-1-datadir"},
+		"never upgraded":          {step: parser.Step{Identifier: "rpc"}, instance: "rpc", want: ""},
+	}
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			got := dataVolumeOf(state, &test.step, test.instance)
+			if (got == nil) != (test.want == "") || (got != nil && *got != test.want) {
+				t.Errorf("unexpected data volume, got %v, want %q", got, test.want)
+			}
+		})
+	}
+}
+
+func TestExecUpgradeNode_RejectsUnknownAndNonOperaNodes(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	net := driver.NewMockNetwork(ctrl)
+
+	step := &parser.Step{Function: parser.FuncUpgradeNode, Identifier: "missing", ImageName: "sonic:new"}
+	err := execUpgradeNode(t.Context(), step, net, &runState{nodes: map[string]driver.Node{}})
+	if err == nil || !strings.Contains(err.Error(), `node "missing" not found`) {
+		t.Fatalf("expected unknown node error, got %v", err)
+	}
+
+	state := &runState{nodes: map[string]driver.Node{"val": driver.NewMockNode(ctrl)}}
+	step = &parser.Step{Function: parser.FuncUpgradeNode, Identifier: "val", ImageName: "sonic:new"}
+	err = execUpgradeNode(t.Context(), step, net, state)
+	if err == nil || !strings.Contains(err.Error(), "not an OperaNode") {
+		t.Fatalf("expected non-OperaNode error, got %v", err)
+	}
+	if _, found := state.nodes["val"]; !found {
+		t.Errorf("node rejected for upgrade should stay active")
+	}
+}
+
+func TestRun_StartNode_RecordsNodeConfigs(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	net := driver.NewMockNetwork(ctrl)
+	node := driver.NewMockNode(ctrl)
+
+	net.EXPECT().DialRandomRpc().Return(nil, driver.ErrEmptyNetwork).AnyTimes()
+	net.EXPECT().CreateNode(gomock.Any()).Return(node, nil)
+	node.EXPECT().GetLabel().Return("rpc").AnyTimes()
+	node.EXPECT().DialRpc(gomock.Any()).Return(nil, fmt.Errorf("no rpc")).AnyTimes()
+
+	step := &parser.Step{
+		Function:   parser.FuncStartNode,
+		Identifier: "rpc",
+		NodeType:   "observer",
+		ImageName:  "sonic:old",
+	}
+	state := &runState{
+		nodes:       map[string]driver.Node{},
+		nodeHistory: map[string]bool{},
+		configs:     map[string]driver.NodeConfig{},
+		scenario: &parser.Scenario{Steps: []parser.Step{
+			*step,
+			{Function: parser.FuncUpgradeNode, Identifier: "rpc", ImageName: "sonic:new"},
+		}},
+	}
+	if err := execStartNode(t.Context(), step, net, nil, state); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	config, found := state.configs["rpc"]
+	if !found {
+		t.Fatalf("configuration of the started node was not recorded")
+	}
+	if config.Image != "sonic:old" {
+		t.Errorf("unexpected image, got %q", config.Image)
+	}
+	if config.DataVolume == nil || *config.DataVolume != "rpc-datadir" {
+		t.Errorf("node to be upgraded should keep its data on the host, got %v", config.DataVolume)
+	}
+}
+
 func TestRun_RunAndCaptureEventExecution_CapturesAllSteps(t *testing.T) {
 	ctrl := gomock.NewController(t)
 	net := driver.NewMockNetwork(ctrl)
@@ -695,6 +1402,7 @@ func TestRun_RunAndCaptureEventExecution_CapturesAllSteps(t *testing.T) {
 		&scenario,
 		nil,
 		nil,
+		"",
 	)
 	if err != nil {
 		t.Fatalf("unexpected error: %v", err)
@@ -1526,3 +2234,44 @@ func undelegateStep(node, delegator string, stake *uint64) parser.Step {
 		},
 	}
 }
+
+func TestWaitForBlockProduction_StopsWaitingWhenCancelled(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	net := driver.NewMockNetwork(ctrl)
+	client := rpc.NewMockClient(ctrl)
+
+	ctx, cancel := context.WithCancel(t.Context())
+	net.EXPECT().DialRandomRpc().Return(client, nil)
+	client.EXPECT().BlockNumber(gomock.Any()).DoAndReturn(func(context.Context) (uint64, error) {
+		cancel()
+		return 5, nil
+	}).Times(2)
+	client.EXPECT().Close()
+
+	err := waitForBlockProduction(ctx, net)
+	if !errors.Is(err, context.Canceled) {
+		t.Errorf("expected the wait to be cancelled, got %v", err)
+	}
+}
+
+func TestWaitForNodeSync_StopsWaitingWhenCancelled(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	node := driver.NewMockNode(ctrl)
+	client := rpc.NewMockClient(ctrl)
+
+	ctx, cancel := context.WithCancel(t.Context())
+	node.EXPECT().DialRpc(gomock.Any()).Return(client, nil)
+	client.EXPECT().BlockNumber(gomock.Any()).DoAndReturn(func(context.Context) (uint64, error) {
+		cancel()
+		return 5, nil
+	})
+	client.EXPECT().Close()
+
+	start := time.Now()
+	if err := waitForNodeSync(ctx, node, 10); !errors.Is(err, context.Canceled) {
+		t.Errorf("expected the wait to be cancelled, got %v", err)
+	}
+	if elapsed := time.Since(start); elapsed > time.Second {
+		t.Errorf("cancelled wait took %v", elapsed)
+	}
+}
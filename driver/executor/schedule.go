@@ -0,0 +1,246 @@
+// Copyright 2024 Fantom Foundation
+// This file is part of Norma System Testing Infrastructure for Sonic.
+//
+// Norma is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// Norma is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with Norma. If not, see <http://www.gnu.org/licenses/>.
+
+package executor
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"sync"
+	"time"
+
+	"github.com/0xsoniclabs/norma/driver"
+	"github.com/0xsoniclabs/norma/driver/checking"
+	"github.com/0xsoniclabs/norma/driver/network"
+	"github.com/0xsoniclabs/norma/driver/parser"
+	"github.com/0xsoniclabs/norma/driver/rpc"
+)
+
+// triggerPollPeriod is the time between the readings of the block or epoch
+// number a scheduled step waits for.
+const triggerPollPeriod = 200 * time.Millisecond
+
+// background runs the steps scheduled by the schedule steps of a run, next
+// to the steps after them, until the run joins them.
+//
+// Each schedule step runs its steps on a copy of the run state, and the
+// changes they made are merged back once joined, as for the branches of a
+// parallel step. The parser makes sure no step after a schedule step acts
+// on the nodes, applications or delegators of the steps it schedules.
+type background struct {
+	ctx    context.Context
+	cancel context.CancelFunc
+	group  sync.WaitGroup
+
+	mutex sync.Mutex
+	// err is the first failure of a scheduled step, which cancels the
+	// others.
+	err   error
+	forks []backgroundFork
+}
+
+// backgroundFork is the copy of the run state steps run on in the
+// background, along with the state it was copied from.
+type backgroundFork struct {
+	base, fork *runState
+}
+
+func newBackground(ctx context.Context) *background {
+	ctx, cancel := context.WithCancel(ctx)
+	return &background{ctx: ctx, cancel: cancel}
+}
+
+// start runs the given function in the background, on a copy of the given
+// state.
+func (b *background) start(state *runState, run func(ctx context.Context, state *runState) error) {
+	fork := backgroundFork{base: state.fork(), fork: state.fork()}
+	b.mutex.Lock()
+	b.forks = append(b.forks, fork)
+	b.mutex.Unlock()
+	b.group.Go(func() {
+		if err := run(b.ctx, fork.fork); err != nil {
+			b.mutex.Lock()
+			defer b.mutex.Unlock()
+			if b.err == nil {
+				b.err = err
+				b.cancel()
+			}
+		}
+	})
+}
+
+// failure returns the first failure of a step run in the background so
+// far, if any.
+func (b *background) failure() error {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+	return b.err
+}
+
+// join waits for the steps run in the background to complete, merges the
+// changes they made into the given state and returns the first failure
+// among them, if any.
+func (b *background) join(state *runState) error {
+	b.mutex.Lock()
+	pending := len(b.forks)
+	b.mutex.Unlock()
+	if pending > 0 {
+		slog.Info("awaiting scheduled steps", "schedules", pending)
+	}
+	b.group.Wait()
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+	for _, fork := range b.forks {
+		state.merge(fork.base, fork.fork)
+	}
+	b.forks = nil
+	return b.err
+}
+
+// stop cancels the steps still running in the background and waits for
+// them to return.
+func (b *background) stop() {
+	b.cancel()
+	b.group.Wait()
+}
+
+// execSchedule starts running the steps scheduled by a schedule step in the
+// background, one after another, each once its trigger is reached. It
+// returns right away; the run joins the steps before its end checks.
+func execSchedule(
+	step *parser.Step,
+	net driver.Network,
+	checks checking.Checks,
+	registry validatorRegistry,
+	state *runState,
+) error {
+	if state.background == nil {
+		return fmt.Errorf("schedule steps require a run to await the steps they schedule")
+	}
+	start := time.Now()
+	state.background.start(state, func(ctx context.Context, fork *runState) error {
+		for i := range step.Branches {
+			if err := execScheduled(ctx, &step.Branches[i], start, net, checks, registry, fork); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	return nil
+}
+
+// execScheduled waits for the trigger of a scheduled step and executes it.
+// Times are relative to the given start of its schedule step.
+func execScheduled(
+	ctx context.Context,
+	step *parser.Step,
+	start time.Time,
+	net driver.Network,
+	checks checking.Checks,
+	registry validatorRegistry,
+	state *runState,
+) error {
+	name := fmt.Sprintf("scheduled at %v: %s", step.Trigger, describeStep(step))
+	if err := waitForTrigger(ctx, net, *step.Trigger, start); err != nil {
+		return fmt.Errorf("step scheduled at %v (%s) never fired: %w", step.Trigger, describeStep(step), err)
+	}
+
+	slog.Info("executing scheduled step",
+		"trigger", step.Trigger.String(),
+		"function", step.Function,
+		"identifier", step.Identifier,
+		"elapsed", time.Since(start).Round(time.Millisecond),
+	)
+	begin := time.Now()
+	err := executeStep(ctx, step, net, checks, registry, state)
+	observed, err := checkExpectedError(ctx, step, err)
+	if state.onEvent != nil {
+		state.onEvent(EventExecution{Name: name, Start: begin, End: time.Now(), Error: observed})
+	}
+	if err != nil {
+		slog.Error("scheduled step failed",
+			"trigger", step.Trigger.String(),
+			"function", step.Function,
+			"identifier", step.Identifier,
+			"error", err,
+		)
+		return fmt.Errorf("step scheduled at %v (%s) failed: %w", step.Trigger, describeStep(step), err)
+	}
+	return nil
+}
+
+// waitForTrigger waits until the given trigger is reached. An offset is
+// counted from the given start, block and epoch numbers are read from the
+// network.
+func waitForTrigger(ctx context.Context, net driver.Network, trigger parser.Trigger, start time.Time) error {
+	switch {
+	case trigger.Block != nil:
+		return waitForNumber(ctx, net, *trigger.Block, func(client rpc.Client) (uint64, error) {
+			return client.BlockNumber(ctx)
+		})
+	case trigger.Epoch != nil:
+		return waitForNumber(ctx, net, *trigger.Epoch, func(client rpc.Client) (uint64, error) {
+			epoch, err := network.GetCurrentEpoch(client)
+			return uint64(epoch), err
+		})
+	case trigger.After != nil:
+		timer := time.NewTimer(time.Until(start.Add(*trigger.After)))
+		defer timer.Stop()
+		select {
+		case <-timer.C:
+			return nil
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	default:
+		return fmt.Errorf("no trigger")
+	}
+}
+
+// waitForNumber polls the network until the number read from it reaches the
+// given target. Failed readings are retried on another node, as the one read
+// from may have been stopped in the meantime.
+func waitForNumber(ctx context.Context, net driver.Network, target uint64, read func(rpc.Client) (uint64, error)) error {
+	var client rpc.Client
+	defer func() {
+		if client != nil {
+			client.Close()
+		}
+	}()
+	for {
+		if client == nil {
+			if dialed, err := net.DialRandomRpc(); err == nil {
+				client = dialed
+			}
+		}
+		if client != nil {
+			number, err := read(client)
+			if err == nil && number >= target {
+				return nil
+			}
+			if err != nil {
+				client.Close()
+				client = nil
+			}
+		}
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(triggerPollPeriod):
+		}
+	}
+}
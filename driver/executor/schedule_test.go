@@ -0,0 +1,123 @@
+// Copyright 2024 Fantom Foundation
+// This file is part of Norma System Testing Infrastructure for Sonic.
+//
+// Norma is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// Norma is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with Norma. If not, see <http://www.gnu.org/licenses/>.
+
+package executor
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/0xsoniclabs/norma/driver"
+	"github.com/0xsoniclabs/norma/driver/parser"
+	"github.com/0xsoniclabs/norma/driver/rpc"
+	"go.uber.org/mock/gomock"
+)
+
+func TestRun_ScheduledStepsAreJoinedBeforeTheEndChecks(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	net := driver.NewMockNetwork(ctrl)
+
+	// DialRandomRpc returns error so waitForBlockProduction is skipped.
+	net.EXPECT().DialRandomRpc().Return(nil, fmt.Errorf("no nodes")).AnyTimes()
+	gomock.InOrder(
+		net.EXPECT().AdvanceEpoch(gomock.Any(), 1).Return(nil),
+		net.EXPECT().WaitForEpochChange(gomock.Any()).Return(nil),
+	)
+
+	after := 20 * time.Millisecond
+	scenario := parser.Scenario{
+		Name:        "Scheduled",
+		Description: "Test scenario.",
+		Steps: []parser.Step{
+			{Function: parser.FuncSchedule, Branches: []parser.Step{
+				{Function: parser.FuncAdvanceEpoch, Trigger: &parser.Trigger{After: &after}},
+			}},
+			{Function: parser.FuncWaitForEpoch},
+		},
+		EndChecks: 1,
+	}
+
+	var events []string
+	err := runWithObserver(t.Context(), net, &scenario, nil, nil,
+		func(event EventExecution) { events = append(events, event.Name) }, nil, "", "")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	want := []string{
+		"step 1: schedule",
+		"scheduled at 20ms: advanceEpoch",
+		"step 2: waitForEpoch",
+	}
+	if !slices.Equal(events, want) {
+		t.Errorf("unexpected events, got %v, want %v", events, want)
+	}
+}
+
+func TestRun_FailingScheduledStepFailsTheRun(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	net := driver.NewMockNetwork(ctrl)
+
+	net.EXPECT().DialRandomRpc().Return(nil, fmt.Errorf("no nodes")).AnyTimes()
+	net.EXPECT().AdvanceEpoch(gomock.Any(), 1).Return(fmt.Errorf("injected"))
+
+	var after time.Duration
+	scenario := parser.Scenario{
+		Name:        "Scheduled",
+		Description: "Test scenario.",
+		Steps: []parser.Step{
+			{Function: parser.FuncSchedule, Branches: []parser.Step{
+				{Function: parser.FuncAdvanceEpoch, Trigger: &parser.Trigger{After: &after}},
+			}},
+			{Function: parser.FuncWaitFor, Duration: 10 * time.Millisecond},
+		},
+	}
+
+	err := run(t.Context(), net, &scenario, nil, nil)
+	want := "step scheduled at 0s (advanceEpoch) failed: injected"
+	if err == nil || !strings.Contains(err.Error(), want) {
+		t.Errorf("expected error to contain %q, got %v", want, err)
+	}
+}
+
+func TestWaitForTrigger_WaitsForTheBlockNumber(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	net := driver.NewMockNetwork(ctrl)
+	client := rpc.NewMockClient(ctrl)
+
+	// The first node fails to answer, the second one is read until the
+	// block is reached.
+	failing := rpc.NewMockClient(ctrl)
+	failing.EXPECT().BlockNumber(gomock.Any()).Return(uint64(0), fmt.Errorf("node stopped"))
+	failing.EXPECT().Close()
+	gomock.InOrder(
+		net.EXPECT().DialRandomRpc().Return(failing, nil),
+		net.EXPECT().DialRandomRpc().Return(client, nil),
+	)
+	gomock.InOrder(
+		client.EXPECT().BlockNumber(gomock.Any()).Return(uint64(4), nil),
+		client.EXPECT().BlockNumber(gomock.Any()).Return(uint64(5), nil),
+		client.EXPECT().Close(),
+	)
+
+	block := uint64(5)
+	if err := waitForTrigger(t.Context(), net, parser.Trigger{Block: &block}, time.Now()); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}
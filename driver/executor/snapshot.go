@@ -0,0 +1,362 @@
+// Copyright 2024 Fantom Foundation
+// This file is part of Norma System Testing Infrastructure for Sonic.
+//
+// Norma is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// Norma is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with Norma. If not, see <http://www.gnu.org/licenses/>.
+
+package executor
+
+import (
+	"cmp"
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"maps"
+	"os"
+	"path/filepath"
+	"slices"
+
+	"github.com/0xsoniclabs/norma/driver"
+	"github.com/0xsoniclabs/norma/driver/node"
+	"github.com/0xsoniclabs/norma/driver/parser"
+	"golang.org/x/sync/errgroup"
+)
+
+// Layout of a snapshot directory: the run state is kept in a JSON file next
+// to a directory holding a copy of the data directory of every node.
+const (
+	snapshotStateFile = "state.json"
+	snapshotNodesDir  = "nodes"
+)
+
+// SnapshotDir returns the directory the snapshot step of the given name
+// saves the network to, within the output directory of a run.
+func SnapshotDir(outputDir, name string) string {
+	return filepath.Join(outputDir, "snapshots", name)
+}
+
+// snapshotState is the part of the run state a snapshot holds, besides the
+// data directories of the nodes.
+type snapshotState struct {
+	// Scenario is the name of the scenario that took the snapshot.
+	Scenario string `json:"scenario"`
+	// Name is the name given by the snapshot step.
+	Name string `json:"name"`
+	// Step is the number of the snapshot step in the scenario, counting
+	// from 1; a restored run continues with the step after it.
+	Step int `json:"step"`
+	// Nodes are the configurations of the active nodes, for the containers
+	// the restored data directories are mounted into.
+	Nodes []driver.NodeConfig `json:"nodes"`
+	// NodeHistory lists the names of all nodes started so far.
+	NodeHistory []string `json:"nodeHistory"`
+	// ValidatorIds maps node names to the IDs of their validators.
+	ValidatorIds map[string]int `json:"validatorIds"`
+	// Delegators lists the names of the delegators used so far. Their keys
+	// are derived from the names, see getOrCreateDelegator.
+	Delegators []string `json:"delegators"`
+	// ExpectedStakes are the stakes the verifyStakes step expects.
+	ExpectedStakes []snapshotStake `json:"expectedStakes"`
+}
+
+// snapshotStake is a tracked stake of a delegator on a validator.
+type snapshotStake struct {
+	Delegator   string `json:"delegator"`
+	ValidatorId int    `json:"validatorId"`
+	Stake       uint64 `json:"stake"`
+}
+
+// execSnapshot saves the network under the name the step gives. All clients
+// are stopped gracefully, so their databases are consistent, and their data
+// directories are copied together with the run state. The clients are then
+// started again on their own data directories, all at once, as the network
+// has to get going again without any running node to sync with.
+func execSnapshot(
+	ctx context.Context,
+	step *parser.Step,
+	net driver.Network,
+	state *runState,
+) error {
+	if state.outputDir == "" {
+		return fmt.Errorf("snapshot requires an output directory")
+	}
+	dir := SnapshotDir(state.outputDir, step.Identifier)
+	if _, err := os.Stat(dir); err == nil {
+		return fmt.Errorf("snapshot directory %s exists already", dir)
+	}
+
+	instances := findAllNodeInstances(state)
+	if len(instances) == 0 {
+		return fmt.Errorf("there are no active nodes to take a snapshot of")
+	}
+	nodes := make([]*node.OperaNode, 0, len(instances))
+	configs := make([]driver.NodeConfig, 0, len(instances))
+	for _, instance := range instances {
+		opera, ok := instance.node.(*node.OperaNode)
+		if !ok {
+			return fmt.Errorf("node %q is not an OperaNode", instance.name)
+		}
+		config, ok := state.configs[instance.name]
+		if !ok {
+			return fmt.Errorf("node %q has no known configuration to restore it with", instance.name)
+		}
+		if config.DataVolume == nil {
+			return fmt.Errorf("node %q keeps no data directory on the host to take a snapshot of", instance.name)
+		}
+		// The twin of a cheating validator is not tracked as a node of its
+		// own and would keep running while the network is stopped.
+		if config.Cheater {
+			return fmt.Errorf("node %q runs with a cheating twin, which a snapshot cannot hold", instance.name)
+		}
+		// A restored node keeps the clock it runs on.
+		if config.ClockOffset != nil {
+			offset := opera.ClockOffset()
+			config.ClockOffset = &offset
+		}
+		nodes = append(nodes, opera)
+		configs = append(configs, config)
+	}
+
+	slog.Info("stopping network for snapshot", "name", step.Identifier, "nodes", len(nodes))
+	g, gctx := errgroup.WithContext(ctx)
+	for i, opera := range nodes {
+		net.SuspendNode(instances[i].node)
+		g.Go(func() error {
+			if err := opera.StopSonicd(gctx); err != nil {
+				return fmt.Errorf("failed to stop sonicd on %s: %w", instances[i].name, err)
+			}
+			return nil
+		})
+	}
+	if err := g.Wait(); err != nil {
+		return err
+	}
+
+	for i, opera := range nodes {
+		target := filepath.Join(dir, snapshotNodesDir, instances[i].name)
+		slog.Info("copying data directory", "node", instances[i].name, "target", target)
+		if err := opera.CopyDataDir(ctx, target); err != nil {
+			return err
+		}
+	}
+	if err := writeSnapshotState(dir, newSnapshotState(step, configs, state)); err != nil {
+		return err
+	}
+	slog.Info("snapshot taken", "name", step.Identifier, "dir", dir)
+
+	g, gctx = errgroup.WithContext(ctx)
+	for i, opera := range nodes {
+		g.Go(func() error {
+			if err := opera.StartSonicdBootstrapping(gctx); err != nil {
+				return fmt.Errorf("failed to restart sonicd on %s: %w", instances[i].name, err)
+			}
+			if err := opera.WaitForSync(gctx); err != nil {
+				return fmt.Errorf("failed to sync restarted node %s: %w", instances[i].name, err)
+			}
+			return nil
+		})
+	}
+	if err := g.Wait(); err != nil {
+		return err
+	}
+	for i := range nodes {
+		if err := net.ReconnectNode(ctx, instances[i].node); err != nil {
+			return fmt.Errorf("failed to reconnect node %s: %w", instances[i].name, err)
+		}
+		net.ResumeNode(instances[i].node)
+	}
+	return nil
+}
+
+// findAllNodeInstances returns all active nodes, sorted by name.
+func findAllNodeInstances(state *runState) []nodeInstance {
+	res := make([]nodeInstance, 0, len(state.nodes))
+	for _, name := range slices.Sorted(maps.Keys(state.nodes)) {
+		res = append(res, nodeInstance{name: name, node: state.nodes[name]})
+	}
+	return res
+}
+
+// takesSnapshots reports whether the scenario being run has a snapshot step.
+func takesSnapshots(state *runState) bool {
+	if state.scenario == nil {
+		return false
+	}
+	return slices.ContainsFunc(state.scenario.Steps, func(step parser.Step) bool {
+		return step.Function == parser.FuncSnapshot
+	})
+}
+
+// newSnapshotState collects the run state a snapshot taken by the given
+// step holds, for the nodes of the given configurations.
+func newSnapshotState(step *parser.Step, configs []driver.NodeConfig, state *runState) *snapshotState {
+	res := &snapshotState{
+		Name:         step.Identifier,
+		Step:         snapshotStepNumber(state.scenario, step.Identifier),
+		Nodes:        configs,
+		NodeHistory:  slices.Sorted(maps.Keys(state.nodeHistory)),
+		ValidatorIds: state.validatorIds,
+		Delegators:   slices.Sorted(maps.Keys(state.delegators)),
+	}
+	if state.scenario != nil {
+		res.Scenario = state.scenario.Name
+	}
+	for key, stake := range state.expectedStakes {
+		res.ExpectedStakes = append(res.ExpectedStakes, snapshotStake{
+			Delegator:   key.delegator,
+			ValidatorId: key.validatorId,
+			Stake:       stake,
+		})
+	}
+	slices.SortFunc(res.ExpectedStakes, func(a, b snapshotStake) int {
+		return cmp.Or(
+			cmp.Compare(a.Delegator, b.Delegator),
+			cmp.Compare(a.ValidatorId, b.ValidatorId),
+		)
+	})
+	return res
+}
+
+// snapshotStepNumber returns the number of the step taking the snapshot of
+// the given name, counting from 1, or 0 if there is none. Snapshot names
+// are unique within a scenario.
+func snapshotStepNumber(scenario *parser.Scenario, name string) int {
+	if scenario == nil {
+		return 0
+	}
+	for i, step := range scenario.Steps {
+		if step.Function == parser.FuncSnapshot && step.Identifier == name {
+			return i + 1
+		}
+	}
+	return 0
+}
+
+// writeSnapshotState writes the run state of a snapshot into its directory.
+func writeSnapshotState(dir string, state *snapshotState) error {
+	data, err := json.MarshalIndent(state, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode snapshot state: %w", err)
+	}
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return fmt.Errorf("failed to create snapshot directory: %w", err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, snapshotStateFile), data, 0644); err != nil {
+		return fmt.Errorf("failed to write snapshot state: %w", err)
+	}
+	return nil
+}
+
+// readSnapshotState reads the run state of the snapshot in the given
+// directory.
+func readSnapshotState(dir string) (*snapshotState, error) {
+	data, err := os.ReadFile(filepath.Join(dir, snapshotStateFile)) //#nosec G304 -- the snapshot is chosen by the user
+	if err != nil {
+		return nil, fmt.Errorf("failed to read snapshot state: %w", err)
+	}
+	var state snapshotState
+	if err := json.Unmarshal(data, &state); err != nil {
+		return nil, fmt.Errorf("failed to decode snapshot state: %w", err)
+	}
+	return &state, nil
+}
+
+// restoreSnapshot starts the nodes of the snapshot in the given directory on
+// copies of their data directories, and restores the run state it holds.
+// It returns the number of the step that took the snapshot, which has to be
+// a snapshot step of the scenario being run.
+func restoreSnapshot(
+	ctx context.Context,
+	net driver.Network,
+	dir string,
+	state *runState,
+) (int, error) {
+	snapshot, err := readSnapshotState(dir)
+	if err != nil {
+		return 0, err
+	}
+	if err := checkSnapshotFits(snapshot, state.scenario); err != nil {
+		return 0, err
+	}
+	if state.outputDir == "" {
+		return 0, fmt.Errorf("restoring a snapshot requires an output directory")
+	}
+
+	// The data directories are copied, so the snapshot can be restored
+	// again, and mounted where the nodes of this run keep theirs.
+	for _, config := range snapshot.Nodes {
+		if config.DataVolume == nil {
+			return 0, fmt.Errorf("node %q has no data directory in the snapshot", config.Name)
+		}
+		source := filepath.Join(dir, snapshotNodesDir, config.Name)
+		target := filepath.Join(state.outputDir, *config.DataVolume)
+		slog.Info("restoring data directory", "node", config.Name, "source", source)
+		if err := os.CopyFS(target, os.DirFS(source)); err != nil {
+			return 0, fmt.Errorf("failed to restore the data directory of %s: %w", config.Name, err)
+		}
+	}
+
+	// All nodes start at once, as the validators depend on each other to
+	// produce blocks.
+	nodes := make([]driver.Node, len(snapshot.Nodes))
+	g, _ := errgroup.WithContext(ctx)
+	for i, config := range snapshot.Nodes {
+		g.Go(func() error {
+			created, err := net.CreateNode(&config)
+			if err != nil {
+				return fmt.Errorf("failed to create node %s: %w", config.Name, err)
+			}
+			nodes[i] = created
+			return nil
+		})
+	}
+	if err := g.Wait(); err != nil {
+		return 0, err
+	}
+
+	for i, config := range snapshot.Nodes {
+		state.nodes[config.Name] = nodes[i]
+		state.configs[config.Name] = config
+	}
+	for _, name := range snapshot.NodeHistory {
+		state.nodeHistory[name] = true
+	}
+	maps.Copy(state.validatorIds, snapshot.ValidatorIds)
+	for _, name := range snapshot.Delegators {
+		if _, err := getOrCreateDelegator(state, name); err != nil {
+			return 0, fmt.Errorf("failed to derive delegator %q: %w", name, err)
+		}
+	}
+	for _, stake := range snapshot.ExpectedStakes {
+		state.expectedStakes[stakeKey{stake.Delegator, stake.ValidatorId}] = stake.Stake
+	}
+	slog.Info("snapshot restored", "name", snapshot.Name, "step", snapshot.Step, "nodes", len(nodes))
+	return snapshot.Step, nil
+}
+
+// checkSnapshotFits fails unless the snapshot was taken by a snapshot step
+// of the given scenario.
+func checkSnapshotFits(snapshot *snapshotState, scenario *parser.Scenario) error {
+	if snapshot.Scenario != scenario.Name {
+		return fmt.Errorf("snapshot was taken by scenario %q, not %q", snapshot.Scenario, scenario.Name)
+	}
+	if snapshot.Step < 1 || snapshot.Step > len(scenario.Steps) {
+		return fmt.Errorf("snapshot was taken by step %d, but the scenario has %d steps", snapshot.Step, len(scenario.Steps))
+	}
+	step := scenario.Steps[snapshot.Step-1]
+	if step.Function != parser.FuncSnapshot || step.Identifier != snapshot.Name {
+		return fmt.Errorf("step %d of the scenario does not take snapshot %q", snapshot.Step, snapshot.Name)
+	}
+	return nil
+}
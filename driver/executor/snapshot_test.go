@@ -0,0 +1,226 @@
+// Copyright 2024 Fantom Foundation
+// This file is part of Norma System Testing Infrastructure for Sonic.
+//
+// Norma is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// Norma is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with Norma. If not, see <http://www.gnu.org/licenses/>.
+
+package executor
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/0xsoniclabs/norma/driver"
+	"github.com/0xsoniclabs/norma/driver/parser"
+	"go.uber.org/mock/gomock"
+)
+
+func TestSnapshotState_SurvivesWritingAndReading(t *testing.T) {
+	scenario := &parser.Scenario{Name: "Test", Steps: []parser.Step{
+		{Function: parser.FuncStartNode, Identifier: "val"},
+		{Function: parser.FuncSnapshot, Identifier: "warm"},
+	}}
+	id := 1
+	state := &runState{
+		nodeHistory:    map[string]bool{"val": true, "gone": true},
+		validatorIds:   map[string]int{"val": 1, "gone": 2},
+		delegators:     map[string]*delegatorAccount{},
+		expectedStakes: map[stakeKey]uint64{{"bob", 1}: 5, {"alice", 2}: 0, {"alice", 1}: 3},
+		scenario:       scenario,
+	}
+	if _, err := getOrCreateDelegator(state, "bob"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	configs := []driver.NodeConfig{{Name: "val", Validator: true, ValidatorId: &id, DataVolume: dataVolumePtr("val-datadir")}}
+
+	want := &snapshotState{
+		Scenario:     "Test",
+		Name:         "warm",
+		Step:         2,
+		Nodes:        configs,
+		NodeHistory:  []string{"gone", "val"},
+		ValidatorIds: map[string]int{"val": 1, "gone": 2},
+		Delegators:   []string{"bob"},
+		ExpectedStakes: []snapshotStake{
+			{Delegator: "alice", ValidatorId: 1, Stake: 3},
+			{Delegator: "alice", ValidatorId: 2, Stake: 0},
+			{Delegator: "bob", ValidatorId: 1, Stake: 5},
+		},
+	}
+	got := newSnapshotState(&scenario.Steps[1], configs, state)
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("unexpected snapshot state\ngot:  %+v\nwant: %+v", got, want)
+	}
+
+	dir := t.TempDir()
+	if err := writeSnapshotState(dir, got); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	read, err := readSnapshotState(dir)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !reflect.DeepEqual(read, want) {
+		t.Errorf("snapshot state changed on disk\ngot:  %+v\nwant: %+v", read, want)
+	}
+}
+
+func TestCheckSnapshotFits_RequiresTheSnapshotStepOfTheScenario(t *testing.T) {
+	scenario := &parser.Scenario{Name: "Test", Steps: []parser.Step{
+		{Function: parser.FuncStartNode, Identifier: "val"},
+		{Function: parser.FuncSnapshot, Identifier: "warm"},
+	}}
+	tests := map[string]struct {
+		snapshot  snapshotState
+		errSubstr string
+	}{
+		"fits":           {snapshotState{Scenario: "Test", Name: "warm", Step: 2}, ""},
+		"other scenario": {snapshotState{Scenario: "Other", Name: "warm", Step: 2}, `scenario "Other"`},
+		"step too large": {snapshotState{Scenario: "Test", Name: "warm", Step: 3}, "has 2 steps"},
+		"no step":        {snapshotState{Scenario: "Test", Name: "warm", Step: 0}, "has 2 steps"},
+		"not a snapshot": {snapshotState{Scenario: "Test", Name: "warm", Step: 1}, `does not take snapshot "warm"`},
+		"other name":     {snapshotState{Scenario: "Test", Name: "cold", Step: 2}, `does not take snapshot "cold"`},
+	}
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			err := checkSnapshotFits(&test.snapshot, scenario)
+			if test.errSubstr == "" {
+				if err != nil {
+					t.Fatalf("unexpected error: %v", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), test.errSubstr) {
+				t.Errorf("expected error containing %q, got %v", test.errSubstr, err)
+			}
+		})
+	}
+}
+
+func TestExecSnapshot_RejectsNetworksItCannotSave(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	net := driver.NewMockNetwork(ctrl)
+	step := &parser.Step{Function: parser.FuncSnapshot, Identifier: "warm"}
+
+	err := execSnapshot(t.Context(), step, net, &runState{})
+	if err == nil || !strings.Contains(err.Error(), "requires an output directory") {
+		t.Errorf("expected missing output directory error, got %v", err)
+	}
+
+	state := &runState{outputDir: t.TempDir(), nodes: map[string]driver.Node{}}
+	err = execSnapshot(t.Context(), step, net, state)
+	if err == nil || !strings.Contains(err.Error(), "no active nodes") {
+		t.Errorf("expected empty network error, got %v", err)
+	}
+
+	state.nodes["val"] = driver.NewMockNode(ctrl)
+	err = execSnapshot(t.Context(), step, net, state)
+	if err == nil || !strings.Contains(err.Error(), "not an OperaNode") {
+		t.Errorf("expected non-OperaNode error, got %v", err)
+	}
+
+	if err := os.MkdirAll(SnapshotDir(state.outputDir, "warm"), 0755); err != nil {
+		t.Fatalf("failed to create snapshot directory: %v", err)
+	}
+	err = execSnapshot(t.Context(), step, net, state)
+	if err == nil || !strings.Contains(err.Error(), "exists already") {
+		t.Errorf("expected existing snapshot error, got %v", err)
+	}
+}
+
+func TestRestoreSnapshot_StartsNodesOnCopiesOfTheirDataAndRestoresRunState(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	net := driver.NewMockNetwork(ctrl)
+	restored := driver.NewMockNode(ctrl)
+
+	scenario := &parser.Scenario{Name: "Test", Steps: []parser.Step{
+		{Function: parser.FuncStartNode, Identifier: "val"},
+		{Function: parser.FuncSnapshot, Identifier: "warm"},
+		{Function: parser.FuncVerifyStakes},
+	}}
+	id := 1
+	config := driver.NodeConfig{Name: "val", Validator: true, ValidatorId: &id, DataVolume: dataVolumePtr("val-datadir")}
+	dir := t.TempDir()
+	if err := writeSnapshotState(dir, &snapshotState{
+		Scenario:       "Test",
+		Name:           "warm",
+		Step:           2,
+		Nodes:          []driver.NodeConfig{config},
+		NodeHistory:    []string{"val"},
+		ValidatorIds:   map[string]int{"val": 1},
+		Delegators:     []string{"bob"},
+		ExpectedStakes: []snapshotStake{{Delegator: "bob", ValidatorId: 1, Stake: 5}},
+	}); err != nil {
+		t.Fatalf("failed to write snapshot state: %v", err)
+	}
+	dbFile := filepath.Join(dir, snapshotNodesDir, "val", "chaindata", "db")
+	if err := os.MkdirAll(filepath.Dir(dbFile), 0755); err != nil {
+		t.Fatalf("failed to create data directory: %v", err)
+	}
+	if err := os.WriteFile(dbFile, []byte("blocks"), 0644); err != nil {
+		t.Fatalf("failed to write data directory: %v", err)
+	}
+
+	outputDir := t.TempDir()
+	net.EXPECT().CreateNode(gomock.Any()).DoAndReturn(func(got *driver.NodeConfig) (driver.Node, error) {
+		if !reflect.DeepEqual(*got, config) {
+			t.Errorf("unexpected node configuration, got %+v, want %+v", *got, config)
+		}
+		data, err := os.ReadFile(filepath.Join(outputDir, "val-datadir", "chaindata", "db"))
+		if err != nil || string(data) != "blocks" {
+			t.Errorf("data directory was not restored before the node was created: %q, %v", data, err)
+		}
+		return restored, nil
+	})
+
+	state := &runState{
+		nodes:          map[string]driver.Node{},
+		nodeHistory:    map[string]bool{},
+		validatorIds:   map[string]int{},
+		delegators:     map[string]*delegatorAccount{},
+		expectedStakes: map[stakeKey]uint64{},
+		configs:        map[string]driver.NodeConfig{},
+		scenario:       scenario,
+		outputDir:      outputDir,
+	}
+	taken, err := restoreSnapshot(t.Context(), net, dir, state)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if taken != 2 {
+		t.Errorf("unexpected snapshot step, got %d, want 2", taken)
+	}
+	if state.nodes["val"] != restored || !state.nodeHistory["val"] || state.validatorIds["val"] != 1 {
+		t.Errorf("node was not restored: %+v", state)
+	}
+	if _, found := state.configs["val"]; !found {
+		t.Errorf("configuration of the restored node was not recorded")
+	}
+	if state.delegators["bob"] == nil || state.expectedStakes[stakeKey{"bob", 1}] != 5 {
+		t.Errorf("delegations were not restored: %v, %v", state.delegators, state.expectedStakes)
+	}
+}
+
+func TestDataVolumeOf_KeepsDataOfAllNodesOnTheHostForSnapshots(t *testing.T) {
+	scenario := &parser.Scenario{Steps: []parser.Step{
+		{Function: parser.FuncSnapshot, Identifier: "warm"},
+	}}
+	state := &runState{scenario: scenario}
+	got := dataVolumeOf(state, &parser.Step{Identifier: "rpc"}, "rpc-1")
+	if got == nil || *got != "rpc-1-datadir" {
+		t.Errorf("unexpected data volume, got %v", got)
+	}
+}
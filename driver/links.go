@@ -0,0 +1,88 @@
+// Copyright 2024 Fantom Foundation
+// This file is part of Norma System Testing Infrastructure for Sonic.
+//
+// Norma is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// Norma is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with Norma. If not, see <http://www.gnu.org/licenses/>.
+
+package driver
+
+import (
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// LinkConfig describes the conditions of the network link between two
+// nodes. Every property applies to each direction of the link separately.
+type LinkConfig struct {
+	// Latency is the one-way delay added to every packet.
+	Latency time.Duration
+	// Jitter is the random variation of the delay.
+	Jitter time.Duration
+	// Loss is the percentage of packets dropped.
+	Loss float64
+	// Reorder is the percentage of packets sent immediately, overtaking the
+	// delayed ones. It has no effect without a latency.
+	Reorder float64
+}
+
+// LinkRule assigns a link configuration to the links between two sets of
+// nodes. Each set lists nodes by label, or by the base name of a node started
+// with several instances, which covers all of them.
+type LinkRule struct {
+	A, B []string
+	Link LinkConfig
+}
+
+// Applies reports whether the rule covers the link between the nodes with
+// the given labels, in either direction.
+func (r *LinkRule) Applies(a, b string) bool {
+	return (matchesAny(r.A, a) && matchesAny(r.B, b)) ||
+		(matchesAny(r.A, b) && matchesAny(r.B, a))
+}
+
+// ResolveLink returns the configuration of the link between the nodes with
+// the given labels: that of the last of the rules covering it, or false if
+// none does.
+func ResolveLink(rules []LinkRule, a, b string) (LinkConfig, bool) {
+	for i := len(rules) - 1; i >= 0; i-- {
+		if rules[i].Applies(a, b) {
+			return rules[i].Link, true
+		}
+	}
+	return LinkConfig{}, false
+}
+
+// matchesAny reports whether the node with the given label is referred to by
+// any of the names.
+func matchesAny(names []string, label string) bool {
+	return slices.ContainsFunc(names, func(name string) bool {
+		return MatchesNodeName(label, name)
+	})
+}
+
+// MatchesNodeName reports whether the node with the given label is referred
+// to by the given name, which is either the label itself or the base name of
+// a node started with several instances, labeled <name>-<i>.
+func MatchesNodeName(label, name string) bool {
+	if label == name {
+		return true
+	}
+	suffix, found := strings.CutPrefix(label, name+"-")
+	if !found {
+		return false
+	}
+	_, err := strconv.Atoi(suffix)
+	return err == nil
+}
@@ -0,0 +1,66 @@
+// Copyright 2024 Fantom Foundation
+// This file is part of Norma System Testing Infrastructure for Sonic.
+//
+// Norma is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// Norma is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with Norma. If not, see <http://www.gnu.org/licenses/>.
+
+package driver
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestMatchesNodeName(t *testing.T) {
+	tests := map[string]struct {
+		label, name string
+		want        bool
+	}{
+		"exact label":          {label: "rpc", name: "rpc", want: true},
+		"instance of base":     {label: "val-2", name: "val", want: true},
+		"different base":       {label: "validator-2", name: "val", want: false},
+		"non-numeric suffix":   {label: "val-eu", name: "val", want: false},
+		"instance not a base":  {label: "val-2", name: "val-2-1", want: false},
+		"base of nested names": {label: "val-eu-3", name: "val-eu", want: true},
+	}
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			require.Equal(t, test.want, MatchesNodeName(test.label, test.name))
+		})
+	}
+}
+
+func TestLinkRule_AppliesInBothDirections(t *testing.T) {
+	rule := LinkRule{A: []string{"eu"}, B: []string{"us", "rpc"}}
+	require.True(t, rule.Applies("eu-1", "us-0"))
+	require.True(t, rule.Applies("rpc", "eu-1"))
+	require.False(t, rule.Applies("us-0", "rpc"))
+	require.False(t, rule.Applies("eu-1", "eu-2"))
+}
+
+func TestResolveLink_LastMatchingRuleWins(t *testing.T) {
+	rules := []LinkRule{
+		{A: []string{"a"}, B: []string{"b"}, Link: LinkConfig{Latency: 10 * time.Millisecond}},
+		{A: []string{"b"}, B: []string{"a"}, Link: LinkConfig{Latency: 20 * time.Millisecond}},
+		{A: []string{"a"}, B: []string{"c"}, Link: LinkConfig{Latency: 30 * time.Millisecond}},
+	}
+
+	link, found := ResolveLink(rules, "a", "b")
+	require.True(t, found)
+	require.Equal(t, 20*time.Millisecond, link.Latency)
+
+	_, found = ResolveLink(rules, "b", "c")
+	require.False(t, found)
+}
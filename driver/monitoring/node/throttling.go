@@ -0,0 +1,80 @@
+// Copyright 2024 Fantom Foundation
+// This file is part of Norma System Testing Infrastructure for Sonic.
+//
+// Norma is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// Norma is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with Norma. If not, see <http://www.gnu.org/licenses/>.
+
+package nodemon
+
+import (
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/0xsoniclabs/norma/driver"
+	mon "github.com/0xsoniclabs/norma/driver/monitoring"
+	"github.com/0xsoniclabs/norma/driver/monitoring/utils"
+)
+
+// NodeThrottled marks the periods in which the bandwidth of a node was
+// throttled, to be plotted against metrics like BlockCompletionTime. Each
+// change of the limits adds a point: 1 if the node is throttled from then
+// on, 0 if it is not.
+var NodeThrottled = mon.Metric[mon.Node, mon.Series[mon.Time, int]]{
+	Name:        "NodeThrottled",
+	Description: "Whether the bandwidth of nodes was throttled at various times.",
+}
+
+func init() {
+	if err := mon.RegisterSource(NodeThrottled, newNodeThrottledSource); err != nil {
+		panic(fmt.Sprintf("failed to register metric source: %v", err))
+	}
+}
+
+// nodeThrottledSource collects the throttling periods reported by the node
+// log provider.
+type nodeThrottledSource struct {
+	*utils.SyncedSeriesSource[mon.Node, mon.Time, int]
+	monitor *mon.Monitor
+}
+
+func newNodeThrottledSource(monitor *mon.Monitor) mon.Source[mon.Node, mon.Series[mon.Time, int]] {
+	res := &nodeThrottledSource{
+		SyncedSeriesSource: utils.NewSyncedSeriesSource(NodeThrottled),
+		monitor:            monitor,
+	}
+	monitor.NodeLogProvider().RegisterLogListener(res)
+	return res
+}
+
+func (s *nodeThrottledSource) Shutdown() error {
+	s.monitor.NodeLogProvider().UnregisterLogListener(s)
+	return s.SyncedSeriesSource.Shutdown()
+}
+
+func (s *nodeThrottledSource) OnBlock(mon.Node, mon.Block) {
+	// only throttling changes are of interest
+}
+
+func (s *nodeThrottledSource) OnThrottlingChange(node mon.Node, at time.Time, config *driver.ThrottleConfig) {
+	throttled := 0
+	if config != nil {
+		throttled = 1
+	}
+	if err := s.GetOrAddSubject(node).Append(mon.NewTime(at), throttled); err != nil {
+		slog.Error("failed to append throttling data",
+			"metric", NodeThrottled.Name,
+			"node", node,
+			"error", err)
+	}
+}
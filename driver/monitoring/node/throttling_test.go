@@ -0,0 +1,67 @@
+// Copyright 2024 Fantom Foundation
+// This file is part of Norma System Testing Infrastructure for Sonic.
+//
+// Norma is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// Norma is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with Norma. If not, see <http://www.gnu.org/licenses/>.
+
+package nodemon
+
+import (
+	"testing"
+
+	"github.com/0xsoniclabs/norma/driver"
+	mon "github.com/0xsoniclabs/norma/driver/monitoring"
+	"go.uber.org/mock/gomock"
+)
+
+func TestNodeThrottled_RecordsThrottlingChanges(t *testing.T) {
+	ctrl := gomock.NewController(t)
+
+	net := driver.NewMockNetwork(ctrl)
+	net.EXPECT().RegisterListener(gomock.Any()).AnyTimes()
+	net.EXPECT().GetActiveNodes().Return([]driver.Node{}).AnyTimes()
+
+	node := driver.NewMockNode(ctrl)
+	node.EXPECT().GetLabel().Return("node-A").AnyTimes()
+
+	monitor, err := mon.NewMonitor(net, mon.MonitorConfig{OutputDir: t.TempDir()})
+	if err != nil {
+		t.Fatalf("failed to create monitor: %v", err)
+	}
+	source := newNodeThrottledSource(monitor)
+	defer func() {
+		if err := source.Shutdown(); err != nil {
+			t.Errorf("failed to shut down source: %v", err)
+		}
+	}()
+
+	dispatcher, ok := monitor.NodeLogProvider().(*mon.NodeLogDispatcher)
+	if !ok {
+		t.Fatalf("unexpected node log provider %T", monitor.NodeLogProvider())
+	}
+	dispatcher.OnNodeThrottled(node, &driver.ThrottleConfig{Egress: 1_000_000})
+	dispatcher.OnNodeThrottled(node, nil)
+
+	series, exists := source.GetData("node-A")
+	if !exists {
+		t.Fatalf("expected throttling data of node-A")
+	}
+	latest := series.GetLatest()
+	if latest == nil || latest.Value != 0 {
+		t.Fatalf("expected the node to end up unthrottled, got %v", latest)
+	}
+	points := series.GetRange(0, latest.Position)
+	if len(points) != 1 || points[0].Value != 1 {
+		t.Errorf("expected the node to have been throttled before, got %v", points)
+	}
+}
@@ -23,6 +23,7 @@ import (
 	"log/slog"
 	"os"
 	"sync"
+	"time"
 
 	"github.com/0xsoniclabs/norma/driver"
 )
@@ -45,6 +46,22 @@ type NodeRestartListener interface {
 	OnNodeRestart(node Node)
 }
 
+// ThrottleListener is an optional interface a LogListener may implement to
+// be told when the bandwidth of a node gets limited or lifted.
+type ThrottleListener interface {
+	// OnThrottlingChange is called with the moment the limits of the node
+	// changed and the limits in place from then on, or nil if it is no
+	// longer throttled.
+	OnThrottlingChange(node Node, at time.Time, config *driver.ThrottleConfig)
+}
+
+// ThrottlePeriod is a span of time during which the bandwidth of a node was
+// limited. End is zero while the period lasts.
+type ThrottlePeriod struct {
+	Start, End time.Time
+	Config     driver.ThrottleConfig
+}
+
 // NodeLogProvider is an interface for registering listeners that will be notified about incoming blocks.
 type NodeLogProvider interface {
 
@@ -60,6 +77,8 @@ type NodeLogProvider interface {
 // Log streams of all the nodes maintained in this registry are read and parsed,
 // while the parsed blocks from the logs are distributed to all registered listeners.
 // Furthermore, all collected logs are written to a configurable output directory.
+// The dispatcher also records the periods in which the bandwidth of a node was
+// throttled, so they can be related to the blocks found in the logs.
 type NodeLogDispatcher struct {
 	listeners     map[LogListener]bool
 	listenersLock sync.Mutex
@@ -70,6 +89,9 @@ type NodeLogDispatcher struct {
 
 	nodes     map[Node]*nodeLogState
 	nodesLock sync.Mutex
+
+	throttling     map[Node][]ThrottlePeriod
+	throttlingLock sync.Mutex
 }
 
 // nodeLogState records which node object's log stream is being consumed;
@@ -88,10 +110,11 @@ func NewNodeLogDispatcher(network driver.Network, outputDir string) (*NodeLogDis
 	}
 
 	res := &NodeLogDispatcher{
-		network:   network,
-		listeners: make(map[LogListener]bool, 50),
-		logDir:    logDir,
-		nodes:     make(map[Node]*nodeLogState, 50),
+		network:    network,
+		listeners:  make(map[LogListener]bool, 50),
+		logDir:     logDir,
+		nodes:      make(map[Node]*nodeLogState, 50),
+		throttling: map[Node][]ThrottlePeriod{},
 	}
 
 	// listen for new Nodes
@@ -187,6 +210,41 @@ func (n *NodeLogDispatcher) AfterApplicationCreation(driver.Application) {
 	// ignored
 }
 
+// OnNodeThrottled records the start or end of a throttling period of the
+// given node and informs every listener implementing ThrottleListener.
+func (n *NodeLogDispatcher) OnNodeThrottled(node driver.Node, config *driver.ThrottleConfig) {
+	nodeId := Node(node.GetLabel())
+	now := time.Now()
+
+	n.throttlingLock.Lock()
+	periods := n.throttling[nodeId]
+	// Changing the limits of a throttled node ends its current period.
+	if last := len(periods) - 1; last >= 0 && periods[last].End.IsZero() {
+		periods[last].End = now
+	}
+	if config != nil {
+		periods = append(periods, ThrottlePeriod{Start: now, Config: *config})
+	}
+	n.throttling[nodeId] = periods
+	n.throttlingLock.Unlock()
+
+	n.listenersLock.Lock()
+	defer n.listenersLock.Unlock()
+	for listener := range n.listeners {
+		if l, ok := listener.(ThrottleListener); ok {
+			l.OnThrottlingChange(nodeId, now, config)
+		}
+	}
+}
+
+// GetThrottlePeriods returns the periods in which the bandwidth of the given
+// node was throttled, in chronological order.
+func (n *NodeLogDispatcher) GetThrottlePeriods(node Node) []ThrottlePeriod {
+	n.throttlingLock.Lock()
+	defer n.throttlingLock.Unlock()
+	return append([]ThrottlePeriod{}, n.throttling[node]...)
+}
+
 func (n *NodeLogDispatcher) startDispatcher(nodeId Node, source driver.Node, reader io.ReadCloser, state *nodeLogState) {
 	go func() {
 		defer n.wg.Done()
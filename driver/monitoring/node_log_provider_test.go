@@ -23,6 +23,7 @@ import (
 	"strings"
 	"sync"
 	"testing"
+	"time"
 
 	"github.com/0xsoniclabs/norma/driver"
 	"go.uber.org/mock/gomock"
@@ -31,6 +32,7 @@ import (
 func TestLogsParsersImplements(t *testing.T) {
 	var inst NodeLogDispatcher
 	var _ NodeLogProvider = &inst
+	var _ driver.NodeThrottleListener = &inst
 }
 
 func TestRegisterLogParser(t *testing.T) {
@@ -247,6 +249,71 @@ func TestNodeLogDispatcher_NodeSuspensionNotifiesRestartListeners(t *testing.T)
 	}
 }
 
+func TestNodeLogDispatcher_RecordsThrottlePeriods(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	net := driver.NewMockNetwork(ctrl)
+	net.EXPECT().RegisterListener(gomock.Any())
+	net.EXPECT().GetActiveNodes().AnyTimes().Return([]driver.Node{})
+
+	node := driver.NewMockNode(ctrl)
+	node.EXPECT().GetLabel().AnyTimes().Return(string(Node1TestId))
+
+	reg, err := NewNodeLogDispatcher(net, t.TempDir())
+	if err != nil {
+		t.Fatalf("failed to create log dispatcher: %v", err)
+	}
+	listener := &throttleRecordingListener{}
+	reg.RegisterLogListener(listener)
+
+	slow := driver.ThrottleConfig{Egress: 1_000_000}
+	slower := driver.ThrottleConfig{Egress: 100_000}
+	reg.OnNodeThrottled(node, &slow)
+	reg.OnNodeThrottled(node, &slower)
+	reg.OnNodeThrottled(node, nil)
+
+	periods := reg.GetThrottlePeriods(Node1TestId)
+	if len(periods) != 2 {
+		t.Fatalf("expected two throttle periods, got %v", periods)
+	}
+	if periods[0].Config != slow || periods[1].Config != slower {
+		t.Errorf("unexpected limits of throttle periods: %v", periods)
+	}
+	if !periods[0].End.Equal(periods[1].Start) {
+		t.Errorf("changing the limits must end the previous period: %v", periods)
+	}
+	if periods[1].End.IsZero() || periods[1].End.Before(periods[1].Start) {
+		t.Errorf("lifting the limits must end the last period: %v", periods)
+	}
+	if got := reg.GetThrottlePeriods(Node2TestId); len(got) != 0 {
+		t.Errorf("expected no throttle periods of an untouched node, got %v", got)
+	}
+
+	want := []*driver.ThrottleConfig{&slow, &slower, nil}
+	if len(listener.changes) != len(want) {
+		t.Fatalf("expected %d notifications, got %d", len(want), len(listener.changes))
+	}
+	for i, change := range listener.changes {
+		if change.node != Node1TestId || change.config != want[i] {
+			t.Errorf("unexpected notification %d: %v", i, change)
+		}
+	}
+}
+
+type throttleChange struct {
+	node   Node
+	config *driver.ThrottleConfig
+}
+
+type throttleRecordingListener struct {
+	changes []throttleChange
+}
+
+func (l *throttleRecordingListener) OnBlock(Node, Block) {}
+
+func (l *throttleRecordingListener) OnThrottlingChange(node Node, _ time.Time, config *driver.ThrottleConfig) {
+	l.changes = append(l.changes, throttleChange{node: node, config: config})
+}
+
 type restartRecordingListener struct {
 	mu       sync.Mutex
 	blocks   map[Node][]Block
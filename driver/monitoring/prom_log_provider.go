@@ -228,7 +228,11 @@ func (n *PrometheusLogDispatcher) BeforeNodeRemoval(node driver.Node) {
 	defer n.nodesLock.Unlock()
 
 	nodeId := Node(node.GetLabel())
-	ch := n.nodes[nodeId]
+	ch, found := n.nodes[nodeId]
+	if !found {
+		// the node offers no metrics, so it was never registered
+		return
+	}
 	delete(n.nodes, nodeId)
 	close(ch)
 	// also drain the channel not to trigger more reads
@@ -17,6 +17,7 @@
 package monitoring
 
 import (
+	"fmt"
 	"sync"
 	"sync/atomic"
 	"testing"
@@ -285,6 +286,26 @@ func TestLogsDispatchedShutdownTwice(t *testing.T) {
 	dispatcher.Shutdown() /// should not block, panic, etc
 }
 
+func TestLogsDispatchedIgnoresRemovalOfNodeWithoutMetrics(t *testing.T) {
+	t.Parallel()
+
+	ctrl := gomock.NewController(t)
+	net := driver.NewMockNetwork(ctrl)
+
+	node1 := driver.NewMockNode(ctrl)
+	node1.EXPECT().GetServiceUrl(gomock.Any()).AnyTimes().Return(nil, fmt.Errorf("no metrics"))
+	node1.EXPECT().GetLabel().AnyTimes().Return("A")
+
+	net.EXPECT().RegisterListener(gomock.Any())
+	net.EXPECT().GetActiveNodes().AnyTimes().Return([]driver.Node{})
+
+	dispatcher := NewPrometheusLogDispatcher(net)
+	defer dispatcher.Shutdown()
+
+	dispatcher.AfterNodeCreation(node1)
+	dispatcher.BeforeNodeRemoval(node1) // should not panic
+}
+
 func TestLogsCannotAddListenerAfterShutdown(t *testing.T) {
 	t.Parallel()
 
@@ -39,6 +39,11 @@ import (
 // explicitly.
 const DefaultClientDockerImageName = "sonic:local"
 
+// ClockClientDockerImageName is the image of the clients of
+// DefaultClientDockerImageName built with the clock offset hook, which the
+// clients of nodes with a controllable clock need.
+const ClockClientDockerImageName = "sonic-clock:local"
+
 // DefaultValidators is a default configuration for a single validator.
 func DefaultValidators(name string) Validators {
 	return NewDefaultTestValidators(name, 1)
@@ -129,6 +134,41 @@ type Network interface {
 	// again (e.g. sonicd was restarted after heal). Monitoring sensors
 	// will resume polling this node.
 	ResumeNode(node Node)
+
+	// PartitionNodes cuts the connectivity between the given groups of
+	// nodes: nodes within a group stay connected, nodes in different groups
+	// can no longer exchange any traffic. Nodes not listed in any group are
+	// unaffected, and the nodes remain reachable for RPC and monitoring.
+	// Only one partition can be in place at a time.
+	PartitionNodes(ctx context.Context, groups [][]Node) error
+
+	// HealPartition restores the connectivity cut by PartitionNodes and
+	// re-establishes the peer connections across the former cut.
+	HealPartition(ctx context.Context) error
+
+	// SetLink adds a link rule on top of the ones configured so far and
+	// applies it to all current nodes it covers, as well as to nodes
+	// created later.
+	SetLink(ctx context.Context, rule LinkRule) error
+
+	// ThrottleNode limits the bandwidth of the given node, replacing any
+	// limits set before. Listeners implementing NodeThrottleListener are
+	// notified once the limits are in place.
+	ThrottleNode(ctx context.Context, node Node, config ThrottleConfig) error
+
+	// UnthrottleNode lifts the bandwidth limits of the given node, which
+	// must have been throttled by ThrottleNode.
+	UnthrottleNode(ctx context.Context, node Node) error
+
+	// ConnectNodes peers the two given nodes, whether or not the topology
+	// of the network does. The peering outlives restarts of either node
+	// until it is lifted by DisconnectNodes.
+	ConnectNodes(ctx context.Context, a, b Node) error
+
+	// DisconnectNodes drops the peering of the two given nodes, whether or
+	// not the topology of the network includes it. The nodes stay apart
+	// across restarts until ConnectNodes peers them again.
+	DisconnectNodes(ctx context.Context, a, b Node) error
 }
 
 // NetworkConfig is a collection of network parameters to be used by factories
@@ -138,8 +178,18 @@ type NetworkConfig struct {
 	Validators Validators
 	// RoundTripTime is the average round trip time between nodes in the network.
 	RoundTripTime time.Duration
+	// Links overrides the conditions of individual links between nodes. A
+	// link covered by none of the rules has a delay of half the RoundTripTime
+	// in each direction; for a link covered by several, the last one wins.
+	Links []LinkRule
+	// Topology determines which nodes are peered with each other. Nodes of
+	// any topology other than a full mesh do not discover peers on their own.
+	Topology Topology
 	// NetworkRules is a map of network rules to be applied to the network.
 	NetworkRules NetworkRules
+	// InitialState is the state the genesis is pre-populated with, on top of
+	// the accounts every network starts with; nil adds none.
+	InitialState *genesis.InitialState
 	// OutputDir is the directory where temp data are written.
 	OutputDir string
 	// ClientImages lists the images of the clients the scenario starts once the
@@ -170,6 +220,14 @@ type NetworkListener interface {
 	AfterApplicationCreation(Application)
 }
 
+// NodeThrottleListener is an optional interface a NetworkListener may
+// implement to be told when the bandwidth of a node gets limited or lifted.
+type NodeThrottleListener interface {
+	// OnNodeThrottled is called after the limits of a node changed, with the
+	// limits now in place, or nil once they have been lifted.
+	OnNodeThrottled(node Node, config *ThrottleConfig)
+}
+
 // TransactionSource identifies the load generator a transaction originates
 // from: the application it belongs to and which of the application's users
 // created it.
@@ -202,6 +260,16 @@ type NodeConfig struct {
 	Image          string
 	DataVolume     *string
 	ExtraArguments string
+	// ClockOffset is the offset of the node's clock from the host's clock.
+	// Nil leaves the clock untouched, and it cannot be offset later on.
+	ClockOffset *time.Duration
+	// Resources limits the host resources the node may use.
+	Resources Resources
+	// Container names the container the node's client shares with the
+	// clients of other nodes; empty if the node has a container of its own.
+	Container string
+	// Memory tunes the memory the node's client uses.
+	Memory ClientMemory
 }
 
 type ApplicationConfig struct {
@@ -174,7 +174,7 @@ func GetDelegatorStake(
 // address using a plain value-transfer transaction.
 func FundAccount(
 	ctx context.Context,
-	client rpc.Client,
+	client ContractBackend,
 	source *ecdsa.PrivateKey,
 	dest common.Address,
 	amount uint64,
@@ -8,20 +8,24 @@ import (
 	"time"
 
 	"github.com/0xsoniclabs/norma/driver/rpc"
-	"github.com/0xsoniclabs/sonic/evmcore"
 	"github.com/0xsoniclabs/sonic/gossip/contract/driverauth100"
 	"github.com/0xsoniclabs/sonic/gossip/contract/sfc100"
-	"github.com/0xsoniclabs/sonic/opera"
 	"github.com/0xsoniclabs/sonic/opera/contracts/driverauth"
 	"github.com/0xsoniclabs/sonic/opera/contracts/sfc"
-	"github.com/ethereum/go-ethereum/accounts/abi/bind"
 	"github.com/ethereum/go-ethereum/common/hexutil"
 	"github.com/ethereum/go-ethereum/core/types"
 )
 
-// AdvanceEpoch triggers the sealing of an epoch advancing it by the given number.
+// AdvanceEpoch triggers the sealing of an epoch advancing it by the given number
+// on a network started from a fake genesis.
 // The function blocks until the final epoch has been reached.
 func AdvanceEpoch(ctx context.Context, client rpc.Client, epochIncrement int) error {
+	return AdvanceEpochAs(ctx, client, FakeNetSystemTxSigner(), epochIncrement)
+}
+
+// AdvanceEpochAs is AdvanceEpoch for a network whose DriverAuth contract is
+// owned by the given signer.
+func AdvanceEpochAs(ctx context.Context, client rpc.Client, signer SystemTxSigner, epochIncrement int) error {
 	contract, err := driverauth100.NewContract(driverauth.ContractAddress, client)
 	if err != nil {
 		return fmt.Errorf("failed to get driver auth contract representation; %v", err)
@@ -33,17 +37,10 @@ func AdvanceEpoch(ctx context.Context, client rpc.Client, epochIncrement int) er
 	}
 	slog.Info("advancing epoch", "current", uint64(currentEpoch), "increment", epochIncrement)
 
-	originalRules := opera.FakeNetRules(opera.GetSonicUpgrades())
-
-	// Use Fake ID for the network
-	// Driver owner is the first validator from the list i.e., index 1 (defined in genesis export in genesis.GenerateJsonGenesis)
-	txOpts, err := bind.NewKeyedTransactorWithChainID(evmcore.FakeKey(1), big.NewInt(int64(originalRules.NetworkID)))
+	txOpts, err := signer.transactOpts(ctx)
 	if err != nil {
-		return fmt.Errorf("failed to create txOpts; %v", err)
+		return err
 	}
-	txOpts.Context = ctx
-	txOpts.GasTipCap = systemTxGasTipCap
-	txOpts.GasLimit = systemTxGasLimit
 
 	tx, err := contract.AdvanceEpochs(txOpts, big.NewInt(int64(epochIncrement)))
 	if err != nil {
@@ -141,6 +138,35 @@ func GetActiveValidatorIDs(client rpc.Client) ([]int, error) {
 	return getValidatorIDsOfEpoch(sfcContract, epoch)
 }
 
+// GetValidatorStakes returns the stakes, in S, of the validators taking part
+// in consensus in the epoch currently being built, by validator id. They
+// are the weights the validators carry in the epoch.
+func GetValidatorStakes(client rpc.Client) (map[int]uint64, error) {
+	sfcContract, err := sfc100.NewContract(sfc.ContractAddress, client)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get SFC contract representation; %v", err)
+	}
+
+	epoch, err := sfcContract.CurrentEpoch(nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get current epoch: %w", err)
+	}
+	ids, err := getValidatorIDsOfEpoch(sfcContract, epoch)
+	if err != nil {
+		return nil, err
+	}
+
+	stakes := make(map[int]uint64, len(ids))
+	for _, id := range ids {
+		stake, err := sfcContract.GetEpochReceivedStake(nil, epoch, big.NewInt(int64(id)))
+		if err != nil {
+			return nil, fmt.Errorf("failed to get stake of validator %d in epoch %v: %w", id, epoch, err)
+		}
+		stakes[id] = weiToStake(stake)
+	}
+	return stakes, nil
+}
+
 // getValidatorIDsOfEpoch returns the ids of the validators of the given epoch.
 // Callers that already know the epoch use this to avoid reading it twice, which
 // would let the two reads straddle a seal and report a mismatched pair.
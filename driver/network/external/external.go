@@ -0,0 +1,496 @@
+// Copyright 2024 Fantom Foundation
+// This file is part of Norma System Testing Infrastructure for Sonic.
+//
+// Norma is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// Norma is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with Norma. If not, see <http://www.gnu.org/licenses/>.
+
+package external
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"math/rand"
+	"strings"
+	"sync"
+	"sync/atomic"
+
+	"github.com/0xsoniclabs/norma/driver"
+	"github.com/0xsoniclabs/norma/driver/network"
+	"github.com/0xsoniclabs/norma/driver/network/rpc"
+	"github.com/0xsoniclabs/norma/driver/parser"
+	rpcdriver "github.com/0xsoniclabs/norma/driver/rpc"
+	"github.com/0xsoniclabs/norma/load/app"
+	"github.com/0xsoniclabs/norma/load/controller"
+	"github.com/0xsoniclabs/norma/load/shaper"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+// ExternalNetwork is a network of clients running outside of Norma, such as
+// a devnet, which it attaches to through their RPC interfaces. It runs
+// applications against the network, monitors it and checks it, and can
+// update the network rules and advance epochs, but the nodes are not under
+// its control: steps starting, stopping or otherwise acting on nodes are not
+// supported, and neither are the checks relying on the nodes' logs or on
+// knowing which validators they run.
+//
+// The network rules of the configuration are those of a genesis, which an
+// external network is past, so they are not applied; an updateRules step
+// changes the rules of the running network instead.
+//
+// A funded treasury account pays for the applications. It also signs the
+// rule updates and epoch advances, which the network only accepts if the
+// account owns its DriverAuth contract.
+type ExternalNetwork struct {
+	ctx            context.Context
+	config         driver.NetworkConfig
+	nodes          []*Node
+	primaryAccount *app.Account
+	signer         network.SystemTxSigner
+
+	// apps maintains a list of all applications created on the network.
+	apps []driver.Application
+
+	// appsMutex synchronizes access to the list of applications.
+	appsMutex sync.Mutex
+
+	// nextAppId is the id to use for next created applications.
+	nextAppId atomic.Uint32
+
+	// listeners is the set of registered NetworkListeners.
+	listeners map[driver.NetworkListener]bool
+
+	// listenerMutex is synching access to listeners
+	listenerMutex sync.Mutex
+
+	rpcWorkerPool *rpc.RpcWorkerPool
+
+	// a context for app management operations on the network
+	appContext app.AppContext
+
+	// appContextMu guards lazy initialization of appContext.
+	appContextMu sync.Mutex
+}
+
+// Config locates an external network and the account paying for the load
+// sent to it.
+type Config struct {
+	// Endpoints lists the RPC interfaces of the nodes to use, each of which
+	// becomes a node of the network.
+	Endpoints []Endpoint
+	// TreasuryKey is the hex encoded private key of a funded account.
+	TreasuryKey string
+}
+
+// NewExternalNetwork attaches to the nodes of the given external network.
+// It fails for configurations requiring control over the network's nodes or
+// its genesis.
+func NewExternalNetwork(ctx context.Context, config *driver.NetworkConfig, external Config) (*ExternalNetwork, error) {
+	if err := checkNetworkConfig(config); err != nil {
+		return nil, err
+	}
+	if len(external.Endpoints) == 0 {
+		return nil, fmt.Errorf("an external network requires at least one endpoint")
+	}
+	nodes := make([]*Node, 0, len(external.Endpoints))
+	for i, endpoint := range external.Endpoints {
+		if err := endpoint.check(); err != nil {
+			return nil, fmt.Errorf("endpoint %d: %w", i+1, err)
+		}
+		nodes = append(nodes, &Node{
+			label:    fmt.Sprintf("external-%d", i),
+			endpoint: endpoint,
+		})
+	}
+
+	treasuryKey := strings.TrimPrefix(external.TreasuryKey, "0x")
+	key, err := crypto.HexToECDSA(treasuryKey)
+	if err != nil {
+		return nil, fmt.Errorf("invalid treasury key; %v", err)
+	}
+
+	net := &ExternalNetwork{
+		ctx:       ctx,
+		config:    *config,
+		nodes:     nodes,
+		apps:      []driver.Application{},
+		listeners: map[driver.NetworkListener]bool{},
+	}
+
+	// Transactions are signed for the chain the nodes are running.
+	client, err := net.DialRandomRpc()
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect to network: %w", err)
+	}
+	defer client.Close()
+	chainId, err := client.ChainID(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get chain ID: %w", err)
+	}
+	net.signer = network.SystemTxSigner{Key: key, ChainID: chainId}
+
+	// Create chain account, which will be used for the initialization
+	net.primaryAccount, err = app.NewAccount(0, treasuryKey, chainId.Int64())
+	if err != nil {
+		return nil, fmt.Errorf("failed to create primary account; %v", err)
+	}
+
+	// The nodes are up already, so the RPC pool starts its workers for them
+	// right away.
+	net.rpcWorkerPool = rpc.NewRpcWorkerPool(ctx)
+	for _, node := range nodes {
+		net.rpcWorkerPool.AfterNodeCreation(node)
+	}
+	net.RegisterListener(net.rpcWorkerPool)
+
+	return net, nil
+}
+
+// checkNetworkConfig rejects the parts of a network configuration that need
+// control over the nodes.
+func checkNetworkConfig(config *driver.NetworkConfig) error {
+	if len(config.Validators) > 0 {
+		return unsupported("starting validators")
+	}
+	if config.RoundTripTime > 0 || len(config.Links) > 0 {
+		return unsupported("link shaping")
+	}
+	if !config.Topology.IsFullMesh() {
+		return unsupported("a topology other than a full mesh")
+	}
+	return nil
+}
+
+// unsupported returns the error of a feature the external backend lacks.
+func unsupported(feature string) error {
+	return fmt.Errorf("%s is not supported by the external backend", feature)
+}
+
+// supportedSteps are the step functions an external network supports.
+var supportedSteps = map[parser.StepFunction]bool{
+	parser.FuncRunApp:       true,
+	parser.FuncStopApp:      true,
+	parser.FuncChecks:       true,
+	parser.FuncUpdateRules:  true,
+	parser.FuncAdvanceEpoch: true,
+	parser.FuncWaitForEpoch: true,
+	parser.FuncWaitFor:      true,
+}
+
+// unsupportedChecks are the check functions an external network does not
+// support, with the reason why.
+var unsupportedChecks = map[parser.StepFunction]string{
+	parser.FuncCheckBlockGasRate:     "reads the nodes' logs",
+	parser.FuncCheckBlocksHalted:     "reads the nodes' logs",
+	parser.FuncCheckBlocksProduced:   "reads the nodes' logs",
+	parser.FuncCheckEventThrottled:   "needs to know the validators of the nodes",
+	parser.FuncCheckValidatorsActive: "needs to know the validators of the nodes",
+	parser.FuncCheckValidatorSlashed: "needs to know the validators of the nodes",
+}
+
+// CheckScenario reports every step of the given scenario an external network
+// cannot run, so a scenario fails before it starts rather than midway. The
+// genesis of an external network is long past, so its initial state cannot be
+// chosen either.
+func CheckScenario(scenario *parser.Scenario) error {
+	var errs []error
+	if scenario.InitialState != nil {
+		errs = append(errs, unsupported("an InitialState"))
+	}
+	for i, step := range scenario.Steps {
+		// The branches of a parallel step and the steps of a schedule step
+		// are held to the same terms.
+		for _, step := range step.Steps() {
+			if !supportedSteps[step.Function] {
+				errs = append(errs, fmt.Errorf("step %d (%s): %w", i+1, step.Function,
+					unsupported("a step acting on nodes")))
+				continue
+			}
+			for j, check := range step.SubChecks {
+				if reason, found := unsupportedChecks[check.Function]; found {
+					errs = append(errs, fmt.Errorf("step %d (%s): check %d (%s) %s, which is not supported by the external backend",
+						i+1, step.Function, j+1, check.Function, reason))
+				}
+			}
+		}
+	}
+	return errors.Join(errs...)
+}
+
+func (n *ExternalNetwork) CreateNode(*driver.NodeConfig) (driver.Node, error) {
+	return nil, unsupported("creating a node")
+}
+
+func (n *ExternalNetwork) RemoveNode(driver.Node) error {
+	return unsupported("removing a node")
+}
+
+func (n *ExternalNetwork) ReconnectNode(context.Context, driver.Node) error {
+	return unsupported("reconnecting a node")
+}
+
+func (n *ExternalNetwork) SendTransaction(tx *types.Transaction, source driver.TransactionSource) {
+	n.rpcWorkerPool.SendTransaction(tx, source)
+}
+
+func (n *ExternalNetwork) RegisterTransactionObserver(observer driver.TransactionObserver) {
+	n.rpcWorkerPool.RegisterObserver(observer)
+}
+
+func (n *ExternalNetwork) DialRandomRpc() (rpcdriver.Client, error) {
+	nodes := n.GetActiveNodes()
+	if len(nodes) == 0 {
+		return nil, driver.ErrEmptyNetwork
+	}
+	for _, i := range rand.Perm(len(nodes)) {
+		chosen := nodes[i]
+		client, err := chosen.DialRpc(n.ctx)
+		if err != nil {
+			slog.Warn("node unreachable in DialRandomRpc, skipping",
+				"node", chosen.GetLabel(), "error", err)
+			continue
+		}
+		return client, nil
+	}
+	return nil, fmt.Errorf("no reachable node found among %d active nodes", len(nodes))
+}
+
+func (n *ExternalNetwork) ApplyNetworkRules(ctx context.Context, rules driver.NetworkRules) error {
+	client, err := n.DialRandomRpc()
+	if err != nil {
+		return fmt.Errorf("failed to connect to network: %w", err)
+	}
+	defer client.Close()
+
+	return network.ApplyNetworkRulesAs(ctx, client, n.signer, rules)
+}
+
+func (n *ExternalNetwork) AdvanceEpoch(ctx context.Context, epochIncrement int) error {
+	client, err := n.DialRandomRpc()
+	if err != nil {
+		return fmt.Errorf("failed to connect to network: %w", err)
+	}
+	defer client.Close()
+
+	return network.AdvanceEpochAs(ctx, client, n.signer, epochIncrement)
+}
+
+func (n *ExternalNetwork) WaitForEpochChange(ctx context.Context) error {
+	client, err := n.DialRandomRpc()
+	if err != nil {
+		return fmt.Errorf("failed to connect to network: %w", err)
+	}
+	defer client.Close()
+
+	return network.WaitForEpochChange(ctx, client)
+}
+
+type externalApplication struct {
+	name       string
+	controller *controller.AppController
+	config     *driver.ApplicationConfig
+	cancel     context.CancelFunc
+	done       *sync.WaitGroup
+}
+
+func (a *externalApplication) Start(ctx context.Context) error {
+	ctx, cancel := context.WithCancel(ctx)
+	a.cancel = cancel
+	a.done.Add(1)
+	go func() {
+		defer a.done.Done()
+		if err := a.controller.Run(ctx); err != nil {
+			slog.Error("failed to run load app", "error", err)
+		}
+	}()
+	return nil
+}
+
+func (a *externalApplication) Stop() error {
+	if a.cancel != nil {
+		a.cancel()
+	}
+	a.cancel = nil
+	a.done.Wait()
+	return nil
+}
+
+func (a *externalApplication) Config() *driver.ApplicationConfig {
+	return a.config
+}
+
+func (a *externalApplication) GetNumberOfUsers() int {
+	return a.controller.GetNumberOfUsers()
+}
+
+func (a *externalApplication) GetSentTransactions(user int) (uint64, error) {
+	return a.controller.GetTransactionsSentBy(user)
+}
+
+func (a *externalApplication) GetReceivedTransactions() (uint64, error) {
+	return a.controller.GetReceivedTransactions()
+}
+
+// ensureAppContext initializes the appContext lazily on first use.
+func (n *ExternalNetwork) ensureAppContext() error {
+	n.appContextMu.Lock()
+	defer n.appContextMu.Unlock()
+	if n.appContext != nil {
+		return nil
+	}
+	appCtx, err := app.NewContext(n.ctx, n, n.primaryAccount, n.config.NetworkRules)
+	if err != nil {
+		return fmt.Errorf("failed to create app context: %w", err)
+	}
+	n.appContext = appCtx
+	return nil
+}
+
+func (n *ExternalNetwork) CreateApplication(ctx context.Context, config *driver.ApplicationConfig) (driver.Application, error) {
+	if err := n.ensureAppContext(); err != nil {
+		return nil, fmt.Errorf("failed to initialize app context: %w", err)
+	}
+
+	appId := n.nextAppId.Add(1)
+	application, err := app.NewApplication(config.Type, n.appContext, 0, appId)
+	if err != nil {
+		return nil, fmt.Errorf("failed to initialize on-chain app; %v", err)
+	}
+
+	sh, err := shaper.ParseRate(config.Rate)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse shaper; %v", err)
+	}
+
+	appController, err := controller.NewAppController(config.Name, application, sh, config.Users, n.appContext, n)
+	if err != nil {
+		return nil, err
+	}
+
+	app := &externalApplication{
+		name:       config.Name,
+		controller: appController,
+		config:     config,
+		done:       &sync.WaitGroup{},
+	}
+
+	n.appsMutex.Lock()
+	n.apps = append(n.apps, app)
+	n.appsMutex.Unlock()
+
+	n.listenerMutex.Lock()
+	for listener := range n.listeners {
+		listener.AfterApplicationCreation(app)
+	}
+	n.listenerMutex.Unlock()
+
+	return app, nil
+}
+
+func (n *ExternalNetwork) GetActiveNodes() []driver.Node {
+	res := make([]driver.Node, 0, len(n.nodes))
+	for _, node := range n.nodes {
+		res = append(res, node)
+	}
+	return res
+}
+
+func (n *ExternalNetwork) GetActiveApplications() []driver.Application {
+	n.appsMutex.Lock()
+	defer n.appsMutex.Unlock()
+	return n.apps
+}
+
+func (n *ExternalNetwork) RegisterListener(listener driver.NetworkListener) {
+	n.listenerMutex.Lock()
+	n.listeners[listener] = true
+	n.listenerMutex.Unlock()
+}
+
+func (n *ExternalNetwork) UnregisterListener(listener driver.NetworkListener) {
+	n.listenerMutex.Lock()
+	delete(n.listeners, listener)
+	n.listenerMutex.Unlock()
+}
+
+// SuspendNode notifies listeners as for the LocalNetwork; the node itself is
+// not affected.
+func (n *ExternalNetwork) SuspendNode(node driver.Node) {
+	n.listenerMutex.Lock()
+	for listener := range n.listeners {
+		listener.BeforeNodeRemoval(node)
+	}
+	n.listenerMutex.Unlock()
+}
+
+// ResumeNode notifies listeners as for the LocalNetwork; the node itself is
+// not affected.
+func (n *ExternalNetwork) ResumeNode(node driver.Node) {
+	n.listenerMutex.Lock()
+	for listener := range n.listeners {
+		listener.AfterNodeCreation(node)
+	}
+	n.listenerMutex.Unlock()
+}
+
+func (n *ExternalNetwork) PartitionNodes(context.Context, [][]driver.Node) error {
+	return unsupported("partitioning the network")
+}
+
+func (n *ExternalNetwork) HealPartition(context.Context) error {
+	return unsupported("healing a partition")
+}
+
+func (n *ExternalNetwork) SetLink(context.Context, driver.LinkRule) error {
+	return unsupported("link shaping")
+}
+
+func (n *ExternalNetwork) ThrottleNode(context.Context, driver.Node, driver.ThrottleConfig) error {
+	return unsupported("throttling a node")
+}
+
+func (n *ExternalNetwork) UnthrottleNode(context.Context, driver.Node) error {
+	return unsupported("throttling a node")
+}
+
+func (n *ExternalNetwork) ConnectNodes(context.Context, driver.Node, driver.Node) error {
+	return unsupported("peering nodes")
+}
+
+func (n *ExternalNetwork) DisconnectNodes(context.Context, driver.Node, driver.Node) error {
+	return unsupported("peering nodes")
+}
+
+// Shutdown stops all applications and detaches from the network; the nodes
+// keep running.
+func (n *ExternalNetwork) Shutdown() error {
+	var errs []error
+
+	n.appsMutex.Lock()
+	for _, app := range n.apps {
+		if err := app.Stop(); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	n.apps = n.apps[:0]
+	n.appsMutex.Unlock()
+
+	if n.appContext != nil {
+		n.appContext.Close()
+	}
+
+	errs = append(errs, n.rpcWorkerPool.Close())
+	return errors.Join(errs...)
+}
@@ -0,0 +1,212 @@
+// Copyright 2024 Fantom Foundation
+// This file is part of Norma System Testing Infrastructure for Sonic.
+//
+// Norma is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// Norma is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with Norma. If not, see <http://www.gnu.org/licenses/>.
+
+package external
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/0xsoniclabs/norma/driver"
+	"github.com/0xsoniclabs/norma/driver/network/sim"
+	"github.com/0xsoniclabs/norma/driver/node"
+	"github.com/0xsoniclabs/norma/driver/parser"
+	"github.com/0xsoniclabs/norma/genesis"
+	"github.com/stretchr/testify/require"
+)
+
+// treasuryKey is the key of the first fakenet validator, which is funded on
+// the simulated networks the tests attach to.
+const treasuryKey = "163f5f0f9a621d72fedd85ffca3d08d131ab4e812181e0d30ffd1c885d20aac7"
+
+func TestExternalNetworkIsNetwork(t *testing.T) {
+	var net ExternalNetwork
+	var _ driver.Network = &net
+}
+
+func TestNodeIsNode(t *testing.T) {
+	var n Node
+	var _ driver.Node = &n
+}
+
+// startSimNode starts a simulated network with a single running validator,
+// standing in for a network Norma does not control, and returns the URL of
+// its RPC interface.
+func startSimNode(t *testing.T) string {
+	t.Helper()
+	net, err := sim.NewSimNetwork(t.Context(), &driver.NetworkConfig{
+		Validators: driver.NewDefaultValidators(1),
+	})
+	require.NoError(t, err)
+	t.Cleanup(func() { require.NoError(t, net.Shutdown()) })
+
+	id := 1
+	simNode, err := net.CreateNode(&driver.NodeConfig{
+		Name:        "validator",
+		Validator:   true,
+		ValidatorId: &id,
+	})
+	require.NoError(t, err)
+	url, err := simNode.GetServiceUrl(&node.OperaRpcService)
+	require.NoError(t, err)
+	return string(*url)
+}
+
+func newTestNetwork(t *testing.T, endpoints ...Endpoint) *ExternalNetwork {
+	t.Helper()
+	net, err := NewExternalNetwork(t.Context(), &driver.NetworkConfig{}, Config{
+		Endpoints:   endpoints,
+		TreasuryKey: treasuryKey,
+	})
+	require.NoError(t, err)
+	t.Cleanup(func() { require.NoError(t, net.Shutdown()) })
+	return net
+}
+
+func TestExternalNetwork_AttachesToNodesOfEndpoints(t *testing.T) {
+	url := startSimNode(t)
+	net := newTestNetwork(t, Endpoint{RpcUrl: url}, Endpoint{WsUrl: strings.Replace(url, "http", "ws", 1)})
+
+	nodes := net.GetActiveNodes()
+	require.Len(t, nodes, 2)
+	for i, node := range nodes {
+		require.Equal(t, []string{"external-0", "external-1"}[i], node.GetLabel())
+		require.Equal(t, "127.0.0.1", node.Hostname())
+		require.True(t, node.IsRunning())
+		require.Nil(t, node.GetValidatorId())
+
+		client, err := node.DialRpc(t.Context())
+		require.NoError(t, err)
+		chainId, err := client.ChainID(t.Context())
+		client.Close()
+		require.NoError(t, err)
+		require.Equal(t, net.signer.ChainID, chainId)
+	}
+}
+
+func TestExternalNetwork_ServesRpcAndWebsocketFromEitherUrl(t *testing.T) {
+	n := &Node{label: "external-0", endpoint: Endpoint{RpcUrl: "http://host:8545"}}
+	url, err := n.GetServiceUrl(&node.OperaWsService)
+	require.NoError(t, err)
+	require.Equal(t, driver.URL("http://host:8545"), *url)
+
+	n = &Node{label: "external-0", endpoint: Endpoint{WsUrl: "ws://host:8546"}}
+	url, err = n.GetServiceUrl(&node.OperaRpcService)
+	require.NoError(t, err)
+	require.Equal(t, driver.URL("ws://host:8546"), *url)
+
+	_, err = n.GetServiceUrl(&node.OperaDebugService)
+	require.ErrorContains(t, err, "does not offer service")
+}
+
+func TestExternalNetwork_NodeLifecycleIsRejected(t *testing.T) {
+	net := newTestNetwork(t, Endpoint{RpcUrl: startSimNode(t)})
+	target := net.GetActiveNodes()[0]
+
+	_, err := net.CreateNode(&driver.NodeConfig{Name: "new"})
+	require.ErrorContains(t, err, "not supported by the external backend")
+	require.ErrorContains(t, net.RemoveNode(target), "not supported by the external backend")
+	require.ErrorContains(t, net.PartitionNodes(t.Context(), [][]driver.Node{{target}, {}}), "not supported by the external backend")
+	require.ErrorContains(t, net.ThrottleNode(t.Context(), target, driver.ThrottleConfig{}), "not supported by the external backend")
+	require.ErrorContains(t, target.Stop(t.Context()), "not supported by the external backend")
+	require.ErrorContains(t, target.Kill(t.Context()), "not supported by the external backend")
+	_, err = target.StreamLog(t.Context())
+	require.ErrorContains(t, err, "not accessible")
+	require.Len(t, net.GetActiveNodes(), 1)
+}
+
+func TestNewExternalNetwork_RejectsInvalidConfigs(t *testing.T) {
+	url := startSimNode(t)
+	tests := map[string]struct {
+		network  driver.NetworkConfig
+		external Config
+		want     string
+	}{
+		"validators": {
+			network:  driver.NetworkConfig{Validators: driver.NewDefaultValidators(1)},
+			external: Config{Endpoints: []Endpoint{{RpcUrl: url}}, TreasuryKey: treasuryKey},
+			want:     "starting validators is not supported",
+		},
+		"no endpoints": {
+			external: Config{TreasuryKey: treasuryKey},
+			want:     "requires at least one endpoint",
+		},
+		"empty endpoint": {
+			external: Config{Endpoints: []Endpoint{{}}, TreasuryKey: treasuryKey},
+			want:     "neither an RPC nor a websocket URL",
+		},
+		"websocket URL as RPC URL": {
+			external: Config{Endpoints: []Endpoint{{RpcUrl: "ws://host:8546"}}, TreasuryKey: treasuryKey},
+			want:     "expected scheme http or https",
+		},
+		"invalid treasury key": {
+			external: Config{Endpoints: []Endpoint{{RpcUrl: url}}, TreasuryKey: "0x1234"},
+			want:     "invalid treasury key",
+		},
+		"unreachable endpoint": {
+			external: Config{Endpoints: []Endpoint{{RpcUrl: "http://127.0.0.1:1"}}, TreasuryKey: treasuryKey},
+			want:     "failed to get chain ID",
+		},
+	}
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			_, err := NewExternalNetwork(t.Context(), &test.network, test.external)
+			require.ErrorContains(t, err, test.want)
+		})
+	}
+}
+
+func TestCheckScenario_ReportsStepsActingOnNodes(t *testing.T) {
+	scenario := &parser.Scenario{Steps: []parser.Step{
+		{Function: parser.FuncRunApp},
+		{Function: parser.FuncStartNode},
+		{Function: parser.FuncUpdateRules},
+		{Function: parser.FuncChecks, SubChecks: []parser.CheckSpec{
+			{Function: parser.FuncCheckBlockHashes},
+			{Function: parser.FuncCheckBlocksProduced},
+			{Function: parser.FuncCheckValidatorsActive},
+		}},
+		{Function: parser.FuncAdvanceEpoch},
+		{Function: parser.FuncStopApp},
+	}}
+
+	err := CheckScenario(scenario)
+	require.Error(t, err)
+	lines := strings.Split(err.Error(), "\n")
+	require.Len(t, lines, 3)
+	require.Contains(t, lines[0], "step 2 (startNode)")
+	require.Contains(t, lines[1], "step 4 (checks): check 2 (blocksProduced) reads the nodes' logs")
+	require.Contains(t, lines[2], "step 4 (checks): check 3 (validatorsActive) needs to know the validators")
+}
+
+func TestCheckScenario_AcceptsLoadAndChecks(t *testing.T) {
+	scenario := &parser.Scenario{Steps: []parser.Step{
+		{Function: parser.FuncRunApp},
+		{Function: parser.FuncWaitFor},
+		{Function: parser.FuncChecks, SubChecks: []parser.CheckSpec{
+			{Function: parser.FuncCheckBlockHeights},
+			{Function: parser.FuncCheckNetworkRules},
+		}},
+		{Function: parser.FuncStopApp},
+	}}
+	require.NoError(t, CheckScenario(scenario))
+}
+
+func TestCheckScenario_ReportsInitialState(t *testing.T) {
+	scenario := &parser.Scenario{InitialState: &genesis.InitialState{Accounts: 10}}
+	err := CheckScenario(scenario)
+	require.ErrorContains(t, err, "InitialState is not supported by the external backend")
+}
@@ -0,0 +1,176 @@
+// Copyright 2024 Fantom Foundation
+// This file is part of Norma System Testing Infrastructure for Sonic.
+//
+// Norma is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// Norma is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with Norma. If not, see <http://www.gnu.org/licenses/>.
+
+package external
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/url"
+
+	"github.com/0xsoniclabs/norma/driver"
+	"github.com/0xsoniclabs/norma/driver/network"
+	"github.com/0xsoniclabs/norma/driver/node"
+	rpcdriver "github.com/0xsoniclabs/norma/driver/rpc"
+	"github.com/ethereum/go-ethereum/rpc"
+)
+
+// Node implements the driver's Node interface for a client Norma did not
+// start. All it knows about the client is where its RPC interface is served,
+// so it offers neither a log, nor metrics, nor control over the client's
+// lifecycle; which validator the client runs, if any, is unknown as well.
+type Node struct {
+	label    string
+	endpoint Endpoint
+}
+
+// Endpoint locates the RPC interface of a client. One of the URLs may be left
+// empty, in which case the other one is used for both, as either transport
+// serves the same API.
+type Endpoint struct {
+	RpcUrl string
+	WsUrl  string
+}
+
+// check makes sure the endpoint has at least one URL, and that its URLs use
+// the schemes of their transports.
+func (e Endpoint) check() error {
+	if e.RpcUrl == "" && e.WsUrl == "" {
+		return fmt.Errorf("endpoint has neither an RPC nor a websocket URL")
+	}
+	for _, u := range []struct{ url, transport string }{
+		{e.RpcUrl, "http"}, {e.WsUrl, "ws"},
+	} {
+		if u.url == "" {
+			continue
+		}
+		parsed, err := url.Parse(u.url)
+		if err != nil {
+			return fmt.Errorf("invalid URL %q: %w", u.url, err)
+		}
+		if parsed.Scheme != u.transport && parsed.Scheme != u.transport+"s" {
+			return fmt.Errorf("invalid URL %q: expected scheme %s or %ss", u.url, u.transport, u.transport)
+		}
+	}
+	return nil
+}
+
+func (n *Node) GetLabel() string {
+	return n.label
+}
+
+func (n *Node) IsExpectedFailure() bool {
+	return false
+}
+
+// Hostname returns the host serving the node's RPC interface.
+func (n *Node) Hostname() string {
+	address := n.endpoint.RpcUrl
+	if address == "" {
+		address = n.endpoint.WsUrl
+	}
+	parsed, err := url.Parse(address)
+	if err != nil {
+		return ""
+	}
+	return parsed.Hostname()
+}
+
+// MetricsPort returns 0, as the metrics of an external node are unknown.
+func (n *Node) MetricsPort() int {
+	return 0
+}
+
+// IsRunning returns true, as an external node cannot be stopped.
+func (n *Node) IsRunning() bool {
+	return true
+}
+
+func (n *Node) GetNodeID() (driver.NodeID, error) {
+	rpcClient, err := n.DialRpc(context.Background())
+	if err != nil {
+		return "", err
+	}
+	defer rpcClient.Close()
+	var result struct {
+		Enode string
+	}
+	if err := rpcClient.Call(&result, "admin_nodeInfo"); err != nil {
+		return "", fmt.Errorf("failed to get node info of %s; %v", n.label, err)
+	}
+	return driver.NodeID(result.Enode), nil
+}
+
+// GetValidatorId returns nil, as the validator run by an external node, if
+// any, is unknown.
+func (n *Node) GetValidatorId() *int {
+	return nil
+}
+
+// GetServiceUrl returns the URL of the RPC or websocket service; the node
+// offers no other service.
+func (n *Node) GetServiceUrl(service *network.ServiceDescription) (*driver.URL, error) {
+	var address string
+	switch service.Name {
+	case node.OperaRpcService.Name:
+		address = n.endpoint.RpcUrl
+		if address == "" {
+			address = n.endpoint.WsUrl
+		}
+	case node.OperaWsService.Name:
+		address = n.endpoint.WsUrl
+		if address == "" {
+			address = n.endpoint.RpcUrl
+		}
+	default:
+		return nil, fmt.Errorf("node %s does not offer service %s", n.label, service.Name)
+	}
+	url := driver.URL(address)
+	return &url, nil
+}
+
+func (n *Node) DialRpc(ctx context.Context) (rpcdriver.Client, error) {
+	url, err := n.GetServiceUrl(&node.OperaRpcService)
+	if err != nil {
+		return nil, err
+	}
+	rpcClient, err := rpc.DialContext(ctx, string(*url))
+	if err != nil {
+		return nil, fmt.Errorf("failed to dial RPC for node %s; %v", n.label, err)
+	}
+	return rpcdriver.WrapRpcClient(rpcClient), nil
+}
+
+// StreamLog fails, as the log of an external node is not accessible.
+func (n *Node) StreamLog(context.Context) (io.ReadCloser, error) {
+	return nil, fmt.Errorf("the log of node %s is not accessible", n.label)
+}
+
+// Stop fails, as an external node is not under Norma's control.
+func (n *Node) Stop(context.Context) error {
+	return unsupported("stopping a node")
+}
+
+// Kill fails, as an external node is not under Norma's control.
+func (n *Node) Kill(context.Context) error {
+	return unsupported("killing a node")
+}
+
+// Cleanup does nothing, as Norma left nothing behind on an external node.
+func (n *Node) Cleanup(context.Context) error {
+	return nil
+}
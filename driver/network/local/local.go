@@ -24,6 +24,8 @@ import (
 	"math/rand"
 	"os"
 	"path/filepath"
+	"slices"
+	"strings"
 	"sync"
 	"sync/atomic"
 	"time"
@@ -61,8 +63,28 @@ type LocalNetwork struct {
 	// endpoint for minutes. Guarded by nodesMutex.
 	suspended map[driver.Node]bool
 
-	// nodesMutex synchronizes access to the list of nodes and the
-	// suspended set.
+	// blocked records, for every node cut off from part of the network by
+	// PartitionNodes, the peer addresses it currently drops traffic to.
+	// Guarded by nodesMutex.
+	blocked map[*node.OperaNode][]string
+
+	// linkRules are the rules shaping the links between nodes, starting
+	// with the configured ones and extended by SetLink. Guarded by
+	// nodesMutex.
+	linkRules []driver.LinkRule
+
+	// joined lists the nodes in the order they joined the network, which
+	// places them in a ring or line topology. A node keeps its place while
+	// it reconnects. Guarded by nodesMutex.
+	joined []*node.OperaNode
+
+	// connected and disconnected record, by the labels of their ends, the
+	// peerings added by ConnectNodes and dropped by DisconnectNodes on top
+	// of the topology. Guarded by nodesMutex.
+	connected, disconnected map[driver.Peering]bool
+
+	// nodesMutex synchronizes access to the list of nodes, the suspended
+	// set, the blocked addresses, the link rules and the peerings.
 	nodesMutex sync.Mutex
 
 	// bootstrapped is set once the first node has joined and never resets:
@@ -71,6 +93,12 @@ type LocalNetwork struct {
 	// the meantime. Guarded by nodesMutex.
 	bootstrapped bool
 
+	// packs holds, by name, the containers shared by the clients of several
+	// nodes. A pack is replaced once its last node is gone. Guarded by
+	// packsMutex.
+	packs      map[string]*node.Pack
+	packsMutex sync.Mutex
+
 	// apps maintains a list of all applications created on the network.
 	apps []driver.Application
 
@@ -150,6 +178,11 @@ func NewLocalNetwork(ctx context.Context, config *driver.NetworkConfig) (*LocalN
 		primaryAccount: primaryAccount,
 		nodes:          map[driver.NodeID]*node.OperaNode{},
 		suspended:      map[driver.Node]bool{},
+		blocked:        map[*node.OperaNode][]string{},
+		packs:          map[string]*node.Pack{},
+		linkRules:      slices.Clone(config.Links),
+		connected:      map[driver.Peering]bool{},
+		disconnected:   map[driver.Peering]bool{},
 		apps:           []driver.Application{},
 		listeners:      map[driver.NetworkListener]bool{},
 		rpcWorkerPool:  rpc.NewRpcWorkerPool(ctx),
@@ -199,7 +232,8 @@ func (n *LocalNetwork) startGenesisValidators(
 	return nil
 }
 
-// addNodeIntoNetwork connects the node with other nodes in the network, adds it into the list of nodes.
+// addNodeIntoNetwork connects the node with the nodes in the network it is
+// to be peered with, adds it into the list of nodes.
 // It is best-effort: if some existing nodes are unreachable, the new node will
 // still join the network as long as at least one peer connection succeeds.
 func (n *LocalNetwork) addNodeIntoNetwork(ctx context.Context, node *node.OperaNode) error {
@@ -210,8 +244,19 @@ func (n *LocalNetwork) addNodeIntoNetwork(ctx context.Context, node *node.OperaN
 	if err != nil {
 		return fmt.Errorf("failed to get node id; %v", err)
 	}
+	label := node.GetLabel()
+	before := n.peerings()
+	isNew := !slices.Contains(n.joined, node)
+	if isNew {
+		n.joined = append(n.joined, node)
+	}
+	after := n.peerings()
+
 	var attempted, succeeded int
 	for _, other := range n.nodes {
+		if !after[driver.NewPeering(label, other.GetLabel())] {
+			continue
+		}
 		// A suspended node's client is known to be down; dialing it would
 		// only burn the retry budget. It re-peers itself when resumed.
 		if n.suspended[other] {
@@ -232,10 +277,14 @@ func (n *LocalNetwork) addNodeIntoNetwork(ctx context.Context, node *node.OperaN
 		}
 	}
 	if attempted > 0 && succeeded == 0 {
+		if isNew {
+			n.joined = n.joined[:len(n.joined)-1]
+		}
 		return fmt.Errorf("failed to add peer; no existing node was reachable")
 	}
 	n.nodes[id] = node
 	n.bootstrapped = true
+	n.rewirePeerings(ctx, before, after, label)
 	return nil
 }
 
@@ -280,6 +329,9 @@ func (n *LocalNetwork) createNode(ctx context.Context, nodeConfig *node.OperaNod
 	if err := n.addNodeIntoNetwork(ctx, node); err != nil {
 		return nil, fmt.Errorf("failed to connect node; %w", err)
 	}
+	if err := n.configureLinksOfNewNode(ctx, node); err != nil {
+		return nil, fmt.Errorf("failed to configure links; %w", err)
+	}
 	n.listenerMutex.Lock()
 	for listener := range n.listeners {
 		listener.AfterNodeCreation(node)
@@ -291,13 +343,19 @@ func (n *LocalNetwork) createNode(ctx context.Context, nodeConfig *node.OperaNod
 // CreateNode creates nodes in the network during run.
 func (n *LocalNetwork) CreateNode(config *driver.NodeConfig) (driver.Node, error) {
 	if config.Cheater {
+		// The twin signs with the key of the same validator and has to be
+		// on the same chain for its events to conflict with the node's.
 		_, err := n.createNode(n.ctx, &node.OperaNodeConfig{
-			Label:          "cheater-" + config.Name,
-			Failing:        config.Failing,
-			Image:          config.Image,
-			NetworkConfig:  &n.config,
-			ValidatorId:    config.ValidatorId,
-			ExtraArguments: config.ExtraArguments,
+			Label:           "cheater-" + config.Name,
+			Failing:         config.Failing,
+			Image:           config.Image,
+			NetworkConfig:   &n.config,
+			ValidatorId:     config.ValidatorId,
+			GenesisJsonPath: &n.genesisJsonPath,
+			ExtraArguments:  config.ExtraArguments,
+			ClockOffset:     config.ClockOffset,
+			Resources:       config.Resources,
+			Memory:          config.Memory,
 		})
 		if err != nil {
 			return nil, err
@@ -319,9 +377,30 @@ func (n *LocalNetwork) CreateNode(config *driver.NodeConfig) (driver.Node, error
 		GenesisJsonPath: &n.genesisJsonPath,
 		MountDataDir:    datadir,
 		ExtraArguments:  config.ExtraArguments,
+		ClockOffset:     config.ClockOffset,
+		Resources:       config.Resources,
+		Memory:          config.Memory,
+		Pack:            n.packOf(config.Container),
 	})
 }
 
+// packOf returns the pack of the given name, creating it if there is none
+// yet or the previous one of that name was removed along with its last node.
+// No name means a container of the node's own.
+func (n *LocalNetwork) packOf(name string) *node.Pack {
+	if name == "" {
+		return nil
+	}
+	n.packsMutex.Lock()
+	defer n.packsMutex.Unlock()
+	pack, found := n.packs[name]
+	if !found || pack.IsReleased() {
+		pack = node.NewPack(n.docker, n.network, name)
+		n.packs[name] = pack
+	}
+	return pack
+}
+
 // prepareGenesis generates the genesis.json file for the network based on the
 // configuration provided at startup and stores it in a temporary directory.
 // The path to the generated genesis.json file is stored in the LocalNetwork
@@ -348,6 +427,7 @@ func (n *LocalNetwork) prepareGenesis(ctx context.Context) error {
 		driver.GetValidatorStakes(n.config.Validators),
 		&rules,
 		clientVersions,
+		n.config.InitialState,
 	); err != nil {
 		return errors.Join(fmt.Errorf("failed to generate genesis file: %w", err), os.RemoveAll(tmpDir))
 	}
@@ -357,6 +437,8 @@ func (n *LocalNetwork) prepareGenesis(ctx context.Context) error {
 	return nil
 }
 func (n *LocalNetwork) RemoveNode(node driver.Node) error {
+	n.endThrottlingOf(node)
+
 	n.listenerMutex.Lock()
 	for listener := range n.listeners {
 		listener.BeforeNodeRemoval(node)
@@ -372,6 +454,9 @@ func (n *LocalNetwork) RemoveNode(node driver.Node) error {
 
 	delete(n.nodes, id)
 	delete(n.suspended, node)
+	n.forgetBlockedNode(node)
+	n.forgetLinksTo(node)
+	n.forgetJoinedNode(node)
 	for _, other := range n.nodes {
 		if n.suspended[other] {
 			continue
@@ -634,6 +719,411 @@ func (n *LocalNetwork) ResumeNode(node driver.Node) {
 	n.listenerMutex.Unlock()
 }
 
+// PartitionNodes cuts the connectivity between the given groups of nodes by
+// having every node drop the traffic to the nodes of all other groups. The
+// cut is made with routes inside the containers rather than by detaching
+// them from the docker network, so the nodes stay reachable for RPC and
+// monitoring throughout.
+func (n *LocalNetwork) PartitionNodes(ctx context.Context, groups [][]driver.Node) error {
+	if len(groups) < 2 {
+		return fmt.Errorf("a partition requires at least two groups, got %d", len(groups))
+	}
+
+	// Collect the addresses of each group first, so that every node can be
+	// told about all the addresses it has to drop in one go.
+	members := make([][]*node.OperaNode, len(groups))
+	addresses := make([][]string, len(groups))
+	seen := map[*node.OperaNode]bool{}
+	for i, group := range groups {
+		for _, member := range group {
+			opera, ok := member.(*node.OperaNode)
+			if !ok {
+				return fmt.Errorf("PartitionNodes: unsupported node type")
+			}
+			if seen[opera] {
+				return fmt.Errorf("node %s is listed in more than one group", opera.GetLabel())
+			}
+			seen[opera] = true
+			members[i] = append(members[i], opera)
+			addresses[i] = append(addresses[i], opera.IP())
+		}
+	}
+
+	n.nodesMutex.Lock()
+	defer n.nodesMutex.Unlock()
+	if len(n.blocked) > 0 {
+		return fmt.Errorf("network is already partitioned")
+	}
+
+	for i, group := range members {
+		var others []string
+		for j := range addresses {
+			if j != i {
+				others = append(others, addresses[j]...)
+			}
+		}
+		for _, member := range group {
+			// What was blocked so far is recorded even if a later node
+			// fails, so that HealPartition can still undo it.
+			if err := member.BlockPeers(ctx, others); err != nil {
+				return fmt.Errorf("failed to partition node %s: %w", member.GetLabel(), err)
+			}
+			n.blocked[member] = others
+		}
+	}
+	return nil
+}
+
+// HealPartition lifts the cut made by PartitionNodes. Peers that dropped
+// their connections while the cut was in place are re-added explicitly
+// rather than left to the client's redial backoff, so the two sides resume
+// exchanging events right away. Only nodes that are to be peered are
+// re-added, so the topology survives the cut.
+func (n *LocalNetwork) HealPartition(ctx context.Context) error {
+	n.nodesMutex.Lock()
+	defer n.nodesMutex.Unlock()
+	if len(n.blocked) == 0 {
+		return fmt.Errorf("network is not partitioned")
+	}
+
+	var errs []error
+	peerings := n.peerings()
+	healed := map[*node.OperaNode][]string{}
+	for member, addresses := range n.blocked {
+		if err := member.UnblockPeers(ctx, addresses); err != nil {
+			errs = append(errs, fmt.Errorf("failed to heal node %s: %w", member.GetLabel(), err))
+			continue
+		}
+		healed[member] = addresses
+		delete(n.blocked, member)
+	}
+
+	for member, addresses := range healed {
+		if n.suspended[member] {
+			continue
+		}
+		id, err := member.GetNodeID()
+		if err != nil {
+			errs = append(errs, fmt.Errorf("failed to get node id of %s: %w", member.GetLabel(), err))
+			continue
+		}
+		for _, other := range n.nodes {
+			if n.suspended[other] || !slices.Contains(addresses, other.IP()) ||
+				!peerings[driver.NewPeering(member.GetLabel(), other.GetLabel())] {
+				continue
+			}
+			if err := other.AddPeer(ctx, id); err != nil {
+				slog.Warn("failed to re-add peer after healing partition",
+					"node", other.GetLabel(), "peer", member.GetLabel(), "error", err)
+			}
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// forgetBlockedNode drops the partition bookkeeping of a node that leaves
+// the network. Docker hands out the addresses of removed containers again,
+// so the other nodes must stop dropping the address of the removed one, or
+// a node created later could inherit the cut. Must be called with
+// nodesMutex held.
+func (n *LocalNetwork) forgetBlockedNode(removed driver.Node) {
+	opera, ok := removed.(*node.OperaNode)
+	if !ok {
+		return
+	}
+	delete(n.blocked, opera)
+	ip := opera.IP()
+	for member, addresses := range n.blocked {
+		if !slices.Contains(addresses, ip) {
+			continue
+		}
+		if err := member.UnblockPeers(n.ctx, []string{ip}); err != nil {
+			slog.Warn("failed to unblock address of removed node",
+				"node", member.GetLabel(), "address", ip, "error", err)
+			continue
+		}
+		n.blocked[member] = slices.DeleteFunc(slices.Clone(addresses),
+			func(a string) bool { return a == ip })
+	}
+}
+
+// SetLink adds a link rule on top of the ones in place and reconfigures the
+// links of all current nodes on either of its ends. Nodes created later are
+// configured according to it as well.
+func (n *LocalNetwork) SetLink(ctx context.Context, rule driver.LinkRule) error {
+	n.nodesMutex.Lock()
+	defer n.nodesMutex.Unlock()
+	n.linkRules = append(n.linkRules, rule)
+
+	var affected []*node.OperaNode
+	for _, member := range n.nodes {
+		label := member.GetLabel()
+		if slices.ContainsFunc(slices.Concat(rule.A, rule.B), func(name string) bool {
+			return driver.MatchesNodeName(label, name)
+		}) {
+			affected = append(affected, member)
+		}
+	}
+	return n.configureLinks(ctx, affected)
+}
+
+// configureLinksOfNewNode shapes the links between a node that just joined
+// the network and the nodes already in it. Without any link rules, the
+// uniform delay installed by the node itself is all there is to do. Must
+// not be called with nodesMutex held.
+func (n *LocalNetwork) configureLinksOfNewNode(ctx context.Context, added *node.OperaNode) error {
+	n.nodesMutex.Lock()
+	defer n.nodesMutex.Unlock()
+	if len(n.linkRules) == 0 {
+		return nil
+	}
+	affected := []*node.OperaNode{added}
+	for _, other := range n.nodes {
+		if other != added && n.isShapedLink(added, other) {
+			affected = append(affected, other)
+		}
+	}
+	return n.configureLinks(ctx, affected)
+}
+
+// forgetLinksTo drops the shaping of the links to a node that leaves the
+// network. As for the partition bookkeeping, the address of the removed
+// node may be handed out to a node created later, which must not inherit
+// the shaping. Must be called with nodesMutex held, after the node has
+// been removed from the nodes.
+func (n *LocalNetwork) forgetLinksTo(removed driver.Node) {
+	opera, ok := removed.(*node.OperaNode)
+	if !ok {
+		return
+	}
+	for _, other := range n.nodes {
+		if !n.isShapedLink(opera, other) {
+			continue
+		}
+		if err := n.configureLinks(n.ctx, []*node.OperaNode{other}); err != nil {
+			slog.Warn("failed to drop links to removed node",
+				"node", other.GetLabel(), "removed", opera.GetLabel(), "error", err)
+		}
+	}
+}
+
+// isShapedLink reports whether any link rule covers the link between the
+// given nodes. Must be called with nodesMutex held.
+func (n *LocalNetwork) isShapedLink(a, b *node.OperaNode) bool {
+	_, found := driver.ResolveLink(n.linkRules, a.GetLabel(), b.GetLabel())
+	return found
+}
+
+// configureLinks installs on each of the given nodes the shaping of its
+// links to all other nodes in the network, as resolved from the link
+// rules. Must be called with nodesMutex held.
+func (n *LocalNetwork) configureLinks(ctx context.Context, members []*node.OperaNode) error {
+	var errs []error
+	for _, member := range members {
+		var links []node.PeerLink
+		for _, other := range n.nodes {
+			if other == member {
+				continue
+			}
+			link, found := driver.ResolveLink(n.linkRules, member.GetLabel(), other.GetLabel())
+			if found {
+				links = append(links, node.PeerLink{IP: other.IP(), Link: link})
+			}
+		}
+		slices.SortFunc(links, func(a, b node.PeerLink) int {
+			return strings.Compare(a.IP, b.IP)
+		})
+		if err := member.ConfigureLinks(ctx, links); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// ThrottleNode limits the bandwidth of the given node, on top of the shaping
+// of its links, and notifies the listeners interested in throttling.
+func (n *LocalNetwork) ThrottleNode(ctx context.Context, target driver.Node, config driver.ThrottleConfig) error {
+	opera, ok := target.(*node.OperaNode)
+	if !ok {
+		return fmt.Errorf("node %s cannot be throttled", target.GetLabel())
+	}
+	if err := opera.Throttle(ctx, config); err != nil {
+		return err
+	}
+	n.notifyThrottleListeners(target, &config)
+	return nil
+}
+
+// UnthrottleNode lifts the bandwidth limits of the given node and notifies
+// the listeners interested in throttling.
+func (n *LocalNetwork) UnthrottleNode(ctx context.Context, target driver.Node) error {
+	opera, ok := target.(*node.OperaNode)
+	if !ok {
+		return fmt.Errorf("node %s cannot be throttled", target.GetLabel())
+	}
+	if err := opera.Unthrottle(ctx); err != nil {
+		return err
+	}
+	n.notifyThrottleListeners(target, nil)
+	return nil
+}
+
+// endThrottlingOf tells the listeners that the throttling of a node leaving
+// the network, if any, has ended: its limits go with it.
+func (n *LocalNetwork) endThrottlingOf(removed driver.Node) {
+	if opera, ok := removed.(*node.OperaNode); ok && opera.Throttling() != nil {
+		n.notifyThrottleListeners(removed, nil)
+	}
+}
+
+// notifyThrottleListeners informs every listener implementing
+// driver.NodeThrottleListener about the bandwidth limits of a node.
+func (n *LocalNetwork) notifyThrottleListeners(target driver.Node, config *driver.ThrottleConfig) {
+	n.listenerMutex.Lock()
+	defer n.listenerMutex.Unlock()
+	for listener := range n.listeners {
+		if l, ok := listener.(driver.NodeThrottleListener); ok {
+			l.OnNodeThrottled(target, config)
+		}
+	}
+}
+
+// ConnectNodes peers the given nodes on top of the topology. The peering is
+// recorded by the labels of the nodes, so it is re-established when either
+// of them rejoins the network.
+func (n *LocalNetwork) ConnectNodes(ctx context.Context, a, b driver.Node) error {
+	first, second, err := operaPair(a, b)
+	if err != nil {
+		return err
+	}
+	n.nodesMutex.Lock()
+	defer n.nodesMutex.Unlock()
+	peering := driver.NewPeering(first.GetLabel(), second.GetLabel())
+	delete(n.disconnected, peering)
+	n.connected[peering] = true
+	return connectPair(ctx, first, second)
+}
+
+// DisconnectNodes drops the peering of the given nodes, whether it is part
+// of the topology or was added by ConnectNodes. As for ConnectNodes, the
+// nodes stay apart when either of them rejoins the network.
+func (n *LocalNetwork) DisconnectNodes(ctx context.Context, a, b driver.Node) error {
+	first, second, err := operaPair(a, b)
+	if err != nil {
+		return err
+	}
+	n.nodesMutex.Lock()
+	defer n.nodesMutex.Unlock()
+	peering := driver.NewPeering(first.GetLabel(), second.GetLabel())
+	delete(n.connected, peering)
+	n.disconnected[peering] = true
+	return disconnectPair(ctx, first, second)
+}
+
+// peerings returns the pairs of nodes in the network that are to be peered:
+// those of the topology among the joined nodes, plus the ones added by
+// ConnectNodes and minus the ones dropped by DisconnectNodes. Must be called
+// with nodesMutex held.
+func (n *LocalNetwork) peerings() map[driver.Peering]bool {
+	labels := make([]string, 0, len(n.joined))
+	for _, member := range n.joined {
+		labels = append(labels, member.GetLabel())
+	}
+	res := n.config.Topology.Peerings(labels)
+	for peering := range n.connected {
+		if slices.Contains(labels, peering.A) && slices.Contains(labels, peering.B) {
+			res[peering] = true
+		}
+	}
+	for peering := range n.disconnected {
+		delete(res, peering)
+	}
+	return res
+}
+
+// rewirePeerings establishes the peerings in after that are not in before
+// and drops the ones in before that are not in after, leaving out those of
+// the node with the given label, which its caller takes care of. This is
+// how a node joining or leaving a ring or a line takes the place of the
+// peering of its neighbors. Failures are only logged, since the nodes
+// concerned remain part of the network. Must be called with nodesMutex held.
+func (n *LocalNetwork) rewirePeerings(ctx context.Context, before, after map[driver.Peering]bool, except string) {
+	members := map[string]*node.OperaNode{}
+	for _, member := range n.nodes {
+		if !n.suspended[member] {
+			members[member.GetLabel()] = member
+		}
+	}
+	change := func(peering driver.Peering, update func(context.Context, *node.OperaNode, *node.OperaNode) error) {
+		a, b := members[peering.A], members[peering.B]
+		if peering.Has(except) || a == nil || b == nil {
+			return
+		}
+		if err := update(ctx, a, b); err != nil {
+			slog.Warn("failed to rewire peers", "node", peering.A, "peer", peering.B, "error", err)
+		}
+	}
+	for peering := range after {
+		if !before[peering] {
+			change(peering, connectPair)
+		}
+	}
+	for peering := range before {
+		if !after[peering] {
+			change(peering, disconnectPair)
+		}
+	}
+}
+
+// forgetJoinedNode drops a node that leaves the network from the join order
+// and peers the nodes that take its place, such as its neighbors in a ring.
+// Must be called with nodesMutex held, after the node has been removed from
+// the nodes.
+func (n *LocalNetwork) forgetJoinedNode(removed driver.Node) {
+	before := n.peerings()
+	n.joined = slices.DeleteFunc(n.joined, func(member *node.OperaNode) bool {
+		return member == removed
+	})
+	n.rewirePeerings(n.ctx, before, n.peerings(), removed.GetLabel())
+}
+
+// operaPair returns the given nodes as OperaNodes, which are the only nodes
+// whose peers can be changed.
+func operaPair(a, b driver.Node) (*node.OperaNode, *node.OperaNode, error) {
+	first, ok := a.(*node.OperaNode)
+	if !ok {
+		return nil, nil, fmt.Errorf("node %s cannot be peered", a.GetLabel())
+	}
+	second, ok := b.(*node.OperaNode)
+	if !ok {
+		return nil, nil, fmt.Errorf("node %s cannot be peered", b.GetLabel())
+	}
+	return first, second, nil
+}
+
+// connectPair has the first node dial the second one. The connection is
+// used in both directions, so one end is enough.
+func connectPair(ctx context.Context, a, b *node.OperaNode) error {
+	id, err := b.GetNodeID()
+	if err != nil {
+		return fmt.Errorf("failed to get node id of %s; %v", b.GetLabel(), err)
+	}
+	return a.AddPeer(ctx, id)
+}
+
+// disconnectPair has both nodes drop the other one, so that neither redials.
+func disconnectPair(ctx context.Context, a, b *node.OperaNode) error {
+	idA, err := a.GetNodeID()
+	if err != nil {
+		return fmt.Errorf("failed to get node id of %s; %v", a.GetLabel(), err)
+	}
+	idB, err := b.GetNodeID()
+	if err != nil {
+		return fmt.Errorf("failed to get node id of %s; %v", b.GetLabel(), err)
+	}
+	return errors.Join(a.RemovePeer(ctx, idB), b.RemovePeer(ctx, idA))
+}
+
 func (n *LocalNetwork) Shutdown() error {
 	var errs []error
 
@@ -653,7 +1143,18 @@ func (n *LocalNetwork) Shutdown() error {
 		n.appContext.Close()
 	}
 
-	// Second, shut down the nodes.
+	// Second, lift all bandwidth limits, so the listeners see the end of
+	// every throttling period.
+	for _, node := range n.nodes {
+		if node.Throttling() == nil {
+			continue
+		}
+		if err := n.UnthrottleNode(ctx, node); err != nil {
+			errs = append(errs, err)
+		}
+	}
+
+	// Third, shut down the nodes.
 	for _, node := range n.nodes {
 		// TODO: shutdown nodes in parallel.
 		if err := node.Stop(ctx); err != nil {
@@ -665,7 +1166,7 @@ func (n *LocalNetwork) Shutdown() error {
 	}
 	n.nodes = map[driver.NodeID]*node.OperaNode{}
 
-	// Third, shut down the docker network.
+	// Fourth, shut down the docker network.
 	if n.network != nil {
 		if err := n.network.Cleanup(ctx); err != nil {
 			errs = append(errs, err)
@@ -142,6 +142,95 @@ func TestLocalNetwork_CanEnforceNetworkLatency(t *testing.T) {
 	}
 }
 
+func TestLocalNetwork_CanPartitionAndHealNodes(t *testing.T) {
+	t.Parallel()
+	require := require.New(t)
+	config := driver.NetworkConfig{
+		Validators: driver.NewDefaultTestValidators(t.Name(), 2),
+	}
+	net, err := NewLocalLegacyNetwork(t.Context(), &config)
+	require.NoError(err, "failed to create new local network")
+	t.Cleanup(func() {
+		_ = net.Shutdown()
+	})
+
+	nodes := net.GetActiveNodes()
+	require.Len(nodes, 2)
+	first := nodes[0].(*node.OperaNode)
+	second := nodes[1].(*node.OperaNode)
+
+	require.NoError(net.PartitionNodes(t.Context(), [][]driver.Node{{first}, {second}}))
+	_, err = first.GetRoundTripTime(second.Hostname())
+	require.Error(err, "nodes in different groups can still reach each other")
+	require.Error(net.PartitionNodes(t.Context(), [][]driver.Node{{first}, {second}}),
+		"a second partition must be rejected")
+
+	// The driver keeps its access to partitioned nodes.
+	for _, n := range nodes {
+		client, err := n.DialRpc(t.Context())
+		require.NoError(err)
+		_, err = client.BlockNumber(t.Context())
+		require.NoError(err, "partitioned node is not reachable via RPC")
+		client.Close()
+	}
+
+	require.NoError(net.HealPartition(t.Context()))
+	_, err = first.GetRoundTripTime(second.Hostname())
+	require.NoError(err, "nodes cannot reach each other after healing")
+	require.Error(net.HealPartition(t.Context()), "healing twice must be rejected")
+}
+
+func TestLocalNetwork_CanThrottleAndUnthrottleNodes(t *testing.T) {
+	t.Parallel()
+	require := require.New(t)
+	config := driver.NetworkConfig{
+		Validators: driver.NewDefaultTestValidators(t.Name(), 1),
+	}
+	net, err := NewLocalLegacyNetwork(t.Context(), &config)
+	require.NoError(err, "failed to create new local network")
+	t.Cleanup(func() {
+		_ = net.Shutdown()
+	})
+	listener := &throttleListener{}
+	net.RegisterListener(listener)
+
+	nodes := net.GetActiveNodes()
+	require.Len(nodes, 1)
+	target := nodes[0].(*node.OperaNode)
+
+	limits := driver.ThrottleConfig{Egress: 10_000_000, Ingress: 20_000_000}
+	require.NoError(net.ThrottleNode(t.Context(), target, limits))
+	require.Equal(&limits, target.Throttling())
+
+	// The driver keeps its access to the throttled node.
+	client, err := target.DialRpc(t.Context())
+	require.NoError(err)
+	_, err = client.BlockNumber(t.Context())
+	require.NoError(err, "throttled node is not reachable via RPC")
+	client.Close()
+
+	require.NoError(net.UnthrottleNode(t.Context(), target))
+	require.Nil(target.Throttling())
+	require.Error(net.UnthrottleNode(t.Context(), target), "unthrottling twice must be rejected")
+
+	// Shutting down lifts the limits still in place.
+	require.NoError(net.ThrottleNode(t.Context(), target, limits))
+	require.NoError(net.Shutdown())
+	require.Equal([]*driver.ThrottleConfig{&limits, nil, &limits, nil}, listener.changes)
+}
+
+type throttleListener struct {
+	changes []*driver.ThrottleConfig
+}
+
+func (l *throttleListener) AfterNodeCreation(driver.Node)               {}
+func (l *throttleListener) BeforeNodeRemoval(driver.Node)               {}
+func (l *throttleListener) AfterApplicationCreation(driver.Application) {}
+
+func (l *throttleListener) OnNodeThrottled(_ driver.Node, config *driver.ThrottleConfig) {
+	l.changes = append(l.changes, config)
+}
+
 func TestLocalNetwork_CanStartApplicationsAndShutThemDown(t *testing.T) {
 	t.Parallel()
 	config := driver.NetworkConfig{Validators: driver.DefaultValidators(t.Name())}
@@ -0,0 +1,503 @@
+// Copyright 2024 Fantom Foundation
+// This file is part of Norma System Testing Infrastructure for Sonic.
+//
+// Norma is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// Norma is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with Norma. If not, see <http://www.gnu.org/licenses/>.
+
+package process
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/0xsoniclabs/norma/driver"
+	"github.com/0xsoniclabs/norma/driver/network"
+	"github.com/0xsoniclabs/norma/driver/node"
+	rpcdriver "github.com/0xsoniclabs/norma/driver/rpc"
+	"github.com/0xsoniclabs/norma/genesis"
+	"github.com/ethereum/go-ethereum/rpc"
+)
+
+// Node implements the driver's Node interface by running the client as a
+// process on the host. Its services listen on the loopback interface, on
+// ports allocated when the node starts, so any number of nodes can run side
+// by side.
+//
+// A node runs a single client process: restarting it means creating a new
+// node on the same data directory.
+type Node struct {
+	label       string
+	failing     bool
+	validatorId *int
+
+	// ports maps the ports of the services the client offers in a container
+	// to the host ports it listens on instead.
+	ports    map[network.Port]network.Port
+	p2pPort  network.Port
+	dataDir  string
+	logPath  string
+	tempDirs []string
+
+	// id is the enode of the client, recorded once it answers. It does not
+	// change while the client runs, and is still needed after it is gone to
+	// have the other nodes drop it.
+	id driver.NodeID
+
+	cmd  *exec.Cmd
+	done chan struct{}
+	// exitErr is the error the client process ended with, set before done
+	// is closed.
+	exitErr error
+}
+
+// nodeConfig describes a node to be started by startNode.
+type nodeConfig struct {
+	label          string
+	failing        bool
+	validatorId    *int
+	dataDir        string
+	genesisPath    string
+	logsDir        string
+	configToml     string
+	discovery      bool
+	extraArguments string
+}
+
+// services lists the services a client offers; their ports are the ones
+// monitoring and load generation ask for through GetServiceUrl.
+var services = []*network.ServiceDescription{
+	&node.OperaRpcService,
+	&node.OperaWsService,
+	&node.OperaDebugService,
+}
+
+// shutdownTimeout bounds how long a client may take to exit after being
+// interrupted before it is killed, as the container's shutdown timeout does.
+const shutdownTimeout = 180 * time.Second
+
+// startNode prepares the data directory of a node, starts its client and
+// waits for the client to answer on its RPC port.
+func startNode(ctx context.Context, binaries Binaries, config *nodeConfig) (*Node, error) {
+	ports, err := network.GetFreePorts(len(services) + 1)
+	if err != nil {
+		return nil, fmt.Errorf("failed to allocate ports: %w", err)
+	}
+	n := &Node{
+		label:       config.label,
+		failing:     config.failing,
+		validatorId: config.validatorId,
+		ports:       map[network.Port]network.Port{},
+		p2pPort:     ports[len(services)],
+		dataDir:     config.dataDir,
+		done:        make(chan struct{}),
+	}
+	for i, service := range services {
+		n.ports[service.Port] = ports[i]
+	}
+
+	if err := n.initialize(ctx, binaries.Sonictool, config); err != nil {
+		return nil, errors.Join(err, n.Cleanup(ctx))
+	}
+	if err := n.start(binaries.Sonicd, config); err != nil {
+		return nil, errors.Join(err, n.Cleanup(ctx))
+	}
+	if err := n.waitForRpc(ctx); err != nil {
+		return nil, errors.Join(
+			fmt.Errorf("failed to get node online, see %s: %w", n.logPath, err),
+			n.Kill(ctx), n.Cleanup(ctx))
+	}
+	return n, nil
+}
+
+// initialize writes the validator key and the client configuration into the
+// data directory and, unless it holds the database of an earlier run,
+// initializes the database from the genesis file.
+func (n *Node) initialize(ctx context.Context, sonictool string, config *nodeConfig) error {
+	needsInit := isDataDirEmpty(n.dataDir)
+	if err := os.MkdirAll(n.dataDir, 0755); err != nil {
+		return fmt.Errorf("failed to create datadir: %w", err)
+	}
+
+	if n.validatorId != nil && *n.validatorId > 0 {
+		privKey, _, _, err := genesis.DeriveValidatorKey(*n.validatorId)
+		if err != nil {
+			return fmt.Errorf("failed to derive validator key: %w", err)
+		}
+		if err := genesis.WriteValidatorKeystore(privKey, n.dataDir); err != nil {
+			return fmt.Errorf("failed to write validator keystore: %w", err)
+		}
+		// As in containers, the password only protects throwaway keys.
+		if err := os.WriteFile(n.passwordFile(), []byte("password\n"), 0600); err != nil {
+			return fmt.Errorf("failed to write password file: %w", err)
+		}
+	}
+
+	if needsInit {
+		cmd := exec.CommandContext(ctx, sonictool,
+			"--datadir", n.dataDir,
+			"--statedb.livecache", "1",
+			"genesis", "json", "--experimental", config.genesisPath,
+		)
+		if output, err := cmd.CombinedOutput(); err != nil {
+			return fmt.Errorf("sonictool genesis init failed: %w - output: %s", err, output)
+		}
+	}
+
+	if err := os.WriteFile(n.configFile(), []byte(config.configToml), 0644); err != nil {
+		return fmt.Errorf("failed to write client config: %w", err)
+	}
+	return nil
+}
+
+// start launches the client process, writing its output into a log file.
+func (n *Node) start(sonicd string, config *nodeConfig) error {
+	if err := os.MkdirAll(config.logsDir, 0755); err != nil {
+		return fmt.Errorf("failed to create logs dir: %w", err)
+	}
+	ts := time.Now().UTC().Format("20060102T150405.000Z")
+	n.logPath = filepath.Join(config.logsDir, fmt.Sprintf("%s_sonicd_%s.log", n.label, ts))
+	logFile, err := os.Create(n.logPath) //#nosec G304 -- path is constructed internally
+	if err != nil {
+		return fmt.Errorf("failed to create client log: %w", err)
+	}
+
+	validatorId := n.validatorId
+	var pubKey string
+	if validatorId != nil && *validatorId > 0 {
+		if _, pubKey, _, err = genesis.DeriveValidatorKey(*validatorId); err != nil {
+			return errors.Join(fmt.Errorf("failed to derive validator key: %w", err), logFile.Close())
+		}
+	}
+
+	// The client is not tied to a context: it runs until the node is
+	// stopped, not until the step that created it is done.
+	n.cmd = exec.Command(sonicd, n.buildSonicdCmd(pubKey, config.discovery, config.extraArguments)...) //#nosec G204 -- binary and arguments are set by the network
+	n.cmd.Stdout = logFile
+	n.cmd.Stderr = logFile
+	slog.Info("Starting sonicd", "node", n.label, "log", n.logPath)
+	if err := n.cmd.Start(); err != nil {
+		return errors.Join(fmt.Errorf("failed to start sonicd: %w", err), logFile.Close())
+	}
+	go func() {
+		err := n.cmd.Wait()
+		n.exitErr = errors.Join(err, logFile.Close())
+		close(n.done)
+	}()
+	return nil
+}
+
+// buildSonicdCmd constructs the arguments of the client, which are those of
+// a client in a container but for the paths and ports.
+func (n *Node) buildSonicdCmd(pubKey string, discovery bool, extraArguments string) []string {
+	port := func(service *network.ServiceDescription) string {
+		return fmt.Sprintf("%d", n.ports[service.Port])
+	}
+	args := []string{
+		"--datadir=" + n.dataDir,
+		"--port", fmt.Sprintf("%d", n.p2pPort),
+		"--http", "--http.addr", "127.0.0.1",
+		"--http.port", port(&node.OperaRpcService),
+		"--http.api", "admin,dag,eth,sonic,txpool",
+		"--ws", "--ws.addr", "127.0.0.1",
+		"--ws.port", port(&node.OperaWsService),
+		"--ws.api", "admin,dag,eth,sonic,txpool",
+		"--pprof", "--pprof.addr", "127.0.0.1",
+		"--pprof.port", port(&node.OperaDebugService),
+		"--nat", "extip:127.0.0.1",
+		"--metrics", "--metrics.expensive",
+		"--config", n.configFile(),
+		"--datadir.minfreedisk", "0",
+		"--statedb.livecache", "256000000",
+	}
+	if n.validatorId != nil && *n.validatorId > 0 {
+		args = append(args,
+			"--validator.id", fmt.Sprintf("%d", *n.validatorId),
+			"--validator.pubkey", pubKey,
+			"--validator.password", n.passwordFile(),
+			"--mode", "rpc",
+		)
+	}
+	if !discovery {
+		args = append(args, "--nodiscover")
+	}
+	if extraArguments != "" {
+		args = append(args, strings.Fields(extraArguments)...)
+	}
+	return args
+}
+
+// waitForRpc waits until the client answers on its RPC port, failing right
+// away if the client exits in the meantime.
+func (n *Node) waitForRpc(ctx context.Context) error {
+	return network.Retry(ctx, network.DefaultRetryAttempts, 1*time.Second,
+		func(ctx context.Context) error {
+			if !n.IsRunning() {
+				return fmt.Errorf("client process exited: %v: %w", n.exitErr, network.ErrPermanent)
+			}
+			id, err := n.GetNodeID()
+			n.id = id
+			return err
+		})
+}
+
+func (n *Node) configFile() string {
+	return filepath.Join(n.dataDir, "config.toml")
+}
+
+func (n *Node) passwordFile() string {
+	return filepath.Join(n.dataDir, "password.txt")
+}
+
+// isDataDirEmpty reports whether a data directory holds no database yet,
+// ignoring the files norma writes into it before the database is created.
+func isDataDirEmpty(path string) bool {
+	entries, err := os.ReadDir(path)
+	if err != nil {
+		return true
+	}
+	for _, e := range entries {
+		switch e.Name() {
+		case "keystore", "password.txt", "config.toml":
+		default:
+			return false
+		}
+	}
+	return true
+}
+
+func (n *Node) GetLabel() string {
+	return n.label
+}
+
+func (n *Node) IsExpectedFailure() bool {
+	return n.failing
+}
+
+// Hostname returns the address the node's services listen on.
+func (n *Node) Hostname() string {
+	return "127.0.0.1"
+}
+
+// MetricsPort returns the host port on which the node exports its metrics.
+func (n *Node) MetricsPort() int {
+	return int(n.ports[node.OperaDebugService.Port])
+}
+
+func (n *Node) IsRunning() bool {
+	select {
+	case <-n.done:
+		return false
+	default:
+		return n.cmd != nil
+	}
+}
+
+func (n *Node) GetNodeID() (driver.NodeID, error) {
+	if n.id != "" {
+		return n.id, nil
+	}
+	url, err := n.GetServiceUrl(&node.OperaRpcService)
+	if err != nil {
+		return "", err
+	}
+	rpcClient, err := rpc.DialContext(context.Background(), string(*url))
+	if err != nil {
+		return "", err
+	}
+	defer rpcClient.Close()
+	var result struct {
+		Enode string
+	}
+	if err := rpcClient.Call(&result, "admin_nodeInfo"); err != nil {
+		return "", err
+	}
+	return driver.NodeID(result.Enode), nil
+}
+
+func (n *Node) GetValidatorId() *int {
+	return n.validatorId
+}
+
+func (n *Node) GetServiceUrl(service *network.ServiceDescription) (*driver.URL, error) {
+	port, found := n.ports[service.Port]
+	if !found {
+		return nil, fmt.Errorf("node %s does not offer service %s", n.label, service.Name)
+	}
+	url := driver.URL(fmt.Sprintf("%s://127.0.0.1:%d", service.Protocol, port))
+	return &url, nil
+}
+
+func (n *Node) DialRpc(ctx context.Context) (rpcdriver.Client, error) {
+	url, err := n.GetServiceUrl(&node.OperaRpcService)
+	if err != nil {
+		return nil, err
+	}
+	rpcClient, err := network.RetryReturn(ctx, network.DefaultRetryAttempts, 1*time.Second,
+		func(ctx context.Context) (*rpc.Client, error) {
+			return rpc.DialContext(ctx, string(*url))
+		})
+	if err != nil {
+		return nil, fmt.Errorf("failed to dial RPC for node %s; %v", n.label, err)
+	}
+	return rpcdriver.WrapRpcClient(rpcClient), nil
+}
+
+// AddPeer has the client connect to the node of the given ID and keep the
+// connection, as OperaNode.AddPeer does.
+func (n *Node) AddPeer(ctx context.Context, id driver.NodeID) error {
+	return n.callAdmin(ctx, id, "admin_addTrustedPeer", "admin_addPeer")
+}
+
+// RemovePeer has the client drop the node of the given ID and no longer
+// trust it, as OperaNode.RemovePeer does.
+func (n *Node) RemovePeer(ctx context.Context, id driver.NodeID) error {
+	return n.callAdmin(ctx, id, "admin_removeTrustedPeer", "admin_removePeer")
+}
+
+// callAdmin calls the given admin methods, in order, with the node ID.
+func (n *Node) callAdmin(ctx context.Context, id driver.NodeID, methods ...string) error {
+	rpcClient, err := n.DialRpc(ctx)
+	if err != nil {
+		return err
+	}
+	defer rpcClient.Close()
+	return network.Retry(ctx, network.DefaultRetryAttempts, 1*time.Second,
+		func(ctx context.Context) error {
+			for _, method := range methods {
+				if err := rpcClient.Call(nil, method, id); err != nil {
+					return fmt.Errorf("failed to call %s on node %s: %v", method, n.label, err)
+				}
+			}
+			return nil
+		})
+}
+
+// StreamLog follows the output of the client until it exits or the context
+// is cancelled.
+func (n *Node) StreamLog(ctx context.Context) (io.ReadCloser, error) {
+	if n.logPath == "" {
+		return nil, fmt.Errorf("node %s has no client process", n.label)
+	}
+	file, err := os.Open(n.logPath) //#nosec G304 -- path is constructed internally
+	if err != nil {
+		return nil, err
+	}
+	return newFollowingReader(ctx, file, n.done), nil
+}
+
+// Stop interrupts the client and waits for it to exit, killing it if it
+// does not in time. A client that is not running is not an error.
+func (n *Node) Stop(ctx context.Context) error {
+	if !n.IsRunning() {
+		return nil
+	}
+	if err := n.cmd.Process.Signal(os.Interrupt); err != nil && !errors.Is(err, os.ErrProcessDone) {
+		return fmt.Errorf("failed to interrupt client of node %s: %w", n.label, err)
+	}
+	select {
+	case <-n.done:
+		return nil
+	case <-time.After(shutdownTimeout):
+		slog.Warn("client did not exit in time, killing it", "node", n.label)
+		return n.Kill(ctx)
+	case <-ctx.Done():
+		return errors.Join(ctx.Err(), n.Kill(context.WithoutCancel(ctx)))
+	}
+}
+
+// Kill ends the client with SIGKILL and waits for it to be gone.
+func (n *Node) Kill(ctx context.Context) error {
+	if !n.IsRunning() {
+		return nil
+	}
+	if err := n.cmd.Process.Kill(); err != nil && !errors.Is(err, os.ErrProcessDone) {
+		return fmt.Errorf("failed to kill client of node %s: %w", n.label, err)
+	}
+	select {
+	case <-n.done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// Cleanup kills a client still running and removes the directories owned by
+// the node. A data directory the node was given to keep stays in place.
+func (n *Node) Cleanup(ctx context.Context) error {
+	err := n.Kill(ctx)
+	for _, dir := range n.tempDirs {
+		err = errors.Join(err, os.RemoveAll(dir))
+	}
+	n.tempDirs = nil
+	return err
+}
+
+// followingReader reads a file that is still being written, waiting for more
+// data at its end until done is closed or the context is cancelled.
+type followingReader struct {
+	ctx  context.Context
+	file *os.File
+	done <-chan struct{}
+	// closed is closed by Close, to end a read waiting for data.
+	closed    chan struct{}
+	closeOnce sync.Once
+}
+
+// followPollInterval is how often a followingReader looks for new data.
+const followPollInterval = 100 * time.Millisecond
+
+func newFollowingReader(ctx context.Context, file *os.File, done <-chan struct{}) *followingReader {
+	return &followingReader{ctx: ctx, file: file, done: done, closed: make(chan struct{})}
+}
+
+func (r *followingReader) Read(p []byte) (int, error) {
+	for {
+		// Whether the writer was done is read before the file, so that data
+		// written just before it finished is not lost.
+		finished := false
+		select {
+		case <-r.done:
+			finished = true
+		default:
+		}
+		count, err := r.file.Read(p)
+		if count > 0 || (err != nil && err != io.EOF) {
+			return count, err
+		}
+		if finished {
+			return 0, io.EOF
+		}
+		select {
+		case <-r.ctx.Done():
+			return 0, io.EOF
+		case <-r.closed:
+			return 0, io.EOF
+		case <-r.done:
+		case <-time.After(followPollInterval):
+		}
+	}
+}
+
+func (r *followingReader) Close() error {
+	r.closeOnce.Do(func() { close(r.closed) })
+	return r.file.Close()
+}
@@ -0,0 +1,788 @@
+// Copyright 2024 Fantom Foundation
+// This file is part of Norma System Testing Infrastructure for Sonic.
+//
+// Norma is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// Norma is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with Norma. If not, see <http://www.gnu.org/licenses/>.
+
+package process
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"math/rand"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"sync"
+	"sync/atomic"
+
+	"github.com/0xsoniclabs/norma/driver"
+	"github.com/0xsoniclabs/norma/driver/docker"
+	"github.com/0xsoniclabs/norma/driver/network"
+	"github.com/0xsoniclabs/norma/driver/network/rpc"
+	"github.com/0xsoniclabs/norma/driver/node"
+	"github.com/0xsoniclabs/norma/driver/parser"
+	rpcdriver "github.com/0xsoniclabs/norma/driver/rpc"
+	"github.com/0xsoniclabs/norma/genesis"
+	"github.com/0xsoniclabs/norma/load/app"
+	"github.com/0xsoniclabs/norma/load/controller"
+	"github.com/0xsoniclabs/norma/load/shaper"
+	"github.com/0xsoniclabs/sonic/opera"
+	"github.com/ethereum/go-ethereum/core/types"
+)
+
+// ProcessNetwork is a network running each of its nodes as a client process
+// on the host, without Docker. It runs the client binaries it is given
+// instead of images, so a scenario tests whatever build they come from.
+//
+// There are no containers to isolate the nodes in, so the features relying
+// on them are not supported: partitions, link shaping, throttling, clock
+// offsets, resource limits, topologies other than a full mesh and client
+// images other than the default one. CheckScenario reports the steps of a
+// scenario relying on them.
+type ProcessNetwork struct {
+	ctx            context.Context
+	config         driver.NetworkConfig
+	binaries       Binaries
+	primaryAccount *app.Account
+
+	// nodes provide a register for all nodes in the network.
+	nodes map[driver.NodeID]*Node
+
+	// suspended tracks nodes whose client process is known to be down, as
+	// for the LocalNetwork. Guarded by nodesMutex.
+	suspended map[driver.Node]bool
+
+	// nodesMutex synchronizes access to the list of nodes and the suspended
+	// set.
+	nodesMutex sync.Mutex
+
+	// bootstrapped is set once the first node has joined and never resets,
+	// as for the LocalNetwork. Guarded by nodesMutex.
+	bootstrapped bool
+
+	// apps maintains a list of all applications created on the network.
+	apps []driver.Application
+
+	// appsMutex synchronizes access to the list of applications.
+	appsMutex sync.Mutex
+
+	// nextAppId is the id to use for next created applications.
+	nextAppId atomic.Uint32
+
+	// listeners is the set of registered NetworkListeners.
+	listeners map[driver.NetworkListener]bool
+
+	// listenerMutex is synching access to listeners
+	listenerMutex sync.Mutex
+
+	rpcWorkerPool *rpc.RpcWorkerPool
+
+	// a context for app management operations on the network
+	appContext app.AppContext
+
+	// appContextMu guards lazy initialization of appContext.
+	appContextMu sync.Mutex
+
+	// temporary host directory holding the genesis.json file
+	genesisTmpDir string
+	// host path to the generated genesis.json file
+	genesisJsonPath string
+}
+
+// Binaries locates the client binaries a ProcessNetwork runs.
+type Binaries struct {
+	Sonicd    string
+	Sonictool string
+}
+
+// FindBinaries locates the client binaries in the given directory or, if it
+// is empty, on the PATH.
+func FindBinaries(dir string) (Binaries, error) {
+	find := func(name string) (string, error) {
+		if dir == "" {
+			path, err := exec.LookPath(name)
+			if err != nil {
+				return "", fmt.Errorf("%s not found on the PATH: %w", name, err)
+			}
+			return path, nil
+		}
+		path, err := filepath.Abs(filepath.Join(dir, name))
+		if err != nil {
+			return "", err
+		}
+		if _, err := os.Stat(path); err != nil {
+			return "", fmt.Errorf("%s not found in %s: %w", name, dir, err)
+		}
+		return path, nil
+	}
+	sonicd, err := find("sonicd")
+	if err != nil {
+		return Binaries{}, err
+	}
+	sonictool, err := find("sonictool")
+	if err != nil {
+		return Binaries{}, err
+	}
+	return Binaries{Sonicd: sonicd, Sonictool: sonictool}, nil
+}
+
+// NewProcessNetwork creates an empty network running the given binaries. It
+// fails for configurations only a Docker based network can implement.
+func NewProcessNetwork(ctx context.Context, config *driver.NetworkConfig, binaries Binaries) (*ProcessNetwork, error) {
+	if err := checkNetworkConfig(config); err != nil {
+		return nil, err
+	}
+
+	// Create chain account, which will be used for the initialization
+	primaryAccount, err := app.NewAccount(0, treasureAccountPrivateKey, fakeNetworkID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create primary account; %v", err)
+	}
+
+	net := &ProcessNetwork{
+		ctx:            ctx,
+		config:         *config,
+		binaries:       binaries,
+		primaryAccount: primaryAccount,
+		nodes:          map[driver.NodeID]*Node{},
+		suspended:      map[driver.Node]bool{},
+		apps:           []driver.Application{},
+		listeners:      map[driver.NetworkListener]bool{},
+		rpcWorkerPool:  rpc.NewRpcWorkerPool(ctx),
+	}
+
+	if err := net.prepareGenesis(ctx); err != nil {
+		return nil, errors.Join(fmt.Errorf("failed to prepare genesis: %w", err), net.Shutdown())
+	}
+
+	// Let the RPC pool to start RPC workers when a node start.
+	net.RegisterListener(net.rpcWorkerPool)
+
+	return net, nil
+}
+
+// checkNetworkConfig rejects the parts of a network configuration that need
+// containers.
+func checkNetworkConfig(config *driver.NetworkConfig) error {
+	if config.RoundTripTime > 0 || len(config.Links) > 0 {
+		return unsupported("link shaping")
+	}
+	if !config.Topology.IsFullMesh() {
+		return unsupported("a topology other than a full mesh")
+	}
+	for _, validator := range config.Validators {
+		if err := checkImage(validator.ImageName); err != nil {
+			return err
+		}
+	}
+	for _, image := range config.ClientImages {
+		if err := checkImage(image); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// checkImage accepts only the default client image, which stands for the
+// binaries of the network.
+func checkImage(image string) error {
+	if driver.ResolveClientImageName(image) != driver.DefaultClientDockerImageName {
+		return unsupported(fmt.Sprintf("client image %q", image))
+	}
+	return nil
+}
+
+// unsupported returns the error of a feature the process backend lacks.
+func unsupported(feature string) error {
+	return fmt.Errorf("%s is not supported by the process backend", feature)
+}
+
+// unsupportedSteps are the step functions a process network cannot run,
+// with the feature they rely on.
+var unsupportedSteps = map[parser.StepFunction]string{
+	parser.FuncKillSonic:       "killing a client while keeping its container",
+	parser.FuncHealDb:          "healing the database of a killed client",
+	parser.FuncPauseNode:       "freezing the container of a node",
+	parser.FuncResumeNode:      "freezing the container of a node",
+	parser.FuncSetClockSkew:    "a clock offset",
+	parser.FuncUpdateResources: "a resource limit",
+	parser.FuncUpgradeNode:     "swapping the client image of a node",
+	parser.FuncSnapshot:        "taking a snapshot of the containers",
+	parser.FuncChaos:           "disrupting the containers of nodes",
+	parser.FuncPartition:       "partitioning the network",
+	parser.FuncHeal:            "partitioning the network",
+	parser.FuncSetLink:         "link shaping",
+	parser.FuncThrottleNode:    "throttling",
+	parser.FuncUnthrottleNode:  "throttling",
+	parser.FuncConnect:         "changing the peering of nodes",
+	parser.FuncDisconnect:      "changing the peering of nodes",
+}
+
+// CheckScenario reports every step of the given scenario a process network
+// cannot run, so a scenario fails before any client is started rather than
+// midway.
+func CheckScenario(scenario *parser.Scenario) error {
+	var errs []error
+	for i, step := range scenario.Steps {
+		// The branches of a parallel step and the steps of a schedule step
+		// are held to the same terms.
+		for _, step := range step.Steps() {
+			if feature, found := unsupportedSteps[step.Function]; found {
+				errs = append(errs, fmt.Errorf("step %d (%s): %w", i+1, step.Function, unsupported(feature)))
+				continue
+			}
+			if step.Function != parser.FuncStartNode {
+				continue
+			}
+			if err := checkStartNode(&step); err != nil {
+				errs = append(errs, fmt.Errorf("step %d (%s): %w", i+1, step.Function, err))
+			}
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// checkStartNode rejects the parameters of a startNode step that CreateNode
+// would reject for the nodes it starts.
+func checkStartNode(step *parser.Step) error {
+	switch {
+	case step.ClockOffset != nil:
+		return unsupported("a clock offset")
+	case step.CPUs > 0 || step.Memory > 0 || step.BlkioWeight > 0:
+		return unsupported("a resource limit")
+	case step.ClientsPerContainer != nil && *step.ClientsPerContainer > 1:
+		return unsupported("packing clients into a container")
+	case step.LiveCache > 0 || step.Cache > 0 || step.GoMemLimit > 0:
+		return unsupported("a client memory setting")
+	}
+	return checkImage(step.ImageName)
+}
+
+// prepareGenesis generates the genesis.json file for the network, as the
+// LocalNetwork does, but with the version reported by the sonicd binary.
+func (n *ProcessNetwork) prepareGenesis(ctx context.Context) error {
+	version, err := n.clientVersion(ctx)
+	if err != nil {
+		return err
+	}
+
+	tmpDir, err := os.MkdirTemp("", "norma-genesis-*")
+	if err != nil {
+		return fmt.Errorf("failed to create genesis temp dir: %w", err)
+	}
+
+	rules := opera.FakeNetRules(opera.GetSonicUpgrades())
+	if err := genesis.ApplyNetworkRulesPatch(&rules, n.config.NetworkRules); err != nil {
+		return errors.Join(fmt.Errorf("failed to apply network rules to genesis: %w", err), os.RemoveAll(tmpDir))
+	}
+
+	genesisPath := filepath.Join(tmpDir, "genesis.json")
+	if err := genesis.GenerateJsonGenesis(
+		genesisPath,
+		driver.GetValidatorStakes(n.config.Validators),
+		&rules,
+		[]string{version},
+		n.config.InitialState,
+	); err != nil {
+		return errors.Join(fmt.Errorf("failed to generate genesis file: %w", err), os.RemoveAll(tmpDir))
+	}
+
+	n.genesisTmpDir = tmpDir
+	n.genesisJsonPath = genesisPath
+	return nil
+}
+
+// clientVersion has the sonicd binary report its own version.
+func (n *ProcessNetwork) clientVersion(ctx context.Context) (string, error) {
+	output, err := exec.CommandContext(ctx, n.binaries.Sonicd, "version").CombinedOutput() //#nosec G204 -- binary is set by the user
+	if err != nil {
+		return "", fmt.Errorf("failed to ask %s for its client version: %w\n%s",
+			n.binaries.Sonicd, err, strings.TrimSpace(string(output)))
+	}
+	version, found := docker.ParseReportedVersion(output)
+	if !found {
+		return "", fmt.Errorf("%s reported no client version:\n%s",
+			n.binaries.Sonicd, strings.TrimSpace(string(output)))
+	}
+	return version, nil
+}
+
+// CreateNode creates nodes in the network during run.
+func (n *ProcessNetwork) CreateNode(config *driver.NodeConfig) (driver.Node, error) {
+	if config.ClockOffset != nil {
+		return nil, unsupported("a clock offset")
+	}
+	if config.Resources != (driver.Resources{}) {
+		return nil, unsupported("a resource limit")
+	}
+	if config.Container != "" {
+		return nil, unsupported("packing clients into a container")
+	}
+	if config.Memory != (driver.ClientMemory{}) {
+		return nil, unsupported("a client memory setting")
+	}
+	if err := checkImage(config.Image); err != nil {
+		return nil, err
+	}
+
+	if config.Cheater {
+		// The twin signs with the key of the same validator and has to be
+		// on the same chain for its events to conflict with the node's.
+		twin := *config
+		twin.Name = "cheater-" + config.Name
+		twin.Cheater = false
+		twin.DataVolume = nil
+		if _, err := n.createNode(n.ctx, &twin); err != nil {
+			return nil, err
+		}
+	}
+	return n.createNode(n.ctx, config)
+}
+
+// createNode starts a node on a data directory of its own and connects it
+// with all the other nodes of the network.
+func (n *ProcessNetwork) createNode(ctx context.Context, config *driver.NodeConfig) (*Node, error) {
+	n.nodesMutex.Lock()
+	bootstrap := !n.bootstrapped
+	n.nodesMutex.Unlock()
+
+	// A data volume is kept in the run's output directory, so a later node
+	// of the same volume finds the database; any other data directory goes
+	// with the node.
+	var dataDir string
+	var tempDirs []string
+	if config.DataVolume != nil {
+		dataDir = filepath.Join(n.config.OutputDir, *config.DataVolume)
+	} else {
+		dir, err := os.MkdirTemp("", fmt.Sprintf("norma-%s-*", config.Name))
+		if err != nil {
+			return nil, fmt.Errorf("failed to create datadir: %w", err)
+		}
+		dataDir = filepath.Join(dir, "datadir")
+		tempDirs = append(tempDirs, dir)
+	}
+
+	opera := &node.OperaNodeConfig{
+		ValidatorId:   config.ValidatorId,
+		NetworkConfig: &n.config,
+	}
+	started, err := startNode(ctx, n.binaries, &nodeConfig{
+		label:          config.Name,
+		failing:        config.Failing,
+		validatorId:    config.ValidatorId,
+		dataDir:        dataDir,
+		genesisPath:    n.genesisJsonPath,
+		logsDir:        filepath.Join(n.config.OutputDir, "client-logs"),
+		configToml:     node.ClientConfigToml(opera, bootstrap),
+		discovery:      true,
+		extraArguments: config.ExtraArguments,
+	})
+	if err != nil {
+		for _, dir := range tempDirs {
+			err = errors.Join(err, os.RemoveAll(dir))
+		}
+		return nil, fmt.Errorf("failed to start node %s; %w", config.Name, err)
+	}
+	started.tempDirs = tempDirs
+
+	if err := n.addNodeIntoNetwork(ctx, started); err != nil {
+		return nil, errors.Join(fmt.Errorf("failed to connect node; %w", err), started.Cleanup(ctx))
+	}
+	n.listenerMutex.Lock()
+	for listener := range n.listeners {
+		listener.AfterNodeCreation(started)
+	}
+	n.listenerMutex.Unlock()
+	return started, nil
+}
+
+// addNodeIntoNetwork peers the node with all nodes in the network and adds it
+// into the list of nodes. As for the LocalNetwork, it is best-effort: the
+// node joins as long as at least one existing node could be reached.
+func (n *ProcessNetwork) addNodeIntoNetwork(ctx context.Context, added *Node) error {
+	n.nodesMutex.Lock()
+	defer n.nodesMutex.Unlock()
+
+	id, err := added.GetNodeID()
+	if err != nil {
+		return fmt.Errorf("failed to get node id; %v", err)
+	}
+
+	var attempted, succeeded int
+	for _, other := range n.nodes {
+		// A suspended node's client is known to be down; dialing it would
+		// only burn the retry budget. It re-peers itself when resumed.
+		if n.suspended[other] {
+			continue
+		}
+		attempted++
+		if err := other.AddPeer(ctx, id); err != nil {
+			slog.Warn("failed to add peer to node", "node", other.GetLabel(), "error", err)
+		} else {
+			succeeded++
+		}
+	}
+	if attempted > 0 && succeeded == 0 {
+		return fmt.Errorf("failed to add peer; no existing node was reachable")
+	}
+	n.nodes[id] = added
+	n.bootstrapped = true
+	return nil
+}
+
+// ReconnectNode re-establishes peer connections between the given node
+// and the rest of the network, under the node's current enode ID.
+func (n *ProcessNetwork) ReconnectNode(ctx context.Context, driverNode driver.Node) error {
+	target, ok := driverNode.(*Node)
+	if !ok {
+		return fmt.Errorf("ReconnectNode: unsupported node type")
+	}
+
+	n.nodesMutex.Lock()
+	for id, existing := range n.nodes {
+		if existing == target {
+			delete(n.nodes, id)
+			break
+		}
+	}
+	n.nodesMutex.Unlock()
+
+	return n.addNodeIntoNetwork(ctx, target)
+}
+
+func (n *ProcessNetwork) RemoveNode(node driver.Node) error {
+	n.listenerMutex.Lock()
+	for listener := range n.listeners {
+		listener.BeforeNodeRemoval(node)
+	}
+	n.listenerMutex.Unlock()
+
+	n.nodesMutex.Lock()
+	defer n.nodesMutex.Unlock()
+	id, err := node.GetNodeID()
+	if err != nil {
+		return fmt.Errorf("failed to get node id; %v", err)
+	}
+
+	delete(n.nodes, id)
+	delete(n.suspended, node)
+	for _, other := range n.nodes {
+		if n.suspended[other] {
+			continue
+		}
+		if err = other.RemovePeer(n.ctx, id); err != nil {
+			return fmt.Errorf("failed to remove peer; %v", err)
+		}
+	}
+	return nil
+}
+
+func (n *ProcessNetwork) SendTransaction(tx *types.Transaction, source driver.TransactionSource) {
+	n.rpcWorkerPool.SendTransaction(tx, source)
+}
+
+func (n *ProcessNetwork) RegisterTransactionObserver(observer driver.TransactionObserver) {
+	n.rpcWorkerPool.RegisterObserver(observer)
+}
+
+func (n *ProcessNetwork) DialRandomRpc() (rpcdriver.Client, error) {
+	nodes := n.GetActiveNodes()
+	if len(nodes) == 0 {
+		return nil, driver.ErrEmptyNetwork
+	}
+	reliable := make([]driver.Node, 0, len(nodes))
+	for _, node := range nodes {
+		if !node.IsExpectedFailure() {
+			reliable = append(reliable, node)
+		}
+	}
+	if len(reliable) == 0 {
+		reliable = nodes // use failing nodes if there are no reliable nodes
+	}
+	for _, i := range rand.Perm(len(reliable)) {
+		chosen := reliable[i]
+		client, err := chosen.DialRpc(n.ctx)
+		if err != nil {
+			slog.Warn("node unreachable in DialRandomRpc, skipping",
+				"node", chosen.GetLabel(), "error", err)
+			continue
+		}
+		return client, nil
+	}
+	return nil, fmt.Errorf("no reachable node found among %d active nodes", len(reliable))
+}
+
+func (n *ProcessNetwork) ApplyNetworkRules(ctx context.Context, rules driver.NetworkRules) error {
+	client, err := n.DialRandomRpc()
+	if err != nil {
+		return fmt.Errorf("failed to connect to network: %w", err)
+	}
+	defer client.Close()
+
+	return network.ApplyNetworkRules(ctx, client, rules)
+}
+
+func (n *ProcessNetwork) AdvanceEpoch(ctx context.Context, epochIncrement int) error {
+	client, err := n.DialRandomRpc()
+	if err != nil {
+		return fmt.Errorf("failed to connect to network: %w", err)
+	}
+	defer client.Close()
+
+	return network.AdvanceEpoch(ctx, client, epochIncrement)
+}
+
+func (n *ProcessNetwork) WaitForEpochChange(ctx context.Context) error {
+	client, err := n.DialRandomRpc()
+	if err != nil {
+		return fmt.Errorf("failed to connect to network: %w", err)
+	}
+	defer client.Close()
+
+	return network.WaitForEpochChange(ctx, client)
+}
+
+// treasureAccountPrivateKey is an account with tokens that can be used to
+// initiate test applications and accounts.
+const treasureAccountPrivateKey = "163f5f0f9a621d72fedd85ffca3d08d131ab4e812181e0d30ffd1c885d20aac7" // Fakenet validator 1
+
+const fakeNetworkID = 0xfa3
+
+type processApplication struct {
+	name       string
+	controller *controller.AppController
+	config     *driver.ApplicationConfig
+	cancel     context.CancelFunc
+	done       *sync.WaitGroup
+}
+
+func (a *processApplication) Start(ctx context.Context) error {
+	ctx, cancel := context.WithCancel(ctx)
+	a.cancel = cancel
+
+	a.done.Add(1)
+	go func() {
+		defer a.done.Done()
+		if err := a.controller.Run(ctx); err != nil {
+			slog.Error("failed to run load app", "error", err)
+		}
+	}()
+	return nil
+}
+
+func (a *processApplication) Stop() error {
+	if a.cancel != nil {
+		a.cancel()
+	}
+	a.cancel = nil
+	a.done.Wait()
+	return nil
+}
+
+func (a *processApplication) Config() *driver.ApplicationConfig {
+	return a.config
+}
+
+func (a *processApplication) GetNumberOfUsers() int {
+	return a.controller.GetNumberOfUsers()
+}
+
+func (a *processApplication) GetSentTransactions(user int) (uint64, error) {
+	return a.controller.GetTransactionsSentBy(user)
+}
+
+func (a *processApplication) GetReceivedTransactions() (uint64, error) {
+	return a.controller.GetReceivedTransactions()
+}
+
+// ensureAppContext initializes the appContext lazily on first use.
+// It requires at least one node to be running (for RPC connectivity).
+func (n *ProcessNetwork) ensureAppContext() error {
+	n.appContextMu.Lock()
+	defer n.appContextMu.Unlock()
+	if n.appContext != nil {
+		return nil
+	}
+	appCtx, err := app.NewContext(n.ctx, n, n.primaryAccount, n.config.NetworkRules)
+	if err != nil {
+		return fmt.Errorf("failed to create app context: %w", err)
+	}
+	n.appContext = appCtx
+	return nil
+}
+
+func (n *ProcessNetwork) CreateApplication(ctx context.Context, config *driver.ApplicationConfig) (driver.Application, error) {
+	if err := n.ensureAppContext(); err != nil {
+		return nil, fmt.Errorf("failed to initialize app context: %w", err)
+	}
+
+	appId := n.nextAppId.Add(1)
+	application, err := app.NewApplication(config.Type, n.appContext, 0, appId)
+	if err != nil {
+		return nil, fmt.Errorf("failed to initialize on-chain app; %v", err)
+	}
+
+	sh, err := shaper.ParseRate(config.Rate)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse shaper; %v", err)
+	}
+
+	appController, err := controller.NewAppController(config.Name, application, sh, config.Users, n.appContext, n)
+	if err != nil {
+		return nil, err
+	}
+
+	app := &processApplication{
+		name:       config.Name,
+		controller: appController,
+		config:     config,
+		done:       &sync.WaitGroup{},
+	}
+
+	n.appsMutex.Lock()
+	n.apps = append(n.apps, app)
+	n.appsMutex.Unlock()
+
+	n.listenerMutex.Lock()
+	for listener := range n.listeners {
+		listener.AfterApplicationCreation(app)
+	}
+	n.listenerMutex.Unlock()
+
+	return app, nil
+}
+
+func (n *ProcessNetwork) GetActiveNodes() []driver.Node {
+	n.nodesMutex.Lock()
+	defer n.nodesMutex.Unlock()
+	res := make([]driver.Node, 0, len(n.nodes))
+	for _, node := range n.nodes {
+		if node.IsRunning() {
+			res = append(res, node)
+		}
+	}
+	return res
+}
+
+func (n *ProcessNetwork) GetActiveApplications() []driver.Application {
+	n.appsMutex.Lock()
+	defer n.appsMutex.Unlock()
+	return n.apps
+}
+
+func (n *ProcessNetwork) RegisterListener(listener driver.NetworkListener) {
+	n.listenerMutex.Lock()
+	n.listeners[listener] = true
+	n.listenerMutex.Unlock()
+}
+
+func (n *ProcessNetwork) UnregisterListener(listener driver.NetworkListener) {
+	n.listenerMutex.Lock()
+	delete(n.listeners, listener)
+	n.listenerMutex.Unlock()
+}
+
+func (n *ProcessNetwork) SuspendNode(node driver.Node) {
+	n.nodesMutex.Lock()
+	n.suspended[node] = true
+	n.nodesMutex.Unlock()
+
+	n.listenerMutex.Lock()
+	for listener := range n.listeners {
+		listener.BeforeNodeRemoval(node)
+	}
+	n.listenerMutex.Unlock()
+}
+
+func (n *ProcessNetwork) ResumeNode(node driver.Node) {
+	n.nodesMutex.Lock()
+	delete(n.suspended, node)
+	n.nodesMutex.Unlock()
+
+	n.listenerMutex.Lock()
+	for listener := range n.listeners {
+		listener.AfterNodeCreation(node)
+	}
+	n.listenerMutex.Unlock()
+}
+
+func (n *ProcessNetwork) PartitionNodes(context.Context, [][]driver.Node) error {
+	return unsupported("partitioning the network")
+}
+
+func (n *ProcessNetwork) HealPartition(context.Context) error {
+	return unsupported("partitioning the network")
+}
+
+func (n *ProcessNetwork) SetLink(context.Context, driver.LinkRule) error {
+	return unsupported("link shaping")
+}
+
+func (n *ProcessNetwork) ThrottleNode(context.Context, driver.Node, driver.ThrottleConfig) error {
+	return unsupported("throttling")
+}
+
+func (n *ProcessNetwork) UnthrottleNode(context.Context, driver.Node) error {
+	return unsupported("throttling")
+}
+
+func (n *ProcessNetwork) ConnectNodes(context.Context, driver.Node, driver.Node) error {
+	return unsupported("changing the peering of nodes")
+}
+
+func (n *ProcessNetwork) DisconnectNodes(context.Context, driver.Node, driver.Node) error {
+	return unsupported("changing the peering of nodes")
+}
+
+func (n *ProcessNetwork) Shutdown() error {
+	var errs []error
+
+	// cleanups shall be completed, even after execution context is canceled.
+	ctx := context.Background()
+
+	// First stop all generators.
+	for _, app := range n.apps {
+		if err := app.Stop(); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	n.apps = n.apps[:0]
+
+	if n.appContext != nil {
+		n.appContext.Close()
+	}
+
+	// Second, shut down the nodes.
+	for _, node := range n.nodes {
+		if err := node.Stop(ctx); err != nil {
+			errs = append(errs, err)
+		}
+		if err := node.Cleanup(ctx); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	n.nodes = map[driver.NodeID]*Node{}
+
+	errs = append(errs, n.rpcWorkerPool.Close())
+	if n.genesisTmpDir != "" {
+		errs = append(errs, os.RemoveAll(n.genesisTmpDir))
+		n.genesisTmpDir = ""
+		n.genesisJsonPath = ""
+	}
+
+	return errors.Join(errs...)
+}
@@ -0,0 +1,338 @@
+// Copyright 2024 Fantom Foundation
+// This file is part of Norma System Testing Infrastructure for Sonic.
+//
+// Norma is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// Norma is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with Norma. If not, see <http://www.gnu.org/licenses/>.
+
+package process
+
+import (
+	"context"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/0xsoniclabs/norma/driver"
+	"github.com/0xsoniclabs/norma/driver/network"
+	"github.com/0xsoniclabs/norma/driver/node"
+	"github.com/0xsoniclabs/norma/driver/parser"
+	"github.com/stretchr/testify/require"
+)
+
+func TestProcessNetworkIsNetwork(t *testing.T) {
+	var net ProcessNetwork
+	var _ driver.Network = &net
+}
+
+func TestNodeIsNode(t *testing.T) {
+	var n Node
+	var _ driver.Node = &n
+}
+
+func testNode(validatorId *int) *Node {
+	return &Node{
+		label:       "test",
+		validatorId: validatorId,
+		dataDir:     "/data/test",
+		p2pPort:     4000,
+		ports: map[network.Port]network.Port{
+			node.OperaRpcService.Port:   4001,
+			node.OperaWsService.Port:    4002,
+			node.OperaDebugService.Port: 4003,
+		},
+		done: make(chan struct{}),
+	}
+}
+
+func TestNode_BuildSonicdCmd_ListensOnAllocatedPorts(t *testing.T) {
+	args := strings.Join(testNode(nil).buildSonicdCmd("", true, ""), " ")
+	require.Contains(t, args, "--datadir=/data/test")
+	require.Contains(t, args, "--port 4000")
+	require.Contains(t, args, "--http.port 4001")
+	require.Contains(t, args, "--ws.port 4002")
+	require.Contains(t, args, "--pprof.port 4003")
+	require.Contains(t, args, "--config /data/test/config.toml")
+	require.NotContains(t, args, "--validator.id")
+	require.NotContains(t, args, "--nodiscover")
+}
+
+func TestNode_BuildSonicdCmd_AddsValidatorAndExtraArguments(t *testing.T) {
+	id := 2
+	args := strings.Join(testNode(&id).buildSonicdCmd("0xc004", false, "--foo  --bar=1"), " ")
+	require.Contains(t, args, "--validator.id 2 --validator.pubkey 0xc004")
+	require.Contains(t, args, "--validator.password /data/test/password.txt --mode rpc")
+	require.Contains(t, args, "--nodiscover")
+	require.True(t, strings.HasSuffix(args, "--foo --bar=1"), args)
+}
+
+func TestNode_GetServiceUrl_MapsServicesToHostPorts(t *testing.T) {
+	n := testNode(nil)
+	url, err := n.GetServiceUrl(&node.OperaRpcService)
+	require.NoError(t, err)
+	require.Equal(t, driver.URL("http://127.0.0.1:4001"), *url)
+
+	url, err = n.GetServiceUrl(&node.OperaWsService)
+	require.NoError(t, err)
+	require.Equal(t, driver.URL("ws://127.0.0.1:4002"), *url)
+
+	require.Equal(t, 4003, n.MetricsPort())
+
+	_, err = n.GetServiceUrl(&network.ServiceDescription{Name: "other", Port: 1234})
+	require.ErrorContains(t, err, "does not offer service other")
+}
+
+func TestNode_StopAndKill_IgnoreNodesWithoutClient(t *testing.T) {
+	n := testNode(nil)
+	require.False(t, n.IsRunning())
+	require.NoError(t, n.Stop(t.Context()))
+	require.NoError(t, n.Kill(t.Context()))
+	_, err := n.StreamLog(t.Context())
+	require.ErrorContains(t, err, "has no client process")
+}
+
+func TestIsDataDirEmpty_IgnoresFilesWrittenBeforeGenesis(t *testing.T) {
+	dir := t.TempDir()
+	require.True(t, isDataDirEmpty(filepath.Join(dir, "missing")))
+
+	require.NoError(t, os.Mkdir(filepath.Join(dir, "keystore"), 0755))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "config.toml"), nil, 0644))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "password.txt"), nil, 0644))
+	require.True(t, isDataDirEmpty(dir))
+
+	require.NoError(t, os.Mkdir(filepath.Join(dir, "chaindata"), 0755))
+	require.False(t, isDataDirEmpty(dir))
+}
+
+func TestFollowingReader_ReadsDataAppendedUntilDone(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "log")
+	out, err := os.Create(path)
+	require.NoError(t, err)
+	defer out.Close()
+	in, err := os.Open(path)
+	require.NoError(t, err)
+
+	done := make(chan struct{})
+	reader := newFollowingReader(t.Context(), in, done)
+	defer reader.Close()
+
+	_, err = out.WriteString("first\n")
+	require.NoError(t, err)
+	go func() {
+		time.Sleep(2 * followPollInterval)
+		_, _ = out.WriteString("second\n")
+		close(done)
+	}()
+
+	data, err := io.ReadAll(reader)
+	require.NoError(t, err)
+	require.Equal(t, "first\nsecond\n", string(data))
+}
+
+func TestFollowingReader_EndsWhenClosed(t *testing.T) {
+	in, err := os.Create(filepath.Join(t.TempDir(), "log"))
+	require.NoError(t, err)
+	reader := newFollowingReader(t.Context(), in, make(chan struct{}))
+
+	go func() {
+		time.Sleep(2 * followPollInterval)
+		_ = reader.Close()
+	}()
+	// Depending on when the file is closed, the read ends with EOF or with
+	// the error of reading a closed file; either way, it does not hang.
+	_, _ = io.ReadAll(reader)
+}
+
+func TestFollowingReader_EndsWhenContextIsCancelled(t *testing.T) {
+	in, err := os.Create(filepath.Join(t.TempDir(), "log"))
+	require.NoError(t, err)
+	ctx, cancel := context.WithTimeout(t.Context(), 2*followPollInterval)
+	defer cancel()
+	reader := newFollowingReader(ctx, in, make(chan struct{}))
+	defer reader.Close()
+
+	data, err := io.ReadAll(reader)
+	require.NoError(t, err)
+	require.Empty(t, data)
+}
+
+func TestCheckNetworkConfig_RejectsFeaturesNeedingContainers(t *testing.T) {
+	require.NoError(t, checkNetworkConfig(&driver.NetworkConfig{
+		Validators: driver.DefaultValidators(t.Name()),
+	}))
+
+	tests := map[string]driver.NetworkConfig{
+		"link shaping":  {RoundTripTime: time.Millisecond},
+		"links":         {Links: []driver.LinkRule{{}}},
+		"topology":      {Topology: driver.Topology{Kind: "ring"}},
+		"client image":  {Validators: driver.Validators{{ImageName: "sonic:v2.0.0"}}},
+		"client images": {ClientImages: []string{"sonic:v2.0.0"}},
+	}
+	for name, config := range tests {
+		t.Run(name, func(t *testing.T) {
+			require.ErrorContains(t, checkNetworkConfig(&config), "not supported by the process backend")
+		})
+	}
+}
+
+func TestProcessNetwork_CreateNode_RejectsFeaturesNeedingContainers(t *testing.T) {
+	net := &ProcessNetwork{}
+	offset := time.Second
+	tests := map[string]driver.NodeConfig{
+		"clock offset": {Name: "A", ClockOffset: &offset},
+		"resources":    {Name: "A", Resources: driver.Resources{CPUs: 1}},
+		"image":        {Name: "A", Image: "sonic:v2.0.0"},
+	}
+	for name, config := range tests {
+		t.Run(name, func(t *testing.T) {
+			_, err := net.CreateNode(&config)
+			require.ErrorContains(t, err, "not supported by the process backend")
+		})
+	}
+}
+
+func TestProcessNetwork_UnsupportedOperationsFail(t *testing.T) {
+	net := &ProcessNetwork{}
+	ctx := t.Context()
+	require.Error(t, net.PartitionNodes(ctx, nil))
+	require.Error(t, net.HealPartition(ctx))
+	require.Error(t, net.SetLink(ctx, driver.LinkRule{}))
+	require.Error(t, net.ThrottleNode(ctx, nil, driver.ThrottleConfig{}))
+	require.Error(t, net.UnthrottleNode(ctx, nil))
+	require.Error(t, net.ConnectNodes(ctx, nil, nil))
+	require.Error(t, net.DisconnectNodes(ctx, nil, nil))
+}
+
+func TestCheckScenario_ReportsStepsNeedingContainers(t *testing.T) {
+	offset := time.Second
+	scenario := &parser.Scenario{Steps: []parser.Step{
+		{Function: parser.FuncStartNode, Identifier: "A"},
+		{Function: parser.FuncStartNode, Identifier: "B", ClockOffset: &offset},
+		{Function: parser.FuncRunApp},
+		{Function: parser.FuncParallel, Branches: []parser.Step{
+			{Function: parser.FuncPauseNode, Identifier: "A"},
+			{Function: parser.FuncAdvanceEpoch},
+		}},
+		{Function: parser.FuncSchedule, Branches: []parser.Step{
+			{Function: parser.FuncKillSonic, Identifier: "A"},
+		}},
+		{Function: parser.FuncUpgradeNode, Identifier: "A", ImageName: "sonic:v2.0.0"},
+		{Function: parser.FuncStopApp},
+	}}
+
+	err := CheckScenario(scenario)
+	require.Error(t, err)
+	lines := strings.Split(err.Error(), "\n")
+	require.Len(t, lines, 4)
+	require.Contains(t, lines[0], "step 2 (startNode): a clock offset is not supported by the process backend")
+	require.Contains(t, lines[1], "step 4 (pauseNode)")
+	require.Contains(t, lines[2], "step 5 (killSonic)")
+	require.Contains(t, lines[3], "step 6 (upgradeNode)")
+}
+
+func TestCheckScenario_ReportsStartNodeParametersNeedingContainers(t *testing.T) {
+	offset := time.Second
+	packed := 2
+	tests := map[string]parser.Step{
+		"clock offset": {ClockOffset: &offset},
+		"resources":    {CPUs: 1},
+		"packing":      {ClientsPerContainer: &packed},
+		"memory":       {GoMemLimit: 1 << 30},
+		"image":        {ImageName: "sonic:v2.0.0"},
+	}
+	for name, step := range tests {
+		t.Run(name, func(t *testing.T) {
+			step.Function = parser.FuncStartNode
+			scenario := &parser.Scenario{Steps: []parser.Step{step}}
+			require.ErrorContains(t, CheckScenario(scenario), "not supported by the process backend")
+		})
+	}
+}
+
+func TestCheckScenario_AcceptsStepsOfProcessNodes(t *testing.T) {
+	single := 1
+	scenario := &parser.Scenario{Steps: []parser.Step{
+		{Function: parser.FuncStartNode, Identifier: "A", ClientsPerContainer: &single},
+		{Function: parser.FuncStartNode, Identifier: "B", Cheater: true},
+		{Function: parser.FuncRunApp},
+		{Function: parser.FuncStopNode, Identifier: "A"},
+		{Function: parser.FuncStartNode, Identifier: "A"},
+		{Function: parser.FuncWaitForEpoch},
+		{Function: parser.FuncStopApp},
+	}}
+	require.NoError(t, CheckScenario(scenario))
+}
+
+func TestFindBinaries_LooksInGivenDirectory(t *testing.T) {
+	dir := t.TempDir()
+	_, err := FindBinaries(dir)
+	require.ErrorContains(t, err, "sonicd not found")
+
+	for _, name := range []string{"sonicd", "sonictool"} {
+		require.NoError(t, os.WriteFile(filepath.Join(dir, name), nil, 0755))
+	}
+	binaries, err := FindBinaries(dir)
+	require.NoError(t, err)
+	require.Equal(t, filepath.Join(dir, "sonicd"), binaries.Sonicd)
+	require.Equal(t, filepath.Join(dir, "sonictool"), binaries.Sonictool)
+}
+
+func TestProcessNetwork_CanStartKillAndRestartNode(t *testing.T) {
+	binaries, err := FindBinaries("")
+	if err != nil {
+		t.Skipf("client binaries not available: %v", err)
+	}
+	ctx, cancel := context.WithTimeout(t.Context(), 5*time.Minute)
+	defer cancel()
+
+	config := driver.NetworkConfig{
+		Validators: driver.DefaultValidators(t.Name()),
+		OutputDir:  t.TempDir(),
+	}
+	net, err := NewProcessNetwork(ctx, &config, binaries)
+	require.NoError(t, err)
+	t.Cleanup(func() { require.NoError(t, net.Shutdown()) })
+
+	id := 1
+	volume := "A-datadir"
+	created, err := net.CreateNode(&driver.NodeConfig{
+		Name: "A", Validator: true, ValidatorId: &id, DataVolume: &volume,
+	})
+	require.NoError(t, err)
+	require.Len(t, net.GetActiveNodes(), 1)
+
+	client, err := created.DialRpc(ctx)
+	require.NoError(t, err)
+	_, err = client.BlockNumber(ctx)
+	client.Close()
+	require.NoError(t, err)
+
+	log, err := created.StreamLog(ctx)
+	require.NoError(t, err)
+	defer log.Close()
+
+	require.NoError(t, created.Kill(ctx))
+	require.False(t, created.IsRunning())
+	data, err := io.ReadAll(log)
+	require.NoError(t, err)
+	require.NotEmpty(t, data)
+
+	require.NoError(t, net.RemoveNode(created))
+	restarted, err := net.CreateNode(&driver.NodeConfig{
+		Name: "A", Validator: true, ValidatorId: &id, DataVolume: &volume,
+	})
+	require.NoError(t, err)
+	require.NoError(t, net.ReconnectNode(ctx, restarted))
+}
@@ -4,19 +4,22 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
-	"math/big"
 
 	"github.com/0xsoniclabs/norma/genesis"
-	"github.com/0xsoniclabs/sonic/evmcore"
 	"github.com/0xsoniclabs/sonic/gossip/contract/driverauth100"
-	"github.com/0xsoniclabs/sonic/opera"
 	"github.com/0xsoniclabs/sonic/opera/contracts/driverauth"
-	"github.com/ethereum/go-ethereum/accounts/abi/bind"
 	"github.com/ethereum/go-ethereum/core/types"
 )
 
-// ApplyNetworkRules updates the network rules on the network.
+// ApplyNetworkRules updates the network rules on a network started from a
+// fake genesis.
 func ApplyNetworkRules(ctx context.Context, backend ContractBackend, rules genesis.NetworkRulesPatch) error {
+	return ApplyNetworkRulesAs(ctx, backend, FakeNetSystemTxSigner(), rules)
+}
+
+// ApplyNetworkRulesAs updates the network rules on the network, signing the
+// update with the given signer.
+func ApplyNetworkRulesAs(ctx context.Context, backend ContractBackend, signer SystemTxSigner, rules genesis.NetworkRulesPatch) error {
 	// Bind contract to update network rules
 	contract, err := driverauth100.NewContract(driverauth.ContractAddress, backend)
 	if err != nil {
@@ -27,17 +30,11 @@ func ApplyNetworkRules(ctx context.Context, backend ContractBackend, rules genes
 	if err != nil {
 		return fmt.Errorf("failed to marshal network rules patch; %v", err)
 	}
-	originalRules := opera.FakeNetRules(opera.GetSonicUpgrades())
 
-	// Use Fake ID for the network
-	// Driver owner is the first validator from the list i.e., index 1 (defined in genesis export in genesis.GenerateJsonGenesis)
-	txOpts, err := bind.NewKeyedTransactorWithChainID(evmcore.FakeKey(1), big.NewInt(int64(originalRules.NetworkID)))
+	txOpts, err := signer.transactOpts(ctx)
 	if err != nil {
-		return fmt.Errorf("failed to create txOpts; %v", err)
+		return err
 	}
-	txOpts.Context = ctx
-	txOpts.GasTipCap = systemTxGasTipCap
-	txOpts.GasLimit = systemTxGasLimit
 
 	tx, err := contract.UpdateNetworkRules(txOpts, []byte(patchJSON))
 	if err != nil {
@@ -0,0 +1,245 @@
+// Copyright 2024 Fantom Foundation
+// This file is part of Norma System Testing Infrastructure for Sonic.
+//
+// Norma is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// Norma is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with Norma. If not, see <http://www.gnu.org/licenses/>.
+
+package sim
+
+import (
+	"encoding/json"
+	"fmt"
+	"math/big"
+
+	"github.com/0xsoniclabs/sonic/opera"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/rpc"
+)
+
+// ethAPI serves the part of the eth namespace of a client used by Norma.
+// Accounts have no history: their balance and nonce are the current ones,
+// whatever block is asked for, and the nonce includes pending transactions.
+type ethAPI struct {
+	node *Node
+}
+
+// callArgs are the arguments of eth_call and eth_estimateGas.
+type callArgs struct {
+	From  *common.Address `json:"from"`
+	To    *common.Address `json:"to"`
+	Gas   *hexutil.Uint64 `json:"gas"`
+	Value *hexutil.Big    `json:"value"`
+	Data  *hexutil.Bytes  `json:"data"`
+	Input *hexutil.Bytes  `json:"input"`
+}
+
+func (a callArgs) data() []byte {
+	if a.Input != nil {
+		return *a.Input
+	}
+	if a.Data != nil {
+		return *a.Data
+	}
+	return nil
+}
+
+func (api *ethAPI) ChainId() *hexutil.Big {
+	return (*hexutil.Big)(api.node.chain.chainID)
+}
+
+func (api *ethAPI) BlockNumber() hexutil.Uint64 {
+	return hexutil.Uint64(api.node.getHeight())
+}
+
+func (api *ethAPI) CurrentEpoch() hexutil.Uint64 {
+	return hexutil.Uint64(api.node.headBlock().nextEpoch)
+}
+
+// GetRules returns the rules of the given epoch, which is the current one
+// for latest.
+func (api *ethAPI) GetRules(epoch rpc.BlockNumber) *opera.Rules {
+	number := uint64(epoch)
+	if epoch < 0 {
+		number = api.node.headBlock().nextEpoch
+	}
+	snapshot := api.node.chain.getSnapshot(number)
+	if snapshot == nil {
+		return nil
+	}
+	return &snapshot.rules
+}
+
+func (api *ethAPI) GetBlockByNumber(number rpc.BlockNumber, fullTx bool) (map[string]any, error) {
+	b := api.node.blockByNumber(number)
+	if b == nil {
+		return nil, nil
+	}
+	encoded, err := json.Marshal(b.header)
+	if err != nil {
+		return nil, err
+	}
+	var result map[string]any
+	if err := json.Unmarshal(encoded, &result); err != nil {
+		return nil, err
+	}
+	result["hash"] = b.hash
+	result["epoch"] = hexutil.Uint64(b.epoch)
+	result["timestampNano"] = hexutil.Uint64(b.time.UnixNano())
+	result["uncles"] = []common.Hash{}
+	if fullTx {
+		result["transactions"] = b.transactions
+	} else {
+		hashes := make([]common.Hash, 0, len(b.transactions))
+		for _, tx := range b.transactions {
+			hashes = append(hashes, tx.Hash())
+		}
+		result["transactions"] = hashes
+	}
+	return result, nil
+}
+
+func (api *ethAPI) GetBlockReceipts(number rpc.BlockNumber) types.Receipts {
+	b := api.node.blockByNumber(number)
+	if b == nil {
+		return nil
+	}
+	if b.receipts == nil {
+		return types.Receipts{}
+	}
+	return b.receipts
+}
+
+func (api *ethAPI) GetTransactionReceipt(hash common.Hash) *types.Receipt {
+	receipt := api.node.chain.getReceipt(hash)
+	if receipt == nil || receipt.BlockNumber.Uint64() > api.node.getHeight() {
+		return nil
+	}
+	return receipt
+}
+
+func (api *ethAPI) GetBalance(account common.Address, _ rpc.BlockNumberOrHash) *hexutil.Big {
+	return (*hexutil.Big)(api.node.chain.getBalance(account))
+}
+
+func (api *ethAPI) GetTransactionCount(account common.Address, _ rpc.BlockNumberOrHash) hexutil.Uint64 {
+	return hexutil.Uint64(api.node.chain.getNonce(account))
+}
+
+func (api *ethAPI) GetCode(account common.Address, _ rpc.BlockNumberOrHash) hexutil.Bytes {
+	if isSystemContract(account) {
+		return systemContractCode
+	}
+	return hexutil.Bytes{}
+}
+
+// GasPrice returns zero, as the simulated chain charges no fees.
+func (api *ethAPI) GasPrice() *hexutil.Big {
+	return (*hexutil.Big)(new(big.Int))
+}
+
+// MaxPriorityFeePerGas returns zero, as the simulated chain charges no fees.
+func (api *ethAPI) MaxPriorityFeePerGas() *hexutil.Big {
+	return (*hexutil.Big)(new(big.Int))
+}
+
+// EstimateGas returns the gas of a plain transfer, or a generous limit for
+// calls carrying data.
+func (api *ethAPI) EstimateGas(args callArgs, _ *rpc.BlockNumberOrHash) hexutil.Uint64 {
+	if len(args.data()) == 0 {
+		return 21_000
+	}
+	return 1_000_000
+}
+
+func (api *ethAPI) Call(args callArgs, _ *rpc.BlockNumberOrHash) (hexutil.Bytes, error) {
+	if args.To == nil {
+		return nil, fmt.Errorf("contract creation is not supported")
+	}
+	result, err := api.node.chain.callContract(*args.To, args.data())
+	if err != nil {
+		return nil, fmt.Errorf("execution reverted: %w", err)
+	}
+	return result, nil
+}
+
+func (api *ethAPI) SendRawTransaction(input hexutil.Bytes) (common.Hash, error) {
+	tx := new(types.Transaction)
+	if err := tx.UnmarshalBinary(input); err != nil {
+		return common.Hash{}, err
+	}
+	if err := api.node.chain.sendTransaction(tx); err != nil {
+		return common.Hash{}, err
+	}
+	return tx.Hash(), nil
+}
+
+// adminAPI serves the admin methods Norma calls. Peering has no effect on
+// the simulated chain, so peers are accepted and dropped without checks.
+type adminAPI struct {
+	node *Node
+}
+
+func (api *adminAPI) NodeInfo() map[string]any {
+	return map[string]any{
+		"enode": api.node.id,
+		"name":  api.node.label,
+	}
+}
+
+func (api *adminAPI) AddPeer(string) bool {
+	return true
+}
+
+func (api *adminAPI) RemovePeer(string) bool {
+	return true
+}
+
+func (api *adminAPI) AddTrustedPeer(string) bool {
+	return true
+}
+
+func (api *adminAPI) RemoveTrustedPeer(string) bool {
+	return true
+}
+
+// txpoolAPI serves the content of the pool of pending transactions, which
+// all nodes share.
+type txpoolAPI struct {
+	node *Node
+}
+
+func (api *txpoolAPI) Content() map[string]map[string]map[string]*types.Transaction {
+	pending := map[string]map[string]*types.Transaction{}
+	txs, senders := api.node.chain.getPending()
+	for i, tx := range txs {
+		from := senders[i].Hex()
+		if pending[from] == nil {
+			pending[from] = map[string]*types.Transaction{}
+		}
+		pending[from][fmt.Sprint(tx.Nonce())] = tx
+	}
+	return map[string]map[string]map[string]*types.Transaction{
+		"pending": pending,
+		"queued":  {},
+	}
+}
+
+// dagAPI serves the event DAG of a client, which is empty on a simulated
+// chain as it is not produced by events.
+type dagAPI struct{}
+
+func (api *dagAPI) GetHeads(rpc.BlockNumber) []common.Hash {
+	return []common.Hash{}
+}
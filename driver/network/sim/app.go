@@ -0,0 +1,138 @@
+// Copyright 2024 Fantom Foundation
+// This file is part of Norma System Testing Infrastructure for Sonic.
+//
+// Norma is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// Norma is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with Norma. If not, see <http://www.gnu.org/licenses/>.
+
+package sim
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/0xsoniclabs/norma/driver"
+	"github.com/0xsoniclabs/norma/load/shaper"
+)
+
+// application is a load generator of a simulated network. It sends no
+// transactions: it counts those its users would send at the rate of its
+// shaper, and the chain includes them in the next block it produces.
+type application struct {
+	config   *driver.ApplicationConfig
+	shaper   shaper.Shaper
+	chain    *chain
+	sent     []atomic.Uint64
+	received atomic.Uint64
+
+	cancel context.CancelFunc
+	done   sync.WaitGroup
+}
+
+// appTickInterval is the interval at which applications produce load.
+const appTickInterval = 100 * time.Millisecond
+
+func newApplication(config *driver.ApplicationConfig, chain *chain) (*application, error) {
+	sh, err := shaper.ParseRate(config.Rate)
+	if err != nil {
+		return nil, err
+	}
+	return &application{
+		config: config,
+		shaper: sh,
+		chain:  chain,
+		sent:   make([]atomic.Uint64, max(config.Users, 1)),
+	}, nil
+}
+
+func (a *application) Start(ctx context.Context) error {
+	ctx, cancel := context.WithCancel(ctx)
+	a.cancel = cancel
+
+	a.done.Add(1)
+	go func() {
+		defer a.done.Done()
+		ticker := time.NewTicker(appTickInterval)
+		defer ticker.Stop()
+
+		var pending float64
+		var next int
+		lastUpdate := time.Now()
+		a.shaper.Start(lastUpdate, loadInfo{a})
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case now := <-ticker.C:
+				pending += a.shaper.GetNumMessagesInInterval(lastUpdate, now.Sub(lastUpdate))
+				lastUpdate = now
+				var count uint64
+				for ; pending >= 1; pending-- {
+					a.sent[next].Add(1)
+					next = (next + 1) % len(a.sent)
+					count++
+				}
+				if count > 0 {
+					a.chain.addLoad(a, count)
+				}
+			}
+		}
+	}()
+	return nil
+}
+
+func (a *application) Stop() error {
+	if a.cancel != nil {
+		a.cancel()
+	}
+	a.cancel = nil
+	a.done.Wait()
+	return nil
+}
+
+func (a *application) Config() *driver.ApplicationConfig {
+	return a.config
+}
+
+func (a *application) GetNumberOfUsers() int {
+	return len(a.sent)
+}
+
+func (a *application) GetSentTransactions(user int) (uint64, error) {
+	if user < 0 || user >= len(a.sent) {
+		return 0, nil
+	}
+	return a.sent[user].Load(), nil
+}
+
+func (a *application) GetReceivedTransactions() (uint64, error) {
+	return a.received.Load(), nil
+}
+
+// loadInfo provides the load of an application to its shaper.
+type loadInfo struct {
+	app *application
+}
+
+func (l loadInfo) GetSentTransactions() (uint64, error) {
+	var sum uint64
+	for i := range l.app.sent {
+		sum += l.app.sent[i].Load()
+	}
+	return sum, nil
+}
+
+func (l loadInfo) GetReceivedTransactions() (uint64, error) {
+	return l.app.GetReceivedTransactions()
+}
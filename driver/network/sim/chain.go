@@ -0,0 +1,446 @@
+// Copyright 2024 Fantom Foundation
+// This file is part of Norma System Testing Infrastructure for Sonic.
+//
+// Norma is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// Norma is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with Norma. If not, see <http://www.gnu.org/licenses/>.
+
+package sim
+
+import (
+	"fmt"
+	"log/slog"
+	"math/big"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/0xsoniclabs/norma/genesis"
+	"github.com/0xsoniclabs/sonic/opera"
+	"github.com/0xsoniclabs/sonic/opera/contracts/driverauth"
+	"github.com/0xsoniclabs/sonic/opera/contracts/sfc"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/trie"
+)
+
+// chain is the state all nodes of a simulated network share: the blocks
+// produced so far, the epochs and their validator sets, the records of the
+// SFC contract and the accounts. There is no EVM: transactions transfer value
+// or call the system contracts, whose effects are implemented in Go.
+type chain struct {
+	mu sync.Mutex
+
+	chainID *big.Int
+	rules   opera.Rules
+	// pendingRules are the rules taking effect when the current epoch is
+	// sealed, or nil if they do not change.
+	pendingRules *opera.Rules
+
+	blocks     []*block
+	epoch      uint64
+	epochStart time.Time
+	// snapshots hold the validator set of every epoch, by epoch.
+	snapshots map[uint64]*epochSnapshot
+	// sealsRequested counts the epochs to be sealed at the end of the block
+	// being built, as requested by advanceEpochs.
+	sealsRequested uint64
+
+	validators      map[int]*validator
+	lastValidatorID int
+
+	balances map[common.Address]*big.Int
+	// nonces are the nonces of the next transactions the accounts may send,
+	// pending ones included.
+	nonces map[common.Address]uint64
+
+	pending  []*types.Transaction
+	senders  map[common.Hash]common.Address
+	receipts map[common.Hash]*types.Receipt
+
+	// load counts the transactions the simulated applications have sent
+	// since the last block, by application.
+	load map[*application]uint64
+}
+
+// block is a block of a simulated chain.
+type block struct {
+	header *types.Header
+	hash   common.Hash
+	epoch  uint64
+	// nextEpoch is the epoch following the block, which differs from its
+	// epoch if the block sealed it.
+	nextEpoch uint64
+	time      time.Time
+	// txs counts the transactions in the block, those of the simulated
+	// applications included.
+	txs          int
+	transactions types.Transactions
+	receipts     types.Receipts
+}
+
+// newBlock creates a block on top of the given parent, which is nil for the
+// genesis block. Its hash is completed by seal.
+func newBlock(parent *block, epoch uint64, now time.Time, gasLimit uint64) *block {
+	header := &types.Header{
+		UncleHash:   types.EmptyUncleHash,
+		TxHash:      types.EmptyTxsHash,
+		ReceiptHash: types.EmptyReceiptsHash,
+		Difficulty:  new(big.Int),
+		Number:      new(big.Int),
+		GasLimit:    gasLimit,
+		Time:        uint64(now.Unix()),
+		BaseFee:     new(big.Int),
+	}
+	if parent != nil {
+		header.ParentHash = parent.hash
+		header.Number.Add(parent.header.Number, big.NewInt(1))
+	}
+	return &block{header: header, epoch: epoch, nextEpoch: epoch, time: now}
+}
+
+// number returns the number of the block.
+func (b *block) number() uint64 {
+	return b.header.Number.Uint64()
+}
+
+// seal completes the header of the block once all its transactions are in.
+func (b *block) seal() {
+	if len(b.transactions) > 0 {
+		b.header.TxHash = types.DeriveSha(b.transactions, trie.NewStackTrie(nil))
+		b.header.ReceiptHash = types.DeriveSha(b.receipts, trie.NewStackTrie(nil))
+	}
+	b.hash = b.header.Hash()
+}
+
+// epochSnapshot is the validator set and the rules of an epoch.
+type epochSnapshot struct {
+	validatorIDs  []int
+	receivedStake map[int]*big.Int
+	rules         opera.Rules
+}
+
+// totalStake returns the stake of all validators of the epoch.
+func (s *epochSnapshot) totalStake() *big.Int {
+	total := new(big.Int)
+	for _, stake := range s.receivedStake {
+		total.Add(total, stake)
+	}
+	return total
+}
+
+// genesisSupply is the amount of S split evenly among the validator accounts
+// funded in the genesis, as in the genesis of a real network; see
+// genesis.ValidatorAccounts. Validators registered later stake from the
+// accounts beyond the genesis validators, or from funds of the treasury.
+const genesisSupply = 1_000_000_000_000_000
+
+// systemContractCode is the code reported for the system contracts, which
+// only needs to be non-empty for bindings to call them.
+var systemContractCode = []byte{0xfe}
+
+// newChain creates the genesis of a chain whose validators have the given
+// stakes in S. The validators are numbered from 1 and their accounts are the
+// fake accounts of the same numbers.
+func newChain(rules opera.Rules, stakes []uint64, now time.Time) *chain {
+	c := &chain{
+		chainID:    new(big.Int).SetUint64(rules.NetworkID),
+		rules:      rules,
+		epoch:      1,
+		epochStart: now,
+		snapshots:  map[uint64]*epochSnapshot{},
+		validators: map[int]*validator{},
+		balances:   map[common.Address]*big.Int{},
+		nonces:     map[common.Address]uint64{},
+		senders:    map[common.Hash]common.Address{},
+		receipts:   map[common.Hash]*types.Receipt{},
+		load:       map[*application]uint64{},
+	}
+	accounts := genesis.ValidatorAccounts(len(stakes))
+	for id := 1; id <= accounts; id++ {
+		c.balances[fakeAddress(id)] = toWei(genesisSupply / uint64(accounts))
+	}
+	for i, stake := range stakes {
+		id := i + 1
+		c.validators[id] = newValidator(id, fakeAddress(id), toWei(stake), c.epoch, now)
+		c.lastValidatorID = id
+	}
+	c.snapshots[c.epoch] = c.takeSnapshot()
+	first := newBlock(nil, c.epoch, now, c.rules.Blocks.MaxBlockGas)
+	first.seal()
+	c.blocks = append(c.blocks, first)
+	return c
+}
+
+// fakeAddress returns the address of the account of the validator with the
+// given ID.
+func fakeAddress(id int) common.Address {
+	key, err := genesis.ValidatorKey(id)
+	if err != nil {
+		panic(err) // validator IDs start at 1
+	}
+	return crypto.PubkeyToAddress(key.PublicKey)
+}
+
+// takeSnapshot returns the validator set of an epoch starting now, all
+// active validators holding stake, along with the current rules. Must be called with the mutex held.
+func (c *chain) takeSnapshot() *epochSnapshot {
+	snapshot := &epochSnapshot{receivedStake: map[int]*big.Int{}, rules: c.rules}
+	for id, v := range c.validators {
+		if stake := v.receivedStake(); v.status == 0 && stake.Sign() > 0 {
+			snapshot.validatorIDs = append(snapshot.validatorIDs, id)
+			snapshot.receivedStake[id] = stake
+		}
+	}
+	slices.Sort(snapshot.validatorIDs)
+	return snapshot
+}
+
+// currentValidators returns the validator set of the current epoch.
+func (c *chain) currentValidators() *epochSnapshot {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.snapshots[c.epoch]
+}
+
+// head returns the latest block.
+func (c *chain) head() *block {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.blocks[len(c.blocks)-1]
+}
+
+// getBlock returns the block of the given number, or nil if there is none.
+func (c *chain) getBlock(number uint64) *block {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if number >= uint64(len(c.blocks)) {
+		return nil
+	}
+	return c.blocks[number]
+}
+
+// getReceipt returns the receipt of the transaction of the given hash, or nil
+// if it is not in a block yet.
+func (c *chain) getReceipt(hash common.Hash) *types.Receipt {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.receipts[hash]
+}
+
+// getSnapshot returns the validator set and rules of the given epoch, or nil
+// if the epoch has not started yet.
+func (c *chain) getSnapshot(epoch uint64) *epochSnapshot {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.snapshots[epoch]
+}
+
+// getBalance returns the balance of an account.
+func (c *chain) getBalance(account common.Address) *big.Int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return new(big.Int).Set(c.balanceOf(account))
+}
+
+// getNonce returns the nonce of the next transaction of an account, pending
+// transactions included.
+func (c *chain) getNonce(account common.Address) uint64 {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.nonces[account]
+}
+
+// getPending returns the transactions waiting for the next block, with their
+// senders.
+func (c *chain) getPending() (types.Transactions, []common.Address) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	senders := make([]common.Address, 0, len(c.pending))
+	for _, tx := range c.pending {
+		senders = append(senders, c.senders[tx.Hash()])
+	}
+	return slices.Clone(c.pending), senders
+}
+
+// callContract executes a read-only call of the given contract; only the
+// system contracts can be called.
+func (c *chain) callContract(to common.Address, data []byte) ([]byte, error) {
+	if !isSystemContract(to) {
+		return nil, nil
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.call(data)
+}
+
+// isSystemContract tells whether the given address is one of the contracts
+// the simulated chain implements.
+func isSystemContract(address common.Address) bool {
+	return address == sfc.ContractAddress || address == driverauth.ContractAddress
+}
+
+// sendTransaction adds a transaction to the pending ones, to be included in
+// the next block. Unlike a real transaction pool, it takes the transactions
+// of an account only in the order of their nonces.
+func (c *chain) sendTransaction(tx *types.Transaction) error {
+	from, err := types.Sender(types.LatestSignerForChainID(c.chainID), tx)
+	if err != nil {
+		return fmt.Errorf("invalid sender: %w", err)
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if _, found := c.senders[tx.Hash()]; found {
+		return fmt.Errorf("already known")
+	}
+	switch nonce := c.nonces[from]; {
+	case tx.Nonce() < nonce:
+		return fmt.Errorf("nonce too low: next nonce %d, tx nonce %d", nonce, tx.Nonce())
+	case tx.Nonce() > nonce:
+		return fmt.Errorf("nonce too high: next nonce %d, tx nonce %d", nonce, tx.Nonce())
+	}
+	if c.balanceOf(from).Cmp(tx.Value()) < 0 {
+		return fmt.Errorf("insufficient funds for transfer")
+	}
+	c.nonces[from]++
+	c.senders[tx.Hash()] = from
+	c.pending = append(c.pending, tx)
+	return nil
+}
+
+// addLoad records transactions sent by a simulated application, which are
+// included in the next block.
+func (c *chain) addLoad(app *application, count uint64) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.load[app] += count
+}
+
+// produceBlock appends a block holding all pending transactions and seals
+// the epoch if that is due, either because it was requested or because the
+// epoch lasted as long as the rules allow.
+func (c *chain) produceBlock(now time.Time) *block {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	b := newBlock(c.blocks[len(c.blocks)-1], c.epoch, now, c.rules.Blocks.MaxBlockGas)
+	for _, tx := range c.pending {
+		receipt := c.apply(tx, now)
+		receipt.TransactionIndex = uint(len(b.receipts))
+		b.header.GasUsed += receipt.GasUsed
+		receipt.CumulativeGasUsed = b.header.GasUsed
+		b.transactions = append(b.transactions, tx)
+		b.receipts = append(b.receipts, receipt)
+		c.receipts[tx.Hash()] = receipt
+	}
+	b.txs = len(c.pending)
+	c.pending = nil
+	for app, count := range c.load {
+		b.txs += int(count)
+		app.received.Add(count)
+	}
+	clear(c.load)
+	b.seal()
+	for _, receipt := range b.receipts {
+		receipt.BlockHash = b.hash
+		receipt.BlockNumber = new(big.Int).Set(b.header.Number)
+	}
+	c.blocks = append(c.blocks, b)
+
+	maxDuration := time.Duration(c.rules.Epochs.MaxEpochDuration)
+	if c.sealsRequested == 0 && maxDuration > 0 && now.Sub(c.epochStart) >= maxDuration {
+		c.sealsRequested = 1
+	}
+	for ; c.sealsRequested > 0; c.sealsRequested-- {
+		c.sealEpoch(now)
+	}
+	b.nextEpoch = c.epoch
+	return b
+}
+
+// apply executes a transaction, returning its receipt. Must be called with
+// the mutex held.
+func (c *chain) apply(tx *types.Transaction, now time.Time) *types.Receipt {
+	from := c.senders[tx.Hash()]
+	receipt := &types.Receipt{
+		Type:              tx.Type(),
+		Status:            types.ReceiptStatusSuccessful,
+		TxHash:            tx.Hash(),
+		GasUsed:           tx.Gas(),
+		Logs:              []*types.Log{},
+		EffectiveGasPrice: new(big.Int),
+	}
+	// The balance was checked when the transaction was sent, but the
+	// account may have spent it in the meantime.
+	if c.balanceOf(from).Cmp(tx.Value()) < 0 {
+		receipt.Status = types.ReceiptStatusFailed
+		return receipt
+	}
+	to := tx.To()
+	switch {
+	case to == nil:
+		slog.Debug("simulated chain cannot deploy contracts", "tx", tx.Hash())
+		receipt.Status = types.ReceiptStatusFailed
+	case isSystemContract(*to):
+		if err := c.transact(from, tx.Value(), tx.Data(), now); err != nil {
+			slog.Debug("simulated system contract call reverted", "tx", tx.Hash(), "error", err)
+			receipt.Status = types.ReceiptStatusFailed
+			return receipt
+		}
+		c.balances[from] = new(big.Int).Sub(c.balanceOf(from), tx.Value())
+	default:
+		c.balances[from] = new(big.Int).Sub(c.balanceOf(from), tx.Value())
+		c.balances[*to] = new(big.Int).Add(c.balanceOf(*to), tx.Value())
+		receipt.GasUsed = min(tx.Gas(), 21_000)
+	}
+	return receipt
+}
+
+// sealEpoch ends the current epoch, starting the next one with the active
+// validators and the rules updated during the epoch. Must be called with the
+// mutex held.
+func (c *chain) sealEpoch(now time.Time) {
+	c.epoch++
+	c.epochStart = now
+	if c.pendingRules != nil {
+		c.rules = *c.pendingRules
+		c.pendingRules = nil
+	}
+	c.snapshots[c.epoch] = c.takeSnapshot()
+	slog.Info("simulated epoch sealed",
+		"epoch", c.epoch, "validators", c.snapshots[c.epoch].validatorIDs)
+}
+
+// reportDoubleSign deactivates a validator found signing on two nodes, as the
+// network does once it sees the conflicting events. Only validators of the
+// current epoch emit events, so others cannot double-sign yet.
+func (c *chain) reportDoubleSign(id int, now time.Time) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if _, found := c.snapshots[c.epoch].receivedStake[id]; !found {
+		return
+	}
+	if v := c.validators[id]; v != nil && v.status&doublesignBit == 0 {
+		slog.Info("simulated validator double-signed", "validator", id)
+		v.deactivate(doublesignBit, c.epoch, now)
+	}
+}
+
+// balanceOf returns the balance of an account. Must be called with the mutex
+// held.
+func (c *chain) balanceOf(account common.Address) *big.Int {
+	if balance, found := c.balances[account]; found {
+		return balance
+	}
+	return new(big.Int)
+}
@@ -0,0 +1,338 @@
+// Copyright 2024 Fantom Foundation
+// This file is part of Norma System Testing Infrastructure for Sonic.
+//
+// Norma is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// Norma is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with Norma. If not, see <http://www.gnu.org/licenses/>.
+
+package sim
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"strings"
+	"time"
+
+	"github.com/0xsoniclabs/norma/genesis"
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// The simulation implements the parts of the SFC and NodeDriverAuth contracts
+// that Norma uses, as described by the ABI fragments below. Calls to either
+// contract are told apart by their selectors, which do not collide.
+const (
+	sfcABIJson = `[
+	{"type":"function","name":"currentEpoch","stateMutability":"view","inputs":[],"outputs":[{"type":"uint256"}]},
+	{"type":"function","name":"currentSealedEpoch","stateMutability":"view","inputs":[],"outputs":[{"type":"uint256"}]},
+	{"type":"function","name":"lastValidatorID","stateMutability":"view","inputs":[],"outputs":[{"type":"uint256"}]},
+	{"type":"function","name":"getEpochValidatorIDs","stateMutability":"view","inputs":[{"name":"epoch","type":"uint256"}],"outputs":[{"type":"uint256[]"}]},
+	{"type":"function","name":"getEpochReceivedStake","stateMutability":"view","inputs":[{"name":"epoch","type":"uint256"},{"name":"validatorID","type":"uint256"}],"outputs":[{"type":"uint256"}]},
+	{"type":"function","name":"getSelfStake","stateMutability":"view","inputs":[{"name":"validatorID","type":"uint256"}],"outputs":[{"type":"uint256"}]},
+	{"type":"function","name":"getStake","stateMutability":"view","inputs":[{"name":"delegator","type":"address"},{"name":"toValidatorID","type":"uint256"}],"outputs":[{"type":"uint256"}]},
+	{"type":"function","name":"getValidator","stateMutability":"view","inputs":[{"name":"validatorID","type":"uint256"}],"outputs":[
+		{"name":"status","type":"uint256"},{"name":"receivedStake","type":"uint256"},{"name":"auth","type":"address"},
+		{"name":"createdEpoch","type":"uint256"},{"name":"createdTime","type":"uint256"},
+		{"name":"deactivatedTime","type":"uint256"},{"name":"deactivatedEpoch","type":"uint256"}]},
+	{"type":"function","name":"isSlashed","stateMutability":"view","inputs":[{"name":"validatorID","type":"uint256"}],"outputs":[{"type":"bool"}]},
+	{"type":"function","name":"createValidator","stateMutability":"payable","inputs":[{"name":"pubkey","type":"bytes"}],"outputs":[]},
+	{"type":"function","name":"delegate","stateMutability":"payable","inputs":[{"name":"toValidatorID","type":"uint256"}],"outputs":[]},
+	{"type":"function","name":"undelegate","stateMutability":"nonpayable","inputs":[{"name":"toValidatorID","type":"uint256"},{"name":"wrID","type":"uint256"},{"name":"amount","type":"uint256"}],"outputs":[]}
+]`
+	driverAuthABIJson = `[
+	{"type":"function","name":"advanceEpochs","stateMutability":"nonpayable","inputs":[{"name":"num","type":"uint256"}],"outputs":[]},
+	{"type":"function","name":"updateNetworkRules","stateMutability":"nonpayable","inputs":[{"name":"diff","type":"bytes"}],"outputs":[]}
+]`
+)
+
+var (
+	sfcABI        = mustParseABI(sfcABIJson)
+	driverAuthABI = mustParseABI(driverAuthABIJson)
+)
+
+func mustParseABI(definition string) abi.ABI {
+	parsed, err := abi.JSON(strings.NewReader(definition))
+	if err != nil {
+		panic(fmt.Sprintf("invalid ABI: %v", err))
+	}
+	return parsed
+}
+
+// The rules of the SFC contract a validator's stake is held to.
+var (
+	// minSelfStake is the least stake a validator has to hold itself.
+	minSelfStake = toWei(500_000)
+	// maxDelegatedRatio bounds the total stake of a validator to this many
+	// times its self-stake.
+	maxDelegatedRatio = big.NewInt(16)
+)
+
+// The status bits of a validator, as kept by the SFC contract. A validator
+// with any bit set is deactivated.
+const (
+	withdrawnBit  = 1
+	doublesignBit = 1 << 7
+)
+
+// errNoSuchMethod is returned for calls of methods the simulation lacks.
+var errNoSuchMethod = errors.New("method not supported by the simulation")
+
+// findMethod returns the method of a system contract the given call data
+// selects.
+func findMethod(data []byte) (*abi.Method, error) {
+	if len(data) < 4 {
+		return nil, errNoSuchMethod
+	}
+	for _, contract := range []abi.ABI{sfcABI, driverAuthABI} {
+		if method, err := contract.MethodById(data[:4]); err == nil {
+			return method, nil
+		}
+	}
+	return nil, errNoSuchMethod
+}
+
+// call executes a read-only call of a system contract. Must be called with
+// the chain's mutex held.
+func (c *chain) call(data []byte) ([]byte, error) {
+	method, err := findMethod(data)
+	if err != nil {
+		return nil, err
+	}
+	args, err := method.Inputs.Unpack(data[4:])
+	if err != nil {
+		return nil, err
+	}
+	switch method.Name {
+	case "currentEpoch":
+		return method.Outputs.Pack(new(big.Int).SetUint64(c.epoch))
+	case "currentSealedEpoch":
+		return method.Outputs.Pack(new(big.Int).SetUint64(c.epoch - 1))
+	case "lastValidatorID":
+		return method.Outputs.Pack(big.NewInt(int64(c.lastValidatorID)))
+	case "getEpochValidatorIDs":
+		ids := []*big.Int{}
+		if snapshot, found := c.snapshots[args[0].(*big.Int).Uint64()]; found {
+			for _, id := range snapshot.validatorIDs {
+				ids = append(ids, big.NewInt(int64(id)))
+			}
+		}
+		return method.Outputs.Pack(ids)
+	case "getEpochReceivedStake":
+		stake := new(big.Int)
+		if snapshot, found := c.snapshots[args[0].(*big.Int).Uint64()]; found {
+			if received, found := snapshot.receivedStake[int(args[1].(*big.Int).Int64())]; found {
+				stake = received
+			}
+		}
+		return method.Outputs.Pack(stake)
+	case "getSelfStake":
+		stake := new(big.Int)
+		if v := c.validators[int(args[0].(*big.Int).Int64())]; v != nil {
+			stake = v.stakeOf(v.auth)
+		}
+		return method.Outputs.Pack(stake)
+	case "getStake":
+		stake := new(big.Int)
+		if v := c.validators[int(args[1].(*big.Int).Int64())]; v != nil {
+			stake = v.stakeOf(args[0].(common.Address))
+		}
+		return method.Outputs.Pack(stake)
+	case "getValidator":
+		v := c.validators[int(args[0].(*big.Int).Int64())]
+		if v == nil {
+			v = &validator{}
+		}
+		return method.Outputs.Pack(
+			new(big.Int).SetUint64(v.status),
+			v.receivedStake(),
+			v.auth,
+			new(big.Int).SetUint64(v.createdEpoch),
+			unixTime(v.createdTime),
+			unixTime(v.deactivatedTime),
+			new(big.Int).SetUint64(v.deactivatedEpoch),
+		)
+	case "isSlashed":
+		v := c.validators[int(args[0].(*big.Int).Int64())]
+		return method.Outputs.Pack(v != nil && v.status&doublesignBit != 0)
+	}
+	return nil, fmt.Errorf("%s cannot be called read-only", method.Name)
+}
+
+// transact executes a transaction calling a system contract, sent by the
+// given account with the given value. An error reverts the transaction.
+// Must be called with the chain's mutex held.
+func (c *chain) transact(from common.Address, value *big.Int, data []byte, now time.Time) error {
+	method, err := findMethod(data)
+	if err != nil {
+		return err
+	}
+	args, err := method.Inputs.Unpack(data[4:])
+	if err != nil {
+		return err
+	}
+	if value.Sign() > 0 && method.StateMutability != "payable" {
+		return fmt.Errorf("%s is not payable", method.Name)
+	}
+	switch method.Name {
+	case "createValidator":
+		return c.createValidator(from, value, now)
+	case "delegate":
+		return c.delegate(from, int(args[0].(*big.Int).Int64()), value)
+	case "undelegate":
+		return c.undelegate(from, int(args[0].(*big.Int).Int64()), args[2].(*big.Int), now)
+	case "advanceEpochs":
+		c.sealsRequested += args[0].(*big.Int).Uint64()
+		return nil
+	case "updateNetworkRules":
+		return c.updateNetworkRules(args[0].([]byte))
+	}
+	return fmt.Errorf("%s cannot be called in a transaction", method.Name)
+}
+
+func (c *chain) createValidator(auth common.Address, stake *big.Int, now time.Time) error {
+	for _, v := range c.validators {
+		if v.auth == auth {
+			return fmt.Errorf("validator already exists")
+		}
+	}
+	if stake.Cmp(minSelfStake) < 0 {
+		return fmt.Errorf("insufficient self-stake")
+	}
+	c.lastValidatorID++
+	c.validators[c.lastValidatorID] = newValidator(c.lastValidatorID, auth, stake, c.epoch, now)
+	return nil
+}
+
+func (c *chain) delegate(delegator common.Address, id int, stake *big.Int) error {
+	v := c.validators[id]
+	if v == nil || v.status != 0 {
+		return fmt.Errorf("validator isn't active")
+	}
+	if stake.Sign() == 0 {
+		return fmt.Errorf("zero amount")
+	}
+	v.stakes[delegator] = new(big.Int).Add(v.stakeOf(delegator), stake)
+	return v.checkDelegatedStakeLimit()
+}
+
+func (c *chain) undelegate(delegator common.Address, id int, amount *big.Int, now time.Time) error {
+	v := c.validators[id]
+	if v == nil {
+		return fmt.Errorf("validator doesn't exist")
+	}
+	if amount.Sign() == 0 {
+		return fmt.Errorf("zero amount")
+	}
+	stake := v.stakeOf(delegator)
+	if stake.Cmp(amount) < 0 {
+		return fmt.Errorf("not enough stake")
+	}
+	v.stakes[delegator] = new(big.Int).Sub(stake, amount)
+	if delegator != v.auth {
+		return nil
+	}
+	self := v.stakeOf(v.auth)
+	if self.Sign() == 0 {
+		v.deactivate(withdrawnBit, c.epoch, now)
+		return nil
+	}
+	if self.Cmp(minSelfStake) < 0 {
+		return fmt.Errorf("insufficient self-stake")
+	}
+	return v.checkDelegatedStakeLimit()
+}
+
+func (c *chain) updateNetworkRules(diff []byte) error {
+	var patch genesis.NetworkRulesPatch
+	if err := json.Unmarshal(diff, &patch); err != nil {
+		return fmt.Errorf("invalid rules diff: %w", err)
+	}
+	rules := c.rules
+	if c.pendingRules != nil {
+		rules = *c.pendingRules
+	}
+	if err := genesis.ApplyNetworkRulesPatch(&rules, patch); err != nil {
+		return err
+	}
+	c.pendingRules = &rules
+	return nil
+}
+
+// validator is the record the SFC contract keeps of a validator.
+type validator struct {
+	id     int
+	auth   common.Address
+	status uint64
+	// stakes are the stakes delegated to the validator, by delegator; the
+	// self-stake is the one of auth.
+	stakes           map[common.Address]*big.Int
+	createdEpoch     uint64
+	createdTime      time.Time
+	deactivatedEpoch uint64
+	deactivatedTime  time.Time
+}
+
+func newValidator(id int, auth common.Address, stake *big.Int, epoch uint64, now time.Time) *validator {
+	return &validator{
+		id:           id,
+		auth:         auth,
+		stakes:       map[common.Address]*big.Int{auth: stake},
+		createdEpoch: epoch,
+		createdTime:  now,
+	}
+}
+
+func (v *validator) stakeOf(delegator common.Address) *big.Int {
+	if stake, found := v.stakes[delegator]; found {
+		return new(big.Int).Set(stake)
+	}
+	return new(big.Int)
+}
+
+// receivedStake returns the total stake delegated to the validator.
+func (v *validator) receivedStake() *big.Int {
+	total := new(big.Int)
+	for _, stake := range v.stakes {
+		total.Add(total, stake)
+	}
+	return total
+}
+
+func (v *validator) checkDelegatedStakeLimit() error {
+	limit := new(big.Int).Mul(v.stakeOf(v.auth), maxDelegatedRatio)
+	if v.receivedStake().Cmp(limit) > 0 {
+		return fmt.Errorf("validator's delegations limit is exceeded")
+	}
+	return nil
+}
+
+func (v *validator) deactivate(bit uint64, epoch uint64, now time.Time) {
+	if v.status == 0 {
+		v.deactivatedEpoch = epoch
+		v.deactivatedTime = now
+	}
+	v.status |= bit
+}
+
+// toWei converts an amount of S to wei.
+func toWei(amount uint64) *big.Int {
+	return new(big.Int).Mul(new(big.Int).SetUint64(amount), big.NewInt(1e18))
+}
+
+func unixTime(t time.Time) *big.Int {
+	if t.IsZero() {
+		return new(big.Int)
+	}
+	return big.NewInt(t.Unix())
+}
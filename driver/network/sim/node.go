@@ -0,0 +1,325 @@
+// Copyright 2024 Fantom Foundation
+// This file is part of Norma System Testing Infrastructure for Sonic.
+//
+// Norma is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// Norma is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with Norma. If not, see <http://www.gnu.org/licenses/>.
+
+package sim
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/0xsoniclabs/norma/driver"
+	"github.com/0xsoniclabs/norma/driver/network"
+	"github.com/0xsoniclabs/norma/driver/node"
+	rpcdriver "github.com/0xsoniclabs/norma/driver/rpc"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/rpc"
+)
+
+// Node implements the driver's Node interface by serving the RPC interface
+// of a client from a simulated chain. All nodes of a network share the same
+// chain, and a node knows its blocks up to the height it has followed the
+// chain to; a node cut off from block production stays behind until it is
+// reconnected.
+//
+// The node runs no client: its log only reports the blocks it learns about,
+// it has no events to offer, and no metrics.
+type Node struct {
+	label       string
+	failing     bool
+	validatorId *int
+	id          driver.NodeID
+	chain       *chain
+
+	server   *http.Server
+	listener net.Listener
+	log      *nodeLog
+
+	mu      sync.Mutex
+	height  uint64
+	running bool
+}
+
+// startNode starts serving the RPC interface of a node following the given
+// chain from its current head.
+func startNode(label string, failing bool, validatorId *int, id driver.NodeID, chain *chain) (*Node, error) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		return nil, fmt.Errorf("failed to listen for RPC of node %s: %w", label, err)
+	}
+	n := &Node{
+		label:       label,
+		failing:     failing,
+		validatorId: validatorId,
+		id:          id,
+		chain:       chain,
+		listener:    listener,
+		log:         newNodeLog(),
+		running:     true,
+	}
+
+	server := rpc.NewServer()
+	for namespace, api := range map[string]any{
+		"eth":    &ethAPI{n},
+		"admin":  &adminAPI{n},
+		"txpool": &txpoolAPI{n},
+		"dag":    &dagAPI{},
+	} {
+		if err := server.RegisterName(namespace, api); err != nil {
+			return nil, errors.Join(err, listener.Close())
+		}
+	}
+	websocket := server.WebsocketHandler([]string{"*"})
+	n.server = &http.Server{
+		Handler: http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if strings.EqualFold(r.Header.Get("Upgrade"), "websocket") {
+				websocket.ServeHTTP(w, r)
+				return
+			}
+			server.ServeHTTP(w, r)
+		}),
+		ReadHeaderTimeout: 10 * time.Second,
+	}
+	go func() {
+		_ = n.server.Serve(listener)
+	}()
+
+	n.follow(chain.head().number())
+	return n, nil
+}
+
+// follow advances the node to the given height, logging every block it
+// learns about as the client does.
+func (n *Node) follow(height uint64) {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	if !n.running || height <= n.height {
+		return
+	}
+	for number := n.height + 1; number <= height; number++ {
+		if b := n.chain.getBlock(number); b != nil {
+			n.log.write(blockLogLine(b, time.Now()))
+		}
+	}
+	n.height = height
+}
+
+// getHeight returns the number of the latest block the node knows.
+func (n *Node) getHeight() uint64 {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	return n.height
+}
+
+// headBlock returns the latest block the node knows.
+func (n *Node) headBlock() *block {
+	return n.chain.getBlock(n.getHeight())
+}
+
+// blockByNumber returns the block of the given number, resolving the tags of
+// the RPC interface against the height of the node, or nil if the node does
+// not know the block.
+func (n *Node) blockByNumber(number rpc.BlockNumber) *block {
+	height := n.getHeight()
+	switch {
+	case number == rpc.EarliestBlockNumber:
+		return n.chain.getBlock(0)
+	case number < 0:
+		return n.chain.getBlock(height)
+	case uint64(number) > height:
+		return nil
+	}
+	return n.chain.getBlock(uint64(number))
+}
+
+// blockLogLine renders the line the client logs for a new block, as read by
+// the monitoring.
+func blockLogLine(b *block, now time.Time) string {
+	return fmt.Sprintf("INFO [%s] New block index=%d id=%d:%d:%s gas_used=%d base_fee=0 gas_rate=0 txs=%d/0 age=%s t=0s\n",
+		now.Format("01-02|15:04:05.000"), b.number(), b.epoch, b.number(), b.hash.Hex()[2:8],
+		b.header.GasUsed, b.txs, now.Sub(b.time).Round(time.Millisecond))
+}
+
+// newNodeID derives an enode for a node of the given label, unique among the
+// nodes of a network through the given sequence number.
+func newNodeID(label string, seq int) driver.NodeID {
+	hash := crypto.Keccak256([]byte(fmt.Sprintf("%s/%d", label, seq)))
+	return driver.NodeID(fmt.Sprintf("enode://%x%x@127.0.0.1:5050", hash, hash))
+}
+
+func (n *Node) GetLabel() string {
+	return n.label
+}
+
+func (n *Node) IsExpectedFailure() bool {
+	return n.failing
+}
+
+// Hostname returns the loopback address the node listens on.
+func (n *Node) Hostname() string {
+	return "127.0.0.1"
+}
+
+// MetricsPort returns 0, as a simulated node offers no metrics.
+func (n *Node) MetricsPort() int {
+	return 0
+}
+
+func (n *Node) IsRunning() bool {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	return n.running
+}
+
+func (n *Node) GetNodeID() (driver.NodeID, error) {
+	return n.id, nil
+}
+
+func (n *Node) GetValidatorId() *int {
+	return n.validatorId
+}
+
+// GetServiceUrl returns the URL of the RPC and websocket services, which
+// share a port; the node offers no other service.
+func (n *Node) GetServiceUrl(service *network.ServiceDescription) (*driver.URL, error) {
+	if service.Port != node.OperaRpcService.Port && service.Port != node.OperaWsService.Port {
+		return nil, fmt.Errorf("node %s does not offer service %s", n.label, service.Name)
+	}
+	url := driver.URL(fmt.Sprintf("%s://%s", service.Protocol, n.listener.Addr()))
+	return &url, nil
+}
+
+func (n *Node) DialRpc(ctx context.Context) (rpcdriver.Client, error) {
+	url, err := n.GetServiceUrl(&node.OperaRpcService)
+	if err != nil {
+		return nil, err
+	}
+	rpcClient, err := rpc.DialContext(ctx, string(*url))
+	if err != nil {
+		return nil, fmt.Errorf("failed to dial RPC for node %s; %v", n.label, err)
+	}
+	return rpcdriver.WrapRpcClient(rpcClient), nil
+}
+
+// StreamLog follows the log of the node until it stops or the context is
+// cancelled.
+func (n *Node) StreamLog(ctx context.Context) (io.ReadCloser, error) {
+	return n.log.follow(ctx), nil
+}
+
+// Stop shuts the node down; it no longer answers on its RPC port.
+func (n *Node) Stop(context.Context) error {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	if !n.running {
+		return nil
+	}
+	n.running = false
+	n.log.close()
+	return n.server.Close()
+}
+
+// Kill shuts the node down, as Stop does; a simulated node has no state to
+// lose.
+func (n *Node) Kill(ctx context.Context) error {
+	return n.Stop(ctx)
+}
+
+// Cleanup does nothing, as a simulated node leaves nothing behind.
+func (n *Node) Cleanup(context.Context) error {
+	return nil
+}
+
+// nodeLog is the log of a node, kept in memory for any number of readers to
+// follow.
+type nodeLog struct {
+	mu     sync.Mutex
+	data   []byte
+	closed bool
+	// changed is closed and replaced whenever the log grows or is closed.
+	changed chan struct{}
+}
+
+func newNodeLog() *nodeLog {
+	return &nodeLog{changed: make(chan struct{})}
+}
+
+func (l *nodeLog) write(line string) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.data = append(l.data, line...)
+	close(l.changed)
+	l.changed = make(chan struct{})
+}
+
+func (l *nodeLog) close() {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if !l.closed {
+		l.closed = true
+		close(l.changed)
+	}
+}
+
+// follow returns a reader of the log from its start, which blocks for more
+// lines until the log is closed, the context is cancelled or the reader is
+// closed.
+func (l *nodeLog) follow(ctx context.Context) io.ReadCloser {
+	return &logReader{log: l, ctx: ctx, done: make(chan struct{})}
+}
+
+type logReader struct {
+	log       *nodeLog
+	ctx       context.Context
+	offset    int
+	done      chan struct{}
+	closeOnce sync.Once
+}
+
+func (r *logReader) Read(p []byte) (int, error) {
+	for {
+		r.log.mu.Lock()
+		if r.offset < len(r.log.data) {
+			n := copy(p, r.log.data[r.offset:])
+			r.offset += n
+			r.log.mu.Unlock()
+			return n, nil
+		}
+		closed, changed := r.log.closed, r.log.changed
+		r.log.mu.Unlock()
+		if closed {
+			return 0, io.EOF
+		}
+		select {
+		case <-changed:
+		case <-r.ctx.Done():
+			return 0, io.EOF
+		case <-r.done:
+			return 0, io.EOF
+		}
+	}
+}
+
+func (r *logReader) Close() error {
+	r.closeOnce.Do(func() { close(r.done) })
+	return nil
+}
@@ -0,0 +1,616 @@
+// Copyright 2024 Fantom Foundation
+// This file is part of Norma System Testing Infrastructure for Sonic.
+//
+// Norma is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// Norma is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with Norma. If not, see <http://www.gnu.org/licenses/>.
+
+package sim
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"math/big"
+	"math/rand"
+	"sync"
+	"time"
+
+	"github.com/0xsoniclabs/norma/driver"
+	"github.com/0xsoniclabs/norma/driver/network"
+	"github.com/0xsoniclabs/norma/driver/parser"
+	rpcdriver "github.com/0xsoniclabs/norma/driver/rpc"
+	"github.com/0xsoniclabs/norma/genesis"
+	"github.com/0xsoniclabs/sonic/opera"
+	"github.com/ethereum/go-ethereum/core/types"
+)
+
+// SimNetwork is a network simulated in memory: its nodes serve the RPC
+// interface of a client from a chain the network produces itself, without
+// running any client. It starts instantly and needs neither Docker nor
+// client binaries, so scenarios, checks and the executor can be exercised
+// quickly and deterministically enough for development and CI.
+//
+// The chain has blocks, epochs and a validator set kept by a simulated SFC
+// contract, which supports registering validators, delegating and
+// undelegating stake, advancing epochs and updating the network rules. A
+// block is produced whenever the running validators reachable from each
+// other hold more than two thirds of the stake of the epoch, so stopping
+// validators or partitioning the network stalls it as it would a real one.
+// Two nodes running the same validator are reported as double-signing.
+//
+// There is no EVM: applications produce load by counting transactions rather
+// than sending them, and transactions sent to the network can only transfer
+// value or call the simulated system contracts. Link rules, peerings and
+// throttling are accepted but have no effect, client images are ignored, and
+// there are no containers for steps acting on them; CheckScenario reports
+// the steps of a scenario relying on them.
+type SimNetwork struct {
+	ctx    context.Context
+	cancel context.CancelFunc
+	done   sync.WaitGroup
+	config driver.NetworkConfig
+	chain  *chain
+
+	// nodes provide a register for all nodes in the network.
+	nodes map[driver.NodeID]*Node
+	// suspended tracks nodes announced as temporarily unavailable.
+	suspended map[driver.Node]bool
+	// partition lists the groups of nodes the network is partitioned into,
+	// or is nil if it is not partitioned.
+	partition [][]*Node
+	// nextNodeSeq makes the IDs of the nodes unique.
+	nextNodeSeq int
+	// nodesMutex synchronizes access to the nodes and their connectivity.
+	nodesMutex sync.Mutex
+
+	apps      []driver.Application
+	appsMutex sync.Mutex
+
+	listeners     map[driver.NetworkListener]bool
+	observers     []driver.TransactionObserver
+	listenerMutex sync.Mutex
+}
+
+// blockInterval is the interval at which the network produces blocks.
+const blockInterval = 250 * time.Millisecond
+
+// NewSimNetwork creates an empty simulated network, with the chain set up
+// for the validators of the configuration. The validators join once nodes
+// are created for them.
+func NewSimNetwork(ctx context.Context, config *driver.NetworkConfig) (*SimNetwork, error) {
+	rules := opera.FakeNetRules(opera.GetSonicUpgrades())
+	if err := genesis.ApplyNetworkRulesPatch(&rules, config.NetworkRules); err != nil {
+		return nil, fmt.Errorf("failed to apply network rules to genesis: %w", err)
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	net := &SimNetwork{
+		ctx:       ctx,
+		cancel:    cancel,
+		config:    *config,
+		chain:     newChain(rules, driver.GetValidatorStakes(config.Validators), time.Now()),
+		nodes:     map[driver.NodeID]*Node{},
+		suspended: map[driver.Node]bool{},
+		apps:      []driver.Application{},
+		listeners: map[driver.NetworkListener]bool{},
+	}
+
+	net.done.Add(1)
+	go func() {
+		defer net.done.Done()
+		ticker := time.NewTicker(blockInterval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case now := <-ticker.C:
+				net.produceBlock(now)
+			}
+		}
+	}()
+	return net, nil
+}
+
+// produceBlock has the network produce a block if the validators of some
+// group of connected nodes have a quorum, and brings the nodes of that group
+// up to date.
+func (n *SimNetwork) produceBlock(now time.Time) {
+	n.nodesMutex.Lock()
+	online := map[*Node]bool{}
+	running := map[int]int{}
+	for _, node := range n.nodes {
+		if !node.IsRunning() || n.suspended[node] {
+			continue
+		}
+		online[node] = true
+		if id := node.GetValidatorId(); id != nil {
+			running[*id]++
+		}
+	}
+	components := n.components(online)
+	n.nodesMutex.Unlock()
+
+	for id, count := range running {
+		if count > 1 {
+			n.chain.reportDoubleSign(id, now)
+		}
+	}
+
+	validators := n.chain.currentValidators()
+	quorum := new(big.Int).Mul(validators.totalStake(), big.NewInt(2))
+	for _, component := range components {
+		stake := new(big.Int)
+		counted := map[int]bool{}
+		for _, node := range component {
+			id := node.GetValidatorId()
+			if id == nil || counted[*id] {
+				continue
+			}
+			counted[*id] = true
+			if received, found := validators.receivedStake[*id]; found {
+				stake.Add(stake, received)
+			}
+		}
+		if stake.Sign() == 0 || new(big.Int).Mul(stake, big.NewInt(3)).Cmp(quorum) <= 0 {
+			continue
+		}
+		head := n.chain.produceBlock(now).number()
+		for _, node := range component {
+			node.follow(head)
+		}
+		return
+	}
+}
+
+// components returns the groups of the given nodes that can reach each
+// other: all of them, or the groups of the partition, each joined by the
+// nodes not listed in any group. Must be called with the nodes mutex held.
+func (n *SimNetwork) components(online map[*Node]bool) [][]*Node {
+	if n.partition == nil {
+		all := make([]*Node, 0, len(online))
+		for node := range online {
+			all = append(all, node)
+		}
+		return [][]*Node{all}
+	}
+	listed := map[*Node]bool{}
+	for _, group := range n.partition {
+		for _, node := range group {
+			listed[node] = true
+		}
+	}
+	var unlisted []*Node
+	for node := range online {
+		if !listed[node] {
+			unlisted = append(unlisted, node)
+		}
+	}
+	components := make([][]*Node, 0, len(n.partition))
+	for _, group := range n.partition {
+		component := append([]*Node{}, unlisted...)
+		for _, node := range group {
+			if online[node] {
+				component = append(component, node)
+			}
+		}
+		components = append(components, component)
+	}
+	return components
+}
+
+// CreateNode creates nodes in the network during run.
+func (n *SimNetwork) CreateNode(config *driver.NodeConfig) (driver.Node, error) {
+	if config.ClockOffset != nil {
+		return nil, unsupported("a clock offset")
+	}
+	if config.Resources != (driver.Resources{}) {
+		return nil, unsupported("a resource limit")
+	}
+	if config.Container != "" {
+		return nil, unsupported("packing clients into a container")
+	}
+	if config.Memory != (driver.ClientMemory{}) {
+		return nil, unsupported("a client memory setting")
+	}
+	if config.Cheater {
+		// The twin runs the same validator, which the network reports as
+		// double-signing.
+		if _, err := n.createNode("cheater-"+config.Name, config); err != nil {
+			return nil, err
+		}
+	}
+	return n.createNode(config.Name, config)
+}
+
+// createNode starts a node of the given label and adds it to the network.
+func (n *SimNetwork) createNode(label string, config *driver.NodeConfig) (*Node, error) {
+	n.nodesMutex.Lock()
+	n.nextNodeSeq++
+	id := newNodeID(label, n.nextNodeSeq)
+	n.nodesMutex.Unlock()
+
+	node, err := startNode(label, config.Failing, config.ValidatorId, id, n.chain)
+	if err != nil {
+		return nil, fmt.Errorf("failed to start node %s; %w", label, err)
+	}
+
+	n.nodesMutex.Lock()
+	n.nodes[id] = node
+	n.nodesMutex.Unlock()
+
+	n.listenerMutex.Lock()
+	for listener := range n.listeners {
+		listener.AfterNodeCreation(node)
+	}
+	n.listenerMutex.Unlock()
+	return node, nil
+}
+
+// unsupported returns the error of a feature the sim backend lacks.
+func unsupported(feature string) error {
+	return fmt.Errorf("%s is not supported by the sim backend", feature)
+}
+
+// unsupportedSteps are the step functions a simulated network cannot run,
+// with the feature they rely on.
+var unsupportedSteps = map[parser.StepFunction]string{
+	parser.FuncKillSonic:       "killing a client while keeping its container",
+	parser.FuncHealDb:          "healing the database of a killed client",
+	parser.FuncPauseNode:       "freezing the container of a node",
+	parser.FuncResumeNode:      "freezing the container of a node",
+	parser.FuncSetClockSkew:    "a clock offset",
+	parser.FuncUpdateResources: "a resource limit",
+	parser.FuncUpgradeNode:     "swapping the client image of a node",
+	parser.FuncSnapshot:        "taking a snapshot of the containers",
+	parser.FuncChaos:           "disrupting the containers of nodes",
+}
+
+// CheckScenario reports every step of the given scenario a simulated network
+// cannot run, so a scenario fails before the network starts rather than
+// midway.
+func CheckScenario(scenario *parser.Scenario) error {
+	var errs []error
+	for i, step := range scenario.Steps {
+		// The branches of a parallel step and the steps of a schedule step
+		// are held to the same terms.
+		for _, step := range step.Steps() {
+			if feature, found := unsupportedSteps[step.Function]; found {
+				errs = append(errs, fmt.Errorf("step %d (%s): %w", i+1, step.Function, unsupported(feature)))
+				continue
+			}
+			if step.Function != parser.FuncStartNode {
+				continue
+			}
+			if err := checkStartNode(&step); err != nil {
+				errs = append(errs, fmt.Errorf("step %d (%s): %w", i+1, step.Function, err))
+			}
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// checkStartNode rejects the parameters of a startNode step that CreateNode
+// would reject for the nodes it starts.
+func checkStartNode(step *parser.Step) error {
+	switch {
+	case step.ClockOffset != nil:
+		return unsupported("a clock offset")
+	case step.CPUs > 0 || step.Memory > 0 || step.BlkioWeight > 0:
+		return unsupported("a resource limit")
+	case step.ClientsPerContainer != nil && *step.ClientsPerContainer > 1:
+		return unsupported("packing clients into a container")
+	case step.LiveCache > 0 || step.Cache > 0 || step.GoMemLimit > 0:
+		return unsupported("a client memory setting")
+	}
+	return nil
+}
+
+// simNode returns the node of the network the given node is.
+func (n *SimNetwork) simNode(node driver.Node) (*Node, error) {
+	sim, ok := node.(*Node)
+	if !ok {
+		return nil, fmt.Errorf("node %s is not a node of the simulated network", node.GetLabel())
+	}
+	return sim, nil
+}
+
+// ReconnectNode has nothing to do, as the nodes of a simulated network
+// follow the chain as long as they are running.
+func (n *SimNetwork) ReconnectNode(_ context.Context, node driver.Node) error {
+	_, err := n.simNode(node)
+	return err
+}
+
+func (n *SimNetwork) RemoveNode(node driver.Node) error {
+	n.listenerMutex.Lock()
+	for listener := range n.listeners {
+		listener.BeforeNodeRemoval(node)
+	}
+	n.listenerMutex.Unlock()
+
+	n.nodesMutex.Lock()
+	defer n.nodesMutex.Unlock()
+	id, err := node.GetNodeID()
+	if err != nil {
+		return fmt.Errorf("failed to get node id; %v", err)
+	}
+	delete(n.nodes, id)
+	delete(n.suspended, node)
+	return nil
+}
+
+// SendTransaction hands the transaction to the pool of the chain, which all
+// nodes share.
+func (n *SimNetwork) SendTransaction(tx *types.Transaction, source driver.TransactionSource) {
+	err := n.chain.sendTransaction(tx)
+	if err != nil {
+		slog.Debug("simulated network rejected transaction", "source", source, "error", err)
+	}
+	n.listenerMutex.Lock()
+	defer n.listenerMutex.Unlock()
+	for _, observer := range n.observers {
+		observer.OnTransactionSubmitted(source, tx, time.Now(), err)
+	}
+}
+
+func (n *SimNetwork) RegisterTransactionObserver(observer driver.TransactionObserver) {
+	n.listenerMutex.Lock()
+	defer n.listenerMutex.Unlock()
+	n.observers = append(n.observers, observer)
+}
+
+func (n *SimNetwork) DialRandomRpc() (rpcdriver.Client, error) {
+	nodes := n.GetActiveNodes()
+	if len(nodes) == 0 {
+		return nil, driver.ErrEmptyNetwork
+	}
+	reliable := make([]driver.Node, 0, len(nodes))
+	for _, node := range nodes {
+		if !node.IsExpectedFailure() {
+			reliable = append(reliable, node)
+		}
+	}
+	if len(reliable) == 0 {
+		reliable = nodes // use failing nodes if there are no reliable nodes
+	}
+	return reliable[rand.Intn(len(reliable))].DialRpc(n.ctx)
+}
+
+func (n *SimNetwork) ApplyNetworkRules(ctx context.Context, rules driver.NetworkRules) error {
+	client, err := n.DialRandomRpc()
+	if err != nil {
+		return fmt.Errorf("failed to connect to network: %w", err)
+	}
+	defer client.Close()
+
+	return network.ApplyNetworkRules(ctx, client, rules)
+}
+
+func (n *SimNetwork) AdvanceEpoch(ctx context.Context, epochIncrement int) error {
+	client, err := n.DialRandomRpc()
+	if err != nil {
+		return fmt.Errorf("failed to connect to network: %w", err)
+	}
+	defer client.Close()
+
+	return network.AdvanceEpoch(ctx, client, epochIncrement)
+}
+
+func (n *SimNetwork) WaitForEpochChange(ctx context.Context) error {
+	client, err := n.DialRandomRpc()
+	if err != nil {
+		return fmt.Errorf("failed to connect to network: %w", err)
+	}
+	defer client.Close()
+
+	return network.WaitForEpochChange(ctx, client)
+}
+
+func (n *SimNetwork) CreateApplication(_ context.Context, config *driver.ApplicationConfig) (driver.Application, error) {
+	app, err := newApplication(config, n.chain)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse shaper; %v", err)
+	}
+
+	n.appsMutex.Lock()
+	n.apps = append(n.apps, app)
+	n.appsMutex.Unlock()
+
+	n.listenerMutex.Lock()
+	for listener := range n.listeners {
+		listener.AfterApplicationCreation(app)
+	}
+	n.listenerMutex.Unlock()
+
+	return app, nil
+}
+
+func (n *SimNetwork) GetActiveNodes() []driver.Node {
+	n.nodesMutex.Lock()
+	defer n.nodesMutex.Unlock()
+	res := make([]driver.Node, 0, len(n.nodes))
+	for _, node := range n.nodes {
+		if node.IsRunning() {
+			res = append(res, node)
+		}
+	}
+	return res
+}
+
+func (n *SimNetwork) GetActiveApplications() []driver.Application {
+	n.appsMutex.Lock()
+	defer n.appsMutex.Unlock()
+	return n.apps
+}
+
+func (n *SimNetwork) RegisterListener(listener driver.NetworkListener) {
+	n.listenerMutex.Lock()
+	n.listeners[listener] = true
+	n.listenerMutex.Unlock()
+}
+
+func (n *SimNetwork) UnregisterListener(listener driver.NetworkListener) {
+	n.listenerMutex.Lock()
+	delete(n.listeners, listener)
+	n.listenerMutex.Unlock()
+}
+
+// SuspendNode takes the node out of block production until it is resumed,
+// and notifies listeners as for the LocalNetwork.
+func (n *SimNetwork) SuspendNode(node driver.Node) {
+	n.nodesMutex.Lock()
+	n.suspended[node] = true
+	n.nodesMutex.Unlock()
+
+	n.listenerMutex.Lock()
+	for listener := range n.listeners {
+		listener.BeforeNodeRemoval(node)
+	}
+	n.listenerMutex.Unlock()
+}
+
+func (n *SimNetwork) ResumeNode(node driver.Node) {
+	n.nodesMutex.Lock()
+	delete(n.suspended, node)
+	n.nodesMutex.Unlock()
+
+	n.listenerMutex.Lock()
+	for listener := range n.listeners {
+		listener.AfterNodeCreation(node)
+	}
+	n.listenerMutex.Unlock()
+}
+
+// PartitionNodes splits the network into the given groups: only a group
+// whose validators have a quorum, together with the nodes not listed in any
+// group, keeps producing blocks.
+func (n *SimNetwork) PartitionNodes(_ context.Context, groups [][]driver.Node) error {
+	if len(groups) < 2 {
+		return fmt.Errorf("a partition requires at least two groups, got %d", len(groups))
+	}
+	partition := make([][]*Node, len(groups))
+	seen := map[*Node]bool{}
+	for i, group := range groups {
+		for _, member := range group {
+			sim, err := n.simNode(member)
+			if err != nil {
+				return err
+			}
+			if seen[sim] {
+				return fmt.Errorf("node %s is listed in more than one group", sim.GetLabel())
+			}
+			seen[sim] = true
+			partition[i] = append(partition[i], sim)
+		}
+	}
+
+	n.nodesMutex.Lock()
+	defer n.nodesMutex.Unlock()
+	if n.partition != nil {
+		return fmt.Errorf("network is already partitioned")
+	}
+	n.partition = partition
+	return nil
+}
+
+func (n *SimNetwork) HealPartition(context.Context) error {
+	n.nodesMutex.Lock()
+	defer n.nodesMutex.Unlock()
+	if n.partition == nil {
+		return fmt.Errorf("network is not partitioned")
+	}
+	n.partition = nil
+	return nil
+}
+
+// SetLink accepts the rule without effect, as blocks reach all connected
+// nodes of a simulated network at once.
+func (n *SimNetwork) SetLink(context.Context, driver.LinkRule) error {
+	return nil
+}
+
+// ThrottleNode notifies listeners of the limits, which have no effect on a
+// simulated node.
+func (n *SimNetwork) ThrottleNode(_ context.Context, target driver.Node, config driver.ThrottleConfig) error {
+	if _, err := n.simNode(target); err != nil {
+		return err
+	}
+	n.notifyThrottleListeners(target, &config)
+	return nil
+}
+
+func (n *SimNetwork) UnthrottleNode(_ context.Context, target driver.Node) error {
+	if _, err := n.simNode(target); err != nil {
+		return err
+	}
+	n.notifyThrottleListeners(target, nil)
+	return nil
+}
+
+func (n *SimNetwork) notifyThrottleListeners(target driver.Node, config *driver.ThrottleConfig) {
+	n.listenerMutex.Lock()
+	defer n.listenerMutex.Unlock()
+	for listener := range n.listeners {
+		if l, ok := listener.(driver.NodeThrottleListener); ok {
+			l.OnNodeThrottled(target, config)
+		}
+	}
+}
+
+// ConnectNodes accepts the peering without effect, as the nodes of a
+// simulated network are connected unless partitioned.
+func (n *SimNetwork) ConnectNodes(_ context.Context, a, b driver.Node) error {
+	return n.checkPair(a, b)
+}
+
+// DisconnectNodes accepts the peering without effect, as the nodes of a
+// simulated network are connected unless partitioned.
+func (n *SimNetwork) DisconnectNodes(_ context.Context, a, b driver.Node) error {
+	return n.checkPair(a, b)
+}
+
+func (n *SimNetwork) checkPair(a, b driver.Node) error {
+	_, errA := n.simNode(a)
+	_, errB := n.simNode(b)
+	return errors.Join(errA, errB)
+}
+
+func (n *SimNetwork) Shutdown() error {
+	var errs []error
+
+	// First stop all generators.
+	n.appsMutex.Lock()
+	for _, app := range n.apps {
+		if err := app.Stop(); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	n.apps = n.apps[:0]
+	n.appsMutex.Unlock()
+
+	// Second, stop producing blocks and shut down the nodes.
+	n.cancel()
+	n.done.Wait()
+	n.nodesMutex.Lock()
+	for _, node := range n.nodes {
+		if err := node.Stop(context.Background()); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	n.nodes = map[driver.NodeID]*Node{}
+	n.nodesMutex.Unlock()
+
+	return errors.Join(errs...)
+}
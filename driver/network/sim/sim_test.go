@@ -0,0 +1,493 @@
+// Copyright 2024 Fantom Foundation
+// This file is part of Norma System Testing Infrastructure for Sonic.
+//
+// Norma is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// Norma is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with Norma. If not, see <http://www.gnu.org/licenses/>.
+
+package sim
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"math/big"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/0xsoniclabs/norma/driver"
+	"github.com/0xsoniclabs/norma/driver/monitoring"
+	"github.com/0xsoniclabs/norma/driver/parser"
+	"github.com/0xsoniclabs/norma/driver/rpc"
+	"github.com/0xsoniclabs/sonic/evmcore"
+	"github.com/0xsoniclabs/sonic/opera/contracts/driverauth"
+	"github.com/0xsoniclabs/sonic/opera/contracts/sfc"
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSimNetworkIsNetwork(t *testing.T) {
+	var net SimNetwork
+	var _ driver.Network = &net
+}
+
+func TestNodeIsNode(t *testing.T) {
+	var n Node
+	var _ driver.Node = &n
+}
+
+func newTestNetwork(t *testing.T, validators int) *SimNetwork {
+	t.Helper()
+	net, err := NewSimNetwork(t.Context(), &driver.NetworkConfig{
+		Validators: driver.NewDefaultValidators(validators),
+	})
+	require.NoError(t, err)
+	t.Cleanup(func() { require.NoError(t, net.Shutdown()) })
+	return net
+}
+
+func startValidator(t *testing.T, net *SimNetwork, id int) driver.Node {
+	t.Helper()
+	node, err := net.CreateNode(&driver.NodeConfig{
+		Name:        "validator-" + string(rune('0'+id)),
+		Validator:   true,
+		ValidatorId: &id,
+	})
+	require.NoError(t, err)
+	return node
+}
+
+func dial(t *testing.T, node driver.Node) rpc.Client {
+	t.Helper()
+	client, err := node.DialRpc(t.Context())
+	require.NoError(t, err)
+	t.Cleanup(client.Close)
+	return client
+}
+
+func blockNumber(t *testing.T, client rpc.Client) uint64 {
+	t.Helper()
+	number, err := client.BlockNumber(t.Context())
+	require.NoError(t, err)
+	return number
+}
+
+func waitForBlocks(t *testing.T, client rpc.Client, count uint64) {
+	t.Helper()
+	target := blockNumber(t, client) + count
+	require.Eventually(t, func() bool {
+		return blockNumber(t, client) >= target
+	}, 5*time.Second, 10*time.Millisecond)
+}
+
+func TestSimNetwork_ProducesBlocksOnlyWithQuorum(t *testing.T) {
+	net := newTestNetwork(t, 3)
+	client := dial(t, startValidator(t, net, 1))
+
+	time.Sleep(3 * blockInterval)
+	require.Zero(t, blockNumber(t, client), "one of three validators must not produce blocks")
+
+	startValidator(t, net, 2)
+	time.Sleep(3 * blockInterval)
+	require.Zero(t, blockNumber(t, client), "two of three validators must not produce blocks")
+
+	startValidator(t, net, 3)
+	waitForBlocks(t, client, 2)
+}
+
+func TestSimNetwork_PartitionStallsMinorityUntilHealed(t *testing.T) {
+	net := newTestNetwork(t, 4)
+	var nodes []driver.Node
+	for id := 1; id <= 4; id++ {
+		nodes = append(nodes, startValidator(t, net, id))
+	}
+	majority, minority := dial(t, nodes[0]), dial(t, nodes[3])
+	waitForBlocks(t, minority, 1)
+
+	require.NoError(t, net.PartitionNodes(t.Context(), [][]driver.Node{nodes[:3], nodes[3:]}))
+	require.Error(t, net.PartitionNodes(t.Context(), [][]driver.Node{nodes[:1], nodes[1:]}))
+	stalled := blockNumber(t, minority)
+	waitForBlocks(t, majority, 3)
+	require.Equal(t, stalled, blockNumber(t, minority))
+
+	require.NoError(t, net.HealPartition(t.Context()))
+	require.Eventually(t, func() bool {
+		return blockNumber(t, minority) >= blockNumber(t, majority)
+	}, 5*time.Second, 10*time.Millisecond)
+	require.Error(t, net.HealPartition(t.Context()))
+}
+
+func TestSimNetwork_PartitionWithoutQuorumStallsAll(t *testing.T) {
+	net := newTestNetwork(t, 4)
+	var nodes []driver.Node
+	for id := 1; id <= 4; id++ {
+		nodes = append(nodes, startValidator(t, net, id))
+	}
+	client := dial(t, nodes[0])
+	waitForBlocks(t, client, 1)
+
+	require.NoError(t, net.PartitionNodes(t.Context(), [][]driver.Node{nodes[:2], nodes[2:]}))
+	time.Sleep(2 * blockInterval)
+	stalled := blockNumber(t, client)
+	time.Sleep(3 * blockInterval)
+	require.Equal(t, stalled, blockNumber(t, client))
+}
+
+func TestSimNetwork_BlocksAreServedAsByClient(t *testing.T) {
+	net := newTestNetwork(t, 1)
+	client := dial(t, startValidator(t, net, 1))
+	waitForBlocks(t, client, 2)
+
+	header, err := client.HeaderByNumber(t.Context(), nil)
+	require.NoError(t, err)
+	parent, err := client.HeaderByNumber(t.Context(), new(big.Int).Sub(header.Number, big.NewInt(1)))
+	require.NoError(t, err)
+	require.Equal(t, parent.Hash(), header.ParentHash)
+
+	var block struct {
+		Hash          common.Hash    `json:"hash"`
+		Epoch         hexutil.Uint64 `json:"epoch"`
+		TimestampNano hexutil.Uint64 `json:"timestampNano"`
+	}
+	require.NoError(t, client.Call(&block, "eth_getBlockByNumber", hexutil.EncodeBig(header.Number), false))
+	require.Equal(t, header.Hash(), block.Hash)
+	require.EqualValues(t, 1, block.Epoch)
+	require.InDelta(t, time.Now().UnixNano(), int64(block.TimestampNano), float64(time.Minute))
+
+	var missing map[string]any
+	require.NoError(t, client.Call(&missing, "eth_getBlockByNumber", hexutil.EncodeUint64(1<<40), false))
+	require.Nil(t, missing)
+
+	rules, err := client.GetNetworkRules("latest")
+	require.NoError(t, err)
+	require.Equal(t, net.chain.rules, rules)
+}
+
+func TestSimNetwork_NodeLogReportsBlocks(t *testing.T) {
+	net := newTestNetwork(t, 1)
+	node := startValidator(t, net, 1)
+
+	ctx, cancel := context.WithCancel(t.Context())
+	defer cancel()
+	reader, err := node.StreamLog(ctx)
+	require.NoError(t, err)
+	defer reader.Close()
+
+	blocks := monitoring.NewLogReader(reader)
+	for want := 1; want <= 3; want++ {
+		select {
+		case block := <-blocks:
+			require.Equal(t, want, block.Height)
+		case <-time.After(5 * time.Second):
+			t.Fatalf("no block %d reported", want)
+		}
+	}
+}
+
+func TestSimNetwork_StoppedNodeNoLongerAnswers(t *testing.T) {
+	net := newTestNetwork(t, 1)
+	node := startValidator(t, net, 1)
+	require.True(t, node.IsRunning())
+	require.Len(t, net.GetActiveNodes(), 1)
+
+	require.NoError(t, node.Stop(t.Context()))
+	require.False(t, node.IsRunning())
+	require.Empty(t, net.GetActiveNodes())
+	client := dial(t, node)
+	_, err := client.BlockNumber(t.Context())
+	require.Error(t, err)
+	require.NoError(t, net.RemoveNode(node))
+}
+
+// sendTx sends a transaction of the given key through the client and waits
+// for its receipt.
+func sendTx(
+	t *testing.T, client rpc.Client, key *ecdsa.PrivateKey,
+	to common.Address, value *big.Int, data []byte,
+) *types.Receipt {
+	t.Helper()
+	chainId, err := client.ChainID(t.Context())
+	require.NoError(t, err)
+	nonce, err := client.PendingNonceAt(t.Context(), crypto.PubkeyToAddress(key.PublicKey))
+	require.NoError(t, err)
+	tx, err := types.SignNewTx(key, types.LatestSignerForChainID(chainId), &types.DynamicFeeTx{
+		ChainID:   chainId,
+		Nonce:     nonce,
+		GasFeeCap: big.NewInt(1),
+		Gas:       1_000_000,
+		To:        &to,
+		Value:     value,
+		Data:      data,
+	})
+	require.NoError(t, err)
+	require.NoError(t, client.SendTransaction(t.Context(), tx))
+	receipt, err := client.WaitTransactionReceipt(t.Context(), tx.Hash())
+	require.NoError(t, err)
+	return receipt
+}
+
+// callSfc calls a view method of the SFC contract and returns its results.
+func callSfc(t *testing.T, client rpc.Client, method string, args ...any) []any {
+	t.Helper()
+	return callContract(t, client, sfc.ContractAddress, sfcABI, method, args...)
+}
+
+func callContract(
+	t *testing.T, client rpc.Client, address common.Address,
+	contract abi.ABI, method string, args ...any,
+) []any {
+	t.Helper()
+	data, err := contract.Pack(method, args...)
+	require.NoError(t, err)
+	output, err := client.CallContract(t.Context(), ethereum.CallMsg{To: &address, Data: data}, nil)
+	require.NoError(t, err)
+	results, err := contract.Unpack(method, output)
+	require.NoError(t, err)
+	return results
+}
+
+func advanceEpochs(t *testing.T, client rpc.Client, epochs int64) {
+	t.Helper()
+	data, err := driverAuthABI.Pack("advanceEpochs", big.NewInt(epochs))
+	require.NoError(t, err)
+	receipt := sendTx(t, client, evmcore.FakeKey(1), driverauth.ContractAddress, new(big.Int), data)
+	require.Equal(t, types.ReceiptStatusSuccessful, receipt.Status)
+}
+
+func currentEpoch(t *testing.T, client rpc.Client) uint64 {
+	t.Helper()
+	var epoch hexutil.Uint64
+	require.NoError(t, client.Call(&epoch, "eth_currentEpoch"))
+	return uint64(epoch)
+}
+
+func TestSimNetwork_AdvanceEpochsSealsEpochs(t *testing.T) {
+	net := newTestNetwork(t, 2)
+	client := dial(t, startValidator(t, net, 1))
+	startValidator(t, net, 2)
+	require.EqualValues(t, 1, currentEpoch(t, client))
+
+	advanceEpochs(t, client, 2)
+	require.Eventually(t, func() bool {
+		return currentEpoch(t, client) == 3
+	}, 5*time.Second, 10*time.Millisecond)
+
+	epoch := callSfc(t, client, "currentEpoch")[0].(*big.Int)
+	require.EqualValues(t, 3, epoch.Int64())
+	ids := callSfc(t, client, "getEpochValidatorIDs", epoch)[0].([]*big.Int)
+	require.Len(t, ids, 2)
+}
+
+func TestSimNetwork_RegisteredValidatorJoinsWithNextEpoch(t *testing.T) {
+	net := newTestNetwork(t, 1)
+	client := dial(t, startValidator(t, net, 1))
+
+	receipt := sendTx(t, client, evmcore.FakeKey(2), sfc.ContractAddress, toWei(1_000_000), []byte{})
+	require.Equal(t, types.ReceiptStatusFailed, receipt.Status, "call without a method must revert")
+
+	data, err := sfcABI.Pack("createValidator", []byte{})
+	require.NoError(t, err)
+	receipt = sendTx(t, client, evmcore.FakeKey(2), sfc.ContractAddress, toWei(1_000_000), data)
+	require.Equal(t, types.ReceiptStatusSuccessful, receipt.Status)
+	lastId := callSfc(t, client, "lastValidatorID")[0].(*big.Int)
+	require.EqualValues(t, 2, lastId.Int64())
+
+	epoch := big.NewInt(int64(currentEpoch(t, client)))
+	ids := callSfc(t, client, "getEpochValidatorIDs", epoch)[0].([]*big.Int)
+	require.Len(t, ids, 1)
+
+	advanceEpochs(t, client, 1)
+	require.Eventually(t, func() bool {
+		return currentEpoch(t, client) == epoch.Uint64()+1
+	}, 5*time.Second, 10*time.Millisecond)
+	ids = callSfc(t, client, "getEpochValidatorIDs", new(big.Int).Add(epoch, big.NewInt(1)))[0].([]*big.Int)
+	require.Len(t, ids, 2)
+}
+
+func TestSimNetwork_UpdatedRulesTakeEffectWithNextEpoch(t *testing.T) {
+	net := newTestNetwork(t, 1)
+	client := dial(t, startValidator(t, net, 1))
+
+	data, err := driverAuthABI.Pack("updateNetworkRules", []byte(`{"Blocks":{"MaxBlockGas":1234}}`))
+	require.NoError(t, err)
+	receipt := sendTx(t, client, evmcore.FakeKey(1), driverauth.ContractAddress, new(big.Int), data)
+	require.Equal(t, types.ReceiptStatusSuccessful, receipt.Status)
+
+	rules, err := client.GetNetworkRules("latest")
+	require.NoError(t, err)
+	require.NotEqualValues(t, 1234, rules.Blocks.MaxBlockGas)
+
+	advanceEpochs(t, client, 1)
+	require.Eventually(t, func() bool {
+		rules, err := client.GetNetworkRules("latest")
+		require.NoError(t, err)
+		return rules.Blocks.MaxBlockGas == 1234
+	}, 5*time.Second, 10*time.Millisecond)
+}
+
+func TestSimNetwork_CheaterIsSlashed(t *testing.T) {
+	net := newTestNetwork(t, 4)
+	var nodes []driver.Node
+	for id := 1; id <= 3; id++ {
+		nodes = append(nodes, startValidator(t, net, id))
+	}
+	client := dial(t, nodes[0])
+	require.False(t, callSfc(t, client, "isSlashed", big.NewInt(4))[0].(bool))
+
+	id := 4
+	_, err := net.CreateNode(&driver.NodeConfig{
+		Name:        "validator-4",
+		Validator:   true,
+		ValidatorId: &id,
+		Cheater:     true,
+	})
+	require.NoError(t, err)
+	require.Len(t, net.GetActiveNodes(), 5)
+	require.Eventually(t, func() bool {
+		return callSfc(t, client, "isSlashed", big.NewInt(4))[0].(bool)
+	}, 5*time.Second, 10*time.Millisecond)
+}
+
+func TestSimNetwork_SendTransactionNotifiesObservers(t *testing.T) {
+	net := newTestNetwork(t, 1)
+	client := dial(t, startValidator(t, net, 1))
+	observer := &recordingObserver{}
+	net.RegisterTransactionObserver(observer)
+
+	key := evmcore.FakeKey(1)
+	to := common.Address{1}
+	chainId, err := client.ChainID(t.Context())
+	require.NoError(t, err)
+	signer := types.LatestSignerForChainID(chainId)
+	for nonce := range uint64(2) {
+		tx, err := types.SignNewTx(key, signer, &types.DynamicFeeTx{
+			ChainID: chainId, Nonce: nonce, Gas: 21_000, To: &to, Value: big.NewInt(1),
+		})
+		require.NoError(t, err)
+		net.SendTransaction(tx, driver.TransactionSource{App: "test"})
+		net.SendTransaction(tx, driver.TransactionSource{App: "test"})
+	}
+	require.Len(t, observer.errs, 4)
+	require.NoError(t, observer.errs[0])
+	require.ErrorContains(t, observer.errs[1], "already known")
+	require.NoError(t, observer.errs[2])
+
+	require.Eventually(t, func() bool {
+		balance, err := client.BalanceAt(t.Context(), to, nil)
+		require.NoError(t, err)
+		return balance.Int64() == 2
+	}, 5*time.Second, 10*time.Millisecond)
+}
+
+type recordingObserver struct {
+	errs []error
+}
+
+func (o *recordingObserver) OnTransactionSubmitted(
+	_ driver.TransactionSource, _ *types.Transaction, _ time.Time, err error,
+) {
+	o.errs = append(o.errs, err)
+}
+
+func TestSimNetwork_ApplicationsProduceLoad(t *testing.T) {
+	net := newTestNetwork(t, 1)
+	startValidator(t, net, 1)
+
+	rate := float32(100)
+	app, err := net.CreateApplication(t.Context(), &driver.ApplicationConfig{
+		Name:  "load",
+		Type:  "counter",
+		Rate:  &parser.Rate{Constant: &rate},
+		Users: 2,
+	})
+	require.NoError(t, err)
+	require.Len(t, net.GetActiveApplications(), 1)
+	require.Equal(t, 2, app.GetNumberOfUsers())
+	require.NoError(t, app.Start(t.Context()))
+
+	require.Eventually(t, func() bool {
+		received, err := app.GetReceivedTransactions()
+		require.NoError(t, err)
+		return received >= 20
+	}, 5*time.Second, 10*time.Millisecond)
+	require.NoError(t, app.Stop())
+
+	for user := range 2 {
+		sent, err := app.GetSentTransactions(user)
+		require.NoError(t, err)
+		require.NotZero(t, sent)
+	}
+}
+
+func TestSimNetwork_UnsupportedNodeConfigsAreRejected(t *testing.T) {
+	net := newTestNetwork(t, 1)
+	offset := time.Second
+	_, err := net.CreateNode(&driver.NodeConfig{Name: "skewed", ClockOffset: &offset})
+	require.ErrorContains(t, err, "not supported by the sim backend")
+	_, err = net.CreateNode(&driver.NodeConfig{Name: "limited", Resources: driver.Resources{CPUs: 1}})
+	require.ErrorContains(t, err, "not supported by the sim backend")
+}
+
+func TestCheckScenario_ReportsStepsNeedingContainers(t *testing.T) {
+	packed := 4
+	scenario := &parser.Scenario{Steps: []parser.Step{
+		{Function: parser.FuncStartNode, Identifier: "A"},
+		{Function: parser.FuncStartNode, Identifier: "B", ClientsPerContainer: &packed},
+		{Function: parser.FuncParallel, Branches: []parser.Step{
+			{Function: parser.FuncSetClockSkew, Identifier: "A"},
+			{Function: parser.FuncAdvanceEpoch},
+		}},
+		{Function: parser.FuncSchedule, Branches: []parser.Step{
+			{Function: parser.FuncChaos},
+		}},
+		{Function: parser.FuncSnapshot},
+	}}
+
+	err := CheckScenario(scenario)
+	require.Error(t, err)
+	lines := strings.Split(err.Error(), "\n")
+	require.Len(t, lines, 4)
+	require.Contains(t, lines[0], "step 2 (startNode): packing clients into a container is not supported by the sim backend")
+	require.Contains(t, lines[1], "step 3 (setClockSkew)")
+	require.Contains(t, lines[2], "step 4 (chaos)")
+	require.Contains(t, lines[3], "step 5 (snapshot)")
+}
+
+func TestCheckScenario_AcceptsStepsOfSimulatedNodes(t *testing.T) {
+	scenario := &parser.Scenario{Steps: []parser.Step{
+		{Function: parser.FuncStartNode, Identifier: "A", ImageName: "sonic:v2.0.0"},
+		{Function: parser.FuncStartNode, Identifier: "B", Cheater: true},
+		{Function: parser.FuncRunApp},
+		{Function: parser.FuncPartition},
+		{Function: parser.FuncHeal},
+		{Function: parser.FuncThrottleNode, Identifier: "A"},
+		{Function: parser.FuncStopNode, Identifier: "A"},
+		{Function: parser.FuncStopApp},
+	}}
+	require.NoError(t, CheckScenario(scenario))
+}
+
+func TestBlockLogLine_IsReadByMonitoring(t *testing.T) {
+	b := newBlock(nil, 1, time.Now(), 0)
+	b.header.Number = big.NewInt(12)
+	b.txs = 7
+	b.seal()
+	blocks := monitoring.NewLogReader(strings.NewReader(blockLogLine(b, time.Now())))
+	block := <-blocks
+	require.Equal(t, 12, block.Height)
+	require.Equal(t, 7, block.Txs)
+}
@@ -1,6 +1,15 @@
 package network
 
-import "math/big"
+import (
+	"context"
+	"crypto/ecdsa"
+	"fmt"
+	"math/big"
+
+	"github.com/0xsoniclabs/sonic/evmcore"
+	"github.com/0xsoniclabs/sonic/opera"
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+)
 
 // systemTxGasTipCap is a high priority fee cap for system transactions
 // (UpdateNetworkRules, AdvanceEpochs). It must be larger than the tip used by
@@ -16,3 +25,33 @@ var systemTxGasTipCap = big.NewInt(1_000_000)
 // allowance (0)". A fixed limit bypasses estimation entirely; the actual
 // on-chain execution of DriverAuth calls consumes far less than this limit.
 const systemTxGasLimit uint64 = 1_000_000
+
+// SystemTxSigner signs system transactions, which the DriverAuth contract only
+// accepts from its owner, for the chain they are meant for.
+type SystemTxSigner struct {
+	Key     *ecdsa.PrivateKey
+	ChainID *big.Int
+}
+
+// FakeNetSystemTxSigner returns the signer of networks started from a fake
+// genesis. Driver owner is the first validator from the list i.e., index 1
+// (defined in genesis export in genesis.GenerateJsonGenesis).
+func FakeNetSystemTxSigner() SystemTxSigner {
+	rules := opera.FakeNetRules(opera.GetSonicUpgrades())
+	return SystemTxSigner{
+		Key:     evmcore.FakeKey(1),
+		ChainID: big.NewInt(int64(rules.NetworkID)),
+	}
+}
+
+// transactOpts returns the options for sending a system transaction.
+func (s SystemTxSigner) transactOpts(ctx context.Context) (*bind.TransactOpts, error) {
+	txOpts, err := bind.NewKeyedTransactorWithChainID(s.Key, s.ChainID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create txOpts; %v", err)
+	}
+	txOpts.Context = ctx
+	txOpts.GasTipCap = systemTxGasTipCap
+	txOpts.GasLimit = systemTxGasLimit
+	return txOpts, nil
+}
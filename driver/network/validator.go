@@ -8,7 +8,7 @@ import (
 	"time"
 
 	"github.com/0xsoniclabs/norma/driver/rpc"
-	"github.com/0xsoniclabs/sonic/evmcore"
+	"github.com/0xsoniclabs/norma/genesis"
 	"github.com/0xsoniclabs/sonic/gossip/contract/sfc100"
 	"github.com/0xsoniclabs/sonic/inter/validatorpk"
 	"github.com/0xsoniclabs/sonic/opera"
@@ -21,8 +21,15 @@ import (
 // defaultStakePerValidator is the default stake used when no stake is specified.
 const defaultStakePerValidator = uint64(5_000_000)
 
+// validatorGasBudget is the amount (in S) a validator beyond the ones funded
+// in the genesis receives on top of its stake, to pay for registering.
+const validatorGasBudget uint64 = 1
+
 // RegisterValidatorNode registers a validator in the SFC contract.
-// If stake is 0, the default stake of 5,000,000 S is used.
+// If stake is 0, the default stake of 5,000,000 S is used. A genesis funds
+// the accounts of its own validators and of the others up to ID
+// genesis.FakeNetValidators; validators beyond are funded from the treasury
+// before registering.
 func RegisterValidatorNode(ctx context.Context, backend ContractBackend, stake uint64) (int, error) {
 	// get a representation of the deployed contract
 	SFCContract, err := sfc100.NewContract(sfc.ContractAddress, backend)
@@ -37,7 +44,21 @@ func RegisterValidatorNode(ctx context.Context, backend ContractBackend, stake u
 	}
 	newValId := int(lastValId.Int64()) + 1
 
-	privateKeyECDSA := evmcore.FakeKey(uint32(newValId))
+	privateKeyECDSA, err := genesis.ValidatorKey(newValId)
+	if err != nil {
+		return 0, err
+	}
+	if stake == 0 {
+		stake = defaultStakePerValidator
+	}
+	if newValId > genesis.FakeNetValidators {
+		treasury := FakeNetSystemTxSigner().Key
+		address := crypto.PubkeyToAddress(privateKeyECDSA.PublicKey)
+		if err := FundAccount(ctx, backend, treasury, address, stake+validatorGasBudget); err != nil {
+			return 0, fmt.Errorf("failed to fund validator %d; %v", newValId, err)
+		}
+	}
+
 	txOpts, err := bind.NewKeyedTransactorWithChainID(privateKeyECDSA, big.NewInt(int64(opera.FakeNetRules(opera.GetSonicUpgrades()).NetworkID)))
 	if err != nil {
 		return 0, fmt.Errorf("failed to create txOpts; %v", err)
@@ -45,10 +66,6 @@ func RegisterValidatorNode(ctx context.Context, backend ContractBackend, stake u
 	txOpts.Context = ctx
 	txOpts.GasTipCap = systemTxGasTipCap
 	txOpts.GasLimit = systemTxGasLimit
-
-	if stake == 0 {
-		stake = defaultStakePerValidator
-	}
 	txOpts.Value = stakeToWei(stake)
 
 	validatorPubKey := validatorpk.PubKey{
@@ -87,7 +104,10 @@ func UnregisterValidatorNode(ctx context.Context, client rpc.Client, validatorId
 		return fmt.Errorf("failed to get SFC contract representation; %v", err)
 	}
 
-	key := evmcore.FakeKey(uint32(validatorId))
+	key, err := genesis.ValidatorKey(validatorId)
+	if err != nil {
+		return err
+	}
 	txOpts, err := bind.NewKeyedTransactorWithChainID(key, big.NewInt(int64(opera.FakeNetRules(opera.GetSonicUpgrades()).NetworkID)))
 	if err != nil {
 		return fmt.Errorf("failed to create txOpts; %v", err)
@@ -6,8 +6,10 @@ import (
 	"math/big"
 	"testing"
 
+	"github.com/0xsoniclabs/norma/genesis"
 	"github.com/ethereum/go-ethereum/accounts/abi"
 	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
 	"go.uber.org/mock/gomock"
 )
 
@@ -81,3 +83,44 @@ func mockBackendForCreateValidator(t *testing.T, test func(backend *MockContract
 
 	test(backend)
 }
+
+func TestRegisterValidatorNode_FundsValidatorsBeyondTheGenesisAccounts(t *testing.T) {
+	uint256Type, err := abi.NewType("uint256", "", nil)
+	if err != nil {
+		t.Fatalf("failed to create uint256 type: %v", err)
+	}
+	lastValIdPacked, err := abi.Arguments{{Type: uint256Type}}.Pack(big.NewInt(genesis.FakeNetValidators))
+	if err != nil {
+		t.Fatalf("failed to pack value: %v", err)
+	}
+	key, err := genesis.ValidatorKey(genesis.FakeNetValidators + 1)
+	if err != nil {
+		t.Fatalf("failed to derive validator key: %v", err)
+	}
+	validator := crypto.PubkeyToAddress(key.PublicKey)
+
+	ctrl := gomock.NewController(t)
+	backend := NewMockContractBackend(ctrl)
+	backend.EXPECT().CallContract(gomock.Any(), gomock.Any(), gomock.Any()).Return(lastValIdPacked, nil)
+	backend.EXPECT().HeaderByNumber(gomock.Any(), gomock.Any()).Return(&types.Header{BaseFee: big.NewInt(123)}, nil)
+	backend.EXPECT().PendingNonceAt(gomock.Any(), gomock.Any()).Return(uint64(0), nil).Times(2)
+	backend.EXPECT().WaitTransactionReceipt(gomock.Any(), gomock.Any()).Return(&types.Receipt{Status: types.ReceiptStatusSuccessful}, nil).Times(2)
+	funding := backend.EXPECT().SendTransaction(gomock.Any(), gomock.Any()).DoAndReturn(func(_ context.Context, tx *types.Transaction) error {
+		if tx.To() == nil || *tx.To() != validator {
+			t.Errorf("first transaction does not fund the validator, got recipient %v", tx.To())
+		}
+		if want := stakeToWei(defaultStakePerValidator + validatorGasBudget); tx.Value().Cmp(want) != 0 {
+			t.Errorf("unexpected funding, got %v, want %v", tx.Value(), want)
+		}
+		return nil
+	})
+	backend.EXPECT().SendTransaction(gomock.Any(), gomock.Any()).Return(nil).After(funding)
+
+	id, err := RegisterValidatorNode(context.Background(), backend, 0)
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if id != genesis.FakeNetValidators+1 {
+		t.Errorf("unexpected validator ID, got %d", id)
+	}
+}
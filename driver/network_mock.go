@@ -71,6 +71,20 @@ func (mr *MockNetworkMockRecorder) ApplyNetworkRules(ctx, rules any) *gomock.Cal
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ApplyNetworkRules", reflect.TypeOf((*MockNetwork)(nil).ApplyNetworkRules), ctx, rules)
 }
 
+// ConnectNodes mocks base method.
+func (m *MockNetwork) ConnectNodes(ctx context.Context, a, b Node) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ConnectNodes", ctx, a, b)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// ConnectNodes indicates an expected call of ConnectNodes.
+func (mr *MockNetworkMockRecorder) ConnectNodes(ctx, a, b any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ConnectNodes", reflect.TypeOf((*MockNetwork)(nil).ConnectNodes), ctx, a, b)
+}
+
 // CreateApplication mocks base method.
 func (m *MockNetwork) CreateApplication(arg0 context.Context, arg1 *ApplicationConfig) (Application, error) {
 	m.ctrl.T.Helper()
@@ -116,6 +130,20 @@ func (mr *MockNetworkMockRecorder) DialRandomRpc() *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DialRandomRpc", reflect.TypeOf((*MockNetwork)(nil).DialRandomRpc))
 }
 
+// DisconnectNodes mocks base method.
+func (m *MockNetwork) DisconnectNodes(ctx context.Context, a, b Node) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DisconnectNodes", ctx, a, b)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DisconnectNodes indicates an expected call of DisconnectNodes.
+func (mr *MockNetworkMockRecorder) DisconnectNodes(ctx, a, b any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DisconnectNodes", reflect.TypeOf((*MockNetwork)(nil).DisconnectNodes), ctx, a, b)
+}
+
 // GetActiveApplications mocks base method.
 func (m *MockNetwork) GetActiveApplications() []Application {
 	m.ctrl.T.Helper()
@@ -144,6 +172,34 @@ func (mr *MockNetworkMockRecorder) GetActiveNodes() *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetActiveNodes", reflect.TypeOf((*MockNetwork)(nil).GetActiveNodes))
 }
 
+// HealPartition mocks base method.
+func (m *MockNetwork) HealPartition(ctx context.Context) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "HealPartition", ctx)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// HealPartition indicates an expected call of HealPartition.
+func (mr *MockNetworkMockRecorder) HealPartition(ctx any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "HealPartition", reflect.TypeOf((*MockNetwork)(nil).HealPartition), ctx)
+}
+
+// PartitionNodes mocks base method.
+func (m *MockNetwork) PartitionNodes(ctx context.Context, groups [][]Node) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "PartitionNodes", ctx, groups)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// PartitionNodes indicates an expected call of PartitionNodes.
+func (mr *MockNetworkMockRecorder) PartitionNodes(ctx, groups any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PartitionNodes", reflect.TypeOf((*MockNetwork)(nil).PartitionNodes), ctx, groups)
+}
+
 // ReconnectNode mocks base method.
 func (m *MockNetwork) ReconnectNode(ctx context.Context, node Node) error {
 	m.ctrl.T.Helper()
@@ -220,6 +276,20 @@ func (mr *MockNetworkMockRecorder) SendTransaction(tx, source any) *gomock.Call
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SendTransaction", reflect.TypeOf((*MockNetwork)(nil).SendTransaction), tx, source)
 }
 
+// SetLink mocks base method.
+func (m *MockNetwork) SetLink(ctx context.Context, rule LinkRule) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetLink", ctx, rule)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SetLink indicates an expected call of SetLink.
+func (mr *MockNetworkMockRecorder) SetLink(ctx, rule any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetLink", reflect.TypeOf((*MockNetwork)(nil).SetLink), ctx, rule)
+}
+
 // Shutdown mocks base method.
 func (m *MockNetwork) Shutdown() error {
 	m.ctrl.T.Helper()
@@ -246,6 +316,20 @@ func (mr *MockNetworkMockRecorder) SuspendNode(node any) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SuspendNode", reflect.TypeOf((*MockNetwork)(nil).SuspendNode), node)
 }
 
+// ThrottleNode mocks base method.
+func (m *MockNetwork) ThrottleNode(ctx context.Context, node Node, config ThrottleConfig) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ThrottleNode", ctx, node, config)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// ThrottleNode indicates an expected call of ThrottleNode.
+func (mr *MockNetworkMockRecorder) ThrottleNode(ctx, node, config any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ThrottleNode", reflect.TypeOf((*MockNetwork)(nil).ThrottleNode), ctx, node, config)
+}
+
 // UnregisterListener mocks base method.
 func (m *MockNetwork) UnregisterListener(arg0 NetworkListener) {
 	m.ctrl.T.Helper()
@@ -258,6 +342,20 @@ func (mr *MockNetworkMockRecorder) UnregisterListener(arg0 any) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UnregisterListener", reflect.TypeOf((*MockNetwork)(nil).UnregisterListener), arg0)
 }
 
+// UnthrottleNode mocks base method.
+func (m *MockNetwork) UnthrottleNode(ctx context.Context, node Node) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UnthrottleNode", ctx, node)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UnthrottleNode indicates an expected call of UnthrottleNode.
+func (mr *MockNetworkMockRecorder) UnthrottleNode(ctx, node any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UnthrottleNode", reflect.TypeOf((*MockNetwork)(nil).UnthrottleNode), ctx, node)
+}
+
 // WaitForEpochChange mocks base method.
 func (m *MockNetwork) WaitForEpochChange(ctx context.Context) error {
 	m.ctrl.T.Helper()
@@ -332,6 +430,42 @@ func (mr *MockNetworkListenerMockRecorder) BeforeNodeRemoval(arg0 any) *gomock.C
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "BeforeNodeRemoval", reflect.TypeOf((*MockNetworkListener)(nil).BeforeNodeRemoval), arg0)
 }
 
+// MockNodeThrottleListener is a mock of NodeThrottleListener interface.
+type MockNodeThrottleListener struct {
+	ctrl     *gomock.Controller
+	recorder *MockNodeThrottleListenerMockRecorder
+	isgomock struct{}
+}
+
+// MockNodeThrottleListenerMockRecorder is the mock recorder for MockNodeThrottleListener.
+type MockNodeThrottleListenerMockRecorder struct {
+	mock *MockNodeThrottleListener
+}
+
+// NewMockNodeThrottleListener creates a new mock instance.
+func NewMockNodeThrottleListener(ctrl *gomock.Controller) *MockNodeThrottleListener {
+	mock := &MockNodeThrottleListener{ctrl: ctrl}
+	mock.recorder = &MockNodeThrottleListenerMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockNodeThrottleListener) EXPECT() *MockNodeThrottleListenerMockRecorder {
+	return m.recorder
+}
+
+// OnNodeThrottled mocks base method.
+func (m *MockNodeThrottleListener) OnNodeThrottled(node Node, config *ThrottleConfig) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "OnNodeThrottled", node, config)
+}
+
+// OnNodeThrottled indicates an expected call of OnNodeThrottled.
+func (mr *MockNodeThrottleListenerMockRecorder) OnNodeThrottled(node, config any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "OnNodeThrottled", reflect.TypeOf((*MockNodeThrottleListener)(nil).OnNodeThrottled), node, config)
+}
+
 // MockTransactionObserver is a mock of TransactionObserver interface.
 type MockTransactionObserver struct {
 	ctrl     *gomock.Controller
@@ -0,0 +1,91 @@
+// Copyright 2024 Fantom Foundation
+// This file is part of Norma System Testing Infrastructure for Sonic.
+//
+// Norma is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// Norma is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with Norma. If not, see <http://www.gnu.org/licenses/>.
+
+package node
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/0xsoniclabs/norma/driver"
+)
+
+const (
+	// clockHookMarker is present in client images whose clients are built
+	// with the clock offset hook of driver/node/clockhook, the sonic-clock
+	// images; see driver.ClockClientDockerImageName.
+	clockHookMarker = "/clock-offset-hook"
+	// clockOffsetEnv names the file the clock offset hook reads the offset
+	// of the client's clock from.
+	clockOffsetEnv = "NORMA_CLOCK_OFFSET_FILE"
+	// clockOffsetPath holds the offset of the clock of the client of a
+	// container of its own; see clientLayout. The client re-reads it every
+	// second, so the offset can be changed without restarting it.
+	clockOffsetPath = "/clock-offset"
+)
+
+// SetClockOffset changes the offset of the client's clock from the host's
+// clock. It takes effect within a second, without restarting the client.
+// Only nodes configured with a ClockOffset have a controllable clock.
+func (n *OperaNode) SetClockOffset(ctx context.Context, offset time.Duration) error {
+	if n.config.ClockOffset == nil {
+		return fmt.Errorf("node %q was not started with a controllable clock", n.GetLabel())
+	}
+	n.clockMutex.Lock()
+	defer n.clockMutex.Unlock()
+	if err := n.writeContainerFile(ctx, n.clientLayout().clockOffsetFile, clockOffsetText(offset)); err != nil {
+		return fmt.Errorf("failed to set clock offset of node %q: %w", n.GetLabel(), err)
+	}
+	n.clockOffset = offset
+	return nil
+}
+
+// ClockOffset returns the current offset of the client's clock from the
+// host's clock.
+func (n *OperaNode) ClockOffset() time.Duration {
+	n.clockMutex.Lock()
+	defer n.clockMutex.Unlock()
+	return n.clockOffset
+}
+
+// clockEnv returns the environment a client start needs to run with the
+// node's clock offset, or nil if the node's clock is not controlled. The
+// offset is only applied to the client, so that the tools run in the
+// container keep the host's clock.
+func (n *OperaNode) clockEnv(ctx context.Context) ([]string, error) {
+	if n.config.ClockOffset == nil {
+		return nil, nil
+	}
+	if _, err := n.container.Exec(ctx, []string{"test", "-e", clockHookMarker}); err != nil {
+		return nil, fmt.Errorf("image %s does not support clock offsets: its client is not built with the clock offset hook, use a sonic-clock image such as %s",
+			n.config.Image, driver.ClockClientDockerImageName)
+	}
+	n.clockMutex.Lock()
+	defer n.clockMutex.Unlock()
+	offsetFile := n.clientLayout().clockOffsetFile
+	if err := n.writeContainerFile(ctx, offsetFile, clockOffsetText(n.clockOffset)); err != nil {
+		return nil, fmt.Errorf("failed to write %s: %w", offsetFile, err)
+	}
+	return []string{clockOffsetEnv + "=" + offsetFile}, nil
+}
+
+// clockOffsetText renders an offset the way the clock offset hook reads
+// it: signed nanoseconds, terminated by a newline. The hook ignores the
+// file while the newline is missing, as the file is then being written.
+func clockOffsetText(offset time.Duration) string {
+	return fmt.Sprintf("%+d\n", offset.Nanoseconds())
+}
@@ -0,0 +1,135 @@
+// Copyright 2024 Fantom Foundation
+// This file is part of Norma System Testing Infrastructure for Sonic.
+//
+// Norma is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// Norma is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with Norma. If not, see <http://www.gnu.org/licenses/>.
+
+package node
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/0xsoniclabs/norma/driver"
+	"github.com/0xsoniclabs/norma/driver/docker"
+	"github.com/0xsoniclabs/norma/driver/rpc"
+)
+
+func TestClockOffsetText_RendersSignedNanoseconds(t *testing.T) {
+	tests := map[time.Duration]string{
+		0:                       "+0\n",
+		2 * time.Second:         "+2000000000\n",
+		-500 * time.Millisecond: "-500000000\n",
+		90 * time.Minute:        "+5400000000000\n",
+		time.Nanosecond:         "+1\n",
+	}
+	for offset, want := range tests {
+		if got := clockOffsetText(offset); got != want {
+			t.Errorf("unexpected offset for %v, got %q, want %q", offset, got, want)
+		}
+	}
+}
+
+// Without a ClockOffset, the client runs without the offset file, so its
+// clock cannot be offset later on.
+func TestOperaNode_SetClockOffset_RequiresControllableClock(t *testing.T) {
+	node := newNodeInState(t, NodeStateRunning)
+
+	err := node.SetClockOffset(t.Context(), time.Second)
+	if err == nil || !strings.Contains(err.Error(), "controllable clock") {
+		t.Fatalf("expected an error for a node without clock control, got %v", err)
+	}
+	if got := node.ClockOffset(); got != 0 {
+		t.Errorf("unexpected clock offset, got %v, want 0", got)
+	}
+}
+
+func TestOperaNode_ClockEnv_IsEmptyWithoutClockOffset(t *testing.T) {
+	node := newNodeInState(t, NodeStateReady)
+
+	env, err := node.clockEnv(t.Context())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if env != nil {
+		t.Errorf("unexpected environment for a node without clock control: %v", env)
+	}
+}
+
+// The clock offset must reach the client itself: a lone validator stamps its
+// blocks with the time of its own clock, so the timestamps of its blocks
+// follow the offset, also after it is changed while the client runs.
+func TestOperaNode_ClockOffsetShiftsBlockTimestamps(t *testing.T) {
+	client, err := docker.NewClient()
+	if err != nil {
+		t.Fatalf("failed to create a docker client: %v", err)
+	}
+	t.Cleanup(func() {
+		_ = client.Close()
+	})
+	dn := client.CreateTestBridgeNetwork(t)
+	offset := time.Hour
+	node, err := StartOperaDockerNode(t.Context(), client, dn, &OperaNodeConfig{
+		Label:         t.Name(),
+		Image:         driver.ClockClientDockerImageName,
+		NetworkConfig: &driver.NetworkConfig{Validators: driver.DefaultValidators(t.Name())},
+		ClockOffset:   &offset,
+	})
+	if err != nil {
+		t.Fatalf("failed to create an Opera node on Docker: %v", err)
+	}
+	t.Cleanup(func() {
+		if err := node.Cleanup(context.Background()); err != nil {
+			t.Errorf("failed to cleanup node: %v", err)
+		}
+	})
+	rpcClient, err := node.DialRpc(t.Context())
+	if err != nil {
+		t.Fatalf("failed to dial the node: %v", err)
+	}
+	t.Cleanup(rpcClient.Close)
+
+	head := waitForBlockShiftedBy(t, rpcClient, 0, offset)
+
+	offset = 2 * time.Hour
+	if err := node.SetClockOffset(t.Context(), offset); err != nil {
+		t.Fatalf("failed to change the clock offset: %v", err)
+	}
+	waitForBlockShiftedBy(t, rpcClient, head, offset)
+}
+
+// waitForBlockShiftedBy waits for a block after the given one whose
+// timestamp is ahead of the host's clock by about the given offset, and
+// returns its number.
+func waitForBlockShiftedBy(t *testing.T, client rpc.Client, after uint64, offset time.Duration) uint64 {
+	t.Helper()
+	const tolerance = 5 * time.Minute
+	var shift time.Duration
+	for deadline := time.Now().Add(time.Minute); time.Now().Before(deadline); time.Sleep(time.Second) {
+		header, err := client.HeaderByNumber(t.Context(), nil)
+		if err != nil {
+			t.Fatalf("failed to get the latest block: %v", err)
+		}
+		if header.Number.Uint64() <= after {
+			continue
+		}
+		shift = time.Until(time.Unix(int64(header.Time), 0))
+		if shift > offset-tolerance && shift < offset+tolerance {
+			return header.Number.Uint64()
+		}
+	}
+	t.Fatalf("no block after block %d is ahead of the host by about %v, the last one by %v", after, offset, shift)
+	return 0
+}
@@ -0,0 +1,115 @@
+// Copyright 2024 Fantom Foundation
+// This file is part of Norma System Testing Infrastructure for Sonic.
+//
+// Norma is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// Norma is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with Norma. If not, see <http://www.gnu.org/licenses/>.
+
+//go:build normaclock
+
+// This file is not part of Norma. The sonic-clock client images add it to
+// the time package of the Go toolchain their client is built with, and make
+// time.Now call offsetClock on each reading of the wall clock; see the
+// "clock" target of the Dockerfile.
+//
+// Go programs read the clock through the vDSO rather than libc, so the
+// clock of the client cannot be offset from outside of the process; it is
+// offset within the time package instead. Only the wall clock is offset:
+// the monotonic clock timers and durations rely on is left as is.
+
+package time
+
+import (
+	"sync/atomic"
+	"syscall"
+)
+
+const (
+	// clockOffsetEnv names the environment variable holding the path of
+	// the file the offset of the clock is read from. Without it, the clock
+	// is not offset.
+	clockOffsetEnv = "NORMA_CLOCK_OFFSET_FILE"
+	// clockOffsetRefresh is the interval, in nanoseconds, at which the
+	// offset file is re-read, so the offset can be changed while the
+	// client runs.
+	clockOffsetRefresh = 1e9
+)
+
+var (
+	clockOffsetFile, _ = syscall.Getenv(clockOffsetEnv)
+	// clockOffset is the offset of the wall clock, in nanoseconds.
+	clockOffset atomic.Int64
+	// clockOffsetRead is the monotonic reading the offset file was last
+	// read at, zero if never.
+	clockOffsetRead atomic.Int64
+)
+
+// offsetClock offsets a reading of the wall clock, taken along with the
+// given monotonic reading, by the offset in the offset file.
+func offsetClock(sec int64, nsec int32, mono int64) (int64, int32) {
+	if clockOffsetFile == "" {
+		return sec, nsec
+	}
+	last := clockOffsetRead.Load()
+	if (last == 0 || mono-last >= clockOffsetRefresh) && clockOffsetRead.CompareAndSwap(last, mono) {
+		if offset, ok := readClockOffset(clockOffsetFile); ok {
+			clockOffset.Store(offset)
+		}
+	}
+	total := int64(nsec) + clockOffset.Load()
+	sec += total / 1e9
+	total %= 1e9
+	if total < 0 {
+		sec--
+		total += 1e9
+	}
+	return sec, int32(total)
+}
+
+// readClockOffset reads an offset in nanoseconds from the given file. The
+// offset is a signed decimal number terminated by a newline; a file lacking
+// the newline is still being written and is ignored.
+func readClockOffset(path string) (int64, bool) {
+	fd, err := syscall.Open(path, syscall.O_RDONLY|syscall.O_CLOEXEC, 0)
+	if err != nil {
+		return 0, false
+	}
+	defer syscall.Close(fd)
+	var buf [32]byte
+	n, err := syscall.Read(fd, buf[:])
+	if err != nil || n < 2 || buf[n-1] != '\n' {
+		return 0, false
+	}
+	digits := buf[:n-1]
+	negative := false
+	switch digits[0] {
+	case '-':
+		negative = true
+		digits = digits[1:]
+	case '+':
+		digits = digits[1:]
+	}
+	if len(digits) == 0 || len(digits) > 18 {
+		return 0, false
+	}
+	var offset int64
+	for _, digit := range digits {
+		if digit < '0' || digit > '9' {
+			return 0, false
+		}
+		offset = offset*10 + int64(digit-'0')
+	}
+	if negative {
+		offset = -offset
+	}
+	return offset, true
+}
@@ -0,0 +1,39 @@
+#!/bin/sh
+# Copyright 2024 Fantom Foundation
+# This file is part of Norma System Testing Infrastructure for Sonic.
+#
+# Norma is free software: you can redistribute it and/or modify
+# it under the terms of the GNU Lesser General Public License as published by
+# the Free Software Foundation, either version 3 of the License, or
+# (at your option) any later version.
+#
+# Norma is distributed in the hope that it will be useful,
+# but WITHOUT ANY WARRANTY; without even the implied warranty of
+# MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+# GNU lesser General Public License for more details.
+#
+# You should have received a copy of the GNU Lesser General Public License
+# along with Norma. If not, see <http://www.gnu.org/licenses/>.
+
+# Installs the clock offset hook of clock_offset.go into the time package of
+# the Go toolchain that builds the module in the working directory, so that
+# every program built with it afterwards offsets its wall clock by the offset
+# in the file named by NORMA_CLOCK_OFFSET_FILE. It is meant for the throwaway
+# build stage of the clock images of the Dockerfile only. It fails if time.Now
+# does not read the clock the way the hook expects, rather than building
+# clients without it.
+set -eu
+
+dir="$(dirname "$0")"
+time_src="$(go env GOROOT)/src/time"
+reading='^\([[:space:]]*\)sec, nsec, mono := runtimeNow()$'
+
+# A toolchain go.mod asks for is unpacked read-only into the module cache.
+chmod u+w "$time_src" "$time_src/time.go"
+
+if [ "$(grep -c "$reading" "$time_src/time.go")" != 1 ]; then
+    echo "time.Now of $(go version) does not read the clock as expected" >&2
+    exit 1
+fi
+sed -i "s/$reading/&\n\1sec, nsec = offsetClock(sec, nsec, mono)/" "$time_src/time.go"
+sed '/^\/\/go:build normaclock$/d' "$dir/clock_offset.go" > "$time_src/norma_clock_offset.go"
@@ -0,0 +1,167 @@
+// Copyright 2024 Fantom Foundation
+// This file is part of Norma System Testing Infrastructure for Sonic.
+//
+// Norma is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// Norma is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with Norma. If not, see <http://www.gnu.org/licenses/>.
+
+package node
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/0xsoniclabs/norma/driver"
+	"github.com/0xsoniclabs/norma/driver/docker"
+)
+
+// PeerLink is the configuration of the link from a node to one of its peers,
+// identified by the peer's address on the docker network.
+type PeerLink struct {
+	IP   string
+	Link driver.LinkConfig
+}
+
+// linkDevice is the container interface attached to the docker network.
+const linkDevice = "eth0"
+
+// ConfigureLinks shapes the traffic the node sends: packets to each of the
+// given peers are subject to the conditions of the link to that peer, all
+// other traffic to the uniform delay of half the network's round trip time
+// that Initialize installs. The previous configuration is replaced as a
+// whole, so passing no links restores the uniform delay. Bandwidth limits
+// set by Throttle stay in place.
+//
+// Each direction of a link is shaped by its sender, so the peers have to be
+// configured accordingly for a link to be symmetric.
+func (n *OperaNode) ConfigureLinks(ctx context.Context, links []PeerLink) error {
+	if len(links) > 0 {
+		if err := n.requireOwnContainer("shape the links of"); err != nil {
+			return err
+		}
+	}
+	n.shapingMutex.Lock()
+	defer n.shapingMutex.Unlock()
+	if err := n.applyShaping(ctx, links, n.throttle); err != nil {
+		return err
+	}
+	n.links = links
+	return nil
+}
+
+// applyShaping replaces the traffic shaping of the node by the one for the
+// given links and bandwidth limits. Must be called with shapingMutex held.
+func (n *OperaNode) applyShaping(ctx context.Context, links []PeerLink, throttle *driver.ThrottleConfig) error {
+	script, err := shapingScript(linkDevice, n.defaultLatency(), links, throttle)
+	if err != nil {
+		return fmt.Errorf("invalid traffic shaping of node %s: %w", n.GetLabel(), err)
+	}
+	cmd := []string{"sh", "-c", script}
+	if output, err := n.container.ExecWithOptions(ctx, cmd,
+		docker.ExecOptions{LogName: "tc-shaping"}); err != nil {
+		return fmt.Errorf("failed to configure traffic shaping of node %s: %w - output: %s",
+			n.GetLabel(), err, output)
+	}
+	return nil
+}
+
+// defaultLatency is the delay of the links no rule covers.
+func (n *OperaNode) defaultLatency() time.Duration {
+	if n.config.NetworkConfig == nil {
+		return 0
+	}
+	return n.config.NetworkConfig.RoundTripTime / 2
+}
+
+// shapingScript builds the shell script installing the shaping of the given
+// links and bandwidth limits on a device. It sets up an htb tree whose
+// default class carries the default delay, and one class per peer with its
+// own netem qdisc, selected by a filter on the peer's address. The classes
+// only classify traffic; their rate is far above anything a container sends.
+// An egress limit puts a tbf in front of the tree, an ingress limit polices
+// the incoming traffic.
+func shapingScript(
+	device string,
+	defaultLatency time.Duration,
+	links []PeerLink,
+	throttle *driver.ThrottleConfig,
+) (string, error) {
+	tc := func(cmd string) string {
+		return "tc " + cmd + " dev " + device
+	}
+	const rate = "rate 10gbit"
+
+	var cmds []string
+	if throttle != nil && throttle.Egress > 0 {
+		cmds = append(cmds,
+			tc("qdisc add")+" root handle f: tbf "+tbfArgs(throttle.Egress, throttle.Burst),
+			tc("qdisc add")+" parent f:1 handle 1: htb default 1",
+		)
+	} else {
+		cmds = append(cmds, tc("qdisc add")+" root handle 1: htb default 1")
+	}
+	cmds = append(cmds, tc("class add")+" parent 1: classid 1:1 htb "+rate)
+	if defaultLatency > 0 {
+		cmds = append(cmds, tc("qdisc add")+" parent 1:1 handle 10: netem "+
+			netemArgs(driver.LinkConfig{Latency: defaultLatency}))
+	}
+	for i, link := range links {
+		// The peer address ends up in a shell command, so it must be
+		// nothing but an address.
+		if net.ParseIP(link.IP) == nil {
+			return "", fmt.Errorf("invalid peer address %q", link.IP)
+		}
+		// Class 1:1 is the default; handles are hexadecimal.
+		class := fmt.Sprintf("1:%x", i+2)
+		handle := fmt.Sprintf("%x:", i+0x100)
+		cmds = append(cmds,
+			tc("class add")+" parent 1: classid "+class+" htb "+rate,
+			tc("qdisc add")+" parent "+class+" handle "+handle+" netem "+netemArgs(link.Link),
+			tc("filter add")+" parent 1: protocol ip prio 1 u32 match ip dst "+link.IP+"/32 flowid "+class,
+		)
+	}
+	if throttle != nil && throttle.Ingress > 0 {
+		cmds = append(cmds,
+			tc("qdisc add")+" handle ffff: ingress",
+			tc("filter add")+" parent ffff: protocol all prio 1 u32 match u32 0 0 police "+
+				policeArgs(throttle.Ingress, throttle.Burst)+" drop flowid :1",
+		)
+	}
+	// Removing qdiscs that do not exist fails, which is fine: the point is
+	// to start from a clean slate.
+	return tc("qdisc del") + " root 2>/dev/null; " +
+		tc("qdisc del") + " ingress 2>/dev/null; " +
+		strings.Join(cmds, " && "), nil
+}
+
+// netemArgs renders a link configuration as netem parameters.
+func netemArgs(link driver.LinkConfig) string {
+	args := []string{fmt.Sprintf("delay %dus", link.Latency.Microseconds())}
+	if link.Jitter > 0 {
+		args = append(args, fmt.Sprintf("%dus", link.Jitter.Microseconds()))
+	}
+	if link.Loss > 0 {
+		args = append(args, "loss "+formatPercent(link.Loss))
+	}
+	if link.Reorder > 0 {
+		args = append(args, "reorder "+formatPercent(link.Reorder))
+	}
+	return strings.Join(args, " ")
+}
+
+func formatPercent(v float64) string {
+	return strconv.FormatFloat(v, 'f', -1, 64) + "%"
+}
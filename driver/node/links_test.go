@@ -0,0 +1,86 @@
+// Copyright 2024 Fantom Foundation
+// This file is part of Norma System Testing Infrastructure for Sonic.
+//
+// Norma is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// Norma is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with Norma. If not, see <http://www.gnu.org/licenses/>.
+
+package node
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/0xsoniclabs/norma/driver"
+)
+
+func TestShapingScript_UsesDefaultClassWithoutLinks(t *testing.T) {
+	script, err := shapingScript("eth0", 50*time.Millisecond, nil, nil)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	want := "tc qdisc del dev eth0 root 2>/dev/null; " +
+		"tc qdisc del dev eth0 ingress 2>/dev/null; " +
+		"tc qdisc add dev eth0 root handle 1: htb default 1 && " +
+		"tc class add dev eth0 parent 1: classid 1:1 htb rate 10gbit && " +
+		"tc qdisc add dev eth0 parent 1:1 handle 10: netem delay 50000us"
+	if script != want {
+		t.Errorf("unexpected script\ngot:  %s\nwant: %s", script, want)
+	}
+}
+
+func TestShapingScript_OmitsDefaultDelayWhenZero(t *testing.T) {
+	script, err := shapingScript("eth0", 0, nil, nil)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if strings.Contains(script, "netem") {
+		t.Errorf("script must not install a default delay: %s", script)
+	}
+}
+
+func TestShapingScript_AddsClassAndFilterPerPeer(t *testing.T) {
+	links := []PeerLink{
+		{IP: "172.18.0.2", Link: driver.LinkConfig{Latency: 80 * time.Millisecond}},
+		{IP: "172.18.0.3", Link: driver.LinkConfig{
+			Latency: 100 * time.Millisecond,
+			Jitter:  5 * time.Millisecond,
+			Loss:    0.5,
+			Reorder: 25,
+		}},
+	}
+	script, err := shapingScript("eth0", 0, links, nil)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	for _, want := range []string{
+		"tc class add dev eth0 parent 1: classid 1:2 htb rate 10gbit",
+		"tc qdisc add dev eth0 parent 1:2 handle 100: netem delay 80000us",
+		"tc filter add dev eth0 parent 1: protocol ip prio 1 u32 match ip dst 172.18.0.2/32 flowid 1:2",
+		"tc class add dev eth0 parent 1: classid 1:3 htb rate 10gbit",
+		"tc qdisc add dev eth0 parent 1:3 handle 101: netem delay 100000us 5000us loss 0.5% reorder 25%",
+		"tc filter add dev eth0 parent 1: protocol ip prio 1 u32 match ip dst 172.18.0.3/32 flowid 1:3",
+	} {
+		if !strings.Contains(script, want) {
+			t.Errorf("script is missing %q: %s", want, script)
+		}
+	}
+}
+
+func TestShapingScript_RejectsInvalidAddress(t *testing.T) {
+	links := []PeerLink{{IP: "1.2.3.4; reboot"}}
+	if _, err := shapingScript("eth0", 0, links, nil); err == nil {
+		t.Fatalf("expected an invalid address to be rejected")
+	}
+}
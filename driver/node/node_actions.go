@@ -19,14 +19,17 @@ package node
 import (
 	"context"
 	"fmt"
+	"io/fs"
 	"log/slog"
 	"os"
+	"path/filepath"
 	"strconv"
 	"strings"
 	"time"
 
 	"github.com/0xsoniclabs/norma/driver/docker"
 	"github.com/0xsoniclabs/norma/driver/network"
+	"github.com/0xsoniclabs/norma/genesis"
 )
 
 // Paths inside the container. Stage 2 of the Dockerfile copies the
@@ -53,6 +56,10 @@ const (
 // than a substring of cmdline) prevents accidental hits on sonictool or
 // any other sibling process.
 //
+// If NORMA_DATADIR is set, only the clients running on that data directory
+// are matched, so that a client sharing its container with others is told
+// apart from them.
+//
 // The snippet increments n for each process it accounts for, and the count
 // is reported on the last line as "matched=<n>". The script always exits 0,
 // because finding no process is a legitimate outcome that the caller must
@@ -61,6 +68,7 @@ const clientScanScript = `n=0;` +
 	` for d in /proc/[0-9]*; do` +
 	` exe=$(readlink "$d/exe" 2>/dev/null) || continue;` +
 	` [ "$exe" = "` + sonicdBinaryPath + `" ] || continue;` +
+	` [ -z "$NORMA_DATADIR" ] || tr '\0' '\n' < "$d/cmdline" 2>/dev/null | grep -qxF -- "--datadir=$NORMA_DATADIR" || continue;` +
 	` %s;` +
 	` done;` +
 	` echo "matched=$n"`
@@ -101,22 +109,24 @@ func (n *OperaNode) Initialize(ctx context.Context) (err error) {
 		}
 	}()
 
+	layout := n.clientLayout()
+
 	// Skip initialization only when re-using a populated mount directory.
 	needsInit := n.config.MountDataDir == nil || isDirEmpty(*n.config.MountDataDir)
 	if needsInit {
-		mkdirCmd := []string{"mkdir", "-m", "755", "-p", dataDir}
+		mkdirCmd := []string{"mkdir", "-m", "755", "-p", layout.dataDir}
 		if output, err := n.container.Exec(ctx, mkdirCmd); err != nil {
 			return fmt.Errorf("failed to create datadir: %w - output: %s", err, output)
 		}
 
 		sonicToolCmd := []string{
 			sonicToolBinaryPath,
-			"--datadir", dataDir,
+			"--datadir", layout.dataDir,
 			"--statedb.livecache", "1",
 			"genesis", "json", "--experimental", "/genesis.json",
 		}
 		output, err := n.container.ExecWithOptions(ctx, sonicToolCmd,
-			docker.ExecOptions{LogName: "sonictool-genesis"})
+			n.execOptions("sonictool-genesis"))
 		if err != nil {
 			return fmt.Errorf("sonictool genesis init failed: %w - output: %s", err, output)
 		}
@@ -126,18 +136,34 @@ func (n *OperaNode) Initialize(ctx context.Context) (err error) {
 	// password is intentionally the fixed string "password" because
 	// norma only spins up fake, throwaway validators for testing; it
 	// is never used to protect real keys.
-	if err := n.writeContainerFile(ctx, passwordFilePath, "password\n"); err != nil {
+	if err := n.writeContainerFile(ctx, layout.passwordFile(), "password\n"); err != nil {
 		return fmt.Errorf("failed to write password file: %w", err)
 	}
 
-	// Network latency simulation via tc netem.
-	latency := n.config.NetworkConfig.RoundTripTime / 2
-	if latency > 0 {
+	// A shared container cannot mount the keystore of each of its
+	// validators, so it is written into their data directories instead.
+	if n.pack != nil {
+		if err := n.writeValidatorKeystore(ctx); err != nil {
+			return err
+		}
+	}
+
+	// Network latency simulation via tc netem. The delay on eth0 is part
+	// of the shaping refined by link rules and bandwidth limits later on.
+	// The clients of a shared container share its interface, so it is
+	// only configured by the first of them.
+	latency := n.defaultLatency()
+	if latency > 0 && (n.pack == nil || n.pack.claimShaping()) {
+		n.shapingMutex.Lock()
+		err := n.applyShaping(ctx, n.links, n.throttle)
+		n.shapingMutex.Unlock()
+		if err != nil {
+			return fmt.Errorf("failed to configure network latency: %w", err)
+		}
 		tcCmd := fmt.Sprintf(
-			"tc qdisc replace dev eth0 root netem delay %v"+
-				" && (ip link show eth1 2>/dev/null"+
-				" && tc qdisc replace dev eth1 root netem delay %v || true)",
-			latency, latency)
+			"(ip link show eth1 2>/dev/null"+
+				" && tc qdisc replace dev eth1 root netem delay %v) || true",
+			latency)
 		cmd := []string{"sh", "-c", tcCmd}
 		if _, err := n.container.ExecWithOptions(ctx, cmd,
 			docker.ExecOptions{LogName: "tc-setup"}); err != nil {
@@ -148,6 +174,52 @@ func (n *OperaNode) Initialize(ctx context.Context) (err error) {
 	return n.transition(NodeStateInitializing, NodeStateReady)
 }
 
+// execOptions returns the options of an exec run for the node's client
+// under the given log name: the environment placing the client's files, and
+// the log stream of the client.
+func (n *OperaNode) execOptions(logName string) docker.ExecOptions {
+	opts := docker.ExecOptions{LogName: logName, Stream: n.logStream()}
+	if n.pack != nil {
+		// The container's environment names the default data directory.
+		opts.Env = []string{"STATE_DB_DATADIR=" + n.clientLayout().dataDir}
+	}
+	return opts
+}
+
+// writeValidatorKeystore writes the keystore of a validator node into its
+// data directory. Nodes that are not validators have no keystore.
+func (n *OperaNode) writeValidatorKeystore(ctx context.Context) error {
+	if n.config.ValidatorId == nil || *n.config.ValidatorId <= 0 {
+		return nil
+	}
+	dir, err := os.MkdirTemp("", fmt.Sprintf("norma-validator-%d-*", *n.config.ValidatorId))
+	if err != nil {
+		return fmt.Errorf("failed to create validator temp dir: %w", err)
+	}
+	defer func() { _ = os.RemoveAll(dir) }()
+	if err := genesis.WriteValidatorKeystore(n.config.PrivKey, dir); err != nil {
+		return fmt.Errorf("failed to write validator keystore: %w", err)
+	}
+	return fs.WalkDir(os.DirFS(dir), ".", func(path string, entry fs.DirEntry, err error) error {
+		if err != nil || entry.IsDir() {
+			return err
+		}
+		content, err := os.ReadFile(filepath.Join(dir, path)) //#nosec G304 -- path is constructed internally
+		if err != nil {
+			return fmt.Errorf("failed to read validator keystore: %w", err)
+		}
+		target := n.clientLayout().dataDir + "/" + path
+		if output, err := n.container.Exec(ctx,
+			[]string{"mkdir", "-p", filepath.Dir(target)}); err != nil {
+			return fmt.Errorf("failed to create keystore dir: %w - output: %s", err, output)
+		}
+		if err := n.writeContainerFile(ctx, target, string(content)); err != nil {
+			return fmt.Errorf("failed to write validator keystore: %w", err)
+		}
+		return nil
+	})
+}
+
 // writeContainerFile writes content to the given absolute path inside the
 // container. The content is passed via an environment variable rather than
 // interpolated into the shell command, so quotes and newlines in it cannot
@@ -174,7 +246,7 @@ func (n *OperaNode) writeContainerFile(
 // for every client start that is not bootstrapping a network on its own.
 const doublesignProtection = 5 * time.Second
 
-// clientConfigToml renders the client configuration for a client start, where
+// ClientConfigToml renders the client configuration for a client start, where
 // bootstrap reports whether that start brings up a brand-new network.
 //
 // A lone genesis validator bootstrapping a network must emit without
@@ -184,7 +256,7 @@ const doublesignProtection = 5 * time.Second
 // validator rejoining a network that is already running, where emitting
 // before its own earlier events have been replayed forks the validator
 // against itself and stops it for good.
-func clientConfigToml(config *OperaNodeConfig, bootstrap bool) string {
+func ClientConfigToml(config *OperaNodeConfig, bootstrap bool) string {
 	protection := doublesignProtection
 	if bootstrap && isLoneGenesisValidator(config) {
 		protection = 0
@@ -211,7 +283,7 @@ func (n *OperaNode) bootstrapsNetwork() bool {
 // It requires the node to be in NodeStateReady and transitions it to
 // NodeStateSyncing. Returns an error if sonicd fails to start.
 func (n *OperaNode) StartSonicd(ctx context.Context) error {
-	return n.startSonicd(ctx, false)
+	return n.startSonicd(ctx, false, false)
 }
 
 // StartSonicdAsObserver starts sonicd without validator flags, so the node
@@ -219,10 +291,19 @@ func (n *OperaNode) StartSonicd(ctx context.Context) error {
 // this way stays a non-validating observer until it is restarted with
 // StartSonicd.
 func (n *OperaNode) StartSonicdAsObserver(ctx context.Context) error {
-	return n.startSonicd(ctx, true)
+	return n.startSonicd(ctx, true, false)
 }
 
-func (n *OperaNode) startSonicd(ctx context.Context, observer bool) error {
+// StartSonicdBootstrapping starts sonicd like StartSonicd, for a network
+// that is restarted as a whole after all of its clients were stopped
+// cleanly. As when the network was first started, there is no running node
+// to sync with, and the databases hold every event the validators emitted,
+// so the client is configured as for bootstrapping the network.
+func (n *OperaNode) StartSonicdBootstrapping(ctx context.Context) error {
+	return n.startSonicd(ctx, false, true)
+}
+
+func (n *OperaNode) startSonicd(ctx context.Context, observer, bootstrap bool) error {
 	gen, err := n.beginClientStart()
 	if err != nil {
 		return err
@@ -235,10 +316,17 @@ func (n *OperaNode) startSonicd(ctx context.Context, observer bool) error {
 		return err
 	}
 
-	configToml := clientConfigToml(n.config, n.bootstrapsNetwork())
-	if err := n.writeContainerFile(ctx, configFilePath, configToml); err != nil {
+	layout := n.clientLayout()
+	configToml := ClientConfigToml(n.config, bootstrap || n.bootstrapsNetwork())
+	if err := n.writeContainerFile(ctx, layout.configFile(), configToml); err != nil {
 		n.forceSetState(NodeStateReady)
-		return fmt.Errorf("failed to write %s: %w", configFilePath, err)
+		return fmt.Errorf("failed to write %s: %w", layout.configFile(), err)
+	}
+
+	clockEnv, err := n.clockEnv(ctx)
+	if err != nil {
+		n.forceSetState(NodeStateReady)
+		return err
 	}
 
 	validatorId := n.config.ValidatorId
@@ -249,11 +337,19 @@ func (n *OperaNode) startSonicd(ctx context.Context, observer bool) error {
 		validatorId,
 		n.config.PubKey,
 		n.container.IP(),
-		n.config.ExtraArguments)
+		n.config.NetworkConfig == nil || n.config.NetworkConfig.Topology.IsFullMesh(),
+		n.config.ExtraArguments,
+		layout,
+		n.config.Memory)
+
+	opts := n.execOptions("sonicd")
+	opts.Env = append(opts.Env, clockEnv...)
+	if n.config.Memory.GoMemLimit != 0 {
+		opts.Env = append(opts.Env, fmt.Sprintf("GOMEMLIMIT=%d", n.config.Memory.GoMemLimit))
+	}
 
 	slog.Info("Starting sonicd", "node", n.config.Label, "observer", observer)
-	handle, err := n.container.ExecBackground(ctx, sonicdCmd,
-		docker.ExecOptions{LogName: "sonicd"})
+	handle, err := n.container.ExecBackground(ctx, sonicdCmd, opts)
 	if err != nil {
 		// Roll back so the caller may retry from Ready.
 		n.forceSetState(NodeStateReady)
@@ -454,11 +550,11 @@ func (n *OperaNode) HealSonicd(ctx context.Context) error {
 
 	healCmd := []string{
 		sonicToolBinaryPath,
-		"--datadir", dataDir,
+		"--datadir", n.clientLayout().dataDir,
 		"heal",
 	}
 	output, err := n.container.ExecWithOptions(ctx, healCmd,
-		docker.ExecOptions{LogName: "sonictool-heal"})
+		n.execOptions("sonictool-heal"))
 	if err != nil {
 		n.forceSetState(NodeStateKilled)
 		return fmt.Errorf("sonictool heal failed: %w - output: %s", err, output)
@@ -467,9 +563,64 @@ func (n *OperaNode) HealSonicd(ctx context.Context) error {
 	return n.transition(NodeStateHealing, NodeStateReady)
 }
 
+// PauseSonicd freezes the node's container, suspending sonicd without
+// terminating it. Requires NodeStateRunning and transitions to
+// NodeStatePaused. Unlike ForceStopSonicd, the client keeps its in-memory
+// state and resumes from it in ResumeSonicd, as a hung validator would.
+// If the container cannot be frozen the node returns to NodeStateRunning.
+func (n *OperaNode) PauseSonicd(ctx context.Context) error {
+	if err := n.transition(NodeStateRunning, NodeStatePaused); err != nil {
+		return err
+	}
+	slog.Info("Pausing sonicd", "node", n.config.Label)
+
+	if err := n.pauseContainer(ctx); err != nil {
+		n.forceSetState(NodeStateRunning)
+		return fmt.Errorf("node %q: %w", n.GetLabel(), err)
+	}
+	return nil
+}
+
+// ResumeSonicd thaws a container frozen by PauseSonicd. Requires
+// NodeStatePaused and transitions to NodeStateRunning. A failed resume
+// leaves the node in NodeStatePaused so it can be retried.
+func (n *OperaNode) ResumeSonicd(ctx context.Context) error {
+	if err := n.transition(NodeStatePaused, NodeStateRunning); err != nil {
+		return err
+	}
+	slog.Info("Resuming sonicd", "node", n.config.Label)
+
+	if err := n.unpauseContainer(ctx); err != nil {
+		n.forceSetState(NodeStatePaused)
+		return fmt.Errorf("node %q: %w", n.GetLabel(), err)
+	}
+	return nil
+}
+
+// pauseContainer freezes the node's container. The cgroup freezer is used
+// rather than SIGSTOP because sonicd is not the container's main process
+// and a signal sent to the container would not reach it.
+func (n *OperaNode) pauseContainer(ctx context.Context) error {
+	if err := n.requireOwnContainer("pause"); err != nil {
+		return err
+	}
+	if n.container == nil {
+		return fmt.Errorf("node %q has no container", n.GetLabel())
+	}
+	return n.container.Pause(ctx)
+}
+
+// unpauseContainer thaws the node's container.
+func (n *OperaNode) unpauseContainer(ctx context.Context) error {
+	if n.container == nil {
+		return fmt.Errorf("node %q has no container", n.GetLabel())
+	}
+	return n.container.Unpause(ctx)
+}
+
 // signalSonicd sends the given POSIX signal (name without the "SIG"
-// prefix, e.g. "INT" or "KILL") to every sonicd process running inside the
-// container, and returns how many processes were signalled. A count of
+// prefix, e.g. "INT" or "KILL") to every sonicd process of the node running
+// inside the container, and returns how many processes were signalled. A count of
 // zero means no client was running, which the caller has to distinguish
 // from a successful stop.
 func (n *OperaNode) signalSonicd(ctx context.Context, sig string) (int, error) {
@@ -484,8 +635,8 @@ func (n *OperaNode) signalSonicd(ctx context.Context, sig string) (int, error) {
 	return count, nil
 }
 
-// countRunningClients reports how many client processes are alive inside
-// the container, as observed from /proc rather than from recorded state.
+// countRunningClients reports how many client processes of the node are
+// alive inside the container, as observed from /proc rather than from recorded state.
 func (n *OperaNode) countRunningClients(ctx context.Context) (int, error) {
 	if n.container == nil {
 		return 0, fmt.Errorf("node %q has no container", n.GetLabel())
@@ -509,10 +660,16 @@ func (n *OperaNode) requireNoClientRunning(ctx context.Context, purpose string)
 }
 
 // scanClientProcesses runs the /proc scan with the given per-match action
-// and returns the reported count.
+// and returns the reported count. In a shared container, only the clients
+// running on the node's data directory are scanned.
 func (n *OperaNode) scanClientProcesses(ctx context.Context, action string) (int, error) {
 	script := fmt.Sprintf(clientScanScript, action)
-	output, err := n.container.Exec(ctx, []string{"sh", "-c", script})
+	var env []string
+	if n.pack != nil {
+		env = []string{"NORMA_DATADIR=" + n.clientLayout().dataDir}
+	}
+	output, err := n.container.ExecWithOptions(ctx, []string{"sh", "-c", script},
+		docker.ExecOptions{Env: env})
 	if err != nil {
 		return 0, fmt.Errorf("%w - output: %s", err, output)
 	}
@@ -557,6 +714,33 @@ func (n *OperaNode) waitForSonicdExit(ctx context.Context) error {
 	}
 }
 
+// CopyDataDir copies the node's data directory into the given directory on
+// the host, which must not hold any of its files yet. The data directory
+// has to be kept on the host, see OperaNodeConfig.MountDataDir, and the
+// client has to be stopped, so that its database is closed. Requires
+// NodeStateReady.
+func (n *OperaNode) CopyDataDir(ctx context.Context, target string) error {
+	if n.config.MountDataDir == nil {
+		return fmt.Errorf("node %q keeps no data directory on the host", n.GetLabel())
+	}
+	if s := n.GetState(); s != NodeStateReady {
+		return fmt.Errorf(
+			"node %q: copying the data directory requires state %s, got %s",
+			n.GetLabel(), NodeStateReady, s)
+	}
+	// The client runs as root, so the files it created may not be readable
+	// by the user running Norma.
+	cmd := []string{"chmod", "-R", "a+rX", dataDir}
+	if output, err := n.container.Exec(ctx, cmd); err != nil {
+		return fmt.Errorf("node %q: failed to make the data directory readable: %w - output: %s",
+			n.GetLabel(), err, output)
+	}
+	if err := os.CopyFS(target, os.DirFS(*n.config.MountDataDir)); err != nil {
+		return fmt.Errorf("node %q: failed to copy the data directory: %w", n.GetLabel(), err)
+	}
+	return nil
+}
+
 // isDirEmpty reports whether the directory at path contains no
 // entries (ignoring the "keystore" directory written before init).
 func isDirEmpty(path string) bool {
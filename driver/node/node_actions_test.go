@@ -62,6 +62,12 @@ func TestOperaNode_Actions_RejectUnexpectedStates(t *testing.T) {
 				return n.StartSonicdAsObserver(t.Context())
 			},
 		},
+		"StartSonicdBootstrapping": {
+			accepts: []NodeState{NodeStateReady},
+			invoke: func(n *OperaNode) error {
+				return n.StartSonicdBootstrapping(t.Context())
+			},
+		},
 		"WaitForSync": {
 			accepts: []NodeState{NodeStateSyncing},
 			invoke:  func(n *OperaNode) error { return n.WaitForSync(t.Context()) },
@@ -80,6 +86,22 @@ func TestOperaNode_Actions_RejectUnexpectedStates(t *testing.T) {
 			accepts: []NodeState{NodeStateKilled},
 			invoke:  func(n *OperaNode) error { return n.HealSonicd(t.Context()) },
 		},
+		"PauseSonicd": {
+			accepts: []NodeState{NodeStateRunning},
+			invoke:  func(n *OperaNode) error { return n.PauseSonicd(t.Context()) },
+		},
+		"ResumeSonicd": {
+			accepts: []NodeState{NodeStatePaused},
+			invoke:  func(n *OperaNode) error { return n.ResumeSonicd(t.Context()) },
+		},
+		"CopyDataDir": {
+			accepts: []NodeState{NodeStateReady},
+			invoke: func(n *OperaNode) error {
+				dir := t.TempDir()
+				n.config.MountDataDir = &dir
+				return n.CopyDataDir(t.Context(), t.TempDir())
+			},
+		},
 	}
 
 	for name, action := range actions {
@@ -101,6 +123,15 @@ func TestOperaNode_Actions_RejectUnexpectedStates(t *testing.T) {
 	}
 }
 
+func TestOperaNode_CopyDataDir_FailsWithoutDataDirOnTheHost(t *testing.T) {
+	node := newNodeInState(t, NodeStateReady)
+
+	err := node.CopyDataDir(t.Context(), t.TempDir())
+	if err == nil || !strings.Contains(err.Error(), "no data directory on the host") {
+		t.Errorf("unexpected error: %v", err)
+	}
+}
+
 // A graceful stop that never completes must not be a dead end: escalating
 // to a kill is the documented way out of Stopping.
 func TestOperaNode_ForceStopSonicd_EscalatesFromStopping(t *testing.T) {
@@ -155,6 +186,28 @@ func TestOperaNode_ForceStopSonicd_StaysKilledOnFailure(t *testing.T) {
 	}
 }
 
+// A node that could not be frozen is still running, and one that could not
+// be thawed is still frozen; either action has to be retryable.
+func TestOperaNode_PauseAndResume_KeepStateOnFailure(t *testing.T) {
+	node := newNodeInState(t, NodeStateRunning)
+	if err := node.PauseSonicd(t.Context()); err == nil {
+		t.Fatalf("expected PauseSonicd to fail without a container")
+	}
+	if got := node.GetState(); got != NodeStateRunning {
+		t.Errorf("unexpected state after failed pause, got %s, want %s",
+			got, NodeStateRunning)
+	}
+
+	node = newNodeInState(t, NodeStatePaused)
+	if err := node.ResumeSonicd(t.Context()); err == nil {
+		t.Fatalf("expected ResumeSonicd to fail without a container")
+	}
+	if got := node.GetState(); got != NodeStatePaused {
+		t.Errorf("unexpected state after failed resume, got %s, want %s",
+			got, NodeStatePaused)
+	}
+}
+
 // The point of the exit watcher: a client that dies on its own must move
 // the node out of a state that claims it is up.
 func TestOperaNode_RecordUnexpectedClientExit_MarksNodeKilled(t *testing.T) {
@@ -446,7 +499,7 @@ func TestClientConfigToml_DropsDoublesignProtectionOnlyWhenBootstrappingAlone(t
 				},
 			}
 
-			got := clientConfigToml(config, test.bootstrap)
+			got := ClientConfigToml(config, test.bootstrap)
 
 			want := fmt.Sprintf("DoublesignProtection = %d\n",
 				doublesignProtection.Nanoseconds())
@@ -489,7 +542,7 @@ func TestOperaNode_BootstrapsNetwork_FalseForNodesJoiningARunningNetwork(t *test
 }
 
 func TestBuildSonicdCmd_UsesExecFormWithoutShell(t *testing.T) {
-	cmd := buildSonicdCmd(nil, "", "1.2.3.4", "")
+	cmd := buildSonicdCmd(nil, "", "1.2.3.4", true, "", defaultLayout, driver.ClientMemory{})
 
 	if len(cmd) == 0 {
 		t.Fatalf("command must not be empty")
@@ -509,7 +562,7 @@ func TestBuildSonicdCmd_UsesExecFormWithoutShell(t *testing.T) {
 
 func TestBuildSonicdCmd_UsesAbsolutePathsForGeneratedFiles(t *testing.T) {
 	validatorId := 3
-	cmd := buildSonicdCmd(&validatorId, "pubkey", "1.2.3.4", "")
+	cmd := buildSonicdCmd(&validatorId, "pubkey", "1.2.3.4", true, "", defaultLayout, driver.ClientMemory{})
 
 	for _, want := range []string{configFilePath, passwordFilePath} {
 		if !slices.Contains(cmd, want) {
@@ -534,7 +587,7 @@ func TestBuildSonicdCmd_AddsValidatorFlagsOnlyForValidators(t *testing.T) {
 
 	for name, test := range tests {
 		t.Run(name, func(t *testing.T) {
-			cmd := buildSonicdCmd(test.id, "pubkey", "1.2.3.4", "")
+			cmd := buildSonicdCmd(test.id, "pubkey", "1.2.3.4", true, "", defaultLayout, driver.ClientMemory{})
 			got := slices.Contains(cmd, "--validator.id")
 			if got != test.wantValidator {
 				t.Errorf("--validator.id present = %t, want %t in %v",
@@ -545,7 +598,7 @@ func TestBuildSonicdCmd_AddsValidatorFlagsOnlyForValidators(t *testing.T) {
 }
 
 func TestBuildSonicdCmd_AppendsExtraArgumentsAsSeparateEntries(t *testing.T) {
-	cmd := buildSonicdCmd(nil, "", "1.2.3.4", "--statedb.checkpointinterval 1")
+	cmd := buildSonicdCmd(nil, "", "1.2.3.4", true, "--statedb.checkpointinterval 1", defaultLayout, driver.ClientMemory{})
 
 	i := slices.Index(cmd, "--statedb.checkpointinterval")
 	if i < 0 {
@@ -556,6 +609,16 @@ func TestBuildSonicdCmd_AppendsExtraArgumentsAsSeparateEntries(t *testing.T) {
 	}
 }
 
+func TestBuildSonicdCmd_DisablesDiscoveryOnRequest(t *testing.T) {
+	for _, discovery := range []bool{true, false} {
+		cmd := buildSonicdCmd(nil, "", "1.2.3.4", discovery, "", defaultLayout, driver.ClientMemory{})
+		if got := slices.Contains(cmd, "--nodiscover"); got == discovery {
+			t.Errorf("discovery %t: --nodiscover present = %t in %v",
+				discovery, got, cmd)
+		}
+	}
+}
+
 func TestIsDirEmpty(t *testing.T) {
 	t.Run("true for empty directory", func(t *testing.T) {
 		if !isDirEmpty(t.TempDir()) {
@@ -29,6 +29,8 @@ package node
 //	Running       --ForceStopSonicd--> Killed
 //	Stopping      --ForceStopSonicd--> Killed
 //	Killed        --HealSonicd-------> Healing      --> Ready
+//	Running       --PauseSonicd------> Paused
+//	Paused        --ResumeSonicd-----> Running
 //
 // Failure returns the node to the state the action started from, except
 // for ForceStopSonicd: once a kill has been attempted the database must be
@@ -76,6 +78,10 @@ const (
 	// NodeStateHealing is the transitional state entered while
 	// sonictool heal is running against a killed node.
 	NodeStateHealing
+	// NodeStatePaused means the container, and with it sonicd, is frozen.
+	// The client keeps its in-memory state and picks up where it stopped
+	// once resumed; until then the container accepts no commands.
+	NodeStatePaused
 )
 
 // String returns a human-readable name for the state, used in error
@@ -98,6 +104,8 @@ func (s NodeState) String() string {
 		return "killed"
 	case NodeStateHealing:
 		return "healing"
+	case NodeStatePaused:
+		return "paused"
 	default:
 		return "unknown"
 	}
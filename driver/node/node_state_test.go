@@ -32,6 +32,7 @@ var allNodeStates = []NodeState{
 	NodeStateStopping,
 	NodeStateKilled,
 	NodeStateHealing,
+	NodeStatePaused,
 }
 
 func TestNodeState_String_IsUniqueAndDefinedForAllStates(t *testing.T) {
@@ -152,6 +153,8 @@ func TestOperaNode_Transition_FollowsDocumentedLifecycle(t *testing.T) {
 		{NodeStateInitializing, NodeStateReady},
 		{NodeStateReady, NodeStateSyncing},
 		{NodeStateSyncing, NodeStateRunning},
+		{NodeStateRunning, NodeStatePaused},
+		{NodeStatePaused, NodeStateRunning},
 		{NodeStateRunning, NodeStateStopping},
 		{NodeStateStopping, NodeStateReady},
 		{NodeStateReady, NodeStateSyncing},
@@ -28,6 +28,7 @@ import (
 	"path/filepath"
 	"regexp"
 	"slices"
+	"strconv"
 	"strings"
 	"sync"
 	"time"
@@ -88,6 +89,14 @@ type OperaNode struct {
 	config    *OperaNodeConfig
 	tempDirs  []string
 
+	// pack is the pack whose container the client shares, with slot being
+	// the client's slot in it; nil if the node has a container of its own.
+	pack *Pack
+	slot int
+	// layout places the client's files and ports inside the container; nil
+	// selects defaultLayout.
+	layout *clientLayout
+
 	// The fields below are all guarded by stateMutex.
 	stateMutex sync.Mutex
 	state      NodeState
@@ -100,6 +109,17 @@ type OperaNode struct {
 	// clientExitWasUnexpected records that the node reached
 	// NodeStateKilled because its client died rather than was killed.
 	clientExitWasUnexpected bool
+
+	// The fields below describe the traffic shaping installed on the
+	// node's network interface. They are guarded by shapingMutex.
+	shapingMutex sync.Mutex
+	links        []PeerLink
+	throttle     *driver.ThrottleConfig
+
+	// clockOffset is the offset of the client's clock from the host's
+	// clock, guarded by clockMutex.
+	clockMutex  sync.Mutex
+	clockOffset time.Duration
 }
 
 type OperaNodeConfig struct {
@@ -122,6 +142,12 @@ type OperaNodeConfig struct {
 	GenesisJsonPath *string
 	// ExtraArguments are additional command line arguments to pass to the node.
 	ExtraArguments string
+	// ClockOffset is the initial offset of the client's clock from the
+	// host's clock. Nil leaves the clock untouched and cannot be changed
+	// later; see SetClockOffset.
+	ClockOffset *time.Duration
+	// Resources limits the host resources the node may use.
+	Resources driver.Resources
 	// NetworkBootstrap is true if this node starts a brand-new network, i.e.
 	// there is no running node it could sync with.
 	NetworkBootstrap bool
@@ -135,6 +161,12 @@ type OperaNodeConfig struct {
 	// empty, a temporary directory is used that is removed on cleanup, so
 	// callers that want the logs to outlive the run must set this.
 	LogsDir string
+	// Pack, if set, is the pack whose container the client shares with the
+	// clients of other nodes instead of getting a container of its own.
+	// MountDataDir and Resources are not supported for packed nodes.
+	Pack *Pack
+	// Memory tunes the memory the client uses.
+	Memory driver.ClientMemory
 }
 
 // imageEnsureState stores the completion signal and final error for one
@@ -203,14 +235,17 @@ func StartOperaDockerNode(
 		return nil, fmt.Errorf("docker network is required to start an Opera node")
 	}
 
-	exists, err := client.ContainerExists(config.Label)
-	if err != nil {
-		return nil, fmt.Errorf("failed to start docker node: %w", err)
-	}
-	if exists {
-		return nil, fmt.Errorf("failed to start docker node: container %q already running", config.Label)
+	if config.Pack == nil {
+		exists, err := client.ContainerExists(config.Label)
+		if err != nil {
+			return nil, fmt.Errorf("failed to start docker node: %w", err)
+		}
+		if exists {
+			return nil, fmt.Errorf("failed to start docker node: container %q already running", config.Label)
+		}
 	}
 
+	var err error
 	if config.ValidatorId != nil && *config.ValidatorId > 0 {
 		var privKey, pubKey, address string
 		if privKey, pubKey, address, err = genesis.DeriveValidatorKey(*config.ValidatorId); err != nil {
@@ -262,7 +297,7 @@ func StartOperaDockerNode(
 
 // connectivityCheck attempts to connect to the Opera RPC service of the given host.
 func connectivityCheck(ctx context.Context, node *OperaNode) error {
-	addr, err := node.container.GetAddressForService(&OperaRpcService)
+	addr, err := node.serviceAddress(&OperaRpcService)
 	if err != nil {
 		return fmt.Errorf("failed to get RPC service address: %w", err)
 	}
@@ -327,6 +362,9 @@ func NewOperaNode(
 	dn *docker.Network,
 	config *OperaNodeConfig,
 ) (*OperaNode, error) {
+	if config.Pack != nil {
+		return newPackedOperaNode(ctx, config)
+	}
 	image := driver.ResolveClientImageName(config.Image)
 	if err := ensureImageAvailable(ctx, image); err != nil {
 		return nil, fmt.Errorf("failed to ensure image %q: %w", image, err)
@@ -407,13 +445,46 @@ func NewOperaNode(
 			GenesisFileBind: &genesisBind,
 			KeystoreBinding: keystoreBinding,
 			LogsDir:         &logsDir,
+			Resources:       dockerResources(config.Resources),
 		})
 	if err != nil {
 		cleanupTempDirs()
 		return nil, err
 	}
 
-	// Use a private copy of the config to avoid modifying the original.
+	return newOperaNode(container, config, image, genesisJSONPath, tempDirs), nil
+}
+
+// newPackedOperaNode adds an Opera node to the pack of its configuration,
+// returning it in NodeStateUninitialized.
+func newPackedOperaNode(ctx context.Context, config *OperaNodeConfig) (*OperaNode, error) {
+	if config.MountDataDir != nil {
+		return nil, fmt.Errorf("packed node %q cannot keep its data directory on the host", config.Label)
+	}
+	if config.Resources != (driver.Resources{}) {
+		return nil, fmt.Errorf("packed node %q cannot have resource limits", config.Label)
+	}
+	container, slot, err := config.Pack.join(ctx, config)
+	if err != nil {
+		return nil, fmt.Errorf("failed to join container: %w", err)
+	}
+	image := driver.ResolveClientImageName(config.Image)
+	node := newOperaNode(container, config, image, "", nil)
+	layout := packedLayout(slot)
+	node.pack = config.Pack
+	node.slot = slot
+	node.layout = &layout
+	return node, nil
+}
+
+// newOperaNode creates an OperaNode in NodeStateUninitialized, keeping a
+// private copy of the given config to avoid modifying the original.
+func newOperaNode(
+	container *docker.Container,
+	config *OperaNodeConfig,
+	image, genesisJSONPath string,
+	tempDirs []string,
+) *OperaNode {
 	nodeConfig := *config
 	nodeConfig.Image = image
 	nodeConfig.GenesisJsonPath = nil
@@ -425,13 +496,36 @@ func NewOperaNode(
 		nodeConfig.ValidatorId = new(int)
 		*nodeConfig.ValidatorId = *config.ValidatorId
 	}
+	var clockOffset time.Duration
+	if config.ClockOffset != nil {
+		clockOffset = *config.ClockOffset
+		nodeConfig.ClockOffset = &clockOffset
+	}
 
 	return &OperaNode{
-		container: container,
-		config:    &nodeConfig,
-		tempDirs:  tempDirs,
-		state:     NodeStateUninitialized,
-	}, nil
+		container:   container,
+		config:      &nodeConfig,
+		tempDirs:    tempDirs,
+		state:       NodeStateUninitialized,
+		clockOffset: clockOffset,
+	}
+}
+
+// clientLayout returns the layout of the node's client in its container.
+func (n *OperaNode) clientLayout() clientLayout {
+	if n.layout == nil {
+		return defaultLayout
+	}
+	return *n.layout
+}
+
+// logStream returns the log stream of the node's client, which is the
+// container's default one unless the container is shared.
+func (n *OperaNode) logStream() string {
+	if n.pack == nil {
+		return ""
+	}
+	return n.config.Label
 }
 
 // resolveGenesisFile returns the host path of the genesis file to mount.
@@ -475,7 +569,8 @@ func resolveGenesisFile(
 	if err := genesis.GenerateJsonGenesis(path,
 		driver.GetValidatorStakes(config.NetworkConfig.Validators),
 		&rules,
-		clientVersions); err != nil {
+		clientVersions,
+		config.NetworkConfig.InitialState); err != nil {
 		return "", tempDir, fmt.Errorf(
 			"failed to generate temporary genesis: %w", err)
 	}
@@ -573,10 +668,13 @@ func (n *OperaNode) Hostname() string {
 // MetricsPort returns the port on which the node exports its metrics.
 // The port is accessible only inside the Docker network.
 func (n *OperaNode) MetricsPort() int {
-	return 6060
+	return n.clientLayout().port(&OperaDebugService)
 }
 
 func (n *OperaNode) IsRunning() bool {
+	if n.pack != nil && n.slot < 0 {
+		return false
+	}
 	return n.container.IsRunning()
 }
 
@@ -587,7 +685,7 @@ func (n *OperaNode) CheckRunning(ctx context.Context) error {
 }
 
 func (n *OperaNode) GetServiceUrl(service *network.ServiceDescription) (*driver.URL, error) {
-	addr, err := n.container.GetAddressForService(service)
+	addr, err := n.serviceAddress(service)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get service address for %s: %w", service.Name, err)
 	}
@@ -595,6 +693,15 @@ func (n *OperaNode) GetServiceUrl(service *network.ServiceDescription) (*driver.
 	return &url, nil
 }
 
+// serviceAddress returns the address the node's client offers the given
+// service on, taking the ports of its slot in a shared container into
+// account.
+func (n *OperaNode) serviceAddress(service *network.ServiceDescription) (*network.AddressPort, error) {
+	shifted := *service
+	shifted.Port = network.Port(n.clientLayout().port(service))
+	return n.container.GetAddressForService(&shifted)
+}
+
 func (n *OperaNode) GetNodeID() (driver.NodeID, error) {
 	url, err := n.GetServiceUrl(&OperaRpcService)
 	if err != nil {
@@ -622,7 +729,7 @@ func (n *OperaNode) GetValidatorId() *int {
 }
 
 func (n *OperaNode) StreamLog(ctx context.Context) (io.ReadCloser, error) {
-	return n.container.StreamLog(ctx)
+	return n.container.StreamLogOf(ctx, n.logStream())
 }
 
 // StreamExecLog opens the client's log file for a one-shot read of the
@@ -660,12 +767,21 @@ func (n *OperaNode) ExecDone() <-chan struct{} {
 }
 
 // Stop shuts the node down: the client process first, then the container.
+// The container of a packed node is shared and only stopped once all of its
+// nodes are cleaned up.
 //
 // The client is stopped on a best-effort basis. A node that was killed,
 // never started, or already stopped is not an error here: Stop is also the
 // teardown path, and refusing to release the container because the client
 // was already gone would leak it and fail otherwise-successful scenarios.
 func (n *OperaNode) Stop(ctx context.Context) error {
+	// A frozen client cannot handle the shutdown request, so thaw it first.
+	if n.GetState() == NodeStatePaused {
+		if err := n.ResumeSonicd(ctx); err != nil {
+			slog.Debug("paused client process was not resumed",
+				"node", n.GetLabel(), "error", err)
+		}
+	}
 	if err := n.StopSonicd(ctx); err != nil {
 		slog.Debug("client process was not stopped gracefully",
 			"node", n.GetLabel(), "error", err)
@@ -681,7 +797,7 @@ func (n *OperaNode) Stop(ctx context.Context) error {
 		}
 	}
 
-	if n.container == nil {
+	if n.container == nil || n.pack != nil {
 		return nil
 	}
 	return n.container.Stop(ctx)
@@ -689,7 +805,9 @@ func (n *OperaNode) Stop(ctx context.Context) error {
 
 func (n *OperaNode) Cleanup(ctx context.Context) error {
 	var err error
-	if n.container != nil {
+	if n.pack != nil {
+		err = n.leavePack(ctx)
+	} else if n.container != nil {
 		err = n.container.Cleanup(ctx)
 	}
 	for _, dir := range n.tempDirs {
@@ -734,7 +852,8 @@ func (n *OperaNode) AddPeer(ctx context.Context, id driver.NodeID) error {
 }
 
 // RemovePeer informs the client instance represented by the OperaNode
-// that the input node is no more available in the network.
+// that the input node is no more available in the network. The node is
+// no longer trusted either, so it is not let back in when it dials.
 func (n *OperaNode) RemovePeer(ctx context.Context, id driver.NodeID) error {
 	rpcClient, err := n.DialRpc(ctx)
 	if err != nil {
@@ -742,12 +861,20 @@ func (n *OperaNode) RemovePeer(ctx context.Context, id driver.NodeID) error {
 	}
 	return network.Retry(ctx, network.DefaultRetryAttempts, 1*time.Second,
 		func(ctx context.Context) error {
+			if err := rpcClient.Call(nil, "admin_removeTrustedPeer", id); err != nil {
+				return fmt.Errorf("failed to remove trusted peer on node %s: %v", id, err)
+			}
 			return rpcClient.Call(nil, "admin_removePeer", id)
 		})
 }
 
-// Kill sends a SigKill signal to node.
+// Kill sends a SigKill signal to node. Only the client of a packed node is
+// killed, as the other clients of its container have to survive.
 func (n *OperaNode) Kill(ctx context.Context) error {
+	if n.pack != nil {
+		_, err := n.signalSonicd(ctx, "KILL")
+		return err
+	}
 	return n.container.SendSignal(ctx, docker.SigKill)
 }
 
@@ -855,6 +982,10 @@ func (n *OperaNode) forceSetState(s NodeState) {
 	n.state = s
 }
 
+// defaultLiveCache is the size, in bytes, of the state database's node cache
+// of a client whose memory settings do not give one.
+const defaultLiveCache = 256_000_000
+
 // buildSonicdCmd constructs the sonicd argument vector.
 //
 // The command is returned in exec form and is run without a shell: the
@@ -864,41 +995,65 @@ func (n *OperaNode) forceSetState(s NodeState) {
 func buildSonicdCmd(
 	validatorId *int,
 	pubKey, externalIP string,
+	discovery bool,
 	extraArguments string,
+	layout clientLayout,
+	memory driver.ClientMemory,
 ) []string {
+	liveCache := uint64(defaultLiveCache)
+	if memory.LiveCache != 0 {
+		liveCache = memory.LiveCache
+	}
 	args := []string{
 		sonicdBinaryPath,
-		"--datadir=" + dataDir,
+		"--datadir=" + layout.dataDir,
 		"--http", "--http.addr", "0.0.0.0",
-		"--http.port", "18545",
+		"--http.port", strconv.Itoa(layout.port(&OperaRpcService)),
 		"--http.api", "admin,dag,eth,sonic,txpool",
 		"--ws", "--ws.addr", "0.0.0.0",
-		"--ws.port", "18546",
+		"--ws.port", strconv.Itoa(layout.port(&OperaWsService)),
 		"--ws.api", "admin,dag,eth,sonic,txpool",
 		"--pprof", "--pprof.addr", "0.0.0.0",
 		"--nat", "extip:" + externalIP,
 		"--metrics", "--metrics.expensive",
-		"--config", configFilePath,
+		"--config", layout.configFile(),
 		"--datadir.minfreedisk", "0",
 		// The size of the state database's node cache, in bytes. Sonic divides it
 		// by the size of one node of the state trie, so a value below that size
 		// yields a cache of a single node: every access to the state then evicts
 		// the previous one, which makes every measurement a measurement of the
-		// disk and has been observed to crash the client. The budget below is
-		// per node of the network, so a scenario with many of them needs the
-		// memory of all of them.
-		"--statedb.livecache", "256000000",
+		// disk and has been observed to crash the client. The budget is per
+		// node of the network, so a scenario with many of them needs the
+		// memory of all of them unless it lowers it; see defaultLiveCache.
+		"--statedb.livecache", strconv.FormatUint(liveCache, 10),
+	}
+
+	// Clients sharing a container need ports of their own.
+	if layout.portOffset != 0 {
+		args = append(args,
+			"--pprof.port", strconv.Itoa(layout.port(&OperaDebugService)),
+			"--port", strconv.Itoa(p2pPort+layout.portOffset),
+		)
+	}
+	if memory.Cache != 0 {
+		args = append(args, "--cache", strconv.FormatUint(max(memory.Cache>>20, 1), 10))
 	}
 
 	if validatorId != nil && *validatorId > 0 {
 		args = append(args,
 			"--validator.id", fmt.Sprintf("%d", *validatorId),
 			"--validator.pubkey", pubKey,
-			"--validator.password", passwordFilePath,
+			"--validator.password", layout.passwordFile(),
 			"--mode", "rpc",
 		)
 	}
 
+	// Without discovery, the client is peered only as instructed through
+	// AddPeer, which is what a topology other than a full mesh relies on.
+	if !discovery {
+		args = append(args, "--nodiscover")
+	}
+
 	if extraArguments != "" {
 		args = append(args, strings.Fields(extraArguments)...)
 	}
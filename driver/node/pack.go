@@ -0,0 +1,294 @@
+// Copyright 2024 Fantom Foundation
+// This file is part of Norma System Testing Infrastructure for Sonic.
+//
+// Norma is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// Norma is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with Norma. If not, see <http://www.gnu.org/licenses/>.
+
+package node
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/0xsoniclabs/norma/driver"
+	"github.com/0xsoniclabs/norma/driver/docker"
+	"github.com/0xsoniclabs/norma/driver/network"
+)
+
+// Pack is a container shared by the clients of several OperaNodes, so that
+// networks of many light nodes fit on one host. Each client occupies a slot
+// of the container, which holds its files and selects its ports; see
+// clientLayout. The container is started by the first node joining the pack
+// and removed once the last of its nodes has been cleaned up.
+//
+// Traffic shaping, freezing and resource limits act on the whole container,
+// so they are not offered for the nodes of a pack.
+type Pack struct {
+	name   string
+	client *docker.Client
+	dn     *docker.Network
+
+	// The fields below are guarded by mutex.
+	mutex     sync.Mutex
+	container *docker.Container
+	image     string
+	tempDirs  []string
+	// slots records the slots taken by the nodes that joined and were not
+	// cleaned up yet.
+	slots    map[int]bool
+	shaped   bool
+	released bool
+}
+
+// NewPack creates a pack of the given name, which is also the hostname of its
+// container. The container is only started once the first node joins it.
+func NewPack(client *docker.Client, dn *docker.Network, name string) *Pack {
+	return &Pack{name: name, client: client, dn: dn}
+}
+
+// Name returns the name of the pack.
+func (p *Pack) Name() string {
+	return p.name
+}
+
+// IsReleased reports whether the container of the pack has been removed,
+// after which no more nodes can join it.
+func (p *Pack) IsReleased() bool {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+	return p.released
+}
+
+// join adds a node of the given configuration to the pack, starting its
+// container if it is the first, and returns the container and the slot of
+// the node's client, the lowest one not taken.
+func (p *Pack) join(ctx context.Context, config *OperaNodeConfig) (*docker.Container, int, error) {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+	if p.released {
+		return nil, 0, fmt.Errorf("container %q was already removed", p.name)
+	}
+	image := driver.ResolveClientImageName(config.Image)
+	if p.container == nil {
+		if err := p.start(ctx, image, config); err != nil {
+			return nil, 0, fmt.Errorf("failed to start container %q: %w", p.name, err)
+		}
+	} else if image != p.image {
+		return nil, 0, fmt.Errorf("container %q runs image %s, not %s", p.name, p.image, image)
+	}
+	if len(p.slots) >= MaxClientsPerContainer {
+		return nil, 0, fmt.Errorf("container %q hosts %d clients already", p.name, len(p.slots))
+	}
+	if p.slots == nil {
+		p.slots = map[int]bool{}
+	}
+	slot := 0
+	for p.slots[slot] {
+		slot++
+	}
+	p.slots[slot] = true
+	return p.container, slot, nil
+}
+
+// start starts the container of the pack, taking the genesis and the logs
+// directory from the configuration of the node joining first. The caller
+// must hold the mutex.
+func (p *Pack) start(ctx context.Context, image string, config *OperaNodeConfig) error {
+	if err := ensureImageAvailable(ctx, image); err != nil {
+		return fmt.Errorf("failed to ensure image %q: %w", image, err)
+	}
+	genesisJSONPath, genesisTempDir, err := resolveGenesisFile(ctx, image, config)
+	if genesisTempDir != "" {
+		p.tempDirs = append(p.tempDirs, genesisTempDir)
+	}
+	if err != nil {
+		return errors.Join(err, p.removeTempDirs())
+	}
+	logsDir, logsDirIsTemporary, err := resolveLogsDir(config)
+	if err != nil {
+		return errors.Join(err, p.removeTempDirs())
+	}
+	if logsDirIsTemporary {
+		p.tempDirs = append(p.tempDirs, logsDir)
+	}
+
+	genesisBind := fmt.Sprintf("%s:/genesis.json:ro", genesisJSONPath)
+	shutdownTimeout := containerShutdownTimeout
+	container, err := p.client.Start(ctx, &docker.ContainerConfig{
+		Hostname:        p.name,
+		ImageName:       image,
+		ShutdownTimeout: &shutdownTimeout,
+		Entrypoint:      containerEntrypoint,
+		Network:         p.dn,
+		GenesisFileBind: &genesisBind,
+		LogsDir:         &logsDir,
+	})
+	if err != nil {
+		return errors.Join(err, p.removeTempDirs())
+	}
+	p.container = container
+	p.image = image
+	return nil
+}
+
+// claimShaping reports whether the caller is the first node of the pack to
+// ask, which is the one to configure the traffic shaping of the container.
+func (p *Pack) claimShaping() bool {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+	claimed := !p.shaped
+	p.shaped = true
+	return claimed
+}
+
+// leave removes the node in the given slot from the pack, removing the
+// container along with the last one. The files of the node are removed, so
+// that the slot can be taken by another node.
+func (p *Pack) leave(ctx context.Context, slot int) error {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+	if !p.slots[slot] {
+		return nil
+	}
+	delete(p.slots, slot)
+	if len(p.slots) > 0 {
+		dir := packedLayout(slot).root()
+		if output, err := p.container.Exec(ctx, []string{"rm", "-rf", dir}); err != nil {
+			return fmt.Errorf("failed to remove %s: %w - output: %s", dir, err, output)
+		}
+		return nil
+	}
+	p.released = true
+	return errors.Join(p.container.Cleanup(ctx), p.removeTempDirs())
+}
+
+// requireOwnContainer fails if the node shares its container with other
+// clients, which the given action on the container would affect as well.
+func (n *OperaNode) requireOwnContainer(action string) error {
+	if n.pack != nil {
+		return fmt.Errorf("cannot %s node %q: it shares container %q with other clients",
+			action, n.GetLabel(), n.pack.Name())
+	}
+	return nil
+}
+
+// leavePack removes a packed node from its pack. Other clients may still run
+// in the container, so only the node's client and files are removed, the
+// container along with the last node. Leaving more than once has no effect.
+func (n *OperaNode) leavePack(ctx context.Context) error {
+	if n.slot < 0 {
+		return nil
+	}
+	if n.GetState() == NodeStateRunning {
+		if err := n.StopSonicd(ctx); err != nil {
+			slog.Debug("client process was not stopped gracefully",
+				"node", n.GetLabel(), "error", err)
+		}
+	}
+	// A client that is still starting up or stuck in shutdown would outlive
+	// the node otherwise.
+	if killed, err := n.signalSonicd(ctx, "KILL"); err != nil {
+		slog.Debug("client process was not killed",
+			"node", n.GetLabel(), "error", err)
+	} else if killed > 0 {
+		if err := n.waitForSonicdExit(ctx); err != nil {
+			slog.Debug("killed client process did not exit",
+				"node", n.GetLabel(), "error", err)
+		}
+	}
+	err := n.pack.leave(ctx, n.slot)
+	// The slot, and with it the files and ports of the node, may be taken by
+	// another node from now on.
+	n.slot = -1
+	return err
+}
+
+// removeTempDirs removes the host directories owned by the pack. The caller
+// must hold the mutex.
+func (p *Pack) removeTempDirs() error {
+	var err error
+	for _, dir := range p.tempDirs {
+		err = errors.Join(err, os.RemoveAll(dir))
+	}
+	p.tempDirs = nil
+	return err
+}
+
+// packRoot is the directory inside a pack's container holding the files of
+// its clients, one directory per slot.
+const packRoot = "/clients"
+
+// MaxClientsPerContainer is the number of clients a pack can host at most.
+// Their ports are spaced packPortStride apart, and the peer-to-peer ports of
+// more clients would reach the metrics port of the first one.
+const MaxClientsPerContainer = (int(6060) - p2pPort) / packPortStride
+
+// packPortStride is the distance between the ports of the clients in
+// neighbouring slots of a pack. It has to exceed the number of ports a
+// client listens on.
+const packPortStride = 10
+
+// clientLayout places the files and ports of a client inside its container.
+// A client with a container of its own uses defaultLayout; the clients of a
+// pack each get the layout of their slot.
+type clientLayout struct {
+	// dataDir is the client's data directory.
+	dataDir string
+	// clockOffsetFile holds the offset of the client's clock.
+	clockOffsetFile string
+	// portOffset is added to each port the client listens on.
+	portOffset int
+}
+
+// defaultLayout is the layout of a client with a container of its own.
+var defaultLayout = clientLayout{
+	dataDir:         dataDir,
+	clockOffsetFile: clockOffsetPath,
+}
+
+// packedLayout returns the layout of the client in the given slot of a pack.
+func packedLayout(slot int) clientLayout {
+	root := packRoot + "/" + strconv.Itoa(slot)
+	return clientLayout{
+		dataDir:         root + dataDir,
+		clockOffsetFile: root + clockOffsetPath,
+		portOffset:      slot * packPortStride,
+	}
+}
+
+// root returns the directory holding all files of the client, or the empty
+// string if the client has a container of its own.
+func (l clientLayout) root() string {
+	return strings.TrimSuffix(l.dataDir, dataDir)
+}
+
+// configFile returns the path of the client's configuration.
+func (l clientLayout) configFile() string {
+	return l.dataDir + "/config.toml"
+}
+
+// passwordFile returns the path of the validator keystore password.
+func (l clientLayout) passwordFile() string {
+	return l.dataDir + "/password.txt"
+}
+
+// port returns the port the client offers the given service on.
+func (l clientLayout) port(service *network.ServiceDescription) int {
+	return int(service.Port) + l.portOffset
+}
@@ -0,0 +1,151 @@
+// Copyright 2024 Fantom Foundation
+// This file is part of Norma System Testing Infrastructure for Sonic.
+//
+// Norma is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// Norma is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with Norma. If not, see <http://www.gnu.org/licenses/>.
+
+package node
+
+import (
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/0xsoniclabs/norma/driver"
+)
+
+func TestPackedLayout_GivesEverySlotDistinctFilesAndPorts(t *testing.T) {
+	dirs := map[string]bool{}
+	ports := map[int]int{}
+	for slot := range MaxClientsPerContainer {
+		layout := packedLayout(slot)
+		if dirs[layout.dataDir] {
+			t.Fatalf("slot %d reuses data directory %s", slot, layout.dataDir)
+		}
+		dirs[layout.dataDir] = true
+		if !strings.HasPrefix(layout.clockOffsetFile, layout.root()+"/") {
+			t.Errorf("slot %d keeps its clock offset outside of %s: %s",
+				slot, layout.root(), layout.clockOffsetFile)
+		}
+
+		for _, port := range []int{
+			layout.port(&OperaRpcService),
+			layout.port(&OperaWsService),
+			layout.port(&OperaDebugService),
+			p2pPort + layout.portOffset,
+		} {
+			if other, found := ports[port]; found {
+				t.Fatalf("slot %d reuses port %d of slot %d", slot, port, other)
+			}
+			ports[port] = slot
+		}
+	}
+	if defaultLayout.root() != "" {
+		t.Errorf("default layout has root %q", defaultLayout.root())
+	}
+}
+
+func TestBuildSonicdCmd_PlacesPackedClientsInTheirSlot(t *testing.T) {
+	validatorId := 3
+	layout := packedLayout(2)
+	cmd := buildSonicdCmd(&validatorId, "pubkey", "1.2.3.4", true, "", layout, driver.ClientMemory{})
+
+	want := map[string]string{
+		"--http.port":          "18565",
+		"--ws.port":            "18566",
+		"--pprof.port":         "6080",
+		"--port":               "5070",
+		"--config":             "/clients/2/datadir/config.toml",
+		"--validator.password": "/clients/2/datadir/password.txt",
+	}
+	for flag, value := range want {
+		i := slices.Index(cmd, flag)
+		if i < 0 || i+1 >= len(cmd) || cmd[i+1] != value {
+			t.Errorf("expected %s %s in %v", flag, value, cmd)
+		}
+	}
+	if !slices.Contains(cmd, "--datadir=/clients/2/datadir") {
+		t.Errorf("command does not use the data directory of the slot: %v", cmd)
+	}
+}
+
+func TestBuildSonicdCmd_AppliesTheMemorySettings(t *testing.T) {
+	tests := map[string]struct {
+		memory    driver.ClientMemory
+		liveCache string
+		cache     string
+	}{
+		"defaults":   {driver.ClientMemory{}, "256000000", ""},
+		"live cache": {driver.ClientMemory{LiveCache: 32 << 20}, "33554432", ""},
+		"cache":      {driver.ClientMemory{Cache: 64 << 20}, "256000000", "64"},
+	}
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			cmd := buildSonicdCmd(nil, "", "1.2.3.4", true, "", defaultLayout, test.memory)
+			i := slices.Index(cmd, "--statedb.livecache")
+			if i < 0 || i+1 >= len(cmd) || cmd[i+1] != test.liveCache {
+				t.Errorf("expected --statedb.livecache %s in %v", test.liveCache, cmd)
+			}
+			i = slices.Index(cmd, "--cache")
+			if test.cache == "" {
+				if i >= 0 {
+					t.Errorf("unexpected --cache in %v", cmd)
+				}
+				return
+			}
+			if i < 0 || i+1 >= len(cmd) || cmd[i+1] != test.cache {
+				t.Errorf("expected --cache %s in %v", test.cache, cmd)
+			}
+		})
+	}
+}
+
+func TestOperaNode_PackedNodeReportsTheMetricsPortOfItsSlot(t *testing.T) {
+	node := newNodeInState(t, NodeStateRunning)
+	if got := node.MetricsPort(); got != 6060 {
+		t.Errorf("unexpected metrics port of a node with its own container, got %d", got)
+	}
+	layout := packedLayout(4)
+	node.layout = &layout
+	if got := node.MetricsPort(); got != 6100 {
+		t.Errorf("unexpected metrics port of a packed node, got %d, want 6100", got)
+	}
+}
+
+func TestOperaNode_PackedNodeRejectsActionsOnTheWholeContainer(t *testing.T) {
+	node := newNodeInState(t, NodeStateRunning)
+	node.pack = NewPack(nil, nil, "pack")
+
+	actions := map[string]func() error{
+		"throttle": func() error {
+			return node.Throttle(t.Context(), driver.ThrottleConfig{Egress: 1000})
+		},
+		"partition": func() error {
+			return node.BlockPeers(t.Context(), []string{"10.0.0.1"})
+		},
+		"links": func() error {
+			return node.ConfigureLinks(t.Context(), []PeerLink{{}})
+		},
+		"pause": func() error {
+			return node.pauseContainer(t.Context())
+		},
+	}
+	for name, action := range actions {
+		t.Run(name, func(t *testing.T) {
+			err := action()
+			if err == nil || !strings.Contains(err.Error(), `shares container "pack"`) {
+				t.Errorf("expected shared container error, got %v", err)
+			}
+		})
+	}
+}
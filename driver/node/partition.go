@@ -0,0 +1,77 @@
+// Copyright 2024 Fantom Foundation
+// This file is part of Norma System Testing Infrastructure for Sonic.
+//
+// Norma is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// Norma is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with Norma. If not, see <http://www.gnu.org/licenses/>.
+
+package node
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"strings"
+
+	"github.com/0xsoniclabs/norma/driver/docker"
+)
+
+// IP returns the address of the node on the docker network. Other nodes
+// reach it under this address; the driver itself does too, but from the
+// network's gateway address, which no peer ever has.
+func (n *OperaNode) IP() string {
+	return n.container.IP()
+}
+
+// BlockPeers drops all traffic between this node and the given peer
+// addresses. It installs a blackhole route per address inside the
+// container, so neither new nor established connections to those peers
+// can be used any longer, while the driver, which reaches the node from
+// the network gateway, keeps its RPC and monitoring access.
+//
+// Blocking an address that is already blocked has no effect.
+func (n *OperaNode) BlockPeers(ctx context.Context, ips []string) error {
+	return n.updatePeerRoutes(ctx, "replace", ips)
+}
+
+// UnblockPeers lifts the blocks installed by BlockPeers for the given peer
+// addresses. Each address must currently be blocked.
+func (n *OperaNode) UnblockPeers(ctx context.Context, ips []string) error {
+	return n.updatePeerRoutes(ctx, "del", ips)
+}
+
+// updatePeerRoutes applies the given `ip route` operation to a blackhole
+// route for each of the addresses, all in a single exec.
+func (n *OperaNode) updatePeerRoutes(ctx context.Context, op string, ips []string) error {
+	if len(ips) == 0 {
+		return nil
+	}
+	if err := n.requireOwnContainer("partition"); err != nil {
+		return err
+	}
+	cmds := make([]string, 0, len(ips))
+	for _, ip := range ips {
+		// The address ends up in a shell command, so it must be nothing
+		// but an address.
+		if net.ParseIP(ip) == nil {
+			return fmt.Errorf("invalid peer address %q", ip)
+		}
+		cmds = append(cmds, fmt.Sprintf("ip route %s blackhole %s/32", op, ip))
+	}
+	cmd := []string{"sh", "-c", strings.Join(cmds, " && ")}
+	if output, err := n.container.ExecWithOptions(ctx, cmd,
+		docker.ExecOptions{LogName: "partition"}); err != nil {
+		return fmt.Errorf("failed to update peer routes on node %s: %w - output: %s",
+			n.GetLabel(), err, output)
+	}
+	return nil
+}
@@ -0,0 +1,49 @@
+// Copyright 2024 Fantom Foundation
+// This file is part of Norma System Testing Infrastructure for Sonic.
+//
+// Norma is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// Norma is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with Norma. If not, see <http://www.gnu.org/licenses/>.
+
+package node
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/0xsoniclabs/norma/driver"
+	"github.com/0xsoniclabs/norma/driver/docker"
+)
+
+// UpdateResources changes the limits of the host resources the node may
+// use, without restarting it. Limits left zero stay as they are.
+func (n *OperaNode) UpdateResources(ctx context.Context, resources driver.Resources) error {
+	if err := n.requireOwnContainer("limit the resources of"); err != nil {
+		return err
+	}
+	if n.container == nil {
+		return fmt.Errorf("node %q has no container", n.GetLabel())
+	}
+	if err := n.container.UpdateResources(ctx, dockerResources(resources)); err != nil {
+		return fmt.Errorf("node %q: %w", n.GetLabel(), err)
+	}
+	return nil
+}
+
+// dockerResources converts resource limits into those of the container.
+func dockerResources(resources driver.Resources) docker.Resources {
+	return docker.Resources{
+		CPUs:        resources.CPUs,
+		Memory:      resources.Memory,
+		BlkioWeight: resources.BlkioWeight,
+	}
+}
@@ -0,0 +1,42 @@
+// Copyright 2024 Fantom Foundation
+// This file is part of Norma System Testing Infrastructure for Sonic.
+//
+// Norma is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// Norma is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with Norma. If not, see <http://www.gnu.org/licenses/>.
+
+package node
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/0xsoniclabs/norma/driver"
+	"github.com/0xsoniclabs/norma/driver/docker"
+)
+
+func TestDockerResources_CopiesAllLimits(t *testing.T) {
+	got := dockerResources(driver.Resources{CPUs: 2, Memory: 1 << 30, BlkioWeight: 500})
+	want := docker.Resources{CPUs: 2, Memory: 1 << 30, BlkioWeight: 500}
+	if got != want {
+		t.Errorf("unexpected resources, got %+v, want %+v", got, want)
+	}
+}
+
+func TestOperaNode_UpdateResources_RequiresContainer(t *testing.T) {
+	node := newNodeInState(t, NodeStateRunning)
+
+	err := node.UpdateResources(t.Context(), driver.Resources{CPUs: 1})
+	if err == nil || !strings.Contains(err.Error(), "has no container") {
+		t.Fatalf("expected an error for a node without container, got %v", err)
+	}
+}
@@ -0,0 +1,195 @@
+// Copyright 2024 Fantom Foundation
+// This file is part of Norma System Testing Infrastructure for Sonic.
+//
+// Norma is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// Norma is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with Norma. If not, see <http://www.gnu.org/licenses/>.
+
+package node
+
+import (
+	"crypto/ecdsa"
+	"fmt"
+	"net"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/p2p/enode"
+)
+
+const (
+	// p2pPort is the port the client listens on for its peers.
+	p2pPort = 5050
+	// genesisFilePath is where the genesis file is mounted in the container.
+	genesisFilePath = "/genesis.json"
+	// genesisImportedPath marks a data directory the genesis has been
+	// imported into, so that restarting a standalone node does not import
+	// it again.
+	genesisImportedPath = dataDir + "/.genesis-imported"
+)
+
+// StandaloneNode describes a node whose container starts the client on its
+// own, without Norma driving it, as the nodes of an exported docker compose
+// project do. Everything Initialize and StartSonicd do for a node started
+// by Norma is done by the container's entrypoint instead.
+type StandaloneNode struct {
+	// Config is the configuration of the node. Its Label, ValidatorId,
+	// PubKey, Address, NetworkConfig, ExtraArguments, ClockOffset and
+	// Memory are used.
+	Config *OperaNodeConfig
+	// IP is the fixed address of the node's container.
+	IP string
+	// NodeKey is the key of the node's p2p identity. It is fixed so that
+	// the peers of the node can be told its enode URL up front.
+	NodeKey *ecdsa.PrivateKey
+	// Peers are the enode URLs of the nodes the node is peered with.
+	Peers []string
+	// Links are the conditions of the links to other nodes that differ
+	// from the uniform delay of the network.
+	Links []PeerLink
+}
+
+// Enode returns the enode URL peers reach the node at.
+func (s *StandaloneNode) Enode() string {
+	return enode.NewV4(&s.NodeKey.PublicKey, net.ParseIP(s.IP), p2pPort, p2pPort).URLv4()
+}
+
+// Environment returns the environment of the node's container.
+func (s *StandaloneNode) Environment() map[string]string {
+	envs := map[string]string{"STATE_DB_DATADIR": dataDir}
+	if s.Config.ValidatorId != nil && *s.Config.ValidatorId > 0 {
+		envs["VALIDATOR_PUBKEY"] = s.Config.PubKey
+		envs["VALIDATOR_ADDRESS"] = s.Config.Address
+	}
+	if s.Config.Memory.GoMemLimit != 0 {
+		envs["GOMEMLIMIT"] = fmt.Sprint(s.Config.Memory.GoMemLimit)
+	}
+	return envs
+}
+
+// StopGracePeriod returns how long the container is given to stop before it
+// is killed, which is as long as for a node started by Norma.
+func (s *StandaloneNode) StopGracePeriod() time.Duration {
+	return containerShutdownTimeout
+}
+
+// Binds returns the bind mounts of the node's container, given the host
+// paths of the genesis file and of the directory the node's validator
+// keystore was written to by genesis.WriteValidatorKeystore. The keystore
+// directory is ignored for nodes that are not validators.
+func (s *StandaloneNode) Binds(genesisPath, keystoreDir string) []string {
+	binds := []string{fmt.Sprintf("%s:%s:ro", genesisPath, genesisFilePath)}
+	if s.Config.ValidatorId != nil && *s.Config.ValidatorId > 0 {
+		binds = append(binds, fmt.Sprintf("%s/keystore:%s/keystore:ro",
+			strings.TrimSuffix(keystoreDir, "/"), dataDir))
+	}
+	return binds
+}
+
+// Entrypoint returns the command the node's container runs. It imports the
+// genesis on the first start, writes the password and client configuration
+// files, installs the traffic shaping of the node's links and then replaces
+// itself by the client, so that the client receives the container's stop
+// signal.
+func (s *StandaloneNode) Entrypoint() ([]string, error) {
+	lines := []string{
+		fmt.Sprintf("mkdir -m 755 -p %s || exit 1", dataDir),
+		fmt.Sprintf("if [ ! -e %s ]; then", genesisImportedPath),
+		"  " + shellJoin([]string{
+			sonicToolBinaryPath,
+			"--datadir", dataDir,
+			"--statedb.livecache", "1",
+			"genesis", "json", "--experimental", genesisFilePath,
+		}) + " || exit 1",
+		"  touch " + genesisImportedPath,
+		"fi",
+		// See Initialize on why the password is fixed.
+		fmt.Sprintf("printf 'password\\n' > %s", passwordFilePath),
+		fmt.Sprintf("cat > %s <<'EOF'", configFilePath),
+		strings.TrimSuffix(s.configToml(), "\n"),
+		"EOF",
+	}
+
+	latency := time.Duration(0)
+	if s.Config.NetworkConfig != nil {
+		latency = s.Config.NetworkConfig.RoundTripTime / 2
+	}
+	if latency > 0 || len(s.Links) > 0 {
+		script, err := shapingScript(linkDevice, latency, s.Links, nil)
+		if err != nil {
+			return nil, fmt.Errorf("invalid traffic shaping of node %s: %w", s.Config.Label, err)
+		}
+		lines = append(lines, "("+script+") || exit 1")
+	}
+
+	args := buildSonicdCmd(
+		s.Config.ValidatorId,
+		s.Config.PubKey,
+		s.IP,
+		s.Config.NetworkConfig == nil || s.Config.NetworkConfig.Topology.IsFullMesh(),
+		s.Config.ExtraArguments,
+		defaultLayout,
+		s.Config.Memory)
+	args = append(args,
+		"--port", fmt.Sprint(p2pPort),
+		"--nodekeyhex", fmt.Sprintf("%x", crypto.FromECDSA(s.NodeKey)))
+
+	exec := "exec "
+	if s.Config.ClockOffset != nil {
+		lines = append(lines,
+			fmt.Sprintf("[ -e %s ] || { echo 'the image does not support clock offsets, use a sonic-clock image' >&2; exit 1; }",
+				clockHookMarker),
+			fmt.Sprintf("echo %+d > %s", s.Config.ClockOffset.Nanoseconds(), clockOffsetPath))
+		exec += shellJoin([]string{"env", clockOffsetEnv + "=" + clockOffsetPath}) + " "
+	}
+	lines = append(lines, exec+shellJoin(args))
+
+	return []string{"sh", "-c", strings.Join(lines, "\n") + "\n"}, nil
+}
+
+// configToml renders the client configuration of the node. All nodes of a
+// standalone network start at the same time, so every one of them takes
+// part in bootstrapping it. The peers are configured as static nodes, the
+// only way to peer a client without talking to it.
+func (s *StandaloneNode) configToml() string {
+	res := ClientConfigToml(s.Config, true)
+	if len(s.Peers) == 0 {
+		return res
+	}
+	peers := make([]string, 0, len(s.Peers))
+	for _, peer := range s.Peers {
+		peers = append(peers, fmt.Sprintf("%q", peer))
+	}
+	return res + fmt.Sprintf("\n[Node.P2P]\nStaticNodes = [%s]\n", strings.Join(peers, ", "))
+}
+
+// plainShellWord matches the words a shell takes literally.
+var plainShellWord = regexp.MustCompile(`^[A-Za-z0-9_./:=,@%+-]+$`)
+
+// shellQuote quotes a word for a POSIX shell, unless it needs no quoting.
+func shellQuote(word string) string {
+	if plainShellWord.MatchString(word) {
+		return word
+	}
+	return "'" + strings.ReplaceAll(word, "'", `'\''`) + "'"
+}
+
+// shellJoin renders an argument vector as a shell command line.
+func shellJoin(args []string) string {
+	quoted := make([]string, 0, len(args))
+	for _, arg := range args {
+		quoted = append(quoted, shellQuote(arg))
+	}
+	return strings.Join(quoted, " ")
+}
@@ -0,0 +1,213 @@
+// Copyright 2024 Fantom Foundation
+// This file is part of Norma System Testing Infrastructure for Sonic.
+//
+// Norma is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// Norma is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with Norma. If not, see <http://www.gnu.org/licenses/>.
+
+package node
+
+import (
+	"os/exec"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/0xsoniclabs/norma/driver"
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+func newTestStandaloneNode(t *testing.T) *StandaloneNode {
+	t.Helper()
+	key, err := crypto.HexToECDSA("4c0883a69102937d6231471b5dbb6204fe5129617082792ae468d01a3f362318")
+	if err != nil {
+		t.Fatalf("failed to parse node key: %v", err)
+	}
+	validatorId := 2
+	return &StandaloneNode{
+		Config: &OperaNodeConfig{
+			Label:         "validator-1",
+			ValidatorId:   &validatorId,
+			NetworkConfig: &driver.NetworkConfig{Validators: driver.NewDefaultValidators(2)},
+			PubKey:        "0xc004",
+			Address:       "0x0002",
+		},
+		IP:      "172.29.0.3",
+		NodeKey: key,
+	}
+}
+
+func TestStandaloneNode_EnodeCarriesTheKeyAndAddressOfTheNode(t *testing.T) {
+	node := newTestStandaloneNode(t)
+	enode := node.Enode()
+	if !strings.HasPrefix(enode, "enode://") || !strings.HasSuffix(enode, "@172.29.0.3:5050") {
+		t.Errorf("unexpected enode URL %q", enode)
+	}
+	if enode != newTestStandaloneNode(t).Enode() {
+		t.Errorf("the enode URL of a node must be stable")
+	}
+}
+
+func TestStandaloneNode_EntrypointIsAValidShellScript(t *testing.T) {
+	node := newTestStandaloneNode(t)
+	node.Config.NetworkConfig.RoundTripTime = 100 * time.Millisecond
+	offset := -1500 * time.Millisecond
+	node.Config.ClockOffset = &offset
+	node.Config.ExtraArguments = "--cache 'odd value'"
+	node.Links = []PeerLink{{IP: "172.29.0.2", Link: driver.LinkConfig{Latency: 20 * time.Millisecond}}}
+
+	entrypoint, err := node.Entrypoint()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(entrypoint) != 3 || entrypoint[0] != "sh" || entrypoint[1] != "-c" {
+		t.Fatalf("entrypoint must run a shell script, got %v", entrypoint)
+	}
+	if output, err := exec.Command("sh", "-n", "-c", entrypoint[2]).CombinedOutput(); err != nil {
+		t.Fatalf("invalid script: %v\n%s\n%s", err, output, entrypoint[2])
+	}
+}
+
+func TestStandaloneNode_EntrypointPreparesAndStartsTheClient(t *testing.T) {
+	node := newTestStandaloneNode(t)
+	node.Peers = []string{"enode://peer@172.29.0.2:5050"}
+
+	entrypoint, err := node.Entrypoint()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	script := entrypoint[2]
+	for _, want := range []string{
+		"genesis json --experimental " + genesisFilePath,
+		"> " + passwordFilePath,
+		"cat > " + configFilePath,
+		`StaticNodes = ["enode://peer@172.29.0.2:5050"]`,
+		"exec " + sonicdBinaryPath,
+		"--validator.id 2",
+		"--nat extip:172.29.0.3",
+		"--nodekeyhex 4c0883a69102937d6231471b5dbb6204fe5129617082792ae468d01a3f362318",
+	} {
+		if !strings.Contains(script, want) {
+			t.Errorf("script does not contain %q:\n%s", want, script)
+		}
+	}
+	// Without latency, links or clock offset, there is nothing to set up.
+	for _, unwanted := range []string{"tc ", clockOffsetEnv} {
+		if strings.Contains(script, unwanted) {
+			t.Errorf("script unexpectedly contains %q:\n%s", unwanted, script)
+		}
+	}
+}
+
+func TestStandaloneNode_EntrypointShapesTheLinks(t *testing.T) {
+	node := newTestStandaloneNode(t)
+	node.Links = []PeerLink{{IP: "172.29.0.2", Link: driver.LinkConfig{Latency: 20 * time.Millisecond}}}
+
+	entrypoint, err := node.Entrypoint()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	want := "match ip dst 172.29.0.2/32"
+	if !strings.Contains(entrypoint[2], want) {
+		t.Errorf("script does not contain %q:\n%s", want, entrypoint[2])
+	}
+
+	node.Links[0].IP = "not an address"
+	if _, err := node.Entrypoint(); err == nil {
+		t.Errorf("a link to an invalid address must be rejected")
+	}
+}
+
+func TestStandaloneNode_EntrypointOffsetsTheClockOfTheClientOnly(t *testing.T) {
+	node := newTestStandaloneNode(t)
+	offset := 2 * time.Second
+	node.Config.ClockOffset = &offset
+
+	entrypoint, err := node.Entrypoint()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	lines := strings.Split(strings.TrimSpace(entrypoint[2]), "\n")
+	last := lines[len(lines)-1]
+	if !strings.HasPrefix(last, "exec env "+clockOffsetEnv+"="+clockOffsetPath) {
+		t.Errorf("the client must be started with the offset clock, got %q", last)
+	}
+	if !slices.Contains(lines, "echo +2000000000 > "+clockOffsetPath) {
+		t.Errorf("the offset is not written:\n%s", entrypoint[2])
+	}
+	if strings.Contains(strings.Join(lines[:len(lines)-1], "\n"), clockOffsetEnv) {
+		t.Errorf("only the client may run with the offset clock:\n%s", entrypoint[2])
+	}
+}
+
+func TestStandaloneNode_TunesTheMemoryOfTheClient(t *testing.T) {
+	node := newTestStandaloneNode(t)
+	entrypoint, err := node.Entrypoint()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if want := "--statedb.livecache 256000000"; !strings.Contains(entrypoint[2], want) {
+		t.Errorf("script does not contain the default %q:\n%s", want, entrypoint[2])
+	}
+	if _, found := node.Environment()["GOMEMLIMIT"]; found {
+		t.Errorf("the memory limit of the client must not be set by default")
+	}
+
+	node.Config.Memory = driver.ClientMemory{LiveCache: 32 << 20, Cache: 64 << 20, GoMemLimit: 1 << 30}
+	entrypoint, err = node.Entrypoint()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	for _, want := range []string{"--statedb.livecache 33554432", "--cache 64"} {
+		if !strings.Contains(entrypoint[2], want) {
+			t.Errorf("script does not contain %q:\n%s", want, entrypoint[2])
+		}
+	}
+	if got, want := node.Environment()["GOMEMLIMIT"], "1073741824"; got != want {
+		t.Errorf("unexpected memory limit of the client, got %q, want %q", got, want)
+	}
+}
+
+func TestStandaloneNode_BindsKeystoreOfValidatorsOnly(t *testing.T) {
+	node := newTestStandaloneNode(t)
+	want := []string{
+		"./genesis.json:/genesis.json:ro",
+		"./validator-1/keystore:/datadir/keystore:ro",
+	}
+	if got := node.Binds("./genesis.json", "./validator-1/"); !slices.Equal(got, want) {
+		t.Errorf("unexpected binds of a validator, got %v, want %v", got, want)
+	}
+
+	node.Config.ValidatorId = nil
+	if got := node.Binds("./genesis.json", "./validator-1"); !slices.Equal(got, want[:1]) {
+		t.Errorf("unexpected binds of an observer, got %v, want %v", got, want[:1])
+	}
+	if _, found := node.Environment()["VALIDATOR_PUBKEY"]; found {
+		t.Errorf("an observer must not be given a validator key")
+	}
+}
+
+func TestShellQuote_QuotesOnlyWhereNeeded(t *testing.T) {
+	tests := map[string]string{
+		"--datadir=/datadir": "--datadir=/datadir",
+		"":                   "''",
+		"two words":          "'two words'",
+		"it's":               `'it'\''s'`,
+		"$HOME":              "'$HOME'",
+	}
+	for word, want := range tests {
+		if got := shellQuote(word); got != want {
+			t.Errorf("shellQuote(%q) = %q, want %q", word, got, want)
+		}
+	}
+}
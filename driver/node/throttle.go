@@ -0,0 +1,101 @@
+// Copyright 2024 Fantom Foundation
+// This file is part of Norma System Testing Infrastructure for Sonic.
+//
+// Norma is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// Norma is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with Norma. If not, see <http://www.gnu.org/licenses/>.
+
+package node
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/0xsoniclabs/norma/driver"
+)
+
+const (
+	// minBurst is the smallest default burst, in bytes. It comfortably
+	// exceeds a full-sized frame, which the limits must always let pass.
+	minBurst = 16 * 1024
+	// tbfLatency bounds the time a packet may wait for its share of the
+	// egress rate; packets that would wait longer are dropped, as on a
+	// congested uplink.
+	tbfLatency = "100ms"
+)
+
+// Throttle limits the bandwidth of the node's network interface, replacing
+// any limits set before. The limits apply to all traffic of the node,
+// including the driver's RPC and monitoring access, and combine with the
+// shaping of its links.
+func (n *OperaNode) Throttle(ctx context.Context, config driver.ThrottleConfig) error {
+	if config.Egress == 0 && config.Ingress == 0 {
+		return fmt.Errorf("throttling node %s requires an egress or ingress rate", n.GetLabel())
+	}
+	if err := n.requireOwnContainer("throttle"); err != nil {
+		return err
+	}
+	n.shapingMutex.Lock()
+	defer n.shapingMutex.Unlock()
+	if err := n.applyShaping(ctx, n.links, &config); err != nil {
+		return err
+	}
+	n.throttle = &config
+	return nil
+}
+
+// Unthrottle lifts the bandwidth limits set by Throttle. The shaping of the
+// node's links stays in place.
+func (n *OperaNode) Unthrottle(ctx context.Context) error {
+	n.shapingMutex.Lock()
+	defer n.shapingMutex.Unlock()
+	if n.throttle == nil {
+		return fmt.Errorf("node %s is not throttled", n.GetLabel())
+	}
+	if err := n.applyShaping(ctx, n.links, nil); err != nil {
+		return err
+	}
+	n.throttle = nil
+	return nil
+}
+
+// Throttling returns the bandwidth limits currently in place, or nil if the
+// node is not throttled.
+func (n *OperaNode) Throttling() *driver.ThrottleConfig {
+	n.shapingMutex.Lock()
+	defer n.shapingMutex.Unlock()
+	if n.throttle == nil {
+		return nil
+	}
+	config := *n.throttle
+	return &config
+}
+
+// tbfArgs renders the parameters of a tbf qdisc limiting the egress rate.
+func tbfArgs(rate, burst uint64) string {
+	return fmt.Sprintf("rate %dbit burst %d latency %s", rate, burstFor(rate, burst), tbfLatency)
+}
+
+// policeArgs renders the parameters of a policer limiting the ingress rate.
+func policeArgs(rate, burst uint64) string {
+	return fmt.Sprintf("rate %dbit burst %d", rate, burstFor(rate, burst))
+}
+
+// burstFor returns the given burst or, if none is given, the amount of data
+// sent at the given rate in 10ms, the granularity at which token buckets are
+// refilled on common kernels, but at least minBurst.
+func burstFor(rate, burst uint64) uint64 {
+	if burst != 0 {
+		return burst
+	}
+	return max(rate/8/100, minBurst)
+}
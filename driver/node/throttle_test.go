@@ -0,0 +1,86 @@
+// Copyright 2024 Fantom Foundation
+// This file is part of Norma System Testing Infrastructure for Sonic.
+//
+// Norma is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// Norma is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with Norma. If not, see <http://www.gnu.org/licenses/>.
+
+package node
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/0xsoniclabs/norma/driver"
+)
+
+func TestShapingScript_PutsTbfInFrontOfLinksForEgressLimit(t *testing.T) {
+	links := []PeerLink{{IP: "172.18.0.2", Link: driver.LinkConfig{Latency: 80 * time.Millisecond}}}
+	throttle := &driver.ThrottleConfig{Egress: 10_000_000, Burst: 32 * 1024}
+	script, err := shapingScript("eth0", 0, links, throttle)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	for _, want := range []string{
+		"tc qdisc add dev eth0 root handle f: tbf rate 10000000bit burst 32768 latency 100ms && " +
+			"tc qdisc add dev eth0 parent f:1 handle 1: htb default 1",
+		"tc filter add dev eth0 parent 1: protocol ip prio 1 u32 match ip dst 172.18.0.2/32 flowid 1:2",
+	} {
+		if !strings.Contains(script, want) {
+			t.Errorf("script is missing %q: %s", want, script)
+		}
+	}
+	if strings.Contains(script, "root handle 1:") || strings.Contains(script, "ingress dev") {
+		t.Errorf("unexpected root htb or ingress policer: %s", script)
+	}
+}
+
+func TestShapingScript_PolicesIngress(t *testing.T) {
+	throttle := &driver.ThrottleConfig{Ingress: 20_000_000}
+	script, err := shapingScript("eth0", 0, nil, throttle)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	for _, want := range []string{
+		"tc qdisc add dev eth0 root handle 1: htb default 1",
+		"tc qdisc add dev eth0 handle ffff: ingress",
+		"tc filter add dev eth0 parent ffff: protocol all prio 1 u32 match u32 0 0 " +
+			"police rate 20000000bit burst 25000 drop flowid :1",
+	} {
+		if !strings.Contains(script, want) {
+			t.Errorf("script is missing %q: %s", want, script)
+		}
+	}
+	if strings.Contains(script, "tbf") {
+		t.Errorf("script must not limit the egress: %s", script)
+	}
+}
+
+func TestBurstFor(t *testing.T) {
+	tests := map[string]struct {
+		rate, burst, want uint64
+	}{
+		"explicit burst":          {rate: 1_000_000_000, burst: 4096, want: 4096},
+		"10ms worth of data":      {rate: 100_000_000, want: 125_000},
+		"at least a minimum size": {rate: 1_000_000, want: minBurst},
+	}
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			if got := burstFor(test.rate, test.burst); got != test.want {
+				t.Errorf("unexpected burst, got %d, want %d", got, test.want)
+			}
+		})
+	}
+}
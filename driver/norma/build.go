@@ -131,6 +131,10 @@ func collectScenarioFiles(path string) ([]string, error) {
 			return walkErr
 		}
 		if d.IsDir() {
+			// Fragments are included by scenarios, not run on their own.
+			if d.Name() == parser.FragmentsDir {
+				return filepath.SkipDir
+			}
 			return nil
 		}
 		if isYAML(d.Name()) {
@@ -46,6 +46,30 @@ func TestCollectScenarioFiles_Recursive(t *testing.T) {
 	}
 }
 
+func TestCollectScenarioFiles_SkipsFragments(t *testing.T) {
+	tmp := t.TempDir()
+
+	if err := os.WriteFile(filepath.Join(tmp, "a.yml"), []byte("name: a\n"), 0644); err != nil {
+		t.Fatalf("failed to write file: %v", err)
+	}
+	if err := os.MkdirAll(filepath.Join(tmp, "fragments"), 0755); err != nil {
+		t.Fatalf("failed to create fragments dir: %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(tmp, "fragments", "setup.yml"), []byte("Steps: []\n"), 0644); err != nil {
+		t.Fatalf("failed to write file: %v", err)
+	}
+
+	got, err := collectScenarioFiles(tmp)
+	if err != nil {
+		t.Fatalf("collectScenarioFiles() failed: %v", err)
+	}
+
+	want := []string{filepath.Join(tmp, "a.yml")}
+	if !slices.Equal(got, want) {
+		t.Fatalf("invalid files\ngot:  %#v\nwant: %#v", got, want)
+	}
+}
+
 func TestCollectScenarioFiles_EmptyDir(t *testing.T) {
 	tmp := t.TempDir()
 
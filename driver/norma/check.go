@@ -30,6 +30,9 @@ var checkCommand = cli.Command{
 	Action: check,
 	Name:   "check",
 	Usage:  "checks a scenario configuration file for issues",
+	Flags: []cli.Flag{
+		&parameterValues,
+	},
 }
 
 func check(ctx *cli.Context) (err error) {
@@ -39,10 +42,15 @@ func check(ctx *cli.Context) (err error) {
 		return fmt.Errorf("requires target file name as argument")
 	}
 
+	parameters, err := parseParameterValues(ctx.StringSlice(parameterValues.Name))
+	if err != nil {
+		return err
+	}
+
 	path := args.First()
 	slog.Info("trying to parse", "path", path)
 
-	scenario, err := parser.ParseFile(path)
+	scenario, err := parser.ParseFileWithParameters(path, parameters)
 	if err != nil {
 		return fmt.Errorf("failed to parse scenario file: %w", err)
 	}
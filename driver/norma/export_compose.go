@@ -0,0 +1,348 @@
+// Copyright 2024 Fantom Foundation
+// This file is part of Norma System Testing Infrastructure for Sonic.
+//
+// Norma is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// Norma is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with Norma. If not, see <http://www.gnu.org/licenses/>.
+
+package main
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"net/netip"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/0xsoniclabs/norma/driver"
+	"github.com/0xsoniclabs/norma/driver/docker"
+	"github.com/0xsoniclabs/norma/driver/executor"
+	"github.com/0xsoniclabs/norma/driver/network"
+	"github.com/0xsoniclabs/norma/driver/node"
+	"github.com/0xsoniclabs/norma/driver/parser"
+	"github.com/0xsoniclabs/norma/genesis"
+	"github.com/0xsoniclabs/sonic/opera"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/urfave/cli/v2"
+	"gopkg.in/yaml.v3"
+)
+
+// Run with `go run ./driver/norma export-compose <scenario.yml>`
+
+var exportComposeCommand = cli.Command{
+	Action: exportCompose,
+	Name:   "export-compose",
+	Usage:  "exports the initial network of a scenario as a docker compose project",
+	Flags: []cli.Flag{
+		&composeDirectory,
+		&composeSubnet,
+	},
+}
+
+var (
+	composeDirectory = cli.StringFlag{
+		Name:    "output-directory",
+		Usage:   "directory the project is written to. If empty, <scenario>-compose in the working directory is used.",
+		Value:   "",
+		Aliases: []string{"o"},
+	}
+	composeSubnet = cli.StringFlag{
+		Name:  "subnet",
+		Usage: "subnet of the project's network, from which the nodes get fixed addresses",
+		Value: "172.29.0.0/16",
+	}
+)
+
+const (
+	// composeFileName is the name of the exported compose file.
+	composeFileName = "docker-compose.yml"
+	// composeNetworkName is the name of the network of the exported project.
+	composeNetworkName = "norma"
+)
+
+// composeClientVersions resolves the client versions the genesis is
+// generated for. Var so tests can avoid running the images.
+var composeClientVersions = docker.ClientVersions
+
+func exportCompose(ctx *cli.Context) error {
+	args := ctx.Args()
+	if args.Len() < 1 {
+		return fmt.Errorf("requires scenario file as argument")
+	}
+	path := args.First()
+
+	scenario, err := parser.ParseFile(path)
+	if err != nil {
+		return fmt.Errorf("failed to parse scenario file: %w", err)
+	}
+	if err := scenario.Check(); err != nil {
+		return err
+	}
+
+	subnet, err := netip.ParsePrefix(ctx.String(composeSubnet.Name))
+	if err != nil {
+		return fmt.Errorf("invalid subnet: %w", err)
+	}
+
+	dir := ctx.String(composeDirectory.Name)
+	if dir == "" {
+		dir = strings.TrimSuffix(filepath.Base(path), filepath.Ext(path)) + "-compose"
+	}
+
+	if err := writeComposeProject(ctx.Context, &scenario, dir, subnet); err != nil {
+		return err
+	}
+	slog.Info("exported docker compose project", "path", filepath.Join(dir, composeFileName))
+	fmt.Printf("Start the network with `docker compose --project-directory %s up`\n", dir)
+	return nil
+}
+
+// composeProject is the part of the compose file format the export uses.
+type composeProject struct {
+	Services map[string]composeService `yaml:"services"`
+	Networks map[string]composeNetwork `yaml:"networks"`
+}
+
+type composeService struct {
+	Image           string                           `yaml:"image"`
+	Hostname        string                           `yaml:"hostname"`
+	Init            bool                             `yaml:"init"`
+	CapAdd          []string                         `yaml:"cap_add"`
+	StopGracePeriod string                           `yaml:"stop_grace_period"`
+	Environment     map[string]string                `yaml:"environment"`
+	Volumes         []string                         `yaml:"volumes"`
+	Ports           []string                         `yaml:"ports"`
+	Networks        map[string]composeServiceNetwork `yaml:"networks"`
+	Entrypoint      []string                         `yaml:"entrypoint"`
+	Cpus            float64                          `yaml:"cpus,omitempty"`
+	MemLimit        uint64                           `yaml:"mem_limit,omitempty"`
+	MemswapLimit    uint64                           `yaml:"memswap_limit,omitempty"`
+	BlkioConfig     *composeBlkioConfig              `yaml:"blkio_config,omitempty"`
+}
+
+type composeServiceNetwork struct {
+	Ipv4Address string `yaml:"ipv4_address"`
+}
+
+type composeBlkioConfig struct {
+	Weight uint16 `yaml:"weight"`
+}
+
+type composeNetwork struct {
+	Ipam composeIpam `yaml:"ipam"`
+}
+
+type composeIpam struct {
+	Config []composeIpamConfig `yaml:"config"`
+}
+
+type composeIpamConfig struct {
+	Subnet string `yaml:"subnet"`
+}
+
+// writeComposeProject writes a docker compose project running the network a
+// scenario starts with into dir: the genesis validators of its first step,
+// peered and shaped as the scenario's topology and links say. Next to the
+// compose file, the project holds the genesis and a directory per validator
+// with its keystore. The nodes get fixed addresses from the given subnet.
+func writeComposeProject(ctx context.Context, scenario *parser.Scenario, dir string, subnet netip.Prefix) error {
+	validators, genesisIds, err := extractBootstrapValidators(scenario)
+	if err != nil {
+		return err
+	}
+	step := scenario.Steps[0]
+	config := &driver.NetworkConfig{
+		Validators:   validators,
+		Links:        executor.NetworkLinks(scenario),
+		Topology:     executor.NetworkTopology(scenario),
+		NetworkRules: scenario.InitialRules,
+		InitialState: scenario.InitialState,
+		ClientImages: collectClientImages(scenario),
+	}
+
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return fmt.Errorf("failed to create output directory: %w", err)
+	}
+	genesisPath := filepath.Join(dir, "genesis.json")
+	if err := writeComposeGenesis(ctx, config, genesisPath); err != nil {
+		return err
+	}
+
+	// The nodes are listed in the order of their validator IDs, which is the
+	// order the topology peers them in.
+	labels := make([]string, 0, len(genesisIds))
+	for label := range genesisIds {
+		labels = append(labels, label)
+	}
+	slices.SortFunc(labels, func(a, b string) int {
+		return genesisIds[a] - genesisIds[b]
+	})
+
+	// The first address of the subnet is its gateway.
+	addr := subnet.Masked().Addr().Next()
+	nodes := make(map[string]*node.StandaloneNode, len(labels))
+	for _, label := range labels {
+		addr = addr.Next()
+		if !subnet.Contains(addr) {
+			return fmt.Errorf("subnet %v is too small for %d nodes", subnet, len(labels))
+		}
+		id := genesisIds[label]
+		privKey, pubKey, address, err := genesis.DeriveValidatorKey(id)
+		if err != nil {
+			return fmt.Errorf("failed to derive validator key: %w", err)
+		}
+		if err := genesis.WriteValidatorKeystore(privKey, filepath.Join(dir, label)); err != nil {
+			return fmt.Errorf("failed to write keystore of %s: %w", label, err)
+		}
+		// The node key only has to be stable, not secret.
+		nodeKey, err := crypto.ToECDSA(crypto.Keccak256([]byte("norma-node-key-" + label)))
+		if err != nil {
+			return fmt.Errorf("failed to derive node key of %s: %w", label, err)
+		}
+		nodes[label] = &node.StandaloneNode{
+			Config: &node.OperaNodeConfig{
+				Label:          label,
+				Image:          driver.ResolveClientImageName(step.ImageName),
+				ValidatorId:    &id,
+				NetworkConfig:  config,
+				ExtraArguments: step.ExtraArguments,
+				ClockOffset:    step.ClockOffset,
+				Memory:         executor.ClientMemoryOf(&step),
+				PubKey:         pubKey,
+				PrivKey:        privKey,
+				Address:        address,
+			},
+			IP:      addr.String(),
+			NodeKey: nodeKey,
+		}
+	}
+
+	peerings := config.Topology.Peerings(labels)
+	for _, label := range labels {
+		current := nodes[label]
+		for _, other := range labels {
+			if other == label {
+				continue
+			}
+			if peerings[driver.NewPeering(label, other)] {
+				current.Peers = append(current.Peers, nodes[other].Enode())
+			}
+			if link, found := driver.ResolveLink(config.Links, label, other); found {
+				current.Links = append(current.Links, node.PeerLink{IP: nodes[other].IP, Link: link})
+			}
+		}
+	}
+
+	project := composeProject{
+		Services: map[string]composeService{},
+		Networks: map[string]composeNetwork{
+			composeNetworkName: {Ipam: composeIpam{
+				Config: []composeIpamConfig{{Subnet: subnet.Masked().String()}},
+			}},
+		},
+	}
+	for _, label := range labels {
+		service, err := composeServiceOf(nodes[label], step)
+		if err != nil {
+			return err
+		}
+		project.Services[label] = service
+	}
+
+	data, err := yaml.Marshal(project)
+	if err != nil {
+		return fmt.Errorf("failed to encode compose file: %w", err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, composeFileName), data, 0644); err != nil {
+		return fmt.Errorf("failed to write compose file: %w", err)
+	}
+	return nil
+}
+
+// writeComposeGenesis generates the genesis of the network the same way the
+// docker backend does.
+func writeComposeGenesis(ctx context.Context, config *driver.NetworkConfig, path string) error {
+	clientVersions, err := composeClientVersions(ctx, config.GetClientImages())
+	if err != nil {
+		return fmt.Errorf("failed to read the client versions of the network: %w", err)
+	}
+	rules := opera.FakeNetRules(opera.GetSonicUpgrades())
+	if err := genesis.ApplyNetworkRulesPatch(&rules, config.NetworkRules); err != nil {
+		return fmt.Errorf("failed to apply network rules to genesis: %w", err)
+	}
+	if err := genesis.GenerateJsonGenesis(
+		path,
+		driver.GetValidatorStakes(config.Validators),
+		&rules,
+		clientVersions,
+		config.InitialState,
+	); err != nil {
+		return fmt.Errorf("failed to generate genesis file: %w", err)
+	}
+	return nil
+}
+
+// composeServiceOf describes the container of a node. Paths are relative to
+// the project directory, so that the project can be moved around.
+func composeServiceOf(standalone *node.StandaloneNode, step parser.Step) (composeService, error) {
+	label := standalone.Config.Label
+	entrypoint, err := standalone.Entrypoint()
+	if err != nil {
+		return composeService{}, err
+	}
+	// The services are reached on the host through ports docker picks, see
+	// `docker compose port`, as well as at the node's address.
+	var ports []string
+	for _, service := range []*network.ServiceDescription{
+		&node.OperaRpcService, &node.OperaWsService, &node.OperaDebugService,
+	} {
+		ports = append(ports, fmt.Sprintf("127.0.0.1::%d", service.Port))
+	}
+	service := composeService{
+		Image:           standalone.Config.Image,
+		Hostname:        label,
+		Init:            true,
+		CapAdd:          []string{"NET_ADMIN"},
+		StopGracePeriod: standalone.StopGracePeriod().String(),
+		Environment:     standalone.Environment(),
+		Volumes:         standalone.Binds("./genesis.json", "./"+label),
+		Ports:           ports,
+		Networks: map[string]composeServiceNetwork{
+			composeNetworkName: {Ipv4Address: standalone.IP},
+		},
+		Entrypoint: escapeComposeInterpolation(entrypoint),
+		Cpus:       step.CPUs,
+	}
+	if step.Memory > 0 {
+		// As for the docker backend, the node cannot swap.
+		service.MemLimit = uint64(step.Memory)
+		service.MemswapLimit = uint64(step.Memory)
+	}
+	if step.BlkioWeight > 0 {
+		service.BlkioConfig = &composeBlkioConfig{Weight: step.BlkioWeight}
+	}
+	return service, nil
+}
+
+// escapeComposeInterpolation escapes the dollar signs of the given values,
+// which docker compose would otherwise substitute variables for before the
+// shell gets to see them.
+func escapeComposeInterpolation(values []string) []string {
+	res := make([]string, 0, len(values))
+	for _, value := range values {
+		res = append(res, strings.ReplaceAll(value, "$", "$$"))
+	}
+	return res
+}
@@ -0,0 +1,149 @@
+// Copyright 2024 Fantom Foundation
+// This file is part of Norma System Testing Infrastructure for Sonic.
+//
+// Norma is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// Norma is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with Norma. If not, see <http://www.gnu.org/licenses/>.
+
+package main
+
+import (
+	"context"
+	"net/netip"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/0xsoniclabs/norma/driver/docker"
+	"github.com/0xsoniclabs/norma/driver/parser"
+	"github.com/stretchr/testify/require"
+	"gopkg.in/yaml.v3"
+)
+
+// exportTestScenario peers three validators in a line and slows down the
+// link between the first and the last, which are not peered.
+const exportTestScenario = `
+Name: Export
+Description: Three validators in a line.
+Topology: line
+Links:
+  - between: [validator-0, validator-1]
+    latency: 40ms
+Scenario:
+  - startNode: validator
+    type: validator
+    instances: 3
+    cpus: 1.5
+    liveCache: 32mb
+    cache: 64mb
+    goMemLimit: 1gb
+  - startNode: observer
+    type: observer
+  - waitFor: 10s
+`
+
+func exportTestProject(t *testing.T) (string, composeProject) {
+	t.Helper()
+	composeClientVersions = func(context.Context, []string) ([]string, error) {
+		return []string{"2.2.0"}, nil
+	}
+	t.Cleanup(func() { composeClientVersions = docker.ClientVersions })
+
+	scenario, err := parser.Parse(strings.NewReader(exportTestScenario))
+	require.NoError(t, err)
+	require.NoError(t, scenario.Check())
+
+	dir := filepath.Join(t.TempDir(), "project")
+	require.NoError(t, writeComposeProject(
+		context.Background(), &scenario, dir, netip.MustParsePrefix("10.1.0.0/24")))
+
+	data, err := os.ReadFile(filepath.Join(dir, composeFileName))
+	require.NoError(t, err)
+	var project composeProject
+	require.NoError(t, yaml.Unmarshal(data, &project))
+	return dir, project
+}
+
+func TestWriteComposeProject_ExportsTheGenesisValidators(t *testing.T) {
+	dir, project := exportTestProject(t)
+
+	require.FileExists(t, filepath.Join(dir, "genesis.json"))
+	require.Len(t, project.Services, 3, "only the genesis validators are exported")
+	for i, label := range []string{"validator-0", "validator-1", "validator-2"} {
+		service, found := project.Services[label]
+		require.True(t, found, "missing service %s", label)
+		require.Equal(t, "sonic:local", service.Image)
+		require.Equal(t, 1.5, service.Cpus)
+		require.Contains(t, service.Volumes, "./genesis.json:/genesis.json:ro")
+		require.Contains(t, service.Volumes, "./"+label+"/keystore:/datadir/keystore:ro")
+
+		ip := netip.MustParseAddr("10.1.0.2")
+		for range i {
+			ip = ip.Next()
+		}
+		require.Equal(t, ip.String(), service.Networks[composeNetworkName].Ipv4Address)
+		require.Contains(t, service.Entrypoint[2], "--nat extip:"+ip.String())
+	}
+	require.Equal(t, "10.1.0.0/24", project.Networks[composeNetworkName].Ipam.Config[0].Subnet)
+}
+
+func TestWriteComposeProject_PeersAndShapesAsTheScenarioSays(t *testing.T) {
+	_, project := exportTestProject(t)
+
+	staticNodes := func(label string) string {
+		for _, line := range strings.Split(project.Services[label].Entrypoint[2], "\n") {
+			if strings.HasPrefix(line, "StaticNodes") {
+				return line
+			}
+		}
+		return ""
+	}
+	// In a line, the middle node is peered with both others, which are not
+	// peered with each other.
+	require.Equal(t, 2, strings.Count(staticNodes("validator-1"), "enode://"))
+	require.Contains(t, staticNodes("validator-0"), "@10.1.0.3:5050")
+	require.Contains(t, staticNodes("validator-2"), "@10.1.0.3:5050")
+	require.NotContains(t, staticNodes("validator-0"), "@10.1.0.4:5050")
+
+	require.Contains(t, project.Services["validator-0"].Entrypoint[2], "match ip dst 10.1.0.3/32")
+	require.Contains(t, project.Services["validator-1"].Entrypoint[2], "match ip dst 10.1.0.2/32")
+	require.NotContains(t, project.Services["validator-2"].Entrypoint[2], "tc ")
+}
+
+func TestWriteComposeProject_KeepsTheMemorySettingsOfTheClients(t *testing.T) {
+	_, project := exportTestProject(t)
+
+	for _, service := range project.Services {
+		require.Contains(t, service.Entrypoint[2], "--statedb.livecache 33554432")
+		require.Contains(t, service.Entrypoint[2], "--cache 64")
+		require.Equal(t, "1073741824", service.Environment["GOMEMLIMIT"])
+	}
+}
+
+func TestWriteComposeProject_RejectsTooSmallSubnets(t *testing.T) {
+	scenario, err := parser.Parse(strings.NewReader(exportTestScenario))
+	require.NoError(t, err)
+	composeClientVersions = func(context.Context, []string) ([]string, error) {
+		return []string{"2.2.0"}, nil
+	}
+	t.Cleanup(func() { composeClientVersions = docker.ClientVersions })
+
+	err = writeComposeProject(context.Background(), &scenario,
+		t.TempDir(), netip.MustParsePrefix("10.1.0.0/30"))
+	require.ErrorContains(t, err, "too small")
+}
+
+func TestEscapeComposeInterpolation_DoublesDollarSigns(t *testing.T) {
+	require.Equal(t, []string{"sh", "echo $$HOME $${X}"},
+		escapeComposeInterpolation([]string{"sh", "echo $HOME ${X}"}))
+}
@@ -45,6 +45,7 @@ func main() {
 			&purgeCommand,
 			&renderCommand,
 			&diffCommand,
+			&exportComposeCommand,
 			&scenarioHelpCommand,
 		},
 		Before: globalflags.ProcessGlobalFlags,
@@ -14,7 +14,6 @@ import (
 	"github.com/0xsoniclabs/norma/driver/monitoring"
 	netmon "github.com/0xsoniclabs/norma/driver/monitoring/network"
 	nodemon "github.com/0xsoniclabs/norma/driver/monitoring/node"
-	"github.com/0xsoniclabs/norma/driver/network/local"
 	"golang.org/x/exp/constraints"
 )
 
@@ -27,7 +26,7 @@ type progressLogger struct {
 }
 
 // startProgressLogger starts a progress logger that logs the progress of the network.
-func startProgressLogger(monitor *monitoring.Monitor, net *local.LocalNetwork) *progressLogger {
+func startProgressLogger(monitor *monitoring.Monitor, net driver.Network) *progressLogger {
 	stop := make(chan bool)
 	done := make(chan bool)
 
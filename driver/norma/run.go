@@ -38,9 +38,13 @@ import (
 	_ "github.com/0xsoniclabs/norma/driver/monitoring/app"
 	_ "github.com/0xsoniclabs/norma/driver/monitoring/transactions"
 	_ "github.com/0xsoniclabs/norma/driver/monitoring/user"
+	"github.com/0xsoniclabs/norma/driver/network/external"
 	"github.com/0xsoniclabs/norma/driver/network/local"
+	"github.com/0xsoniclabs/norma/driver/network/process"
+	"github.com/0xsoniclabs/norma/driver/network/sim"
 	"github.com/0xsoniclabs/norma/driver/parser"
 	"github.com/urfave/cli/v2"
+	"gopkg.in/yaml.v3"
 )
 
 // Run with `go run ./driver/norma run <scenario.yml>`
@@ -55,6 +59,14 @@ var runCommand = cli.Command{
 		&skipReportRendering,
 		&outputDirectory,
 		&openReport,
+		&backend,
+		&binariesDirectory,
+		&rpcUrls,
+		&wsUrls,
+		&treasuryKey,
+		&fromSnapshot,
+		&parameterValues,
+		&scenarioTimeout,
 	},
 }
 
@@ -82,8 +94,60 @@ var (
 		Name:  "open-report",
 		Usage: "automatically open the rendered report in the default browser after rendering",
 	}
+	backend = cli.StringFlag{
+		Name:  "backend",
+		Usage: "the backend running the nodes: \"docker\" runs each in a container, \"process\" runs the client binaries on the host, \"sim\" simulates the network in memory, \"external\" attaches to a network already running elsewhere",
+		Value: dockerBackend,
+	}
+	binariesDirectory = cli.StringFlag{
+		Name:  "binaries",
+		Usage: "directory holding the sonicd and sonictool binaries run by the process backend. If empty, they are looked up on the PATH.",
+		Value: "",
+	}
+	rpcUrls = cli.StringSliceFlag{
+		Name:  "rpc-url",
+		Usage: "RPC URL of a node of the network the external backend attaches to. Repeat for every node to use.",
+	}
+	wsUrls = cli.StringSliceFlag{
+		Name:  "ws-url",
+		Usage: "websocket URL of a node of the network the external backend attaches to, for the node of the --rpc-url in the same position, if any. Repeat for every node to use.",
+	}
+	treasuryKey = cli.StringFlag{
+		Name:    "treasury-key",
+		Usage:   "hex encoded private key of a funded account paying for the load the external backend sends, which also has to own the DriverAuth contract for updateRules and advanceEpoch steps",
+		EnvVars: []string{"NORMA_TREASURY_KEY"},
+	}
+	fromSnapshot = cli.StringFlag{
+		Name:  "from-snapshot",
+		Usage: "directory of a snapshot taken by a snapshot step of the scenario, for instance <output-dir>/snapshots/<name>. The network is restored from it and the scenario continues with the steps after the snapshot step.",
+		Value: "",
+	}
+	parameterValues = cli.StringSliceFlag{
+		Name:  "set",
+		Usage: "value of a parameter of the scenario, as <name>=<value>, replacing its default. Repeat for every parameter to set. Only valid for a single scenario.",
+	}
+	scenarioTimeout = cli.DurationFlag{
+		Name:  "timeout",
+		Usage: "time the scenario may run before it is aborted, replacing the Timeout of the scenario, if any. If zero, the one of the scenario or the default of 10 minutes is used.",
+	}
 )
 
+// The backends a network can be run on.
+const (
+	dockerBackend   = "docker"
+	processBackend  = "process"
+	simBackend      = "sim"
+	externalBackend = "external"
+)
+
+// backendConfig selects the backend a network runs on, along with the
+// settings specific to it.
+type backendConfig struct {
+	name        string
+	binariesDir string
+	external    external.Config
+}
+
 func run(ctx *cli.Context) (err error) {
 	args := ctx.Args()
 	if args.Len() < 1 {
@@ -94,6 +158,39 @@ func run(ctx *cli.Context) (err error) {
 	skipChecks := ctx.Bool(skipChecks.Name)
 	skipReportRendering := ctx.Bool(skipReportRendering.Name)
 	openReport := ctx.Bool(openReport.Name)
+	backend := backendConfig{
+		name:        ctx.String(backend.Name),
+		binariesDir: ctx.String(binariesDirectory.Name),
+	}
+	switch backend.name {
+	case dockerBackend, processBackend, simBackend:
+	case externalBackend:
+		endpoints, err := externalEndpoints(ctx.StringSlice(rpcUrls.Name), ctx.StringSlice(wsUrls.Name))
+		if err != nil {
+			return err
+		}
+		backend.external = external.Config{
+			Endpoints:   endpoints,
+			TreasuryKey: ctx.String(treasuryKey.Name),
+		}
+	default:
+		return fmt.Errorf("unknown backend %q, expected %q, %q, %q or %q",
+			backend.name, dockerBackend, processBackend, simBackend, externalBackend)
+	}
+	snapshotDir := ctx.String(fromSnapshot.Name)
+	if snapshotDir != "" && backend.name != dockerBackend {
+		return fmt.Errorf("--%s requires the %q backend", fromSnapshot.Name, dockerBackend)
+	}
+
+	parameters, err := parseParameterValues(ctx.StringSlice(parameterValues.Name))
+	if err != nil {
+		return err
+	}
+
+	timeout := ctx.Duration(scenarioTimeout.Name)
+	if timeout < 0 {
+		return fmt.Errorf("--%s must not be negative, got %v", scenarioTimeout.Name, timeout)
+	}
 
 	path := args.First()
 
@@ -101,6 +198,14 @@ func run(ctx *cli.Context) (err error) {
 	if err != nil {
 		return fmt.Errorf("failed to collect scenario files: %w", err)
 	}
+	if snapshotDir != "" && len(files) != 1 {
+		return fmt.Errorf("--%s requires a single scenario, got %d", fromSnapshot.Name, len(files))
+	}
+	if len(parameters) > 0 && len(files) != 1 {
+		// The scenarios of a directory declare parameters of their own, so
+		// a value set for all of them would fit some and not others.
+		return fmt.Errorf("--%s requires a single scenario, got %d", parameterValues.Name, len(files))
+	}
 
 	// When the argument is a directory, scenarios are collected recursively
 	// from its subfolders. Print the folder name whenever execution moves
@@ -123,7 +228,7 @@ func run(ctx *cli.Context) (err error) {
 		if label == "" {
 			label = fmt.Sprintf("eval_%d", time.Now().Unix())
 		}
-		if err := runScenario(ctx.Context, file, outputDir, label, skipChecks, skipReportRendering, openReport); err != nil {
+		if err := runScenario(ctx.Context, file, parameters, timeout, outputDir, snapshotDir, label, backend, skipChecks, skipReportRendering, openReport); err != nil {
 			return fmt.Errorf("failed to run scenario %q: %w", file, err)
 		}
 	}
@@ -131,7 +236,7 @@ func run(ctx *cli.Context) (err error) {
 	return nil
 }
 
-func runScenario(ctx context.Context, path, outputDir, label string, skipChecks, skipReportRendering, openReport bool) error {
+func runScenario(ctx context.Context, path string, parameters map[string]string, timeout time.Duration, outputDir, snapshotDir, label string, backend backendConfig, skipChecks, skipReportRendering, openReport bool) error {
 	ctx, cancel := context.WithCancel(ctx)
 	defer cancel()
 
@@ -142,10 +247,13 @@ func runScenario(ctx context.Context, path, outputDir, label string, skipChecks,
 	}
 
 	slog.Info("reading scenario file", "path", path)
-	parsed, err := parser.ParseFile(path)
+	parsed, err := parser.ParseFileWithParameters(path, parameters)
 	if err != nil {
 		return fmt.Errorf("failed to parse scenario file: %w", err)
 	}
+	if timeout > 0 {
+		parsed.Timeout = timeout
+	}
 	if err := parsed.Check(); err != nil {
 		return err
 	}
@@ -180,20 +288,42 @@ func runScenario(ctx context.Context, path, outputDir, label string, skipChecks,
 	if err := os.WriteFile(filepath.Join(outputDir, filepath.Base(path)), data, 0644); err != nil {
 		return err
 	}
+	// and the values its parameters were resolved to next to it.
+	if len(scenario.Parameters) > 0 {
+		data, err := yaml.Marshal(scenario.Parameters)
+		if err != nil {
+			return err
+		}
+		if err := os.WriteFile(filepath.Join(outputDir, parametersFile), data, 0644); err != nil {
+			return err
+		}
+	}
 
 	// Log initial rules.
 	fmt.Println(scenario.InitialRules.PrettyPrint()) // multi line print
 
 	// Startup network. Genesis is configured from the first startNode step,
 	// which must be a validator. The step is NOT removed — nodes are started
-	// explicitly by the runner so they appear in the report timeline.
-	validators, genesisIds, err := extractBootstrapValidators(scenario)
-	if err != nil {
+	// explicitly by the runner so they appear in the report timeline. An
+	// external network is up already, and the scenario only acts on it.
+	// Steps the backend cannot run are reported before anything is started.
+	if err := checkScenario(backend, scenario); err != nil {
 		return err
 	}
-	net, err := local.NewLocalNetwork(ctx, &driver.NetworkConfig{
+	var validators driver.Validators
+	var genesisIds map[string]int
+	if backend.name != externalBackend {
+		validators, genesisIds, err = extractBootstrapValidators(scenario)
+		if err != nil {
+			return err
+		}
+	}
+	net, err := newNetwork(ctx, backend, &driver.NetworkConfig{
 		Validators:   validators,
+		Links:        executor.NetworkLinks(scenario),
+		Topology:     executor.NetworkTopology(scenario),
 		NetworkRules: scenario.InitialRules,
+		InitialState: scenario.InitialState,
 		OutputDir:    outputDir,
 		ClientImages: collectClientImages(scenario),
 	})
@@ -228,6 +358,9 @@ func runScenario(ctx context.Context, path, outputDir, label string, skipChecks,
 		); err != nil {
 			slog.Warn("failed to export scenario step execution timings", "error", err)
 		}
+		if err := writeExpectedErrors(filepath.Join(outputDir, expectedErrorsFile), stepExecutions); err != nil {
+			slog.Warn("failed to export the errors of steps failing as expected", "error", err)
+		}
 		slog.Info("monitoring data was written", "output", outputDir)
 		slog.Info("raw data was exported", "file", monitor.GetMeasurementFileName())
 
@@ -269,13 +402,26 @@ func runScenario(ctx context.Context, path, outputDir, label string, skipChecks,
 	slog.Info("running scenario", "path", path)
 	logger := startProgressLogger(monitor, net)
 	defer logger.shutdown()
-	stepExecutions, err = executor.RunAndCaptureEventExecution(
-		ctx,
-		net,
-		scenario,
-		checks,
-		genesisIds,
-	)
+	if snapshotDir != "" {
+		slog.Info("restoring snapshot", "dir", snapshotDir)
+		stepExecutions, err = executor.RestoreAndCaptureEventExecution(
+			ctx,
+			net,
+			scenario,
+			checks,
+			snapshotDir,
+			outputDir,
+		)
+	} else {
+		stepExecutions, err = executor.RunAndCaptureEventExecution(
+			ctx,
+			net,
+			scenario,
+			checks,
+			genesisIds,
+			outputDir,
+		)
+	}
 	if err != nil {
 		dumpNodeLogs(ctx, net)
 		return err
@@ -332,17 +478,121 @@ func extractBootstrapValidators(scenario *parser.Scenario) (driver.Validators, m
 	return validators, genesisIds, nil
 }
 
+// newNetwork creates an empty network on the given backend, or attaches to
+// the running one of the external backend.
+func newNetwork(ctx context.Context, backend backendConfig, config *driver.NetworkConfig) (driver.Network, error) {
+	switch backend.name {
+	case processBackend:
+		binaries, err := process.FindBinaries(backend.binariesDir)
+		if err != nil {
+			return nil, err
+		}
+		return process.NewProcessNetwork(ctx, config, binaries)
+	case simBackend:
+		return sim.NewSimNetwork(ctx, config)
+	case externalBackend:
+		return external.NewExternalNetwork(ctx, config, backend.external)
+	}
+	return local.NewLocalNetwork(ctx, config)
+}
+
+// checkScenario reports the steps of the given scenario the network of the
+// given backend cannot run.
+func checkScenario(backend backendConfig, scenario *parser.Scenario) error {
+	switch backend.name {
+	case processBackend:
+		return process.CheckScenario(scenario)
+	case simBackend:
+		return sim.CheckScenario(scenario)
+	case externalBackend:
+		return external.CheckScenario(scenario)
+	}
+	return nil
+}
+
+// parametersFile is the file in the output directory of a run listing the
+// values of the parameters of the scenario.
+const parametersFile = "parameters.yml"
+
+// expectedErrorsFile is the file in the output directory of a run listing
+// the errors the steps expected to fail with failed with.
+const expectedErrorsFile = "expected_errors.yml"
+
+// writeExpectedErrors writes the errors of the steps that failed as
+// expected among the given events to the given file, if there are any.
+func writeExpectedErrors(path string, events []executor.EventExecution) error {
+	type expectedError struct {
+		Step  string `yaml:"step"`
+		Error string `yaml:"error"`
+	}
+	var observed []expectedError
+	for _, e := range events {
+		if e.Error != "" {
+			observed = append(observed, expectedError{Step: e.Name, Error: e.Error})
+		}
+	}
+	if len(observed) == 0 {
+		return nil
+	}
+	data, err := yaml.Marshal(observed)
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, data, 0644)
+}
+
+// parseParameterValues parses the values of scenario parameters given as
+// <name>=<value> into a map from names to values.
+func parseParameterValues(assignments []string) (map[string]string, error) {
+	values := make(map[string]string, len(assignments))
+	for _, assignment := range assignments {
+		name, value, found := strings.Cut(assignment, "=")
+		if !found || name == "" {
+			return nil, fmt.Errorf("invalid parameter value %q, expected <name>=<value>", assignment)
+		}
+		if _, duplicate := values[name]; duplicate {
+			return nil, fmt.Errorf("parameter %s is set more than once", name)
+		}
+		values[name] = value
+	}
+	return values, nil
+}
+
+// externalEndpoints pairs the given RPC and websocket URLs by position into
+// the endpoints of an external network. Either list may be empty, but if
+// both are given, they have to be of the same length.
+func externalEndpoints(rpc, ws []string) ([]external.Endpoint, error) {
+	if len(rpc) == 0 && len(ws) == 0 {
+		return nil, fmt.Errorf("the %q backend requires --%s or --%s", externalBackend, rpcUrls.Name, wsUrls.Name)
+	}
+	if len(rpc) > 0 && len(ws) > 0 && len(rpc) != len(ws) {
+		return nil, fmt.Errorf("got %d RPC URLs but %d websocket URLs; give one of each per node", len(rpc), len(ws))
+	}
+	endpoints := make([]external.Endpoint, max(len(rpc), len(ws)))
+	for i := range endpoints {
+		if i < len(rpc) {
+			endpoints[i].RpcUrl = rpc[i]
+		}
+		if i < len(ws) {
+			endpoints[i].WsUrl = ws[i]
+		}
+	}
+	return endpoints, nil
+}
+
 // collectClientImages returns the client image of every node the scenario
-// starts, resolved the way node startup resolves it. The genesis file is
-// generated before the first node runs, and its version-dependent parts have to
-// suit every client that reads it, including the ones started later.
+// starts or upgrades to, resolved the way node startup resolves it. The genesis
+// file is generated before the first node runs, and its version-dependent parts
+// have to suit every client that reads it, including the ones started later.
 func collectClientImages(scenario *parser.Scenario) []string {
 	images := make([]string, 0, len(scenario.Steps))
 	for _, step := range scenario.Steps {
-		if step.Function != parser.FuncStartNode {
-			continue
+		for _, step := range step.Steps() {
+			if step.Function != parser.FuncStartNode && step.Function != parser.FuncUpgradeNode {
+				continue
+			}
+			images = append(images, driver.ResolveClientImageName(step.ImageName))
 		}
-		images = append(images, driver.ResolveClientImageName(step.ImageName))
 	}
 	return images
 }
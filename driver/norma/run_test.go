@@ -19,10 +19,14 @@ package main
 import (
 	"context"
 	"io"
+	"os"
+	"path/filepath"
 	"testing"
 	"time"
 
 	"github.com/0xsoniclabs/norma/driver"
+	"github.com/0xsoniclabs/norma/driver/executor"
+	"github.com/0xsoniclabs/norma/driver/network/external"
 	"github.com/0xsoniclabs/norma/driver/parser"
 	"github.com/stretchr/testify/require"
 	"go.uber.org/mock/gomock"
@@ -38,6 +42,7 @@ func TestCollectClientImages_CoversEveryNodeOfTheScenario(t *testing.T) {
 			{Function: parser.FuncStartNode, Identifier: "validator-v216", ImageName: "sonic:v2.1.6"},
 			{Function: parser.FuncWaitFor},
 			{Function: parser.FuncStartNode, Identifier: "rpc-v220", ImageName: "sonic:v2.2.0"},
+			{Function: parser.FuncUpgradeNode, Identifier: "validator-v216", ImageName: "sonic:v2.3.0"},
 		},
 	}
 
@@ -45,9 +50,62 @@ func TestCollectClientImages_CoversEveryNodeOfTheScenario(t *testing.T) {
 		driver.DefaultClientDockerImageName,
 		"sonic:v2.1.6",
 		"sonic:v2.2.0",
+		"sonic:v2.3.0",
 	}, collectClientImages(scenario))
 }
 
+func TestExternalEndpoints_PairsUrlsByPosition(t *testing.T) {
+	endpoints, err := externalEndpoints(
+		[]string{"http://a:8545", "http://b:8545"},
+		[]string{"ws://a:8546", "ws://b:8546"},
+	)
+	require.NoError(t, err)
+	require.Equal(t, []external.Endpoint{
+		{RpcUrl: "http://a:8545", WsUrl: "ws://a:8546"},
+		{RpcUrl: "http://b:8545", WsUrl: "ws://b:8546"},
+	}, endpoints)
+
+	endpoints, err = externalEndpoints(nil, []string{"ws://a:8546"})
+	require.NoError(t, err)
+	require.Equal(t, []external.Endpoint{{WsUrl: "ws://a:8546"}}, endpoints)
+
+	_, err = externalEndpoints(nil, nil)
+	require.ErrorContains(t, err, "requires --rpc-url or --ws-url")
+	_, err = externalEndpoints([]string{"http://a:8545", "http://b:8545"}, []string{"ws://a:8546"})
+	require.ErrorContains(t, err, "got 2 RPC URLs but 1 websocket URLs")
+}
+
+func TestParseParameterValues_SplitsNamesFromValues(t *testing.T) {
+	values, err := parseParameterValues([]string{"users=200", "image=sonic:v2.2.0", "label="})
+	require.NoError(t, err)
+	require.Equal(t, map[string]string{"users": "200", "image": "sonic:v2.2.0", "label": ""}, values)
+
+	_, err = parseParameterValues([]string{"users"})
+	require.ErrorContains(t, err, `invalid parameter value "users"`)
+	_, err = parseParameterValues([]string{"=1"})
+	require.ErrorContains(t, err, `invalid parameter value "=1"`)
+	_, err = parseParameterValues([]string{"users=1", "users=2"})
+	require.ErrorContains(t, err, "parameter users is set more than once")
+}
+
+func TestWriteExpectedErrors_ListsStepsFailingAsExpected(t *testing.T) {
+	path := filepath.Join(t.TempDir(), expectedErrorsFile)
+	events := []executor.EventExecution{
+		{Name: "1: startNode A"},
+		{Name: "2: delegate", Error: "stake too low"},
+	}
+	require.NoError(t, writeExpectedErrors(path, events))
+
+	data, err := os.ReadFile(path)
+	require.NoError(t, err)
+	require.Equal(t, "- step: '2: delegate'\n  error: stake too low\n", string(data))
+
+	// Without steps failing as expected, no file is written.
+	path = filepath.Join(t.TempDir(), expectedErrorsFile)
+	require.NoError(t, writeExpectedErrors(path, events[:1]))
+	require.NoFileExists(t, path)
+}
+
 // TestDumpNodeLogs_BoundedForRunningNode is a regression test: dumpNodeLogs must
 // return even when a node's log stream never reaches EOF.
 func TestDumpNodeLogs_BoundedForRunningNode(t *testing.T) {
@@ -0,0 +1,103 @@
+// Copyright 2024 Fantom Foundation
+// This file is part of Norma System Testing Infrastructure for Sonic.
+//
+// Norma is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// Norma is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with Norma. If not, see <http://www.gnu.org/licenses/>.
+
+package parser
+
+import (
+	"fmt"
+	"math"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// Bandwidth is a data rate in bits per second. In a scenario it is written
+// with one of the units of tc: bit, kbit, mbit, gbit or tbit, with decimal
+// prefixes, e.g. "10mbit".
+type Bandwidth uint64
+
+// ByteSize is an amount of data in bytes. In a scenario it is written as a
+// plain number of bytes or with one of the units b, kb, mb or gb, with binary
+// prefixes as in tc, e.g. "32kb".
+type ByteSize uint64
+
+// minThrottleBurst is the smallest burst a throttled node may be given. It
+// exceeds a full-sized ethernet frame, which must always fit in a burst.
+const minThrottleBurst = 2 << 10
+
+var (
+	bandwidthPattern = regexp.MustCompile(`^([0-9]+(?:\.[0-9]+)?)(bit|kbit|mbit|gbit|tbit)$`)
+	byteSizePattern  = regexp.MustCompile(`^([0-9]+)(b|kb|mb|gb)?$`)
+)
+
+var bandwidthUnits = map[string]float64{
+	"bit":  1,
+	"kbit": 1e3,
+	"mbit": 1e6,
+	"gbit": 1e9,
+	"tbit": 1e12,
+}
+
+var byteSizeUnits = map[string]uint64{
+	"":   1,
+	"b":  1,
+	"kb": 1 << 10,
+	"mb": 1 << 20,
+	"gb": 1 << 30,
+}
+
+// ParseBandwidth parses a data rate like "10mbit". The rate must be positive.
+func ParseBandwidth(s string) (Bandwidth, error) {
+	match := bandwidthPattern.FindStringSubmatch(strings.ToLower(s))
+	if match == nil {
+		return 0, fmt.Errorf("invalid bandwidth %q, expected a number followed by bit, kbit, mbit, gbit or tbit", s)
+	}
+	value, err := strconv.ParseFloat(match[1], 64)
+	if err != nil {
+		return 0, fmt.Errorf("invalid bandwidth %q: %w", s, err)
+	}
+	bits := math.Round(value * bandwidthUnits[match[2]])
+	if bits < 1 || bits > math.MaxInt64 {
+		return 0, fmt.Errorf("bandwidth %q is out of range", s)
+	}
+	return Bandwidth(bits), nil
+}
+
+// ParseByteSize parses an amount of data like "32kb". The amount must be
+// positive.
+func ParseByteSize(s string) (ByteSize, error) {
+	match := byteSizePattern.FindStringSubmatch(strings.ToLower(s))
+	if match == nil {
+		return 0, fmt.Errorf("invalid size %q, expected a number of bytes, optionally followed by b, kb, mb or gb", s)
+	}
+	value, err := strconv.ParseUint(match[1], 10, 64)
+	if err != nil {
+		return 0, fmt.Errorf("invalid size %q: %w", s, err)
+	}
+	unit := byteSizeUnits[match[2]]
+	if value == 0 || value > math.MaxInt64/unit {
+		return 0, fmt.Errorf("size %q is out of range", s)
+	}
+	return ByteSize(value * unit), nil
+}
+
+func (b Bandwidth) String() string {
+	return fmt.Sprintf("%dbit", uint64(b))
+}
+
+func (b ByteSize) String() string {
+	return fmt.Sprintf("%db", uint64(b))
+}
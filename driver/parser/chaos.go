@@ -0,0 +1,76 @@
+// Copyright 2024 Fantom Foundation
+// This file is part of Norma System Testing Infrastructure for Sonic.
+//
+// Norma is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// Norma is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with Norma. If not, see <http://www.gnu.org/licenses/>.
+
+package parser
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+)
+
+// Disruptions a chaos step chooses from.
+const (
+	// ChaosKillHeal kills the client of a node, heals its database and
+	// restarts it.
+	ChaosKillHeal = "killHeal"
+	// ChaosRestart stops the client of a node gracefully and restarts it.
+	ChaosRestart = "restart"
+	// ChaosPause freezes a node and resumes it.
+	ChaosPause = "pause"
+	// ChaosPartition cuts a node off from all other nodes and heals the cut.
+	ChaosPartition = "partition"
+)
+
+// AllChaosActions lists every disruption a chaos step may choose from.
+var AllChaosActions = []string{ChaosKillHeal, ChaosRestart, ChaosPause, ChaosPartition}
+
+// chaosActions returns the disruptions a chaos step may choose from, which
+// are all of them if the step lists none.
+func (s *Step) chaosActions() []string {
+	if len(s.ChaosActions) == 0 {
+		return AllChaosActions
+	}
+	return s.ChaosActions
+}
+
+func (s *Step) checkChaos() error {
+	errs := []error{}
+
+	if s.Duration <= 0 {
+		errs = append(errs, fmt.Errorf("chaos requires a positive duration, got %v", s.Duration))
+	}
+	if len(s.Targets) == 0 {
+		errs = append(errs, fmt.Errorf("chaos requires at least one target"))
+	}
+	for _, name := range s.Targets {
+		if !NamePattern.Match([]byte(name)) {
+			errs = append(errs, fmt.Errorf("target name must match %v, got %v", namePatternStr, name))
+		}
+	}
+	for i, action := range s.ChaosActions {
+		if !slices.Contains(AllChaosActions, action) {
+			errs = append(errs, fmt.Errorf("unknown chaos action %q, expected one of %v", action, AllChaosActions))
+		} else if slices.Contains(s.ChaosActions[:i], action) {
+			errs = append(errs, fmt.Errorf("chaos action %v is listed twice", action))
+		}
+	}
+	if s.MaxAffected != nil && *s.MaxAffected < 1 {
+		errs = append(errs, fmt.Errorf("maxAffected must be >= 1, got %d", *s.MaxAffected))
+	}
+
+	return errors.Join(errs...)
+}
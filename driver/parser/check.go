@@ -20,6 +20,8 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
+	"maps"
+	"slices"
 	"strings"
 
 	"github.com/0xsoniclabs/norma/genesis"
@@ -36,16 +38,131 @@ func (s *Scenario) Check() error {
 	if strings.TrimSpace(s.Description) == "" {
 		errs = append(errs, fmt.Errorf("scenario description must not be empty"))
 	}
+	if s.Timeout < 0 {
+		errs = append(errs, fmt.Errorf("scenario timeout must be positive, got %v", s.Timeout))
+	}
 	// Validate initial network rules.
 	if err := genesis.ValidateNetworkRulesPatch(s.InitialRules); err != nil {
 		errs = append(errs, fmt.Errorf("invalid initial network rules: %w", err))
 	}
+	if s.InitialState != nil {
+		if err := genesis.ValidateInitialState(*s.InitialState); err != nil {
+			errs = append(errs, fmt.Errorf("invalid initial state: %w", err))
+		}
+	}
+
+	errs = append(errs, s.checkRegions()...)
+	errs = append(errs, s.checkPacking()...)
+	errs = append(errs, s.checkSchedules()...)
+	for i, link := range s.Links {
+		if err := link.Check(); err != nil {
+			errs = append(errs, fmt.Errorf("link %d: %w", i+1, err))
+		}
+	}
+	if err := s.Topology.Check(); err != nil {
+		errs = append(errs, fmt.Errorf("invalid topology: %w", err))
+	}
 
 	// Validate each step.
+	partitioned := false
+	throttled := map[string]bool{}
+	paused := map[string]bool{}
+	runningApps := map[string]bool{}
+	snapshots := map[string]bool{}
 	for i, step := range s.Steps {
 		if err := step.Check(); err != nil {
 			errs = append(errs, fmt.Errorf("step %d (%s): %w", i+1, step.Function, err))
 		}
+
+		// The branches of a parallel step act on different nodes, so they
+		// are tracked as if they ran one after the other, as are the steps
+		// of a schedule step, which no later step acts on.
+		for _, step := range step.Steps() {
+			// Partitions do not nest: each one has to be healed before the
+			// next one is set up.
+			switch step.Function {
+			case FuncPartition:
+				if partitioned {
+					errs = append(errs, fmt.Errorf("step %d (%s): network is already partitioned; heal it first", i+1, step.Function))
+				}
+				partitioned = true
+			case FuncHeal:
+				if !partitioned {
+					errs = append(errs, fmt.Errorf("step %d (%s): no preceding partition to heal", i+1, step.Function))
+				}
+				partitioned = false
+			case FuncThrottleNode:
+				throttled[step.Identifier] = true
+			case FuncUnthrottleNode:
+				if !throttled[step.Identifier] {
+					errs = append(errs, fmt.Errorf("step %d (%s): node %v is not throttled", i+1, step.Function, step.Identifier))
+				}
+				delete(throttled, step.Identifier)
+			case FuncPauseNode:
+				if paused[step.Identifier] {
+					errs = append(errs, fmt.Errorf("step %d (%s): node %v is already paused", i+1, step.Function, step.Identifier))
+				}
+				paused[step.Identifier] = true
+			case FuncResumeNode:
+				if !paused[step.Identifier] {
+					errs = append(errs, fmt.Errorf("step %d (%s): node %v is not paused", i+1, step.Function, step.Identifier))
+				}
+				delete(paused, step.Identifier)
+			case FuncStopNode:
+				// A stopped node is started afresh, neither paused nor
+				// throttled.
+				delete(paused, step.Identifier)
+				delete(throttled, step.Identifier)
+			case FuncChaos:
+				// Chaos partitions the network itself, which it can neither do
+				// while it is partitioned nor with nodes frozen, which could not
+				// take part in the cut.
+				if slices.Contains(step.chaosActions(), ChaosPartition) && (partitioned || len(paused) > 0) {
+					errs = append(errs, fmt.Errorf("step %d (%s): partition action requires a network that is neither partitioned nor has paused nodes", i+1, step.Function))
+				}
+			case FuncUpgradeNode:
+				// The replacement starts without the cut, limits or freeze
+				// the replaced node was under, silently lifting them.
+				if partitioned {
+					errs = append(errs, fmt.Errorf("step %d (%s): network is partitioned; heal it first", i+1, step.Function))
+				}
+				if throttled[step.Identifier] {
+					errs = append(errs, fmt.Errorf("step %d (%s): node %v is throttled; unthrottle it first", i+1, step.Function, step.Identifier))
+				}
+				if paused[step.Identifier] {
+					errs = append(errs, fmt.Errorf("step %d (%s): node %v is paused; resume it first", i+1, step.Function, step.Identifier))
+				}
+			case FuncRunApp:
+				runningApps[step.Identifier] = true
+			case FuncStopApp:
+				delete(runningApps, step.Identifier)
+			case FuncSnapshot:
+				// A snapshot holds the data directories and the bookkeeping of
+				// Norma only, so whatever a restore could not bring back must
+				// not be in effect.
+				if snapshots[step.Identifier] {
+					errs = append(errs, fmt.Errorf("step %d (%s): snapshot %v is taken more than once", i+1, step.Function, step.Identifier))
+				}
+				snapshots[step.Identifier] = true
+				if partitioned {
+					errs = append(errs, fmt.Errorf("step %d (%s): network is partitioned; heal it first", i+1, step.Function))
+				}
+				for _, node := range slices.Sorted(maps.Keys(throttled)) {
+					errs = append(errs, fmt.Errorf("step %d (%s): node %v is throttled; unthrottle it first", i+1, step.Function, node))
+				}
+				for _, node := range slices.Sorted(maps.Keys(paused)) {
+					errs = append(errs, fmt.Errorf("step %d (%s): node %v is paused; resume it first", i+1, step.Function, node))
+				}
+				for _, app := range slices.Sorted(maps.Keys(runningApps)) {
+					errs = append(errs, fmt.Errorf("step %d (%s): application %v is running; stop it first", i+1, step.Function, app))
+				}
+			case FuncConnect, FuncDisconnect:
+				// In a full mesh, discovery would undo whatever the step did.
+				if s.Topology.IsFullMesh() {
+					errs = append(errs, fmt.Errorf("step %d (%s): requires a Topology other than fullMesh", i+1, step.Function))
+				}
+			}
+		}
 	}
 
 	return errors.Join(errs...)
@@ -53,6 +170,12 @@ func (s *Scenario) Check() error {
 
 // Check validates semantic constraints on a single step.
 func (s *Step) Check() error {
+	if s.Failing && s.ExpectError != nil {
+		return fmt.Errorf("failing and expectError must not be combined")
+	}
+	if s.Trigger != nil {
+		return errTriggerOutsideSchedule
+	}
 	switch s.Function {
 	case FuncStartNode:
 		return s.checkStartNode()
@@ -108,21 +231,272 @@ func (s *Step) Check() error {
 		if s.Duration <= 0 {
 			return fmt.Errorf("waitFor requires a positive duration, got %v", s.Duration)
 		}
+		if s.Timeout > 0 && s.Timeout < s.Duration {
+			return fmt.Errorf("waitFor timeout %v is shorter than its duration %v", s.Timeout, s.Duration)
+		}
 		return nil
 	case FuncKillSonic, FuncHealDb:
 		if s.Identifier == "" {
 			return fmt.Errorf("%s requires a node identifier", s.Function)
 		}
 		return nil
-	case FuncAdvanceEpoch, FuncWaitForEpoch:
+	case FuncPartition:
+		return s.checkPartition()
+	case FuncSetLink:
+		if s.Link == nil {
+			return fmt.Errorf("setLink requires a link")
+		}
+		return s.Link.Check()
+	case FuncThrottleNode:
+		return s.checkThrottleNode()
+	case FuncSetClockSkew:
+		if !NamePattern.Match([]byte(s.Identifier)) {
+			return fmt.Errorf("node name must match %v, got %v", namePatternStr, s.Identifier)
+		}
+		if s.ClockOffset == nil {
+			return fmt.Errorf("setClockSkew requires an offset")
+		}
+		return nil
+	case FuncUpdateResources:
+		return s.checkUpdateResources()
+	case FuncConnect, FuncDisconnect:
+		return s.checkPeers()
+	case FuncChaos:
+		return s.checkChaos()
+	case FuncUpgradeNode:
+		if !NamePattern.Match([]byte(s.Identifier)) {
+			return fmt.Errorf("node name must match %v, got %v", namePatternStr, s.Identifier)
+		}
+		if s.ImageName == "" {
+			return fmt.Errorf("upgradeNode requires an imageName")
+		}
+		if s.Interval < 0 {
+			return fmt.Errorf("interval must not be negative, got %v", s.Interval)
+		}
+		return nil
+	case FuncSnapshot:
+		if !NamePattern.Match([]byte(s.Identifier)) {
+			return fmt.Errorf("snapshot name must match %v, got %v", namePatternStr, s.Identifier)
+		}
+		return nil
+	case FuncUnthrottleNode, FuncPauseNode, FuncResumeNode:
+		if !NamePattern.Match([]byte(s.Identifier)) {
+			return fmt.Errorf("node name must match %v, got %v", namePatternStr, s.Identifier)
+		}
+		return nil
+	case FuncAdvanceEpoch, FuncWaitForEpoch, FuncHeal:
 		return nil
 	case FuncChecks:
 		return s.checkSubChecks()
+	case FuncParallel:
+		return s.checkParallel()
+	case FuncSchedule:
+		return s.checkSchedule()
+	case FuncRepeat:
+		// Parse expands repeat steps, so only scenarios not parsed from
+		// YAML may contain one.
+		return fmt.Errorf("repeat steps must be expanded by Parse")
+	case FuncInclude:
+		return fmt.Errorf("include steps must be expanded by Parse")
 	default:
 		return fmt.Errorf("unknown function: %q", s.Function)
 	}
 }
 
+// checkRegions validates the region names and their members. A node may
+// only be part of one region, or the conditions of its links would depend on
+// which of its regions a link rule names.
+func (s *Scenario) checkRegions() []error {
+	errs := []error{}
+	regionOf := map[string]string{}
+	for _, region := range slices.Sorted(maps.Keys(s.Regions)) {
+		if !NamePattern.Match([]byte(region)) {
+			errs = append(errs, fmt.Errorf("region name must match %v, got %v", namePatternStr, region))
+		}
+		members := s.Regions[region]
+		if len(members) == 0 {
+			errs = append(errs, fmt.Errorf("region %v must not be empty", region))
+		}
+		for _, name := range members {
+			if !NamePattern.Match([]byte(name)) {
+				errs = append(errs, fmt.Errorf("region %v: node name must match %v, got %v", region, namePatternStr, name))
+				continue
+			}
+			if other, found := regionOf[name]; found {
+				errs = append(errs, fmt.Errorf("region %v: node %v is already part of region %v", region, name, other))
+				continue
+			}
+			regionOf[name] = region
+		}
+	}
+	return errs
+}
+
+// checkPacking rejects the steps that cannot act on a client sharing its
+// container with others. Throttling, freezing, limiting and upgrading work
+// on the whole container, and a network cut separates addresses, so they
+// would hit every client of the container alike.
+func (s *Scenario) checkPacking() []error {
+	packed := map[string]bool{}
+	for _, step := range s.Steps {
+		for _, step := range step.Steps() {
+			if step.Function == FuncStartNode && step.packsClients() {
+				packed[step.Identifier] = true
+			}
+		}
+	}
+	if len(packed) == 0 {
+		return nil
+	}
+	// Later steps name a node or one of its instances, "<node>-<i>".
+	isPacked := func(name string) bool {
+		if packed[name] {
+			return true
+		}
+		base, instance, found := cutLast(name, "-")
+		return found && isNumber(instance) && packed[base]
+	}
+	linksPacked := func(link *Link) bool {
+		for _, end := range link.Between {
+			if slices.ContainsFunc(s.ResolveLinkEnd(end), isPacked) {
+				return true
+			}
+		}
+		return false
+	}
+
+	errs := []error{}
+	for i := range s.Links {
+		if linksPacked(&s.Links[i]) {
+			errs = append(errs, fmt.Errorf("link %d: links cannot shape the traffic of packed clients", i+1))
+		}
+	}
+	for i, step := range s.Steps {
+		for _, step := range step.Steps() {
+			switch step.Function {
+			case FuncThrottleNode, FuncUnthrottleNode, FuncPauseNode, FuncResumeNode, FuncUpdateResources, FuncUpgradeNode:
+				if isPacked(step.Identifier) {
+					errs = append(errs, fmt.Errorf("step %d (%s): node %v shares its container with other clients", i+1, step.Function, step.Identifier))
+				}
+			case FuncPartition:
+				for _, group := range step.Groups {
+					if slices.ContainsFunc(group, isPacked) {
+						errs = append(errs, fmt.Errorf("step %d (%s): packed clients cannot be partitioned", i+1, step.Function))
+						break
+					}
+				}
+			case FuncSetLink:
+				if step.Link != nil && linksPacked(step.Link) {
+					errs = append(errs, fmt.Errorf("step %d (%s): links cannot shape the traffic of packed clients", i+1, step.Function))
+				}
+			case FuncChaos, FuncSnapshot:
+				errs = append(errs, fmt.Errorf("step %d (%s): not supported in scenarios packing clients into shared containers", i+1, step.Function))
+			}
+		}
+	}
+	return errs
+}
+
+// cutLast slices s around the last instance of sep.
+func cutLast(s, sep string) (before, after string, found bool) {
+	if i := strings.LastIndex(s, sep); i >= 0 {
+		return s[:i], s[i+len(sep):], true
+	}
+	return s, "", false
+}
+
+// isNumber reports whether s is a non-empty string of decimal digits.
+func isNumber(s string) bool {
+	return s != "" && strings.Trim(s, "0123456789") == ""
+}
+
+// Check validates a link of the Links section or of a setLink step.
+func (l *Link) Check() error {
+	errs := []error{}
+
+	if len(l.Between) != 2 {
+		errs = append(errs, fmt.Errorf("link requires exactly two ends in between, got %d", len(l.Between)))
+	}
+	for _, name := range l.Between {
+		if !NamePattern.Match([]byte(name)) {
+			errs = append(errs, fmt.Errorf("link end must match %v, got %v", namePatternStr, name))
+		}
+	}
+	if l.Latency < 0 {
+		errs = append(errs, fmt.Errorf("latency must be non-negative, got %v", l.Latency))
+	}
+	if l.Jitter < 0 {
+		errs = append(errs, fmt.Errorf("jitter must be non-negative, got %v", l.Jitter))
+	}
+	if l.Loss < 0 || l.Loss > 100 {
+		errs = append(errs, fmt.Errorf("loss must be a percentage between 0 and 100, got %v", l.Loss))
+	}
+	if l.Reorder < 0 || l.Reorder > 100 {
+		errs = append(errs, fmt.Errorf("reorder must be a percentage between 0 and 100, got %v", l.Reorder))
+	}
+	// Packets can only overtake each other if they are delayed.
+	if l.Reorder > 0 && l.Latency == 0 {
+		errs = append(errs, fmt.Errorf("reorder requires a latency"))
+	}
+
+	return errors.Join(errs...)
+}
+
+func (s *Step) checkThrottleNode() error {
+	errs := []error{}
+
+	if !NamePattern.Match([]byte(s.Identifier)) {
+		errs = append(errs, fmt.Errorf("node name must match %v, got %v", namePatternStr, s.Identifier))
+	}
+	if s.Egress == 0 && s.Ingress == 0 {
+		errs = append(errs, fmt.Errorf("throttleNode requires an egress or ingress rate"))
+	}
+	// A burst smaller than a full-sized frame would stall the node.
+	if s.Burst != 0 && s.Burst < minThrottleBurst {
+		errs = append(errs, fmt.Errorf("burst must be at least %v, got %v", ByteSize(minThrottleBurst), s.Burst))
+	}
+
+	return errors.Join(errs...)
+}
+
+func (s *Step) checkUpdateResources() error {
+	errs := []error{}
+
+	if !NamePattern.Match([]byte(s.Identifier)) {
+		errs = append(errs, fmt.Errorf("node name must match %v, got %v", namePatternStr, s.Identifier))
+	}
+	if s.CPUs == 0 && s.Memory == 0 && s.BlkioWeight == 0 {
+		errs = append(errs, fmt.Errorf("updateResources requires cpus, memory or blkioWeight"))
+	}
+	errs = append(errs, s.checkResources()...)
+
+	return errors.Join(errs...)
+}
+
+// Bounds of the resource limits docker accepts for a container.
+const (
+	minCPUs        = 0.01
+	minMemory      = 6 << 20
+	minBlkioWeight = 10
+	maxBlkioWeight = 1000
+)
+
+// checkResources validates the resource limits of a step against the bounds
+// docker accepts. Zero values are not limits and always pass.
+func (s *Step) checkResources() []error {
+	errs := []error{}
+	if s.CPUs < 0 || (s.CPUs > 0 && s.CPUs < minCPUs) {
+		errs = append(errs, fmt.Errorf("cpus must be at least %v, got %v", minCPUs, s.CPUs))
+	}
+	if s.Memory != 0 && s.Memory < minMemory {
+		errs = append(errs, fmt.Errorf("memory must be at least %v, got %v", ByteSize(minMemory), s.Memory))
+	}
+	if s.BlkioWeight != 0 && (s.BlkioWeight < minBlkioWeight || s.BlkioWeight > maxBlkioWeight) {
+		errs = append(errs, fmt.Errorf("blkioWeight must be between %d and %d, got %d", minBlkioWeight, maxBlkioWeight, s.BlkioWeight))
+	}
+	return errs
+}
+
 func (s *Step) checkStartNode() error {
 	errs := []error{}
 
@@ -140,14 +514,76 @@ func (s *Step) checkStartNode() error {
 	if err := isTypeValid(nodeType); err != nil {
 		errs = append(errs, err)
 	}
+	if s.Cheater && nodeType != "validator" {
+		errs = append(errs, fmt.Errorf("only validators can cheat, got type %v", nodeType))
+	}
 
 	if s.Instances != nil && *s.Instances < 1 {
 		errs = append(errs, fmt.Errorf("number of instances must be >= 1, got %d", *s.Instances))
 	}
+	errs = append(errs, s.checkResources()...)
+	errs = append(errs, s.checkClientMemory()...)
+
+	if s.ClientsPerContainer != nil {
+		if *s.ClientsPerContainer < 1 {
+			errs = append(errs, fmt.Errorf("clientsPerContainer must be >= 1, got %d", *s.ClientsPerContainer))
+		}
+		if *s.ClientsPerContainer > maxClientsPerContainer {
+			errs = append(errs, fmt.Errorf("clientsPerContainer must be <= %d, got %d", maxClientsPerContainer, *s.ClientsPerContainer))
+		}
+		// The data directory of a packed client is not a directory of its own
+		// container, and limits would be shared by all clients of the
+		// container rather than apply to the node.
+		if s.packsClients() {
+			if s.DataVolume != "" {
+				errs = append(errs, fmt.Errorf("packed clients cannot use a dataVolume"))
+			}
+			if s.CPUs != 0 || s.Memory != 0 || s.BlkioWeight != 0 {
+				errs = append(errs, fmt.Errorf("packed clients cannot have cpus, memory or blkioWeight limits"))
+			}
+		}
+	}
 
 	return errors.Join(errs...)
 }
 
+// maxClientsPerContainer bounds the clients sharing a container. Their ports
+// are spaced apart inside the container, and the range below the metrics port
+// of the first client holds no more.
+const maxClientsPerContainer = 100
+
+// packsClients reports whether a startNode step shares containers between
+// the clients of its instances.
+func (s *Step) packsClients() bool {
+	return s.ClientsPerContainer != nil && *s.ClientsPerContainer > 1
+}
+
+// Smallest client memory settings accepted. A node cache too small to hold
+// the part of the state trie a block touches evicts on every access, which
+// turns every measurement into one of the disk and has been observed to
+// crash the client; the client's other caches are sized in whole megabytes.
+const (
+	minLiveCache  = 16 << 20
+	minCache      = 1 << 20
+	minGoMemLimit = 64 << 20
+)
+
+// checkClientMemory validates the memory settings of the client of a
+// startNode step. Zero values keep the client's default and always pass.
+func (s *Step) checkClientMemory() []error {
+	errs := []error{}
+	if s.LiveCache != 0 && s.LiveCache < minLiveCache {
+		errs = append(errs, fmt.Errorf("liveCache must be at least %v, got %v", ByteSize(minLiveCache), s.LiveCache))
+	}
+	if s.Cache != 0 && s.Cache < minCache {
+		errs = append(errs, fmt.Errorf("cache must be at least %v, got %v", ByteSize(minCache), s.Cache))
+	}
+	if s.GoMemLimit != 0 && s.GoMemLimit < minGoMemLimit {
+		errs = append(errs, fmt.Errorf("goMemLimit must be at least %v, got %v", ByteSize(minGoMemLimit), s.GoMemLimit))
+	}
+	return errs
+}
+
 func (s *Step) checkStopNode() error {
 	errs := []error{}
 
@@ -160,6 +596,51 @@ func (s *Step) checkStopNode() error {
 	return errors.Join(errs...)
 }
 
+func (s *Step) checkPartition() error {
+	if len(s.Groups) < 2 {
+		return fmt.Errorf("partition requires at least two groups, got %d", len(s.Groups))
+	}
+
+	errs := []error{}
+	groupOf := map[string]int{}
+	for i, group := range s.Groups {
+		if len(group) == 0 {
+			errs = append(errs, fmt.Errorf("partition group %d must not be empty", i+1))
+		}
+		for _, name := range group {
+			if !NamePattern.Match([]byte(name)) {
+				errs = append(errs, fmt.Errorf("partition group %d: node name must match %v, got %v", i+1, namePatternStr, name))
+				continue
+			}
+			if other, found := groupOf[name]; found {
+				errs = append(errs, fmt.Errorf("partition group %d: node %v is already part of group %d", i+1, name, other))
+				continue
+			}
+			groupOf[name] = i + 1
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+func (s *Step) checkPeers() error {
+	if len(s.Peers) != 2 {
+		return fmt.Errorf("%s requires exactly two nodes, got %d", s.Function, len(s.Peers))
+	}
+
+	errs := []error{}
+	for _, name := range s.Peers {
+		if !NamePattern.Match([]byte(name)) {
+			errs = append(errs, fmt.Errorf("node name must match %v, got %v", namePatternStr, name))
+		}
+	}
+	if s.Peers[0] == s.Peers[1] {
+		errs = append(errs, fmt.Errorf("%s requires two different nodes, got %v twice", s.Function, s.Peers[0]))
+	}
+
+	return errors.Join(errs...)
+}
+
 func (s *Step) checkRunApp() error {
 	errs := []error{}
 
@@ -263,6 +744,17 @@ func (s *Step) checkSubChecks() error {
 				i+1, check.Function, MinObservationSamples, *check.Tolerance,
 			))
 		}
+
+		if check.Function == FuncCheckValidatorSlashed {
+			if len(check.SlashedNodes) == 0 {
+				errs = append(errs, fmt.Errorf("sub-check %d (%s): requires slashedNodes", i+1, check.Function))
+			}
+			for _, name := range check.SlashedNodes {
+				if !NamePattern.Match([]byte(name)) {
+					errs = append(errs, fmt.Errorf("sub-check %d (%s): node name must match %v, got %v", i+1, check.Function, namePatternStr, name))
+				}
+			}
+		}
 	}
 
 	return errors.Join(errs...)
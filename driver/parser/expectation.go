@@ -0,0 +1,90 @@
+// Copyright 2024 Fantom Foundation
+// This file is part of Norma System Testing Infrastructure for Sonic.
+//
+// Norma is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// Norma is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with Norma. If not, see <http://www.gnu.org/licenses/>.
+
+package parser
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// ErrorExpectation describes the error a step is expected to fail with.
+// The zero value accepts any error.
+type ErrorExpectation struct {
+	// Text is a substring the error has to contain; empty for any error.
+	Text string
+	// Pattern is a regular expression the error has to match instead of
+	// containing Text; nil if the expectation is not a pattern.
+	Pattern *regexp.Regexp
+}
+
+// parseErrorExpectation parses the value of an expectError parameter: true
+// to expect any error, a substring of the expected error, or a regular
+// expression enclosed in slashes. False expects no error, giving nil.
+func parseErrorExpectation(val *yaml.Node) (*ErrorExpectation, error) {
+	if val.Kind != yaml.ScalarNode {
+		return nil, fmt.Errorf("expectError must be true, a substring of the error or a /regular expression/")
+	}
+	if val.ShortTag() == "!!bool" {
+		var expected bool
+		if err := val.Decode(&expected); err != nil {
+			return nil, err
+		}
+		if !expected {
+			return nil, nil
+		}
+		return &ErrorExpectation{}, nil
+	}
+	text := val.Value
+	if len(text) > 2 && strings.HasPrefix(text, "/") && strings.HasSuffix(text, "/") {
+		pattern, err := regexp.Compile(text[1 : len(text)-1])
+		if err != nil {
+			return nil, fmt.Errorf("invalid regular expression: %w", err)
+		}
+		return &ErrorExpectation{Pattern: pattern}, nil
+	}
+	if strings.TrimSpace(text) == "" {
+		return nil, fmt.Errorf("expectError must be true, a substring of the error or a /regular expression/")
+	}
+	return &ErrorExpectation{Text: text}, nil
+}
+
+// Matches reports whether the given error is the expected one.
+func (e *ErrorExpectation) Matches(err error) bool {
+	if err == nil {
+		return false
+	}
+	if e.Pattern != nil {
+		return e.Pattern.MatchString(err.Error())
+	}
+	return strings.Contains(err.Error(), e.Text)
+}
+
+// String describes the expected error, for reporting a step that did not
+// fail as expected.
+func (e *ErrorExpectation) String() string {
+	switch {
+	case e.Pattern != nil:
+		return fmt.Sprintf("an error matching /%s/", e.Pattern)
+	case e.Text != "":
+		return fmt.Sprintf("an error containing %q", e.Text)
+	default:
+		return "an error"
+	}
+}
@@ -0,0 +1,140 @@
+// Copyright 2024 Fantom Foundation
+// This file is part of Norma System Testing Infrastructure for Sonic.
+//
+// Norma is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// Norma is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with Norma. If not, see <http://www.gnu.org/licenses/>.
+
+package parser
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// FragmentsDir is the name of the directories holding fragments. Their
+// files are included by scenarios rather than being scenarios themselves,
+// so the tools collecting the scenarios of a directory skip them.
+const FragmentsDir = "fragments"
+
+// includeSpec holds the parameters of an include step until Parse expands it.
+type includeSpec struct {
+	// line is the line of the step, for reporting errors.
+	line int
+	path string
+	// values are the values the step gives the parameters of the fragment,
+	// by name.
+	values map[string]string
+}
+
+// fragment is a list of steps kept in a file of its own, for scenarios to
+// include. Like a scenario, it may declare parameters, which the including
+// steps give values to.
+type fragment struct {
+	Description string         `yaml:"Description,omitempty"`
+	Parameters  map[string]any `yaml:"Parameters,omitempty"`
+	Steps       []Step         `yaml:"Steps"`
+}
+
+// expandIncludes replaces every include step among the given steps by the
+// steps of the fragment it includes. Relative paths are resolved against
+// dir. chain lists the files the given steps are included from, outermost
+// first, so that a fragment including itself is reported.
+func expandIncludes(steps []Step, dir string, chain []string) ([]Step, error) {
+	res := make([]Step, 0, len(steps))
+	for _, step := range steps {
+		if step.Function != FuncInclude {
+			if len(step.Branches) > 0 {
+				branches, err := expandIncludes(step.Branches, dir, chain)
+				if err != nil {
+					return nil, err
+				}
+				step.Branches = branches
+			}
+			res = append(res, step)
+			continue
+		}
+		include := step.include
+		if include == nil || include.path == "" {
+			return nil, fmt.Errorf("include requires the path of a fragment")
+		}
+		path := include.path
+		if !filepath.IsAbs(path) {
+			path = filepath.Join(dir, path)
+		}
+		if slices.Contains(chain, path) {
+			return nil, fmt.Errorf("line %d: include %s: include cycle %s",
+				include.line, include.path, strings.Join(append(slices.Clone(chain), path), " -> "))
+		}
+		included, err := parseFragment(path, include.values, append(slices.Clone(chain), path))
+		if err != nil {
+			return nil, fmt.Errorf("line %d: include %s: %w", include.line, include.path, err)
+		}
+		for _, included := range included {
+			res = append(res, withIterations(included, step.Iterations))
+		}
+	}
+	return res, nil
+}
+
+// parseFragment parses the fragment in the given file, giving its
+// parameters the given values, and expands the repeat and include steps
+// among its steps.
+func parseFragment(path string, values map[string]string, chain []string) ([]Step, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	data, _, err = resolveParameters(data, values)
+	if err != nil {
+		return nil, err
+	}
+
+	var res fragment
+	decoder := yaml.NewDecoder(bytes.NewReader(data))
+	decoder.KnownFields(true)
+	if err := decoder.Decode(&res); err != nil {
+		return nil, err
+	}
+	if len(res.Steps) == 0 {
+		return nil, fmt.Errorf("fragment has no steps")
+	}
+	steps, err := expandRepeats(res.Steps, nil)
+	if err != nil {
+		return nil, err
+	}
+	return expandIncludes(steps, filepath.Dir(path), chain)
+}
+
+// withIterations returns the given step of an included fragment with the
+// iterations of the include step prepended to its own, as the fragment is
+// repeated along with the include step.
+func withIterations(step Step, iterations []int) Step {
+	if len(iterations) == 0 {
+		return step
+	}
+	step.Iterations = append(slices.Clone(iterations), step.Iterations...)
+	if len(step.Branches) > 0 {
+		branches := make([]Step, len(step.Branches))
+		for i, branch := range step.Branches {
+			branches[i] = withIterations(branch, iterations)
+		}
+		step.Branches = branches
+	}
+	return step
+}
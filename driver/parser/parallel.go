@@ -0,0 +1,142 @@
+// Copyright 2024 Fantom Foundation
+// This file is part of Norma System Testing Infrastructure for Sonic.
+//
+// Norma is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// Norma is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with Norma. If not, see <http://www.gnu.org/licenses/>.
+
+package parser
+
+import (
+	"errors"
+	"fmt"
+)
+
+// notInParallel lists the steps that act on the network as a whole, so they
+// cannot run next to other steps of a parallel step or in the background of
+// the steps after a schedule step: whatever the other steps did would be
+// caught halfway by the cut, the disruptions or the snapshot.
+var notInParallel = map[StepFunction]bool{
+	FuncParallel:  true,
+	FuncSchedule:  true,
+	FuncPartition: true,
+	FuncHeal:      true,
+	FuncChaos:     true,
+	FuncSnapshot:  true,
+}
+
+// Steps returns the steps a step runs: the branches of a parallel step, the
+// steps scheduled by a schedule step, or else the step itself.
+func (s *Step) Steps() []Step {
+	if s.Function == FuncParallel || s.Function == FuncSchedule {
+		return s.Branches
+	}
+	return []Step{*s}
+}
+
+// checkParallel validates the branches of a parallel step. Branches run at
+// the same time, so none may act on a node, application or delegator another
+// branch acts on, as the order of their actions would be left to chance.
+func (s *Step) checkParallel() error {
+	if len(s.Branches) < 2 {
+		return fmt.Errorf("parallel requires at least two steps, got %d", len(s.Branches))
+	}
+
+	errs := []error{}
+	// claims lists the names the branches act on, by branch.
+	type claim struct {
+		name   string
+		branch int
+	}
+	var claims []claim
+	for i, branch := range s.Branches {
+		if notInParallel[branch.Function] {
+			errs = append(errs, fmt.Errorf("branch %d (%s): cannot run in parallel with other steps", i+1, branch.Function))
+			continue
+		}
+		if err := branch.Check(); err != nil {
+			errs = append(errs, fmt.Errorf("branch %d (%s): %w", i+1, branch.Function, err))
+		}
+		subjects := branch.subjects()
+		for _, name := range subjects {
+			for _, other := range claims {
+				if overlaps(name, other.name) {
+					errs = append(errs, fmt.Errorf("branch %d (%s): %v is also acted on by branch %d", i+1, branch.Function, name, other.branch))
+					break
+				}
+			}
+		}
+		for _, name := range subjects {
+			claims = append(claims, claim{name, i + 1})
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// subjects returns the names of the nodes, applications and delegators a
+// step acts on. Checks only observe the network and have none.
+func (s *Step) subjects() []string {
+	var names []string
+	switch s.Function {
+	case FuncChecks, FuncWaitFor, FuncAdvanceEpoch, FuncWaitForEpoch, FuncUpdateRules, FuncVerifyStakes:
+		return nil
+	case FuncDelegate:
+		for _, target := range s.DelegateTargets {
+			names = append(names, target.Node, target.Delegator)
+		}
+	case FuncUndelegate:
+		for _, target := range s.UndelegateTargets {
+			names = append(names, target.Node)
+			if target.Delegator != "" {
+				names = append(names, target.Delegator)
+			}
+		}
+	case FuncConnect, FuncDisconnect:
+		names = append(names, s.Peers...)
+	case FuncSetLink:
+		if s.Link != nil {
+			names = append(names, s.Link.Between...)
+		}
+	case FuncPartition:
+		for _, group := range s.Groups {
+			names = append(names, group...)
+		}
+	case FuncChaos:
+		if len(s.Targets) == 0 {
+			return []string{allNodes}
+		}
+		names = append(names, s.Targets...)
+	default:
+		if s.Identifier != "" {
+			names = append(names, s.Identifier)
+		}
+	}
+	return names
+}
+
+// allNodes is the subject of a step acting on every node, such as a chaos
+// step without targets.
+const allNodes = "all nodes"
+
+// overlaps reports whether two names refer to the same node: they are equal,
+// or one names an instance of the other, "<name>-<i>". All nodes overlap
+// any name, as the names of nodes are not told apart from the others.
+func overlaps(a, b string) bool {
+	if a == b || a == allNodes || b == allNodes {
+		return true
+	}
+	isInstanceOf := func(instance, node string) bool {
+		base, suffix, found := cutLast(instance, "-")
+		return found && isNumber(suffix) && base == node
+	}
+	return isInstanceOf(a, b) || isInstanceOf(b, a)
+}
@@ -0,0 +1,285 @@
+// Copyright 2024 Fantom Foundation
+// This file is part of Norma System Testing Infrastructure for Sonic.
+//
+// Norma is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// Norma is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with Norma. If not, see <http://www.gnu.org/licenses/>.
+
+package parser
+
+import (
+	"bytes"
+	"fmt"
+	"maps"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+
+	"gopkg.in/yaml.v3"
+)
+
+// parametersKey is the top-level key of the block declaring the parameters
+// of a scenario.
+const parametersKey = "Parameters"
+
+// referencePattern matches a reference ${name} to a parameter or to the
+// variable of a repeat step.
+var referencePattern = regexp.MustCompile(`\$\{([A-Za-z_][A-Za-z0-9_]*)\}`)
+
+// resolveParameters substitutes the values of the parameters declared in
+// the Parameters block of a scenario for the references to them, returning
+// the scenario with all of them resolved along with the value of every
+// parameter, by name. A parameter takes the given value of its name, if
+// there is one, and its default otherwise.
+//
+// References are substituted in the text of the scenario rather than in its
+// decoded form, so that it is then parsed as strictly as a scenario without
+// parameters and errors keep referring to the lines it was written on. Only
+// the references in the scalars of the scenario are substituted, those in
+// its comments are left alone, and only values that cannot change the
+// structure of the scenario are accepted.
+func resolveParameters(data []byte, values map[string]string) ([]byte, map[string]any, error) {
+	var doc yaml.Node
+	if err := yaml.Unmarshal(data, &doc); err != nil || len(doc.Content) == 0 {
+		// Malformed scenarios are reported by the parser proper.
+		return data, nil, nil
+	}
+	root := doc.Content[0]
+
+	defaults, err := parseParameterDefaults(root)
+	if err != nil {
+		return nil, nil, err
+	}
+	parameters := maps.Clone(defaults)
+	for _, name := range slices.Sorted(maps.Keys(values)) {
+		def, found := defaults[name]
+		if !found {
+			return nil, nil, fmt.Errorf("scenario declares no parameter %q", name)
+		}
+		value, err := parseParameterValue(def, values[name])
+		if err != nil {
+			return nil, nil, fmt.Errorf("parameter %s: %w", name, err)
+		}
+		parameters[name] = value
+	}
+
+	texts := make(map[string]string, len(parameters))
+	for name, value := range parameters {
+		text := formatParameterValue(value)
+		if !isPlainText(text) {
+			return nil, nil, fmt.Errorf("parameter %s: value %q cannot be written into a scenario", name, text)
+		}
+		texts[name] = text
+	}
+
+	variables, err := repeatVariablesOf(root, defaults)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	lines := bytes.SplitAfter(data, []byte("\n"))
+	inScalars := scalarReferences(root, lines)
+	for i, line := range lines {
+		var resolved []byte
+		last := 0
+		for _, match := range referencePattern.FindAllSubmatchIndex(line, -1) {
+			if !inScalars[textPosition{i, match[0]}] {
+				continue
+			}
+			name := string(line[match[2]:match[3]])
+			text, found := texts[name]
+			if !found {
+				if !variables[name] {
+					return nil, nil, fmt.Errorf("line %d: undefined parameter %q", i+1, name)
+				}
+				continue
+			}
+			resolved = append(resolved, line[last:match[0]]...)
+			resolved = append(resolved, text...)
+			last = match[1]
+		}
+		lines[i] = append(resolved, line[last:]...)
+	}
+	return bytes.Join(lines, nil), parameters, nil
+}
+
+// textPosition is the position of a byte in the text of a scenario, by
+// line and offset in the line, both counted from zero.
+type textPosition struct {
+	line, offset int
+}
+
+// scalarReferences returns the positions of the references written in the
+// scalars of the given node, whose text is split into the given lines, as
+// opposed to those in comments.
+//
+// A scalar holds no comment, so the references in its value are the first
+// ones written from where it starts, in the order of its value. Only block
+// scalars start on the line after their indicator, which may be followed by
+// a comment.
+func scalarReferences(node *yaml.Node, lines [][]byte) map[textPosition]bool {
+	res := map[textPosition]bool{}
+	var visit func(node *yaml.Node)
+	visit = func(node *yaml.Node) {
+		if node.Kind == yaml.ScalarNode {
+			count := len(referencePattern.FindAllStringIndex(node.Value, -1))
+			line, column := node.Line-1, node.Column-1
+			if node.Style&(yaml.LiteralStyle|yaml.FoldedStyle) != 0 {
+				line, column = line+1, 0
+			}
+			for ; count > 0 && line >= 0 && line < len(lines); line, column = line+1, 0 {
+				offset := byteOffset(lines[line], column)
+				for _, match := range referencePattern.FindAllIndex(lines[line], -1) {
+					if count > 0 && match[0] >= offset {
+						res[textPosition{line, match[0]}] = true
+						count--
+					}
+				}
+			}
+		}
+		for _, child := range node.Content {
+			visit(child)
+		}
+	}
+	visit(node)
+	return res
+}
+
+// byteOffset returns the offset of the character of the given column in the
+// given line, as columns count characters rather than bytes.
+func byteOffset(line []byte, column int) int {
+	offset := 0
+	for range column {
+		if offset >= len(line) {
+			break
+		}
+		_, size := utf8.DecodeRune(line[offset:])
+		offset += size
+	}
+	return offset
+}
+
+// parseParameterDefaults returns the defaults of the parameters declared in
+// the given scenario, by name.
+func parseParameterDefaults(root *yaml.Node) (map[string]any, error) {
+	res := map[string]any{}
+	if root.Kind != yaml.MappingNode {
+		return res, nil
+	}
+	var block *yaml.Node
+	for i := 0; i+1 < len(root.Content); i += 2 {
+		if root.Content[i].Value == parametersKey {
+			block = root.Content[i+1]
+		}
+	}
+	if block == nil {
+		return res, nil
+	}
+	if block.Kind != yaml.MappingNode {
+		return nil, fmt.Errorf("line %d: %s must map parameter names to their defaults", block.Line, parametersKey)
+	}
+	for i := 0; i+1 < len(block.Content); i += 2 {
+		key, value := block.Content[i], block.Content[i+1]
+		if !variablePattern.MatchString(key.Value) {
+			return nil, fmt.Errorf("line %d: parameter name %q must match %s", key.Line, key.Value, variablePattern)
+		}
+		switch value.ShortTag() {
+		case "!!int", "!!float", "!!bool", "!!str":
+		default:
+			return nil, fmt.Errorf("line %d: parameter %s must default to a number, boolean or string", value.Line, key.Value)
+		}
+		var def any
+		if err := value.Decode(&def); err != nil {
+			return nil, fmt.Errorf("line %d: parameter %s: %w", value.Line, key.Value, err)
+		}
+		res[key.Value] = def
+	}
+	return res, nil
+}
+
+// parseParameterValue parses a value given for a parameter, which has to be
+// of the type of the parameter's default.
+func parseParameterValue(def any, text string) (any, error) {
+	switch def.(type) {
+	case int:
+		value, err := strconv.ParseInt(text, 0, 64)
+		if err != nil {
+			return nil, fmt.Errorf("must be an integer, got %q", text)
+		}
+		return int(value), nil
+	case float64:
+		value, err := strconv.ParseFloat(text, 64)
+		if err != nil {
+			return nil, fmt.Errorf("must be a number, got %q", text)
+		}
+		return value, nil
+	case bool:
+		value, err := strconv.ParseBool(text)
+		if err != nil {
+			return nil, fmt.Errorf("must be true or false, got %q", text)
+		}
+		return value, nil
+	default:
+		return text, nil
+	}
+}
+
+// formatParameterValue returns the text substituted for a reference to a
+// parameter of the given value.
+func formatParameterValue(value any) string {
+	switch value := value.(type) {
+	case float64:
+		return strconv.FormatFloat(value, 'g', -1, 64)
+	default:
+		return fmt.Sprint(value)
+	}
+}
+
+// isPlainText reports whether the given text reads the same wherever it is
+// written into a scenario, which is not the case for text holding quotes,
+// comments, separators of flow collections or mappings, or starting with an
+// indicator such as the one of an alias.
+func isPlainText(text string) bool {
+	if strings.ContainsAny(text, "\n\r\"'#,[]{}") || strings.Contains(text, ": ") ||
+		strings.HasSuffix(text, ":") || strings.TrimSpace(text) != text {
+		return false
+	}
+	return text == "" || !strings.ContainsAny(text[:1], "*&!|>%@`")
+}
+
+// repeatVariablesOf returns the variables of all repeat steps in the given
+// scenario, which may be referred to as well. None of them may be named
+// like a parameter, as the references to it in the repeated steps would be
+// ambiguous.
+func repeatVariablesOf(node *yaml.Node, parameters map[string]any) (map[string]bool, error) {
+	res := map[string]bool{}
+	var visit func(node *yaml.Node) error
+	visit = func(node *yaml.Node) error {
+		if node.Kind == yaml.MappingNode {
+			if variable := repeatVariableOf(node); variable != "" {
+				if _, found := parameters[variable]; found {
+					return fmt.Errorf("line %d: repeat variable %s has the name of a parameter", node.Line, variable)
+				}
+				res[variable] = true
+			}
+		}
+		for _, child := range node.Content {
+			if err := visit(child); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+	return res, visit(node)
+}
@@ -0,0 +1,174 @@
+// Copyright 2024 Fantom Foundation
+// This file is part of Norma System Testing Infrastructure for Sonic.
+//
+// Norma is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// Norma is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with Norma. If not, see <http://www.gnu.org/licenses/>.
+
+package parser
+
+import (
+	"fmt"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// defaultRepeatVariable is the variable a repeat step substitutes the
+// iteration for if it names none.
+const defaultRepeatVariable = "i"
+
+// variablePattern is the pattern of the name of the variable of a repeat
+// step, which is referred to as ${name} in the repeated steps.
+var variablePattern = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*$`)
+
+// repeatSpec holds the parameters of a repeat step until Parse expands it.
+type repeatSpec struct {
+	// line is the line of the step, for reporting errors.
+	line     int
+	times    *int
+	variable string
+	// steps are the repeated steps as written. They are only decoded per
+	// iteration, since any of their values may refer to the iteration.
+	steps *yaml.Node
+}
+
+// expandRepeats replaces every repeat step among the given steps by the
+// steps it repeats, recording in each of them the iterations of the repeat
+// steps enclosing it, outermost first, after the given ones.
+func expandRepeats(steps []Step, iterations []int) ([]Step, error) {
+	res := make([]Step, 0, len(steps))
+	for _, step := range steps {
+		if step.Function != FuncRepeat {
+			if len(iterations) > 0 {
+				step.Iterations = iterations
+			}
+			if len(step.Branches) > 0 {
+				branches, err := expandRepeats(step.Branches, iterations)
+				if err != nil {
+					return nil, err
+				}
+				step.Branches = branches
+			}
+			res = append(res, step)
+			continue
+		}
+		if step.repeat == nil {
+			return nil, fmt.Errorf("repeat requires times and steps")
+		}
+		expanded, err := step.repeat.expand(iterations)
+		if err != nil {
+			return nil, err
+		}
+		res = append(res, expanded...)
+	}
+	return res, nil
+}
+
+// expand returns the steps of a repeat step for all of its iterations.
+func (r *repeatSpec) expand(iterations []int) ([]Step, error) {
+	if r.times == nil {
+		return nil, fmt.Errorf("line %d: repeat requires times", r.line)
+	}
+	if *r.times < 1 {
+		return nil, fmt.Errorf("line %d: repeat times must be >= 1, got %d", r.line, *r.times)
+	}
+	if r.steps == nil || len(r.steps.Content) == 0 {
+		return nil, fmt.Errorf("line %d: repeat requires at least one step", r.line)
+	}
+	variable := r.variable
+	if variable == "" {
+		variable = defaultRepeatVariable
+	}
+
+	var res []Step
+	for i := range *r.times {
+		body := cloneNode(r.steps)
+		substitute(body, variable, strconv.Itoa(i))
+		var steps []Step
+		if err := body.Decode(&steps); err != nil {
+			return nil, err
+		}
+		expanded, err := expandRepeats(steps, append(slices.Clone(iterations), i))
+		if err != nil {
+			return nil, err
+		}
+		res = append(res, expanded...)
+	}
+	return res, nil
+}
+
+// substitute replaces every reference ${variable} in the scalars of the given
+// node by the given value. The steps of a nested repeat step of the same
+// variable are left alone, as there the variable refers to its iterations.
+func substitute(node *yaml.Node, variable, value string) {
+	switch node.Kind {
+	case yaml.ScalarNode:
+		substituted := strings.ReplaceAll(node.Value, "${"+variable+"}", value)
+		if substituted != node.Value && node.Style&yaml.TaggedStyle == 0 {
+			// The type of an untagged scalar was derived from its text, and
+			// may change with it, as for a number of nodes.
+			node.Tag = ""
+		}
+		node.Value = substituted
+	case yaml.MappingNode:
+		shadowed := repeatVariableOf(node) == variable
+		for i := 0; i+1 < len(node.Content); i += 2 {
+			substitute(node.Content[i], variable, value)
+			if shadowed && node.Content[i].Value == "steps" {
+				continue
+			}
+			substitute(node.Content[i+1], variable, value)
+		}
+	default:
+		for _, child := range node.Content {
+			substitute(child, variable, value)
+		}
+	}
+}
+
+// repeatVariableOf returns the variable of the repeat step written as the
+// given mapping, or the empty string if the mapping is no repeat step.
+func repeatVariableOf(node *yaml.Node) string {
+	isRepeat := false
+	variable := defaultRepeatVariable
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		switch node.Content[i].Value {
+		case string(FuncRepeat):
+			isRepeat = true
+		case "variable":
+			variable = node.Content[i+1].Value
+		}
+	}
+	if !isRepeat {
+		return ""
+	}
+	return variable
+}
+
+// cloneNode returns a deep copy of the given node, so that substituting in
+// the copy leaves the original for the next iteration.
+func cloneNode(node *yaml.Node) *yaml.Node {
+	if node == nil {
+		return nil
+	}
+	res := *node
+	res.Alias = cloneNode(node.Alias)
+	res.Content = make([]*yaml.Node, len(node.Content))
+	for i, child := range node.Content {
+		res.Content[i] = cloneNode(child)
+	}
+	return &res
+}
@@ -22,6 +22,7 @@ import (
 	"fmt"
 	"io"
 	"os"
+	"path/filepath"
 	"slices"
 	"strings"
 	"time"
@@ -34,30 +35,50 @@ import (
 type StepFunction string
 
 const (
-	FuncStartNode    StepFunction = "startNode"
-	FuncStopNode     StepFunction = "stopNode"
-	FuncDelegate     StepFunction = "delegate"
-	FuncUndelegate   StepFunction = "undelegate"
-	FuncVerifyStakes StepFunction = "verifyStakes"
-	FuncUpdateRules  StepFunction = "updateRules"
-	FuncAdvanceEpoch StepFunction = "advanceEpoch"
-	FuncWaitForEpoch StepFunction = "waitForEpoch"
-	FuncRunApp       StepFunction = "runApp"
-	FuncStopApp      StepFunction = "stopApp"
-	FuncChecks       StepFunction = "checks"
-	FuncWaitFor      StepFunction = "waitFor"
-	FuncKillSonic    StepFunction = "killSonic"
-	FuncHealDb       StepFunction = "healDb"
+	FuncStartNode       StepFunction = "startNode"
+	FuncStopNode        StepFunction = "stopNode"
+	FuncDelegate        StepFunction = "delegate"
+	FuncUndelegate      StepFunction = "undelegate"
+	FuncVerifyStakes    StepFunction = "verifyStakes"
+	FuncUpdateRules     StepFunction = "updateRules"
+	FuncAdvanceEpoch    StepFunction = "advanceEpoch"
+	FuncWaitForEpoch    StepFunction = "waitForEpoch"
+	FuncRunApp          StepFunction = "runApp"
+	FuncStopApp         StepFunction = "stopApp"
+	FuncChecks          StepFunction = "checks"
+	FuncWaitFor         StepFunction = "waitFor"
+	FuncKillSonic       StepFunction = "killSonic"
+	FuncHealDb          StepFunction = "healDb"
+	FuncPartition       StepFunction = "partition"
+	FuncHeal            StepFunction = "heal"
+	FuncSetLink         StepFunction = "setLink"
+	FuncThrottleNode    StepFunction = "throttleNode"
+	FuncUnthrottleNode  StepFunction = "unthrottleNode"
+	FuncPauseNode       StepFunction = "pauseNode"
+	FuncResumeNode      StepFunction = "resumeNode"
+	FuncSetClockSkew    StepFunction = "setClockSkew"
+	FuncUpdateResources StepFunction = "updateResources"
+	FuncConnect         StepFunction = "connect"
+	FuncDisconnect      StepFunction = "disconnect"
+	FuncChaos           StepFunction = "chaos"
+	FuncUpgradeNode     StepFunction = "upgradeNode"
+	FuncSnapshot        StepFunction = "snapshot"
+	FuncRepeat          StepFunction = "repeat"
+	FuncParallel        StepFunction = "parallel"
+	FuncInclude         StepFunction = "include"
+	FuncSchedule        StepFunction = "schedule"
 
 	// Check functions used as items inside a checks: step.
 	FuncCheckBlockGasRate     StepFunction = "blockGasRate"
 	FuncCheckBlockHashes      StepFunction = "blockHashes"
 	FuncCheckBlockHeights     StepFunction = "blockHeights"
+	FuncCheckBlockTimestamps  StepFunction = "blockTimestamps"
 	FuncCheckBlocksHalted     StepFunction = "blocksHalted"
 	FuncCheckBlocksProduced   StepFunction = "blocksProduced"
 	FuncCheckEventThrottled   StepFunction = "eventThrottled"
 	FuncCheckNetworkRules     StepFunction = "networkRules"
 	FuncCheckValidatorsActive StepFunction = "validatorsActive"
+	FuncCheckValidatorSlashed StepFunction = "validatorSlashed"
 )
 
 // allStepFunctions lists every known top-level step function constant.
@@ -76,6 +97,24 @@ var allStepFunctions = [...]StepFunction{
 	FuncWaitFor,
 	FuncKillSonic,
 	FuncHealDb,
+	FuncPartition,
+	FuncHeal,
+	FuncSetLink,
+	FuncThrottleNode,
+	FuncUnthrottleNode,
+	FuncPauseNode,
+	FuncResumeNode,
+	FuncSetClockSkew,
+	FuncUpdateResources,
+	FuncConnect,
+	FuncDisconnect,
+	FuncChaos,
+	FuncUpgradeNode,
+	FuncSnapshot,
+	FuncRepeat,
+	FuncParallel,
+	FuncInclude,
+	FuncSchedule,
 }
 
 // allCheckFunctions lists every check function valid as a sub-item of a checks: step.
@@ -83,10 +122,12 @@ var allCheckFunctions = [...]StepFunction{
 	FuncCheckBlockGasRate,
 	FuncCheckBlockHashes,
 	FuncCheckBlockHeights,
+	FuncCheckBlockTimestamps,
 	FuncCheckBlocksHalted,
 	FuncCheckBlocksProduced,
 	FuncCheckEventThrottled,
 	FuncCheckNetworkRules,
+	FuncCheckValidatorSlashed,
 	FuncCheckValidatorsActive,
 }
 
@@ -116,9 +157,11 @@ type CheckSpec struct {
 	Ceiling        *float64
 	Tolerance      *int
 	Duration       *time.Duration
+	Bound          *time.Duration
 	Failing        bool
 	Rules          genesis.NetworkRulesPatch
 	ThrottledNodes []string
+	SlashedNodes   []string
 }
 
 // UnmarshalYAML implements custom YAML unmarshalling for CheckSpec.
@@ -223,6 +266,19 @@ func (c *CheckSpec) parseParam(keyNode, valNode *yaml.Node) error {
 			return fmt.Errorf("line %d: duration must be non-negative, got %s", keyNode.Line, d)
 		}
 		c.Duration = &d
+	case "bound":
+		var s string
+		if err := valNode.Decode(&s); err != nil {
+			return fmt.Errorf("line %d: invalid bound: %w", keyNode.Line, err)
+		}
+		d, err := time.ParseDuration(s)
+		if err != nil {
+			return fmt.Errorf("line %d: invalid bound %q: %w", keyNode.Line, s, err)
+		}
+		if d <= 0 {
+			return fmt.Errorf("line %d: bound must be positive, got %s", keyNode.Line, d)
+		}
+		c.Bound = &d
 	case "ceiling":
 		var v float64
 		if err := valNode.Decode(&v); err != nil {
@@ -256,6 +312,15 @@ func (c *CheckSpec) parseParam(keyNode, valNode *yaml.Node) error {
 			)
 		}
 		c.ThrottledNodes = v
+	case "slashedNodes":
+		var v []string
+		if err := valNode.Decode(&v); err != nil {
+			return fmt.Errorf(
+				"line %d: invalid slashedNodes: %w",
+				keyNode.Line, err,
+			)
+		}
+		c.SlashedNodes = v
 	default:
 		return fmt.Errorf(
 			"line %d: unknown check parameter %q",
@@ -270,9 +335,41 @@ func (c *CheckSpec) parseParam(keyNode, valNode *yaml.Node) error {
 type Scenario struct {
 	Name             string                    `yaml:"Name"`
 	Description      string                    `yaml:"Description"`
+	Parameters       map[string]any            `yaml:"Parameters,omitempty"`
+	Timeout          time.Duration             `yaml:"Timeout,omitempty"`
 	InitialRules     genesis.NetworkRulesPatch `yaml:"InitialNetworkRules"`
+	InitialState     *genesis.InitialState     `yaml:"InitialState,omitempty"`
 	DisableEndChecks bool                      `yaml:"DisableEndChecks,omitempty"`
+	Regions          map[string][]string       `yaml:"Regions,omitempty"`
+	Links            []Link                    `yaml:"Links,omitempty"`
+	Topology         Topology                  `yaml:"Topology,omitempty"`
 	Steps            []Step                    `yaml:"Scenario"`
+
+	// EndChecks is the number of steps at the end of Steps that Parse added
+	// as the end checks of the scenario.
+	EndChecks int `yaml:"-"`
+}
+
+// Link describes the network conditions on the links between the two ends
+// listed in Between. Each end is the name of a region, covering all nodes in
+// it, or a node name, covering that node or all instances of it. Latency,
+// jitter, loss and reordering apply to each direction of a link; loss and
+// reorder are percentages.
+type Link struct {
+	Between []string      `yaml:"between"`
+	Latency time.Duration `yaml:"latency,omitempty"`
+	Jitter  time.Duration `yaml:"jitter,omitempty"`
+	Loss    float64       `yaml:"loss,omitempty"`
+	Reorder float64       `yaml:"reorder,omitempty"`
+}
+
+// ResolveLinkEnd returns the node names an end of a link refers to: the
+// members of the region of that name, or else the name itself.
+func (s *Scenario) ResolveLinkEnd(name string) []string {
+	if members, found := s.Regions[name]; found {
+		return members
+	}
+	return []string{name}
 }
 
 // Step is a single blocking operation in a scenario.
@@ -291,6 +388,26 @@ type Step struct {
 	Instances      *int
 	Failing        bool
 	ExtraArguments string
+	// Cheater starts a second node signing with the same validator key,
+	// making the validator double-sign.
+	Cheater bool
+	// ClockOffset is the offset of the node's clock, set by startNode and
+	// setClockSkew; nil if not given.
+	ClockOffset *time.Duration
+	// Resource limits, set by startNode and updateResources; zero leaves a
+	// resource unlimited, or unchanged when updating.
+	CPUs        float64
+	Memory      ByteSize
+	BlkioWeight uint16
+	// ClientsPerContainer packs the instances of a startNode step into
+	// containers shared by this many clients each; nil gives every instance
+	// a container of its own.
+	ClientsPerContainer *int
+	// Memory settings of the client, set by startNode; zero keeps the
+	// client's default.
+	LiveCache  ByteSize
+	Cache      ByteSize
+	GoMemLimit ByteSize
 
 	// App parameters
 	AppType string
@@ -311,6 +428,59 @@ type Step struct {
 
 	// WaitFor parameters
 	Duration time.Duration
+
+	// Partition parameters: the groups of node labels to separate.
+	Groups [][]string
+
+	// SetLink parameters
+	Link *Link
+
+	// ThrottleNode parameters; a zero rate leaves a direction unlimited,
+	// a zero burst selects the default.
+	Egress  Bandwidth
+	Ingress Bandwidth
+	Burst   ByteSize
+
+	// Connect and disconnect parameters: the names of the two nodes.
+	Peers []string
+
+	// Chaos parameters; the window is given by Duration. A nil seed is
+	// drawn at random, no actions allow all of them.
+	Seed         *int64
+	Targets      []string
+	ChaosActions []string
+	MaxAffected  *int
+
+	// UpgradeNode parameters: the new image is given by ImageName.
+	Interval time.Duration
+
+	// Parallel parameters: the steps run at the same time, each one a
+	// branch of its own. For a schedule step, the steps it schedules.
+	Branches []Step
+
+	// Trigger is the point a step scheduled by a schedule step fires at;
+	// nil for all other steps.
+	Trigger *Trigger
+
+	// Timeout bounds how long the step may take; zero leaves it bounded by
+	// the timeout of the scenario only.
+	Timeout time.Duration
+
+	// ExpectError, if set, expects the step to fail with the error it
+	// describes, failing the step if it succeeds or fails otherwise.
+	ExpectError *ErrorExpectation
+
+	// Iterations lists, for a step repeated by repeat steps, the iteration
+	// of each enclosing repeat step, outermost first; nil for steps outside
+	// of any repeat step.
+	Iterations []int
+
+	// repeat holds the parameters of a repeat step, which Parse replaces by
+	// the steps it repeats.
+	repeat *repeatSpec
+	// include holds the parameters of an include step, which Parse replaces
+	// by the steps of the fragment it includes.
+	include *includeSpec
 }
 
 // DelegateTarget specifies a single delegation from a named external
@@ -341,6 +511,20 @@ type UndelegateTarget struct {
 //   - A scalar string (e.g., "advanceEpoch")
 //   - A mapping where one key is the function name (e.g., "startNode: validator-A")
 func (s *Step) UnmarshalYAML(value *yaml.Node) error {
+	if err := s.unmarshalStep(value); err != nil {
+		return err
+	}
+	switch s.Function {
+	case FuncRepeat:
+		s.repeatSpec().line = value.Line
+	case FuncInclude:
+		s.includeSpec().line = value.Line
+	}
+	return nil
+}
+
+// unmarshalStep parses a step in either of its forms.
+func (s *Step) unmarshalStep(value *yaml.Node) error {
 	switch value.Kind {
 	case yaml.ScalarNode:
 		fn, err := toStepFunction(value.Value)
@@ -454,22 +638,84 @@ func (s *Step) parseFunctionValue(fn StepFunction, val *yaml.Node) error {
 			}
 			s.DelegateTargets = append(s.DelegateTargets, target)
 		}
-	case FuncVerifyStakes, FuncAdvanceEpoch, FuncWaitForEpoch:
+	case FuncPartition:
+		// Value is a list of groups, each a list of node names:
+		//   - partition:
+		//     - [val-1, val-2]
+		//     - [val-3]
+		if val.Kind != yaml.SequenceNode {
+			return fmt.Errorf("partition value must be a list of node groups")
+		}
+		for i, item := range val.Content {
+			if item.Kind != yaml.SequenceNode {
+				return fmt.Errorf("partition group %d must be a list of node names", i+1)
+			}
+			var group []string
+			if err := item.Decode(&group); err != nil {
+				return fmt.Errorf("partition group %d: %w", i+1, err)
+			}
+			s.Groups = append(s.Groups, group)
+		}
+	case FuncSetLink:
+		// Value is a single link, as in the Links section.
+		if val.Kind != yaml.MappingNode {
+			return fmt.Errorf("setLink value must be a link mapping")
+		}
+		var link Link
+		if err := val.Decode(&link); err != nil {
+			return fmt.Errorf("invalid setLink value: %w", err)
+		}
+		s.Link = &link
+	case FuncConnect, FuncDisconnect:
+		// Value is the pair of nodes: `- connect: [val-1, obs-1]`
+		if val.Kind != yaml.SequenceNode {
+			return fmt.Errorf("%s value must be a list of two node names", fn)
+		}
+		var peers []string
+		if err := val.Decode(&peers); err != nil {
+			return fmt.Errorf("invalid %s value: %w", fn, err)
+		}
+		s.Peers = peers
+	case FuncParallel, FuncSchedule:
+		// Value is the list of steps to run at the same time, or in the
+		// background of the following steps:
+		//   - parallel:
+		//     - stopNode: val-1
+		//     - startNode: val-2
+		//   - schedule:
+		//     - stopNode: val-1
+		//       at: 30s
+		if val.Kind != yaml.SequenceNode {
+			return fmt.Errorf("%s value must be a list of steps", fn)
+		}
+		var branches []Step
+		if err := val.Decode(&branches); err != nil {
+			return err
+		}
+		s.Branches = branches
+	case FuncInclude:
+		// Value is the path of the fragment, relative to the including file:
+		//   - include: ../fragments/bootstrap.yml
+		if val.Kind != yaml.ScalarNode || val.Tag == "!!null" || val.Value == "" {
+			return fmt.Errorf("include value must be the path of a fragment")
+		}
+		s.includeSpec().path = val.Value
+	case FuncVerifyStakes, FuncAdvanceEpoch, FuncWaitForEpoch, FuncHeal, FuncRepeat:
 		// These take no value (or null).
 		if val.Kind != yaml.ScalarNode || (val.Tag != "!!null" && val.Value != "" && val.Value != "null") {
 			return fmt.Errorf("%s does not take a value, got %q", fn, val.Value)
 		}
-	case FuncWaitFor:
+	case FuncWaitFor, FuncChaos:
 		// Value is a duration string (e.g., "1s", "5m", "1h").
 		if val.Kind != yaml.ScalarNode || val.Value == "" {
-			return fmt.Errorf("waitFor requires a duration value (e.g., \"1s\", \"5m\")")
+			return fmt.Errorf("%s requires a duration value (e.g., \"1s\", \"5m\")", fn)
 		}
 		d, err := time.ParseDuration(val.Value)
 		if err != nil {
 			return fmt.Errorf("invalid duration %q: %w", val.Value, err)
 		}
 		if d <= 0 {
-			return fmt.Errorf("waitFor duration must be positive, got %s", d)
+			return fmt.Errorf("%s duration must be positive, got %s", fn, d)
 		}
 		s.Duration = d
 	case FuncChecks:
@@ -543,44 +789,245 @@ var stepFunctionDescriptions = map[StepFunction]string{
 	FuncWaitFor:      "Pause scenario execution for a fixed duration.",
 	FuncKillSonic:    "Kill the sonicd process with SIGKILL, leaving the database dirty.",
 	FuncHealDb:       "Run sonictool heal on a killed node to recover its database.",
+	FuncPartition: `Split the network into two or more isolated groups of nodes.
+    Nodes within a group stay connected, nodes in different groups can no
+    longer reach each other. Nodes not listed in any group keep their
+    connections to everyone. A name refers to a node or to all instances
+    of a multi-instance node. Monitoring is not affected.
+    Example:
+      - partition:
+        - [val-1, val-2, val-3]
+        - [val-4]`,
+	FuncHeal: "Restore the connectivity cut by the preceding partition step.",
+	FuncSetLink: `Change the network conditions between two ends, each a node or
+    a region name, overriding the Links section and earlier setLink steps
+    for the links it covers.
+      between: the two ends of the links.
+      latency: one-way delay (e.g. "80ms").
+      jitter:  random variation of the delay.
+      loss:    percentage of dropped packets.
+      reorder: percentage of packets overtaking delayed ones.
+    Example:
+      - setLink:
+          between: [eu, asia]
+          latency: 150ms
+          loss: 1`,
+	FuncThrottleNode: `Limit the bandwidth of a node, or of all instances of a multi-instance
+    node, replacing any limits set before. At least one rate is required.
+      egress:  rate of the traffic the node sends (e.g. "10mbit").
+      ingress: rate of the traffic the node receives.
+      burst:   data sent at full speed before the rates apply (e.g. "32kb").
+    Example:
+      - throttleNode: val-1
+        egress: 10mbit
+        ingress: 20mbit`,
+	FuncUnthrottleNode: "Lift the bandwidth limits set by a preceding throttleNode step.",
+	FuncPauseNode: `Freeze a node, or all instances of a multi-instance node, without
+    stopping it. The client keeps its in-memory state and continues from it
+    when resumed, as a hung validator would. Monitoring skips the node
+    while it is frozen.`,
+	FuncResumeNode: "Resume a node frozen by a preceding pauseNode step.",
+	FuncSetClockSkew: `Change the offset of a node's clock, or of the clocks of all instances
+    of a multi-instance node, from real time. The node must be started with
+    a clockOffset or be named by a setClockSkew step from the start.
+      offset: the new offset (e.g. "+2s", "-500ms").
+    Example:
+      - setClockSkew: val-1
+        offset: -500ms`,
+	FuncUpdateResources: `Change the resource limits of a running node, or of all instances of a
+    multi-instance node. Limits not given stay as they are.
+      cpus:        number of CPUs, may be fractional (e.g. 0.5).
+      memory:      memory without swap (e.g. "2gb").
+      blkioWeight: relative block IO weight, 10 to 1000.
+    Example:
+      - updateResources: val-1
+        cpus: 0.5
+        memory: 1gb`,
+	FuncConnect: `Peer two nodes, or all instances of multi-instance nodes, on top of
+    the Topology of the scenario. The peering is kept when either node
+    restarts. Requires a Topology other than fullMesh.
+    Example:
+      - connect: [val-1, obs-1]`,
+	FuncDisconnect: `Drop the peering of two nodes, or of all instances of multi-instance
+    nodes, whether it is part of the Topology or made by a connect step.
+    Requires a Topology other than fullMesh.
+    Example:
+      - disconnect: [val-1, val-2]`,
+	FuncChaos: `Disrupt randomly chosen nodes for the given duration (e.g. "5m"). Each
+    disruption hits one node for a while and is undone before the next
+    step; all are undone when the duration ends.
+      seed:        seed of the random choices; drawn at random if omitted.
+      targets:     (required) names of the nodes that may be disrupted.
+      actions:     disruptions to choose from: killHeal, restart, pause and
+                   partition. All of them if omitted.
+      maxAffected: validators disrupted at the same time, default 1. Fewer
+                   are if more would cost the network its quorum.
+    Every disruption is recorded in the step timings, so a run can be
+    replayed with the same seed.
+    Example:
+      - chaos: 5m
+        seed: 42
+        targets: [validator]
+        actions: [killHeal, pause]`,
+	FuncUpgradeNode: `Replace the client of a node, or of all instances of a multi-instance
+    node one after the other, with the client of another image. Each node
+    is stopped gracefully and restarted from the new image on its data
+    directory, keeping its validator ID, and synced before the next one
+    is upgraded.
+      imageName: (required) image of the new client.
+      interval:  time to wait between upgrading two instances (e.g. "30s").
+    Example:
+      - upgradeNode: validator
+        imageName: sonic:v2.1.6
+        interval: 30s`,
+	FuncRepeat: `Run the given steps the given number of times, one iteration after
+    the other. In the values of the steps, ${i} is replaced by the number
+    of the iteration, counting from 0, so that each iteration may act on
+    nodes of its own. A nested repeat step names a variable of its own to
+    refer to both iterations.
+      times:    (required) number of iterations.
+      variable: name of the variable replaced by the iteration, default i.
+      steps:    (required) the steps to repeat.
+    Example:
+      - repeat:
+        times: 3
+        steps:
+          - startNode: val-${i}
+            type: validator
+          - waitForEpoch
+          - stopNode: val-${i}`,
+	FuncParallel: `Run the given steps at the same time, each one a branch of its own.
+    The step completes once all branches have, and fails if any of them
+    fails, cancelling the others. Branches must not act on the same
+    nodes, applications or delegators, and partition, heal, chaos,
+    snapshot and parallel steps cannot be branches. A repeat step among
+    the branches adds a branch for each step it repeats.
+    Example:
+      - parallel:
+        - stopNode: val-1
+        - startNode: val-2
+          type: validator`,
+	FuncInclude: `Run the steps of a fragment, a file of its own listing them under
+    Steps, in place of this step. The path is relative to the including
+    file. A fragment may declare Parameters like a scenario, which the
+    include step gives values to.
+      parameters: values of the parameters of the fragment, by name.
+    Example:
+      - include: ../fragments/bootstrap.yml
+        parameters:
+          validators: 4`,
+	FuncSchedule: `Run the given steps in the background of the steps after this one,
+    each once its at, atBlock or atEpoch is reached. The step completes at
+    once, and the scheduled steps run one after another in the order they
+    are listed, a step due while an earlier one still runs firing once
+    that one is done. They are awaited before the end checks, and a failing
+    one fails the scenario. No step after this one may act on the nodes,
+    applications or delegators the scheduled steps act on.
+    Example:
+      - schedule:
+        - stopNode: val-2
+          at: 37s
+        - checks:
+          - blockHeights
+          atBlock: 500`,
+	FuncSnapshot: `Save the state of the network under the given name. All nodes are
+    stopped gracefully, their data directories and the validator IDs,
+    delegators and expected stakes tracked by Norma are copied to
+    snapshots/<name> in the output directory, and the nodes are started
+    again. 'norma run --from-snapshot' continues a scenario from there.
+    Requires that no application is running and that the network is
+    neither partitioned nor has throttled or paused nodes.
+    Example:
+      - snapshot: after-upgrade`,
 }
 
 // paramDescriptions provides a human-readable description for each parameter key.
 var paramDescriptions = map[string]string{
-	"type":           "Node type (\"validator\", \"observer\", \"rpc\") for startNode; application type for runApp.",
-	"imageName":      "Docker image name to use for the node.",
-	"dataVolume":     "Docker volume name to mount as the node data directory.",
-	"stake":          "Stake in S (uint64). Defaults to 5,000,000 S during node start and the full self-stake when undelegating.",
-	"instances":      "Number of node instances to start.",
-	"failing":        "When true, the step is expected to fail; a passing result is treated as an error.",
-	"extraArguments": "Extra command line arguments for sonicd.",
-	"users":          "Number of concurrent user accounts the application should simulate.",
-	"rate":           "Transaction rate configuration for the application.",
+	"type":                "Node type (\"validator\", \"observer\", \"rpc\") for startNode; application type for runApp.",
+	"imageName":           "Docker image name to use for the node.",
+	"dataVolume":          "Docker volume name to mount as the node data directory.",
+	"stake":               "Stake in S (uint64). Defaults to 5,000,000 S during node start and the full self-stake when undelegating.",
+	"instances":           "Number of node instances to start.",
+	"failing":             "When true, the step is expected to fail; a passing result is treated as an error.",
+	"extraArguments":      "Extra command line arguments for sonicd.",
+	"cheater":             "When true, a second node signs with the key of the validator, making it double-sign.",
+	"users":               "Number of concurrent user accounts the application should simulate.",
+	"rate":                "Transaction rate configuration for the application.",
+	"egress":              "Bandwidth limit of the traffic a node sends, e.g. \"10mbit\".",
+	"ingress":             "Bandwidth limit of the traffic a node receives, e.g. \"10mbit\".",
+	"burst":               "Data a throttled node may transfer at full speed before its limits apply, e.g. \"32kb\".",
+	"clockOffset":         "Offset of the node's clock from real time, e.g. \"+2s\" or \"-500ms\".",
+	"offset":              "New offset of the node's clock from real time, e.g. \"+2s\" or \"-500ms\".",
+	"cpus":                "Number of CPUs a node may use, may be fractional, e.g. 1.5.",
+	"memory":              "Memory a node may use, without swap, e.g. \"2gb\".",
+	"blkioWeight":         "Block IO weight of a node relative to other nodes, from 10 to 1000.",
+	"clientsPerContainer": "Number of instances whose clients share one container, e.g. 10.",
+	"liveCache":           "Size of the state database's node cache of a client, e.g. \"32mb\".",
+	"cache":               "Memory a client uses for its other caches, e.g. \"64mb\".",
+	"goMemLimit":          "Soft memory limit of the Go runtime of a client, e.g. \"512mb\".",
+	"seed":                "Seed of the random choices of a chaos step.",
+	"targets":             "Names of the nodes a chaos step may disrupt.",
+	"actions":             "Disruptions a chaos step chooses from: killHeal, restart, pause, partition.",
+	"maxAffected":         "Number of validators a chaos step disrupts at the same time at most.",
+	"interval":            "Time to wait between upgrading two instances of a node, e.g. \"30s\".",
+	"times":               "Number of iterations of a repeat step.",
+	"variable":            "Name of the variable a repeat step replaces by the iteration, referred to as ${name}; default i.",
+	"steps":               "Steps run in each iteration of a repeat step.",
+	"parameters":          "Values of the parameters of an included fragment, by name.",
+	"timeout":             "Time the step may take at most, e.g. \"5m\"; the step fails once it is up.",
+	"at":                  "Only in the steps of a schedule step: time after the schedule step at which the step runs, e.g. \"37s\".",
+	"atBlock":             "Only in the steps of a schedule step: block number from which on the step runs.",
+	"atEpoch":             "Only in the steps of a schedule step: epoch number from which on the step runs.",
+	"expectError":         "Expects the step to fail: true for any error, a substring of the error, or a regular expression enclosed in slashes, e.g. \"/stake .* too low/\". The step fails if it succeeds or fails otherwise.",
 }
 
 // allowedParams defines which parameter keys are valid for each step function.
 var allowedParams = map[StepFunction][]string{
-	FuncStartNode:    {"type", "imageName", "dataVolume", "stake", "instances", "failing", "extraArguments"},
-	FuncStopNode:     {},
-	FuncRunApp:       {"type", "users", "rate"},
-	FuncStopApp:      {},
-	FuncUpdateRules:  {},
-	FuncDelegate:     {},
-	FuncUndelegate:   {},
-	FuncVerifyStakes: {},
-	FuncAdvanceEpoch: {},
-	FuncWaitForEpoch: {},
-	FuncWaitFor:      {},
-	FuncChecks:       {},
-	FuncKillSonic:    {},
-	FuncHealDb:       {},
+	FuncStartNode:       {"type", "imageName", "dataVolume", "stake", "instances", "failing", "extraArguments", "cheater", "clockOffset", "cpus", "memory", "blkioWeight", "clientsPerContainer", "liveCache", "cache", "goMemLimit"},
+	FuncStopNode:        {},
+	FuncRunApp:          {"type", "users", "rate"},
+	FuncStopApp:         {},
+	FuncUpdateRules:     {},
+	FuncDelegate:        {},
+	FuncUndelegate:      {},
+	FuncVerifyStakes:    {},
+	FuncAdvanceEpoch:    {},
+	FuncWaitForEpoch:    {},
+	FuncWaitFor:         {},
+	FuncChecks:          {},
+	FuncKillSonic:       {},
+	FuncHealDb:          {},
+	FuncPartition:       {},
+	FuncHeal:            {},
+	FuncSetLink:         {},
+	FuncThrottleNode:    {"egress", "ingress", "burst"},
+	FuncUnthrottleNode:  {},
+	FuncPauseNode:       {},
+	FuncResumeNode:      {},
+	FuncSetClockSkew:    {"offset"},
+	FuncUpdateResources: {"cpus", "memory", "blkioWeight"},
+	FuncConnect:         {},
+	FuncDisconnect:      {},
+	FuncChaos:           {"seed", "targets", "actions", "maxAffected"},
+	FuncUpgradeNode:     {"imageName", "interval"},
+	FuncSnapshot:        {},
+	FuncRepeat:          {"times", "variable", "steps"},
+	FuncParallel:        {},
+	FuncInclude:         {"parameters"},
+	FuncSchedule:        {},
 }
 
+// commonParams lists the parameters every step accepts on top of its
+// allowedParams, except the repeat and include steps, which Parse replaces
+// by the steps they stand for.
+var commonParams = []string{"timeout", "expectError", "at", "atBlock", "atEpoch"}
+
 // parseParam parses a single parameter key-value pair.
 func (s *Step) parseParam(key string, val *yaml.Node) error {
 	// Validate that this parameter is allowed for the current step function.
 	allowed, known := allowedParams[s.Function]
-	if !known || !slices.Contains(allowed, key) {
+	common := s.Function != FuncRepeat && s.Function != FuncInclude && slices.Contains(commonParams, key)
+	if !known || !(slices.Contains(allowed, key) || common) {
 		return fmt.Errorf("parameter %q is not valid for %s", key, s.Function)
 	}
 
@@ -632,6 +1079,12 @@ func (s *Step) parseParam(key string, val *yaml.Node) error {
 			return fmt.Errorf("invalid extraArguments value: %w", err)
 		}
 		s.ExtraArguments = v
+	case "cheater":
+		var v bool
+		if err := val.Decode(&v); err != nil {
+			return fmt.Errorf("invalid cheater value: %w", err)
+		}
+		s.Cheater = v
 	case "users":
 		var v int
 		if err := val.Decode(&v); err != nil {
@@ -644,17 +1097,256 @@ func (s *Step) parseParam(key string, val *yaml.Node) error {
 			return fmt.Errorf("invalid rate value: %w", err)
 		}
 		s.Rate = &r
+	case "egress", "ingress":
+		var v string
+		if err := val.Decode(&v); err != nil {
+			return fmt.Errorf("invalid %s value: %w", key, err)
+		}
+		rate, err := ParseBandwidth(v)
+		if err != nil {
+			return fmt.Errorf("invalid %s value: %w", key, err)
+		}
+		if key == "egress" {
+			s.Egress = rate
+		} else {
+			s.Ingress = rate
+		}
+	case "burst":
+		var v string
+		if err := val.Decode(&v); err != nil {
+			return fmt.Errorf("invalid burst value: %w", err)
+		}
+		size, err := ParseByteSize(v)
+		if err != nil {
+			return fmt.Errorf("invalid burst value: %w", err)
+		}
+		s.Burst = size
+	case "clockOffset", "offset":
+		var v string
+		if err := val.Decode(&v); err != nil {
+			return fmt.Errorf("invalid %s value: %w", key, err)
+		}
+		d, err := time.ParseDuration(v)
+		if err != nil {
+			return fmt.Errorf("invalid %s value: %w", key, err)
+		}
+		s.ClockOffset = &d
+	case "cpus":
+		var v float64
+		if err := val.Decode(&v); err != nil {
+			return fmt.Errorf("invalid cpus value: %w", err)
+		}
+		s.CPUs = v
+	case "memory":
+		var v string
+		if err := val.Decode(&v); err != nil {
+			return fmt.Errorf("invalid memory value: %w", err)
+		}
+		size, err := ParseByteSize(v)
+		if err != nil {
+			return fmt.Errorf("invalid memory value: %w", err)
+		}
+		s.Memory = size
+	case "blkioWeight":
+		var v uint16
+		if err := val.Decode(&v); err != nil {
+			return fmt.Errorf("invalid blkioWeight value: %w", err)
+		}
+		s.BlkioWeight = v
+	case "clientsPerContainer":
+		var v int
+		if err := val.Decode(&v); err != nil {
+			return fmt.Errorf("invalid clientsPerContainer value: %w", err)
+		}
+		s.ClientsPerContainer = &v
+	case "liveCache", "cache", "goMemLimit":
+		var v string
+		if err := val.Decode(&v); err != nil {
+			return fmt.Errorf("invalid %s value: %w", key, err)
+		}
+		size, err := ParseByteSize(v)
+		if err != nil {
+			return fmt.Errorf("invalid %s value: %w", key, err)
+		}
+		switch key {
+		case "liveCache":
+			s.LiveCache = size
+		case "cache":
+			s.Cache = size
+		default:
+			s.GoMemLimit = size
+		}
+	case "seed":
+		var v int64
+		if err := val.Decode(&v); err != nil {
+			return fmt.Errorf("invalid seed value: %w", err)
+		}
+		s.Seed = &v
+	case "targets":
+		var v []string
+		if err := val.Decode(&v); err != nil {
+			return fmt.Errorf("invalid targets value: %w", err)
+		}
+		s.Targets = v
+	case "actions":
+		var v []string
+		if err := val.Decode(&v); err != nil {
+			return fmt.Errorf("invalid actions value: %w", err)
+		}
+		s.ChaosActions = v
+	case "maxAffected":
+		var v int
+		if err := val.Decode(&v); err != nil {
+			return fmt.Errorf("invalid maxAffected value: %w", err)
+		}
+		s.MaxAffected = &v
+	case "interval":
+		var v string
+		if err := val.Decode(&v); err != nil {
+			return fmt.Errorf("invalid interval value: %w", err)
+		}
+		d, err := time.ParseDuration(v)
+		if err != nil {
+			return fmt.Errorf("invalid interval value: %w", err)
+		}
+		s.Interval = d
+	case "times":
+		var v int
+		if err := val.Decode(&v); err != nil {
+			return fmt.Errorf("invalid times value: %w", err)
+		}
+		s.repeatSpec().times = &v
+	case "variable":
+		var v string
+		if err := val.Decode(&v); err != nil {
+			return fmt.Errorf("invalid variable value: %w", err)
+		}
+		if !variablePattern.MatchString(v) {
+			return fmt.Errorf("variable name must match %v, got %q", variablePattern, v)
+		}
+		s.repeatSpec().variable = v
+	case "steps":
+		if val.Kind != yaml.SequenceNode {
+			return fmt.Errorf("steps must be a list of steps")
+		}
+		s.repeatSpec().steps = val
+	case "timeout":
+		var v string
+		if err := val.Decode(&v); err != nil {
+			return fmt.Errorf("invalid timeout value: %w", err)
+		}
+		d, err := time.ParseDuration(v)
+		if err != nil {
+			return fmt.Errorf("invalid timeout value: %w", err)
+		}
+		if d <= 0 {
+			return fmt.Errorf("timeout must be positive, got %s", d)
+		}
+		s.Timeout = d
+	case "expectError":
+		v, err := parseErrorExpectation(val)
+		if err != nil {
+			return fmt.Errorf("invalid expectError value: %w", err)
+		}
+		s.ExpectError = v
+	case "at":
+		var v string
+		if err := val.Decode(&v); err != nil {
+			return fmt.Errorf("invalid at value: %w", err)
+		}
+		d, err := time.ParseDuration(v)
+		if err != nil {
+			return fmt.Errorf("invalid at value: %w", err)
+		}
+		if d < 0 {
+			return fmt.Errorf("at must not be negative, got %s", d)
+		}
+		return s.setTrigger(Trigger{After: &d})
+	case "atBlock":
+		var v uint64
+		if err := val.Decode(&v); err != nil {
+			return fmt.Errorf("invalid atBlock value: %w", err)
+		}
+		return s.setTrigger(Trigger{Block: &v})
+	case "atEpoch":
+		var v uint64
+		if err := val.Decode(&v); err != nil {
+			return fmt.Errorf("invalid atEpoch value: %w", err)
+		}
+		return s.setTrigger(Trigger{Epoch: &v})
+	case "parameters":
+		if val.Kind != yaml.MappingNode {
+			return fmt.Errorf("parameters must map parameter names to values")
+		}
+		values := map[string]string{}
+		for i := 0; i+1 < len(val.Content); i += 2 {
+			name, value := val.Content[i], val.Content[i+1]
+			if value.Kind != yaml.ScalarNode {
+				return fmt.Errorf("value of parameter %s must be a number, boolean or string", name.Value)
+			}
+			values[name.Value] = value.Value
+		}
+		s.includeSpec().values = values
 	}
 	return nil
 }
 
+// repeatSpec returns the parameters of a repeat step, creating them on first
+// use.
+func (s *Step) repeatSpec() *repeatSpec {
+	if s.repeat == nil {
+		s.repeat = &repeatSpec{}
+	}
+	return s.repeat
+}
+
+// includeSpec returns the parameters of an include step, creating them on
+// first use.
+func (s *Step) includeSpec() *includeSpec {
+	if s.include == nil {
+		s.include = &includeSpec{}
+	}
+	return s.include
+}
+
 // Parse parses a scenario from the given reader.
 func Parse(reader io.Reader) (Scenario, error) {
+	return ParseWithParameters(reader, nil)
+}
+
+// ParseWithParameters parses a scenario, giving its parameters the given
+// values, by name, instead of their defaults. Fragments it includes are
+// looked up relative to the working directory.
+func ParseWithParameters(reader io.Reader, values map[string]string) (Scenario, error) {
+	return parse(reader, values, "", nil)
+}
+
+// parse parses a scenario, giving its parameters the given values. Fragments
+// it includes are looked up relative to dir; chain lists the file of the
+// scenario, if it has one, to detect fragments including it.
+func parse(reader io.Reader, values map[string]string, dir string, chain []string) (Scenario, error) {
+	data, err := io.ReadAll(reader)
+	if err != nil {
+		return Scenario{}, err
+	}
+	data, parameters, err := resolveParameters(data, values)
+	if err != nil {
+		return Scenario{}, err
+	}
+
 	var res Scenario
-	decoder := yaml.NewDecoder(reader)
+	decoder := yaml.NewDecoder(bytes.NewReader(data))
 	decoder.KnownFields(true)
-	err := decoder.Decode(&res)
-	if err != nil {
+	if err := decoder.Decode(&res); err != nil {
+		return Scenario{}, err
+	}
+	if len(parameters) > 0 {
+		res.Parameters = parameters
+	}
+	if res.Steps, err = expandRepeats(res.Steps, nil); err != nil {
+		return Scenario{}, err
+	}
+	if res.Steps, err = expandIncludes(res.Steps, dir, chain); err != nil {
 		return Scenario{}, err
 	}
 	res.setDefaults()
@@ -667,13 +1359,21 @@ func ParseBytes(data []byte) (Scenario, error) {
 }
 
 // ParseFile parses a scenario from a file.
-func ParseFile(path string) (scenario Scenario, err error) {
+func ParseFile(path string) (Scenario, error) {
+	return ParseFileWithParameters(path, nil)
+}
+
+// ParseFileWithParameters parses a scenario from a file, giving its
+// parameters the given values, by name, instead of their defaults. Fragments
+// it includes are looked up relative to the file.
+func ParseFileWithParameters(path string, values map[string]string) (scenario Scenario, err error) {
 	reader, err := os.Open(path)
 	if err != nil {
 		return Scenario{}, err
 	}
 	defer func() { err = errors.Join(err, reader.Close()) }()
-	return Parse(reader)
+	path = filepath.Clean(path)
+	return parse(reader, values, filepath.Dir(path), []string{path})
 }
 
 // setDefaults sets default values on the scenario.
@@ -683,6 +1383,7 @@ func (s *Scenario) setDefaults() {
 	// if the scenario does not disable end checks, append the default end
 	// checks to the steps list
 	if !s.DisableEndChecks {
+		steps := len(s.Steps)
 		s.Steps = append(s.Steps,
 			Step{Function: FuncAdvanceEpoch},
 			Step{Function: FuncAdvanceEpoch},
@@ -694,6 +1395,7 @@ func (s *Scenario) setDefaults() {
 				},
 			},
 		)
+		s.EndChecks = len(s.Steps) - steps
 	}
 }
 
@@ -707,7 +1409,9 @@ var checkFunctionDescriptions = map[StepFunction]string{
 	FuncCheckEventThrottled: "Assert that validators listed in throttledNodes emit events at a significantly lower rate than the rest.",
 	FuncCheckNetworkRules:   "Assert that the active network rules on all nodes match the expected rules patch.",
 
+	FuncCheckBlockTimestamps:  "Assert that block timestamps never decrease and the latest block of every node is within bound of real time.",
 	FuncCheckValidatorsActive: "Assert that every running validator node is in the current epoch's validator set.",
+	FuncCheckValidatorSlashed: "Assert that the validators of the nodes listed in slashedNodes were slashed for double-signing while blocks are still produced.",
 }
 
 // checkFunctionParams lists the optional parameters accepted by each sub-check function.
@@ -720,7 +1424,9 @@ var checkFunctionParams = map[StepFunction][]string{
 	FuncCheckEventThrottled: {"throttledNodes", "failing"},
 	FuncCheckNetworkRules:   {"rules", "duration", "failing"},
 
+	FuncCheckBlockTimestamps:  {"bound", "failing"},
 	FuncCheckValidatorsActive: {"failing"},
+	FuncCheckValidatorSlashed: {"slashedNodes", "duration", "failing"},
 }
 
 // checkParamDescriptions provides a human-readable description for each sub-check parameter.
@@ -730,7 +1436,9 @@ var checkParamDescriptions = map[string]string{
 	"rules":          "Expected network rules patch (NetworkRulesPatch field structure).",
 	"tolerance":      "For a height check, the allowed deviation (int, in blocks) between nodes. For a production, halt or gas rate check, the length of the observation window expressed in monitoring samples (one per second); duration overrides it.",
 	"throttledNodes": "List of node labels expected to be throttled.",
-	"duration":       "Duration (e.g. \"30s\"). For a production, halt or gas rate check, how long to actively observe the network; only data collected while waiting is judged, and the window must be at least 2s. For a height or rules check, how long the nodes are given to converge, with 0 meaning a single attempt.",
+	"slashedNodes":   "List of labels of validator nodes expected to be slashed for double-signing.",
+	"bound":          "Duration (e.g. \"10s\"). For a timestamp check, how far the latest block's timestamp may deviate from real time. Defaults to 10s.",
+	"duration":       "Duration (e.g. \"30s\"). For a production, halt or gas rate check, how long to actively observe the network; only data collected while waiting is judged, and the window must be at least 2s. For a height or rules check, how long the nodes are given to converge, with 0 meaning a single attempt. For a slashing check, how long to wait for the slashing, defaulting to 60s.",
 }
 
 // PrintHelp writes a formatted summary of all available scenario step
@@ -745,6 +1453,11 @@ func PrintHelp(w io.Writer) error {
 	})
 
 	ew := &errWriter{w: w}
+	ew.printf("Parameters of every step but repeat and include:\n")
+	for _, p := range commonParams {
+		ew.printf("  %-26s %s\n", p+":", paramDescriptions[p])
+	}
+	ew.printf("\n")
 	ew.printf("Scenario step functions:\n\n")
 	for _, fn := range stepFns {
 		desc := stepFunctionDescriptions[fn]
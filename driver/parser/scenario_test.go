@@ -17,8 +17,11 @@
 package parser
 
 import (
+	"fmt"
 	"math/big"
 	"os"
+	"path/filepath"
+	"regexp"
 	"strings"
 	"testing"
 	"time"
@@ -40,6 +43,9 @@ func TestAllStepFunctionsAreDocumented(t *testing.T) {
 
 func TestAllParamKeysAreDocumented(t *testing.T) {
 	seen := map[string]bool{}
+	for _, p := range commonParams {
+		seen[p] = true
+	}
 	for _, params := range allowedParams {
 		for _, p := range params {
 			seen[p] = true
@@ -133,6 +139,37 @@ Scenario:
 	)
 }
 
+func TestParseBytes_InitialState(t *testing.T) {
+	input := `
+Name: State Test
+Description: Starts on a large state
+InitialState:
+  seed: 7
+  accounts: 1000
+  balance: 50
+  contracts: 20
+  contractTypes: [counter, erc20]
+  storageSlots: 5000
+Scenario:
+  - startNode: validator
+    type: validator
+`
+	scenario, err := ParseBytes([]byte(input))
+	require.NoError(t, err)
+	require.Equal(t, &genesis.InitialState{
+		Seed:          7,
+		Accounts:      1000,
+		Balance:       50,
+		Contracts:     20,
+		ContractTypes: []string{"counter", "erc20"},
+		StorageSlots:  5000,
+	}, scenario.InitialState)
+	require.NoError(t, scenario.Check())
+
+	scenario.InitialState.ContractTypes = []string{"uniswap"}
+	require.ErrorContains(t, scenario.Check(), `invalid initial state: unknown contract type "uniswap"`)
+}
+
 func TestParseBytes_UpdateRules(t *testing.T) {
 	input := `
 Name: Update Rules Test
@@ -309,895 +346,2885 @@ Scenario:
 	require.Equal(t, FuncVerifyStakes, scenario.Steps[0].Function)
 }
 
-func TestStepCheck_Delegate_ReportsSemanticErrors(t *testing.T) {
+func TestParseBytes_PartitionAndHeal(t *testing.T) {
+	input := `
+Name: Partition Test
+Description: t
+Scenario:
+  - partition:
+    - [val-1, val-2]
+    - [val-3]
+  - heal
+`
+	scenario, err := ParseBytes([]byte(input))
+	require.NoError(t, err)
+	require.NoError(t, scenario.Check())
+
+	step := scenario.Steps[0]
+	require.Equal(t, FuncPartition, step.Function)
+	require.Equal(t, [][]string{{"val-1", "val-2"}, {"val-3"}}, step.Groups)
+
+	require.Equal(t, FuncHeal, scenario.Steps[1].Function)
+}
+
+func TestParseBytes_Partition_RejectsMalformedGroups(t *testing.T) {
+	cases := map[string]string{
+		"scalar":      "partition: val-1",
+		"flat list":   "partition: [val-1, val-2]",
+		"null groups": "partition:",
+	}
+	for name, step := range cases {
+		t.Run(name, func(t *testing.T) {
+			input := "Name: t\nDescription: t\nScenario:\n  - " + step + "\n"
+			_, err := ParseBytes([]byte(input))
+			require.Error(t, err)
+		})
+	}
+}
+
+func TestStepCheck_Partition_ReportsSemanticErrors(t *testing.T) {
 	cases := map[string]struct {
-		step      Step
+		groups    [][]string
 		errSubstr string
 	}{
-		"no targets": {
-			step: Step{
-				Function: FuncDelegate,
-			},
-			errSubstr: "at least one target",
-		},
-		"missing node": {
-			step: Step{
-				Function: FuncDelegate,
-				DelegateTargets: []DelegateTarget{
-					{Delegator: "alice", Stake: 100},
-				},
-			},
-			errSubstr: "'node' field",
+		"single group": {
+			groups:    [][]string{{"a", "b"}},
+			errSubstr: "at least two groups",
 		},
-		"missing delegator": {
-			step: Step{
-				Function: FuncDelegate,
-				DelegateTargets: []DelegateTarget{
-					{Node: "val", Stake: 100},
-				},
-			},
-			errSubstr: "'delegator' field",
+		"empty group": {
+			groups:    [][]string{{"a"}, {}},
+			errSubstr: "must not be empty",
 		},
-		"zero stake": {
-			step: Step{
-				Function: FuncDelegate,
-				DelegateTargets: []DelegateTarget{
-					{Node: "val", Delegator: "alice", Stake: 0},
-				},
-			},
-			errSubstr: "stake must be greater than zero",
+		"invalid name": {
+			groups:    [][]string{{"a"}, {"not a name"}},
+			errSubstr: "node name must match",
 		},
-		"invalid delegator name": {
-			step: Step{
-				Function: FuncDelegate,
-				DelegateTargets: []DelegateTarget{
-					{Node: "val", Delegator: "not a name", Stake: 100},
-				},
-			},
-			errSubstr: "delegator name must match",
+		"node in two groups": {
+			groups:    [][]string{{"a", "b"}, {"b"}},
+			errSubstr: "already part of group 1",
 		},
 	}
 
 	for name, tc := range cases {
 		t.Run(name, func(t *testing.T) {
-			err := tc.step.Check()
+			step := Step{Function: FuncPartition, Groups: tc.groups}
+			err := step.Check()
 			require.Error(t, err)
 			require.Contains(t, err.Error(), tc.errSubstr)
 		})
 	}
 }
 
-func TestStepCheck_UndelegateWithDelegator_RejectsInvalidDelegatorName(t *testing.T) {
-	step := Step{
-		Function: FuncUndelegate,
-		UndelegateTargets: []UndelegateTarget{
-			{Node: "val", Delegator: "not a name"},
+func TestCheck_PartitionsMustBeHealedInOrder(t *testing.T) {
+	partition := Step{Function: FuncPartition, Groups: [][]string{{"a"}, {"b"}}}
+	heal := Step{Function: FuncHeal}
+
+	cases := map[string]struct {
+		steps     []Step
+		errSubstr string
+	}{
+		"heal without partition": {
+			steps:     []Step{heal},
+			errSubstr: "no preceding partition",
+		},
+		"nested partition": {
+			steps:     []Step{partition, partition},
+			errSubstr: "already partitioned",
+		},
+		"second heal": {
+			steps:     []Step{partition, heal, heal},
+			errSubstr: "no preceding partition",
 		},
 	}
-	err := step.Check()
-	require.Error(t, err)
-	require.Contains(t, err.Error(), "delegator name must match")
-}
-
-func TestParseBytes_Checks(t *testing.T) {
-	input := `
-Name: Checks Test
-Scenario:
-  - checks:
-      - blocksHalted:
-        failing: true
-      - blockHeights:
-        tolerance: 5
-      - blockGasRate:
-        ceiling: 16500000
-        failing: true
-      - blockHashes
-`
-	scenario, err := ParseBytes([]byte(input))
-	require.NoError(t, err)
-	require.Len(t, scenario.Steps, 4)
-
-	step := scenario.Steps[0]
-	require.Equal(t, FuncChecks, step.Function)
-	require.Len(t, step.SubChecks, 4)
-
-	// blocksHalted with failing
-	check := step.SubChecks[0]
-	require.Equal(t, FuncCheckBlocksHalted, check.Function)
-	require.True(t, check.Failing)
-
-	// blockHeights with tolerance
-	check = step.SubChecks[1]
-	require.Equal(t, FuncCheckBlockHeights, check.Function)
-	require.NotNil(t, check.Tolerance)
-	require.Equal(t, 5, *check.Tolerance)
 
-	// blockGasRate with ceiling + failing
-	check = step.SubChecks[2]
-	require.Equal(t, FuncCheckBlockGasRate, check.Function)
-	require.NotNil(t, check.Ceiling)
-	require.EqualValues(t, 16500000, *check.Ceiling)
-	require.True(t, check.Failing)
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			scenario := Scenario{Name: "Test", Description: "t", Steps: tc.steps}
+			err := scenario.Check()
+			require.Error(t, err)
+			require.Contains(t, err.Error(), tc.errSubstr)
+		})
+	}
 
-	// blockHashes
-	check = step.SubChecks[3]
-	require.Equal(t, FuncCheckBlockHashes, check.Function)
+	scenario := Scenario{Name: "Test", Description: "t",
+		Steps: []Step{partition, heal, partition}}
+	require.NoError(t, scenario.Check())
 }
 
-func TestParseBytes_CheckParams_NestedForm(t *testing.T) {
+func TestParseBytes_RegionsLinksAndSetLink(t *testing.T) {
 	input := `
-Name: Nested Check Params Test
+Name: Link Test
+Description: t
+Regions:
+  eu: [val-eu]
+  us: [val-us, rpc]
+Links:
+  - between: [eu, us]
+    latency: 80ms
+    jitter: 5ms
+    loss: 0.5
 Scenario:
-  - checks:
-      - eventThrottled:
-          failing: true
-          throttledNodes:
-            - validator-dominant
-            - validator-B
-      - blockGasRate:
-          ceiling: 1000
-          failing: true
+  - setLink:
+      between: [eu, rpc]
+      latency: 150ms
+      reorder: 10
 `
 	scenario, err := ParseBytes([]byte(input))
 	require.NoError(t, err)
-	step := scenario.Steps[0]
-	require.Equal(t, FuncChecks, step.Function)
-	require.Len(t, step.SubChecks, 2)
+	require.NoError(t, scenario.Check())
 
-	et := step.SubChecks[0]
-	require.Equal(t, FuncCheckEventThrottled, et.Function)
-	require.True(t, et.Failing)
-	require.Equal(
-		t, []string{"validator-dominant", "validator-B"},
-		et.ThrottledNodes,
-	)
+	require.Equal(t, map[string][]string{
+		"eu": {"val-eu"},
+		"us": {"val-us", "rpc"},
+	}, scenario.Regions)
+	require.Equal(t, []Link{{
+		Between: []string{"eu", "us"},
+		Latency: 80 * time.Millisecond,
+		Jitter:  5 * time.Millisecond,
+		Loss:    0.5,
+	}}, scenario.Links)
 
-	gr := step.SubChecks[1]
-	require.Equal(t, FuncCheckBlockGasRate, gr.Function)
-	require.NotNil(t, gr.Ceiling)
-	require.EqualValues(t, 1000, *gr.Ceiling)
-	require.True(t, gr.Failing)
+	step := scenario.Steps[0]
+	require.Equal(t, FuncSetLink, step.Function)
+	require.Equal(t, &Link{
+		Between: []string{"eu", "rpc"},
+		Latency: 150 * time.Millisecond,
+		Reorder: 10,
+	}, step.Link)
+
+	require.Equal(t, []string{"val-us", "rpc"}, scenario.ResolveLinkEnd("us"))
+	require.Equal(t, []string{"rpc"}, scenario.ResolveLinkEnd("rpc"))
 }
 
-func TestParseBytes_CheckParams_RejectsUnknown(t *testing.T) {
-	input := `
-Name: Unknown Param Test
-Scenario:
-  - checks:
-      - eventThrottled:
-          bogus: 42
-`
+func TestParseBytes_SetLink_RejectsScalar(t *testing.T) {
+	input := "Name: t\nDescription: t\nScenario:\n  - setLink: eu\n"
 	_, err := ParseBytes([]byte(input))
 	require.Error(t, err)
-	require.Contains(t, err.Error(), `parameter "bogus" is not valid`)
 }
 
-func TestParseBytes_CheckParams_RejectsWrongParamForFunction(t *testing.T) {
-	cases := map[string]string{
-		"throttledNodes on blocksProduced": `
-Name: Wrong Param Test
-Scenario:
-  - checks:
-      - blocksProduced:
-          throttledNodes:
-            - validator-A
-`,
-		"ceiling on blocksHalted": `
-Name: Wrong Param Test
-Scenario:
-  - checks:
-      - blocksHalted:
-          ceiling: 5
-`,
-		"tolerance on eventThrottled": `
-Name: Wrong Param Test
-Scenario:
-  - checks:
-      - eventThrottled:
-          tolerance: 5
-`,
-		"rules on blockGasRate": `
-Name: Wrong Param Test
-Scenario:
-  - checks:
-      - blockGasRate:
-          rules:
-            Epochs:
-              MaxEpochDuration: 5s
-`,
+func TestLinkCheck_ReportsSemanticErrors(t *testing.T) {
+	cases := map[string]struct {
+		link      Link
+		errSubstr string
+	}{
+		"one end": {
+			link:      Link{Between: []string{"a"}},
+			errSubstr: "exactly two ends",
+		},
+		"invalid end": {
+			link:      Link{Between: []string{"a", "not a name"}},
+			errSubstr: "link end must match",
+		},
+		"negative latency": {
+			link:      Link{Between: []string{"a", "b"}, Latency: -time.Millisecond},
+			errSubstr: "latency must be non-negative",
+		},
+		"negative jitter": {
+			link:      Link{Between: []string{"a", "b"}, Jitter: -time.Millisecond},
+			errSubstr: "jitter must be non-negative",
+		},
+		"loss above 100": {
+			link:      Link{Between: []string{"a", "b"}, Loss: 101},
+			errSubstr: "loss must be a percentage",
+		},
+		"reorder without latency": {
+			link:      Link{Between: []string{"a", "b"}, Reorder: 10},
+			errSubstr: "reorder requires a latency",
+		},
 	}
-	for name, input := range cases {
+
+	for name, tc := range cases {
 		t.Run(name, func(t *testing.T) {
-			_, err := ParseBytes([]byte(input))
+			err := tc.link.Check()
 			require.Error(t, err)
-			require.Contains(t, err.Error(), "is not valid for check")
+			require.Contains(t, err.Error(), tc.errSubstr)
 		})
 	}
 }
 
-func TestAllCheckFunctionsAreDocumented(t *testing.T) {
-	for _, fn := range allCheckFunctions {
-		desc, ok := checkFunctionDescriptions[fn]
-		require.Truef(t, ok, "check function %q has no entry in checkFunctionDescriptions", fn)
-		require.NotEmptyf(t, strings.TrimSpace(desc), "check function %q has empty description", fn)
+func TestCheck_Regions_ReportsSemanticErrors(t *testing.T) {
+	cases := map[string]struct {
+		regions   map[string][]string
+		errSubstr string
+	}{
+		"empty region": {
+			regions:   map[string][]string{"eu": {}},
+			errSubstr: "must not be empty",
+		},
+		"invalid region name": {
+			regions:   map[string][]string{"not a name": {"a"}},
+			errSubstr: "region name must match",
+		},
+		"node in two regions": {
+			regions:   map[string][]string{"eu": {"a"}, "us": {"a"}},
+			errSubstr: "already part of region eu",
+		},
+	}
 
-		_, ok = checkFunctionParams[fn]
-		require.Truef(t, ok, "check function %q has no entry in checkFunctionParams", fn)
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			scenario := Scenario{Name: "Test", Description: "t", Regions: tc.regions}
+			err := scenario.Check()
+			require.Error(t, err)
+			require.Contains(t, err.Error(), tc.errSubstr)
+		})
 	}
 }
 
-func TestAllCheckParamKeysAreDocumented(t *testing.T) {
-	seen := map[string]bool{}
-	for _, params := range checkFunctionParams {
-		for _, p := range params {
-			seen[p] = true
-		}
-	}
-	for p := range seen {
-		desc, ok := checkParamDescriptions[p]
-		require.Truef(t, ok, "check parameter %q has no entry in checkParamDescriptions", p)
-		require.NotEmptyf(t, strings.TrimSpace(desc), "check parameter %q has empty description", p)
-	}
+func TestStepCheck_SetLink_RequiresLink(t *testing.T) {
+	step := Step{Function: FuncSetLink}
+	require.Error(t, step.Check())
+
+	step.Link = &Link{Between: []string{"a", "b"}, Latency: time.Millisecond}
+	require.NoError(t, step.Check())
 }
 
-func TestParseBytes_ThrottledNodes_FlatForm(t *testing.T) {
-	input := `
-Name: Flat Throttled Nodes Test
-Scenario:
-  - checks:
-      - eventThrottled:
-        failing: true
-        throttledNodes:
-          - validator-A
-`
-	scenario, err := ParseBytes([]byte(input))
-	require.NoError(t, err)
-	step := scenario.Steps[0]
-	require.Equal(t, FuncChecks, step.Function)
-	require.Len(t, step.SubChecks, 1)
-	et := step.SubChecks[0]
-	require.Equal(t, FuncCheckEventThrottled, et.Function)
-	require.True(t, et.Failing)
-	require.Equal(t, []string{"validator-A"}, et.ThrottledNodes)
+func TestParseBytes_TopologyNames(t *testing.T) {
+	cases := map[string]Topology{
+		"fullMesh":   {Kind: TopologyFullMesh},
+		"ring":       {Kind: TopologyRing},
+		"line":       {Kind: TopologyLine},
+		"star:relay": {Kind: TopologyStar, Hub: "relay"},
+	}
+	for value, want := range cases {
+		t.Run(value, func(t *testing.T) {
+			input := "Name: t\nDescription: t\nTopology: " + value + "\nScenario: []\n"
+			scenario, err := ParseBytes([]byte(input))
+			require.NoError(t, err)
+			require.Equal(t, want, scenario.Topology)
+		})
+	}
 }
 
-func TestParseBytes_BlocksProduced_ParsesDuration(t *testing.T) {
+func TestParseBytes_TopologyRejectsUnknownNames(t *testing.T) {
+	for _, value := range []string{"mesh", "star", "ring:relay", "[ring]"} {
+		t.Run(value, func(t *testing.T) {
+			input := "Name: t\nDescription: t\nTopology: " + value + "\nScenario: []\n"
+			_, err := ParseBytes([]byte(input))
+			require.Error(t, err)
+		})
+	}
+}
+
+func TestParseBytes_TopologyAdjacencyListAndConnectSteps(t *testing.T) {
 	input := `
-Name: Duration Test
+Name: Topology Test
+Description: t
+Regions:
+  eu: [val-eu]
+Topology:
+  eu: [relay]
+  obs: [relay]
 Scenario:
-  - checks:
-      - blocksProduced:
-        tolerance: 10
-        duration: 30s
+  - connect: [obs, val-eu]
+  - disconnect: [obs, relay]
 `
 	scenario, err := ParseBytes([]byte(input))
 	require.NoError(t, err)
-	require.Len(t, scenario.Steps[0].SubChecks, 1)
+	require.NoError(t, scenario.Check())
 
-	check := scenario.Steps[0].SubChecks[0]
-	require.Equal(t, FuncCheckBlocksProduced, check.Function)
-	require.NotNil(t, check.Tolerance)
-	require.Equal(t, 10, *check.Tolerance)
-	require.NotNil(t, check.Duration)
-	require.Equal(t, 30*time.Second, *check.Duration)
+	require.Equal(t, Topology{
+		Kind: TopologyCustom,
+		Adjacency: map[string][]string{
+			"eu":  {"relay"},
+			"obs": {"relay"},
+		},
+	}, scenario.Topology)
+	require.Equal(t, FuncConnect, scenario.Steps[0].Function)
+	require.Equal(t, []string{"obs", "val-eu"}, scenario.Steps[0].Peers)
+	require.Equal(t, FuncDisconnect, scenario.Steps[1].Function)
+	require.Equal(t, []string{"obs", "relay"}, scenario.Steps[1].Peers)
 }
 
-func TestParseBytes_BlocksProduced_RejectsInvalidDuration(t *testing.T) {
-	cases := map[string]string{
-		"non-duration string": `
-Name: Bad
-Scenario:
-  - checks:
-      - blocksProduced:
-        duration: not-a-duration
-`,
-		"negative duration": `
-Name: Bad
-Scenario:
-  - checks:
-      - blocksProduced:
-        duration: -5s
-`,
+func TestParseBytes_Connect_RejectsScalar(t *testing.T) {
+	input := "Name: t\nDescription: t\nTopology: ring\nScenario:\n  - connect: val-1\n"
+	_, err := ParseBytes([]byte(input))
+	require.Error(t, err)
+}
+
+func TestCheck_Topology_ReportsSemanticErrors(t *testing.T) {
+	cases := map[string]struct {
+		topology  Topology
+		errSubstr string
+	}{
+		"star without hub": {
+			topology:  Topology{Kind: TopologyStar},
+			errSubstr: "star hub must match",
+		},
+		"empty adjacency list": {
+			topology:  Topology{Kind: TopologyCustom, Adjacency: map[string][]string{}},
+			errSubstr: "adjacency list must not be empty",
+		},
+		"invalid neighbor": {
+			topology:  Topology{Kind: TopologyCustom, Adjacency: map[string][]string{"a": {"not a name"}}},
+			errSubstr: "name must match",
+		},
 	}
-	for name, input := range cases {
+
+	for name, tc := range cases {
 		t.Run(name, func(t *testing.T) {
-			_, err := ParseBytes([]byte(input))
+			scenario := Scenario{Name: "Test", Description: "t", Topology: tc.topology}
+			err := scenario.Check()
 			require.Error(t, err)
+			require.Contains(t, err.Error(), tc.errSubstr)
 		})
 	}
 }
 
-func TestParseBytes_SlopeRate(t *testing.T) {
+func TestCheck_ConnectRequiresTopologyOtherThanFullMesh(t *testing.T) {
+	for _, fn := range []StepFunction{FuncConnect, FuncDisconnect} {
+		scenario := Scenario{
+			Name:        "Test",
+			Description: "t",
+			Steps:       []Step{{Function: fn, Peers: []string{"a", "b"}}},
+		}
+		err := scenario.Check()
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "requires a Topology other than fullMesh")
+
+		scenario.Topology = Topology{Kind: TopologyLine}
+		require.NoError(t, scenario.Check())
+	}
+}
+
+func TestStepCheck_Connect_RequiresTwoDifferentNodes(t *testing.T) {
+	cases := map[string][]string{
+		"one node":     {"a"},
+		"three nodes":  {"a", "b", "c"},
+		"same node":    {"a", "a"},
+		"invalid name": {"a", "not a name"},
+	}
+	for name, peers := range cases {
+		t.Run(name, func(t *testing.T) {
+			step := Step{Function: FuncConnect, Peers: peers}
+			require.Error(t, step.Check())
+		})
+	}
+}
+
+func TestParseBytes_Chaos(t *testing.T) {
 	input := `
-Name: Slope Rate Test
+Name: Chaos Test
+Description: t
 Scenario:
-  - runApp: subsidies
-    type: counter
-    users: 10
-    rate:
-      slope:
-        start: 20
-        increment: 5
+  - chaos: 5m
+    seed: 42
+    targets: [validator, rpc]
+    actions: [killHeal, pause]
+    maxAffected: 2
 `
 	scenario, err := ParseBytes([]byte(input))
 	require.NoError(t, err)
+	require.NoError(t, scenario.Check())
 
 	step := scenario.Steps[0]
-	require.NotNil(t, step.Rate)
-	require.NotNil(t, step.Rate.Slope)
-	require.EqualValues(t, 20, step.Rate.Slope.Start)
-	require.EqualValues(t, 5, step.Rate.Slope.Increment)
+	require.Equal(t, FuncChaos, step.Function)
+	require.Equal(t, 5*time.Minute, step.Duration)
+	require.Equal(t, int64(42), *step.Seed)
+	require.Equal(t, []string{"validator", "rpc"}, step.Targets)
+	require.Equal(t, []string{ChaosKillHeal, ChaosPause}, step.ChaosActions)
+	require.Equal(t, 2, *step.MaxAffected)
 }
 
-func TestParseBytes_DefaultsApplied(t *testing.T) {
-	input := `
-Name: Defaults Test
-Scenario:
-  - advanceEpoch
-`
-	scenario, err := ParseBytes([]byte(input))
-	require.NoError(t, err)
-	require.NotNil(t, scenario.InitialRules.Epochs)
-	require.NotNil(t, scenario.InitialRules.Epochs.MaxEpochDuration)
-	require.Equal(
-		t,
-		int64(15*time.Second),
-		int64(*scenario.InitialRules.Epochs.MaxEpochDuration),
-	)
+func TestStepCheck_Chaos_ReportsSemanticErrors(t *testing.T) {
+	zero := 0
+	cases := map[string]struct {
+		step      Step
+		errSubstr string
+	}{
+		"no targets": {
+			step:      Step{Function: FuncChaos, Duration: time.Minute},
+			errSubstr: "at least one target",
+		},
+		"unknown action": {
+			step:      Step{Function: FuncChaos, Duration: time.Minute, Targets: []string{"a"}, ChaosActions: []string{"explode"}},
+			errSubstr: "unknown chaos action",
+		},
+		"duplicate action": {
+			step:      Step{Function: FuncChaos, Duration: time.Minute, Targets: []string{"a"}, ChaosActions: []string{"pause", "pause"}},
+			errSubstr: "listed twice",
+		},
+		"no validator affected": {
+			step:      Step{Function: FuncChaos, Duration: time.Minute, Targets: []string{"a"}, MaxAffected: &zero},
+			errSubstr: "maxAffected must be >= 1",
+		},
+	}
 
-	require.Len(t, scenario.Steps, 4)
-	require.Equal(t, FuncAdvanceEpoch, scenario.Steps[1].Function)
-	require.Equal(t, FuncAdvanceEpoch, scenario.Steps[2].Function)
-	require.Equal(t, FuncChecks, scenario.Steps[3].Function)
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			err := tc.step.Check()
+			require.Error(t, err)
+			require.Contains(t, err.Error(), tc.errSubstr)
+		})
+	}
 }
 
-func TestParseBytes_DisableEndChecks(t *testing.T) {
+func TestCheck_ChaosCannotPartitionAPartitionedNetwork(t *testing.T) {
+	scenario := Scenario{
+		Name:        "Test",
+		Description: "t",
+		Steps: []Step{
+			{Function: FuncPartition, Groups: [][]string{{"a"}, {"b"}}},
+			{Function: FuncChaos, Duration: time.Minute, Targets: []string{"a"}},
+			{Function: FuncChaos, Duration: time.Minute, Targets: []string{"a"}, ChaosActions: []string{ChaosPause}},
+			{Function: FuncHeal},
+		},
+	}
+	err := scenario.Check()
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "step 2 (chaos): partition action requires")
+	require.NotContains(t, err.Error(), "step 3")
+}
+
+func TestParseBytes_ThrottleAndUnthrottleNode(t *testing.T) {
 	input := `
-Name: Disable End Checks Test
-DisableEndChecks: true
+Name: Throttle Test
+Description: t
 Scenario:
-  - advanceEpoch
+  - throttleNode: val-1
+    egress: 10mbit
+    ingress: 1.5gbit
+    burst: 32kb
+  - unthrottleNode: val-1
 `
 	scenario, err := ParseBytes([]byte(input))
 	require.NoError(t, err)
-	require.Len(t, scenario.Steps, 1)
-}
+	require.NoError(t, scenario.Check())
 
-func TestParseBytes_UnknownFunction(t *testing.T) {
-	input := `
-Name: Bad Function
-Scenario:
-  - Do something weird: foo
-`
-	_, err := ParseBytes([]byte(input))
-	require.Error(t, err)
+	step := scenario.Steps[0]
+	require.Equal(t, FuncThrottleNode, step.Function)
+	require.Equal(t, "val-1", step.Identifier)
+	require.Equal(t, Bandwidth(10_000_000), step.Egress)
+	require.Equal(t, Bandwidth(1_500_000_000), step.Ingress)
+	require.Equal(t, ByteSize(32*1024), step.Burst)
+
+	require.Equal(t, FuncUnthrottleNode, scenario.Steps[1].Function)
+	require.Equal(t, "val-1", scenario.Steps[1].Identifier)
 }
 
-func TestCheck_EmptyName(t *testing.T) {
-	scenario := Scenario{
-		Name:        "",
-		Description: "A test scenario.",
-		Steps:       []Step{{Function: FuncAdvanceEpoch}},
+func TestParseBytes_ThrottleNode_RejectsMalformedParameters(t *testing.T) {
+	cases := map[string]string{
+		"rate without unit": "egress: 10",
+		"rate in bytes":     "egress: 10mbps",
+		"zero rate":         "ingress: 0mbit",
+		"negative burst":    "burst: -1kb",
+		"unknown parameter": "latency: 10ms",
+	}
+	for name, param := range cases {
+		t.Run(name, func(t *testing.T) {
+			input := "Name: t\nDescription: t\nScenario:\n  - throttleNode: val-1\n    " + param + "\n"
+			_, err := ParseBytes([]byte(input))
+			require.Error(t, err)
+		})
 	}
-	err := scenario.Check()
-	require.Error(t, err)
 }
 
-func TestCheck_EmptyDescription(t *testing.T) {
-	scenario := Scenario{
-		Name:        "Test",
-		Description: "",
-		Steps:       []Step{{Function: FuncAdvanceEpoch}},
+func TestParseBandwidth(t *testing.T) {
+	cases := map[string]Bandwidth{
+		"500bit":   500,
+		"64kbit":   64_000,
+		"10mbit":   10_000_000,
+		"2.5Gbit":  2_500_000_000,
+		"1tbit":    1_000_000_000_000,
+		"0.001bit": 0, // rounds to zero, rejected
+	}
+	for input, want := range cases {
+		t.Run(input, func(t *testing.T) {
+			got, err := ParseBandwidth(input)
+			if want == 0 {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, want, got)
+		})
 	}
-	err := scenario.Check()
-	require.Error(t, err)
 }
 
-func TestCheck_InvalidNodeName(t *testing.T) {
-	scenario := Scenario{
-		Name:        "Test",
-		Description: "A test scenario.",
-		Steps: []Step{{
-			Function:   FuncStartNode,
-			Identifier: "invalid name with spaces",
-			NodeType:   "validator",
-		}},
+func TestParseByteSize(t *testing.T) {
+	cases := map[string]ByteSize{
+		"1500":  1500,
+		"64b":   64,
+		"32kb":  32 * 1024,
+		"1MB":   1024 * 1024,
+		"2gb":   2 << 30,
+		"0kb":   0, // rejected
+		"1.5kb": 0, // rejected
+	}
+	for input, want := range cases {
+		t.Run(input, func(t *testing.T) {
+			got, err := ParseByteSize(input)
+			if want == 0 {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, want, got)
+		})
 	}
-	err := scenario.Check()
-	require.Error(t, err)
 }
 
-func TestCheck_RunAppMissingRate(t *testing.T) {
-	scenario := Scenario{
-		Name:        "Test",
-		Description: "A test scenario.",
-		Steps: []Step{{
-			Function:   FuncRunApp,
-			Identifier: "load",
-			AppType:    "counter",
-		}},
+func TestStepCheck_ThrottleNode_ReportsSemanticErrors(t *testing.T) {
+	cases := map[string]struct {
+		step      Step
+		errSubstr string
+	}{
+		"no node": {
+			step:      Step{Function: FuncThrottleNode, Egress: 1000},
+			errSubstr: "node name must match",
+		},
+		"no rate": {
+			step:      Step{Function: FuncThrottleNode, Identifier: "a", Burst: 4096},
+			errSubstr: "requires an egress or ingress rate",
+		},
+		"burst below a frame": {
+			step:      Step{Function: FuncThrottleNode, Identifier: "a", Egress: 1000, Burst: 1000},
+			errSubstr: "burst must be at least",
+		},
+		"unthrottle without node": {
+			step:      Step{Function: FuncUnthrottleNode},
+			errSubstr: "node name must match",
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			err := tc.step.Check()
+			require.Error(t, err)
+			require.Contains(t, err.Error(), tc.errSubstr)
+		})
 	}
-	err := scenario.Check()
-	require.Error(t, err)
 }
 
-func TestCheck_UpdateRulesEmpty(t *testing.T) {
-	scenario := Scenario{
-		Name:        "Test",
-		Description: "A test scenario.",
-		Steps: []Step{{
-			Function: FuncUpdateRules,
-			Rules:    genesis.NetworkRulesPatch{},
-		}},
+func TestCheck_UnthrottleNodeRequiresThrottledNode(t *testing.T) {
+	throttle := Step{Function: FuncThrottleNode, Identifier: "a", Egress: 1000}
+	unthrottle := Step{Function: FuncUnthrottleNode, Identifier: "a"}
+
+	cases := map[string][]Step{
+		"never throttled": {unthrottle},
+		"other node":      {{Function: FuncThrottleNode, Identifier: "b", Egress: 1000}, unthrottle},
+		"already lifted":  {throttle, unthrottle, unthrottle},
 	}
-	err := scenario.Check()
-	require.Error(t, err)
+	for name, steps := range cases {
+		t.Run(name, func(t *testing.T) {
+			scenario := Scenario{Name: "Test", Description: "t", Steps: steps}
+			err := scenario.Check()
+			require.Error(t, err)
+			require.Contains(t, err.Error(), "node a is not throttled")
+		})
+	}
+
+	scenario := Scenario{Name: "Test", Description: "t",
+		Steps: []Step{throttle, throttle, unthrottle}}
+	require.NoError(t, scenario.Check())
 }
 
-func TestParseBytes_FullBlackoutScenario(t *testing.T) {
+func TestParseBytes_PauseAndResumeNode(t *testing.T) {
 	input := `
-Name: Single Proposer Blackout
-InitialNetworkRules:
-  Upgrades:
-    Sonic: true
-    Allegro: true
-    SingleProposerBlockFormation: true
-  Epochs:
-    MaxEpochDuration: 1000s
+Name: Pause Test
+Description: t
 Scenario:
-  - startNode: validator
-    type: validator
-    instances: 2
-  - runApp: load
-    type: counter
-    users: 50
-    rate:
-      constant: 50
-  - startNode: validator-before-1
-    type: validator
-  - advanceEpoch
-  - startNode: validator-before-2
-    type: validator
-  - advanceEpoch
-  - checks:
-      - blocksProduced
-  - stopNode: validator-before-1
-  - stopNode: validator-before-2
-  - checks:
-      - blocksHalted
-  - startNode: validator-before-1
-    type: validator
-  - startNode: validator-before-2
-    type: validator
-  - advanceEpoch
-  - checks:
-      - blocksProduced
-      - blockHeights
-      - blockHashes
-  - stopApp: load
+  - pauseNode: val-1
+  - waitFor: 30s
+  - resumeNode: val-1
 `
 	scenario, err := ParseBytes([]byte(input))
 	require.NoError(t, err)
-	require.Equal(t, "Single Proposer Blackout", scenario.Name)
-	require.Len(t, scenario.Steps, 18)
+	require.NoError(t, scenario.Check())
 
-	// Verify the rejoin pattern: validator-before-1 is started, stopped, then started again
-	starts := 0
-	for _, step := range scenario.Steps {
-		if step.Function == FuncStartNode && step.Identifier == "validator-before-1" {
-			starts++
-		}
-	}
-	require.Equal(t, 2, starts)
+	require.Equal(t, FuncPauseNode, scenario.Steps[0].Function)
+	require.Equal(t, "val-1", scenario.Steps[0].Identifier)
+	require.Equal(t, FuncResumeNode, scenario.Steps[2].Function)
+	require.Equal(t, "val-1", scenario.Steps[2].Identifier)
 }
 
-func TestParseFile_NonExistentFile(t *testing.T) {
-	_, err := ParseFile("/non/existent/path.yml")
-	require.Error(t, err)
+func TestCheck_PauseAndResumeNodeMustAlternate(t *testing.T) {
+	pause := Step{Function: FuncPauseNode, Identifier: "a"}
+	resume := Step{Function: FuncResumeNode, Identifier: "a"}
+
+	cases := map[string]struct {
+		steps     []Step
+		errSubstr string
+	}{
+		"never paused":    {[]Step{resume}, "node a is not paused"},
+		"other node":      {[]Step{{Function: FuncPauseNode, Identifier: "b"}, resume}, "node a is not paused"},
+		"already resumed": {[]Step{pause, resume, resume}, "node a is not paused"},
+		"paused twice":    {[]Step{pause, pause}, "node a is already paused"},
+		"no node":         {[]Step{{Function: FuncPauseNode}}, "node name must match"},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			scenario := Scenario{Name: "Test", Description: "t", Steps: tc.steps}
+			err := scenario.Check()
+			require.Error(t, err)
+			require.Contains(t, err.Error(), tc.errSubstr)
+		})
+	}
+
+	scenario := Scenario{Name: "Test", Description: "t",
+		Steps: []Step{pause, resume, pause, resume}}
+	require.NoError(t, scenario.Check())
 }
 
-func TestParseFile_InvalidContent(t *testing.T) {
-	// Write invalid YAML to a temp file.
-	path := t.TempDir() + "/bad.yml"
-	require.NoError(t, os.WriteFile(path, []byte(":::not valid yaml"), 0644))
-	_, err := ParseFile(path)
+func TestCheck_StoppedNodeIsNeitherPausedNorThrottled(t *testing.T) {
+	pause := Step{Function: FuncPauseNode, Identifier: "a"}
+	throttle := Step{Function: FuncThrottleNode, Identifier: "a", Egress: 1000}
+	stop := Step{Function: FuncStopNode, Identifier: "a"}
+
+	scenario := Scenario{Name: "Test", Description: "t",
+		Steps: []Step{pause, throttle, stop, pause, {Function: FuncSnapshot, Identifier: "s"}}}
+	err := scenario.Check()
 	require.Error(t, err)
+	require.NotContains(t, err.Error(), "already paused")
+	require.NotContains(t, err.Error(), "throttled")
+	require.Contains(t, err.Error(), "step 5 (snapshot): node a is paused")
+
+	scenario = Scenario{Name: "Test", Description: "t",
+		Steps: []Step{pause, throttle, stop, {Function: FuncUnthrottleNode, Identifier: "a"}}}
+	require.ErrorContains(t, scenario.Check(), "step 4 (unthrottleNode): node a is not throttled")
 }
 
-func TestParseBytes_InvalidParamForFunction(t *testing.T) {
-	tests := []struct {
-		name  string
-		input string
-	}{
-		{
-			name: "users on startNode",
-			input: `
-Name: Test
-Scenario:
-  - startNode: node-A
-    type: validator
-    users: 10
-`,
-		},
-		{
-			name: "rate on startNode",
-			input: `
-Name: Test
+func TestParseBytes_ClockOffsetAndSetClockSkew(t *testing.T) {
+	input := `
+Name: Clock Test
+Description: t
 Scenario:
-  - startNode: node-A
+  - startNode: val-1
     type: validator
-    rate:
-      constant: 5
-`,
-		},
-		{
-			name: "instances on runApp",
-			input: `
-Name: Test
-Scenario:
-  - runApp: load
-    type: counter
-    instances: 3
-    rate:
-      constant: 5
-`,
-		},
-		{
-			name: "tolerance on stopNode",
-			input: `
-Name: Test
-Scenario:
-  - stopNode: node-A
-    tolerance: 5
-`,
-		},
-		{
-			name: "ceiling on checks blocks produced",
-			input: `
-Name: Test
-Scenario:
+    clockOffset: +2s
+  - setClockSkew: val-1
+    offset: -500ms
   - checks:
-    blocksProduced:
-    ceiling: 100
-`,
-		},
-		{
-			name: "type on advanceEpoch",
-			input: `
-Name: Test
+    - blockTimestamps:
+        bound: 5s
+`
+	scenario, err := ParseBytes([]byte(input))
+	require.NoError(t, err)
+	require.NoError(t, scenario.Check())
+
+	require.NotNil(t, scenario.Steps[0].ClockOffset)
+	require.Equal(t, 2*time.Second, *scenario.Steps[0].ClockOffset)
+
+	skew := scenario.Steps[1]
+	require.Equal(t, FuncSetClockSkew, skew.Function)
+	require.Equal(t, "val-1", skew.Identifier)
+	require.NotNil(t, skew.ClockOffset)
+	require.Equal(t, -500*time.Millisecond, *skew.ClockOffset)
+
+	check := scenario.Steps[2].SubChecks[0]
+	require.Equal(t, FuncCheckBlockTimestamps, check.Function)
+	require.NotNil(t, check.Bound)
+	require.Equal(t, 5*time.Second, *check.Bound)
+}
+
+func TestParseBytes_ClockSkew_RejectsMalformedParameters(t *testing.T) {
+	cases := map[string]string{
+		"offset without unit":   "  - setClockSkew: val-1\n    offset: 2\n",
+		"offset on startNode":   "  - startNode: val-1\n    offset: 2s\n",
+		"clockOffset on skew":   "  - setClockSkew: val-1\n    clockOffset: 2s\n",
+		"non-positive bound":    "  - checks:\n    - blockTimestamps:\n        bound: 0s\n",
+		"bound on other checks": "  - checks:\n    - blockHashes:\n        bound: 5s\n",
+	}
+	for name, steps := range cases {
+		t.Run(name, func(t *testing.T) {
+			input := "Name: t\nDescription: t\nScenario:\n" + steps
+			_, err := ParseBytes([]byte(input))
+			require.Error(t, err)
+		})
+	}
+}
+
+func TestStepCheck_SetClockSkew_ReportsSemanticErrors(t *testing.T) {
+	offset := time.Second
+	err := (&Step{Function: FuncSetClockSkew, ClockOffset: &offset}).Check()
+	require.ErrorContains(t, err, "node name must match")
+
+	err = (&Step{Function: FuncSetClockSkew, Identifier: "a"}).Check()
+	require.ErrorContains(t, err, "requires an offset")
+}
+
+func TestParseBytes_ResourceLimitsAndUpdateResources(t *testing.T) {
+	input := `
+Name: Resource Test
+Description: t
 Scenario:
-  - advanceEpoch:
+  - startNode: val-1
     type: validator
-`,
-		},
-		{
-			name: "imageName on runApp",
-			input: `
-Name: Test
-Scenario:
-  - runApp: load
-    type: counter
-    imageName: "sonic:v2.0.0"
-    rate:
-      constant: 5
-`,
-		},
-	}
+    cpus: 0.5
+    memory: 2gb
+    blkioWeight: 100
+  - updateResources: val-1
+    memory: 512mb
+`
+	scenario, err := ParseBytes([]byte(input))
+	require.NoError(t, err)
+	require.NoError(t, scenario.Check())
 
-	for _, tt := range tests {
-		t.Run(tt.name, func(t *testing.T) {
-			_, err := ParseBytes([]byte(tt.input))
+	start := scenario.Steps[0]
+	require.Equal(t, 0.5, start.CPUs)
+	require.Equal(t, ByteSize(2<<30), start.Memory)
+	require.Equal(t, uint16(100), start.BlkioWeight)
+
+	update := scenario.Steps[1]
+	require.Equal(t, FuncUpdateResources, update.Function)
+	require.Equal(t, "val-1", update.Identifier)
+	require.Zero(t, update.CPUs)
+	require.Equal(t, ByteSize(512<<20), update.Memory)
+	require.Zero(t, update.BlkioWeight)
+}
+
+func TestParseBytes_Resources_RejectsMalformedParameters(t *testing.T) {
+	cases := map[string]string{
+		"memory without size":   "  - updateResources: val-1\n    memory: lots\n",
+		"cpus not a number":     "  - updateResources: val-1\n    cpus: two\n",
+		"negative blkioWeight":  "  - updateResources: val-1\n    blkioWeight: -1\n",
+		"too large blkioWeight": "  - startNode: val-1\n    blkioWeight: 70000\n",
+		"unknown parameter":     "  - updateResources: val-1\n    type: validator\n",
+	}
+	for name, steps := range cases {
+		t.Run(name, func(t *testing.T) {
+			input := "Name: t\nDescription: t\nScenario:\n" + steps
+			_, err := ParseBytes([]byte(input))
 			require.Error(t, err)
-			require.Contains(t, err.Error(), "is not valid for")
 		})
 	}
 }
 
-func TestParseBytes_NonScalarStringParam(t *testing.T) {
-	tests := []struct {
-		name  string
-		input string
+func TestStepCheck_Resources_ReportsSemanticErrors(t *testing.T) {
+	cases := map[string]struct {
+		step      Step
+		errSubstr string
 	}{
-		{
-			name: "type as list",
-			input: `
-Name: Test
-Scenario:
-  - startNode: node-A
-    type: [validator, observer]
-`,
+		"no node": {
+			step:      Step{Function: FuncUpdateResources, CPUs: 1},
+			errSubstr: "node name must match",
 		},
-		{
-			name: "imageName as mapping",
-			input: `
-Name: Test
-Scenario:
-  - startNode: node-A
-    type: validator
-    imageName:
-      name: sonic
-      version: v2.0.0
-`,
+		"no limit": {
+			step:      Step{Function: FuncUpdateResources, Identifier: "a"},
+			errSubstr: "requires cpus, memory or blkioWeight",
 		},
-		{
-			name: "dataVolume as list",
-			input: `
-Name: Test
-Scenario:
-  - startNode: node-A
-    type: validator
-    dataVolume: [vol-1, vol-2]
-`,
+		"negative cpus": {
+			step:      Step{Function: FuncUpdateResources, Identifier: "a", CPUs: -1},
+			errSubstr: "cpus must be at least",
+		},
+		"too few cpus": {
+			step:      Step{Function: FuncStartNode, Identifier: "a", CPUs: 0.001},
+			errSubstr: "cpus must be at least",
+		},
+		"too little memory": {
+			step:      Step{Function: FuncStartNode, Identifier: "a", Memory: 1 << 20},
+			errSubstr: "memory must be at least",
+		},
+		"blkioWeight out of range": {
+			step:      Step{Function: FuncUpdateResources, Identifier: "a", BlkioWeight: 5},
+			errSubstr: "blkioWeight must be between 10 and 1000",
 		},
 	}
 
-	for _, tt := range tests {
-		t.Run(tt.name, func(t *testing.T) {
-			_, err := ParseBytes([]byte(tt.input))
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			err := tc.step.Check()
 			require.Error(t, err)
+			require.Contains(t, err.Error(), tc.errSubstr)
 		})
 	}
 }
 
-func TestParseBytes_TypeOrderIndependent(t *testing.T) {
-	// type listed before the function key — should still be parsed correctly.
+func TestParseBytes_CheaterAndValidatorSlashed(t *testing.T) {
 	input := `
-Name: Order Test
+Name: Cheater Test
+Description: t
 Scenario:
-  - type: counter
-    runApp: my-app
-    users: 5
-    rate:
-      constant: 10
+  - startNode: val-1
+    type: validator
+    cheater: true
+  - checks:
+    - validatorSlashed:
+        slashedNodes: [val-1]
+        duration: 2m
 `
 	scenario, err := ParseBytes([]byte(input))
 	require.NoError(t, err)
+	require.NoError(t, scenario.Check())
 
-	step := scenario.Steps[0]
-	require.Equal(t, FuncRunApp, step.Function)
-	require.Equal(t, "counter", step.AppType)
-	require.Empty(t, step.NodeType)
+	require.True(t, scenario.Steps[0].Cheater)
+
+	check := scenario.Steps[1].SubChecks[0]
+	require.Equal(t, FuncCheckValidatorSlashed, check.Function)
+	require.Equal(t, []string{"val-1"}, check.SlashedNodes)
+	require.NotNil(t, check.Duration)
+	require.Equal(t, 2*time.Minute, *check.Duration)
 }
 
-func TestParseBytes_RulesPatchAllCategories(t *testing.T) {
-	input := `
-Name: Full Patch Test
-InitialNetworkRules:
-  Epochs:
-    MaxEpochGas: 5000000000
-    MaxEpochDuration: 30s
-  Blocks:
-    MaxBlockGas: 20500000000
-    MaxEmptyBlockSkipPeriod: 3s
-  Economy:
-    MinBaseFee: "1000000000"
-    MinGasPrice: "500000000"
-  Upgrades:
-    Sonic: true
-    Allegro: true
+func TestCheck_CheaterAndValidatorSlashed_ReportSemanticErrors(t *testing.T) {
+	cases := map[string]struct {
+		step      Step
+		errSubstr string
+	}{
+		"cheating observer": {
+			step:      Step{Function: FuncStartNode, Identifier: "a", Cheater: true},
+			errSubstr: "only validators can cheat",
+		},
+		"no slashed nodes": {
+			step: Step{Function: FuncChecks, SubChecks: []CheckSpec{
+				{Function: FuncCheckValidatorSlashed},
+			}},
+			errSubstr: "requires slashedNodes",
+		},
+		"invalid slashed node": {
+			step: Step{Function: FuncChecks, SubChecks: []CheckSpec{
+				{Function: FuncCheckValidatorSlashed, SlashedNodes: []string{"a b"}},
+			}},
+			errSubstr: "node name must match",
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			err := tc.step.Check()
+			require.Error(t, err)
+			require.Contains(t, err.Error(), tc.errSubstr)
+		})
+	}
+}
+
+func TestStepCheck_Delegate_ReportsSemanticErrors(t *testing.T) {
+	cases := map[string]struct {
+		step      Step
+		errSubstr string
+	}{
+		"no targets": {
+			step: Step{
+				Function: FuncDelegate,
+			},
+			errSubstr: "at least one target",
+		},
+		"missing node": {
+			step: Step{
+				Function: FuncDelegate,
+				DelegateTargets: []DelegateTarget{
+					{Delegator: "alice", Stake: 100},
+				},
+			},
+			errSubstr: "'node' field",
+		},
+		"missing delegator": {
+			step: Step{
+				Function: FuncDelegate,
+				DelegateTargets: []DelegateTarget{
+					{Node: "val", Stake: 100},
+				},
+			},
+			errSubstr: "'delegator' field",
+		},
+		"zero stake": {
+			step: Step{
+				Function: FuncDelegate,
+				DelegateTargets: []DelegateTarget{
+					{Node: "val", Delegator: "alice", Stake: 0},
+				},
+			},
+			errSubstr: "stake must be greater than zero",
+		},
+		"invalid delegator name": {
+			step: Step{
+				Function: FuncDelegate,
+				DelegateTargets: []DelegateTarget{
+					{Node: "val", Delegator: "not a name", Stake: 100},
+				},
+			},
+			errSubstr: "delegator name must match",
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			err := tc.step.Check()
+			require.Error(t, err)
+			require.Contains(t, err.Error(), tc.errSubstr)
+		})
+	}
+}
+
+func TestStepCheck_UndelegateWithDelegator_RejectsInvalidDelegatorName(t *testing.T) {
+	step := Step{
+		Function: FuncUndelegate,
+		UndelegateTargets: []UndelegateTarget{
+			{Node: "val", Delegator: "not a name"},
+		},
+	}
+	err := step.Check()
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "delegator name must match")
+}
+
+func TestParseBytes_Checks(t *testing.T) {
+	input := `
+Name: Checks Test
+Scenario:
+  - checks:
+      - blocksHalted:
+        failing: true
+      - blockHeights:
+        tolerance: 5
+      - blockGasRate:
+        ceiling: 16500000
+        failing: true
+      - blockHashes
+`
+	scenario, err := ParseBytes([]byte(input))
+	require.NoError(t, err)
+	require.Len(t, scenario.Steps, 4)
+
+	step := scenario.Steps[0]
+	require.Equal(t, FuncChecks, step.Function)
+	require.Len(t, step.SubChecks, 4)
+
+	// blocksHalted with failing
+	check := step.SubChecks[0]
+	require.Equal(t, FuncCheckBlocksHalted, check.Function)
+	require.True(t, check.Failing)
+
+	// blockHeights with tolerance
+	check = step.SubChecks[1]
+	require.Equal(t, FuncCheckBlockHeights, check.Function)
+	require.NotNil(t, check.Tolerance)
+	require.Equal(t, 5, *check.Tolerance)
+
+	// blockGasRate with ceiling + failing
+	check = step.SubChecks[2]
+	require.Equal(t, FuncCheckBlockGasRate, check.Function)
+	require.NotNil(t, check.Ceiling)
+	require.EqualValues(t, 16500000, *check.Ceiling)
+	require.True(t, check.Failing)
+
+	// blockHashes
+	check = step.SubChecks[3]
+	require.Equal(t, FuncCheckBlockHashes, check.Function)
+}
+
+func TestParseBytes_CheckParams_NestedForm(t *testing.T) {
+	input := `
+Name: Nested Check Params Test
+Scenario:
+  - checks:
+      - eventThrottled:
+          failing: true
+          throttledNodes:
+            - validator-dominant
+            - validator-B
+      - blockGasRate:
+          ceiling: 1000
+          failing: true
+`
+	scenario, err := ParseBytes([]byte(input))
+	require.NoError(t, err)
+	step := scenario.Steps[0]
+	require.Equal(t, FuncChecks, step.Function)
+	require.Len(t, step.SubChecks, 2)
+
+	et := step.SubChecks[0]
+	require.Equal(t, FuncCheckEventThrottled, et.Function)
+	require.True(t, et.Failing)
+	require.Equal(
+		t, []string{"validator-dominant", "validator-B"},
+		et.ThrottledNodes,
+	)
+
+	gr := step.SubChecks[1]
+	require.Equal(t, FuncCheckBlockGasRate, gr.Function)
+	require.NotNil(t, gr.Ceiling)
+	require.EqualValues(t, 1000, *gr.Ceiling)
+	require.True(t, gr.Failing)
+}
+
+func TestParseBytes_CheckParams_RejectsUnknown(t *testing.T) {
+	input := `
+Name: Unknown Param Test
+Scenario:
+  - checks:
+      - eventThrottled:
+          bogus: 42
+`
+	_, err := ParseBytes([]byte(input))
+	require.Error(t, err)
+	require.Contains(t, err.Error(), `parameter "bogus" is not valid`)
+}
+
+func TestParseBytes_CheckParams_RejectsWrongParamForFunction(t *testing.T) {
+	cases := map[string]string{
+		"throttledNodes on blocksProduced": `
+Name: Wrong Param Test
+Scenario:
+  - checks:
+      - blocksProduced:
+          throttledNodes:
+            - validator-A
+`,
+		"ceiling on blocksHalted": `
+Name: Wrong Param Test
+Scenario:
+  - checks:
+      - blocksHalted:
+          ceiling: 5
+`,
+		"tolerance on eventThrottled": `
+Name: Wrong Param Test
+Scenario:
+  - checks:
+      - eventThrottled:
+          tolerance: 5
+`,
+		"rules on blockGasRate": `
+Name: Wrong Param Test
+Scenario:
+  - checks:
+      - blockGasRate:
+          rules:
+            Epochs:
+              MaxEpochDuration: 5s
+`,
+	}
+	for name, input := range cases {
+		t.Run(name, func(t *testing.T) {
+			_, err := ParseBytes([]byte(input))
+			require.Error(t, err)
+			require.Contains(t, err.Error(), "is not valid for check")
+		})
+	}
+}
+
+func TestAllCheckFunctionsAreDocumented(t *testing.T) {
+	for _, fn := range allCheckFunctions {
+		desc, ok := checkFunctionDescriptions[fn]
+		require.Truef(t, ok, "check function %q has no entry in checkFunctionDescriptions", fn)
+		require.NotEmptyf(t, strings.TrimSpace(desc), "check function %q has empty description", fn)
+
+		_, ok = checkFunctionParams[fn]
+		require.Truef(t, ok, "check function %q has no entry in checkFunctionParams", fn)
+	}
+}
+
+func TestAllCheckParamKeysAreDocumented(t *testing.T) {
+	seen := map[string]bool{}
+	for _, params := range checkFunctionParams {
+		for _, p := range params {
+			seen[p] = true
+		}
+	}
+	for p := range seen {
+		desc, ok := checkParamDescriptions[p]
+		require.Truef(t, ok, "check parameter %q has no entry in checkParamDescriptions", p)
+		require.NotEmptyf(t, strings.TrimSpace(desc), "check parameter %q has empty description", p)
+	}
+}
+
+func TestParseBytes_ThrottledNodes_FlatForm(t *testing.T) {
+	input := `
+Name: Flat Throttled Nodes Test
+Scenario:
+  - checks:
+      - eventThrottled:
+        failing: true
+        throttledNodes:
+          - validator-A
+`
+	scenario, err := ParseBytes([]byte(input))
+	require.NoError(t, err)
+	step := scenario.Steps[0]
+	require.Equal(t, FuncChecks, step.Function)
+	require.Len(t, step.SubChecks, 1)
+	et := step.SubChecks[0]
+	require.Equal(t, FuncCheckEventThrottled, et.Function)
+	require.True(t, et.Failing)
+	require.Equal(t, []string{"validator-A"}, et.ThrottledNodes)
+}
+
+func TestParseBytes_BlocksProduced_ParsesDuration(t *testing.T) {
+	input := `
+Name: Duration Test
+Scenario:
+  - checks:
+      - blocksProduced:
+        tolerance: 10
+        duration: 30s
+`
+	scenario, err := ParseBytes([]byte(input))
+	require.NoError(t, err)
+	require.Len(t, scenario.Steps[0].SubChecks, 1)
+
+	check := scenario.Steps[0].SubChecks[0]
+	require.Equal(t, FuncCheckBlocksProduced, check.Function)
+	require.NotNil(t, check.Tolerance)
+	require.Equal(t, 10, *check.Tolerance)
+	require.NotNil(t, check.Duration)
+	require.Equal(t, 30*time.Second, *check.Duration)
+}
+
+func TestParseBytes_BlocksProduced_RejectsInvalidDuration(t *testing.T) {
+	cases := map[string]string{
+		"non-duration string": `
+Name: Bad
+Scenario:
+  - checks:
+      - blocksProduced:
+        duration: not-a-duration
+`,
+		"negative duration": `
+Name: Bad
+Scenario:
+  - checks:
+      - blocksProduced:
+        duration: -5s
+`,
+	}
+	for name, input := range cases {
+		t.Run(name, func(t *testing.T) {
+			_, err := ParseBytes([]byte(input))
+			require.Error(t, err)
+		})
+	}
+}
+
+func TestParseBytes_SlopeRate(t *testing.T) {
+	input := `
+Name: Slope Rate Test
+Scenario:
+  - runApp: subsidies
+    type: counter
+    users: 10
+    rate:
+      slope:
+        start: 20
+        increment: 5
+`
+	scenario, err := ParseBytes([]byte(input))
+	require.NoError(t, err)
+
+	step := scenario.Steps[0]
+	require.NotNil(t, step.Rate)
+	require.NotNil(t, step.Rate.Slope)
+	require.EqualValues(t, 20, step.Rate.Slope.Start)
+	require.EqualValues(t, 5, step.Rate.Slope.Increment)
+}
+
+func TestParseBytes_DefaultsApplied(t *testing.T) {
+	input := `
+Name: Defaults Test
+Scenario:
+  - advanceEpoch
+`
+	scenario, err := ParseBytes([]byte(input))
+	require.NoError(t, err)
+	require.NotNil(t, scenario.InitialRules.Epochs)
+	require.NotNil(t, scenario.InitialRules.Epochs.MaxEpochDuration)
+	require.Equal(
+		t,
+		int64(15*time.Second),
+		int64(*scenario.InitialRules.Epochs.MaxEpochDuration),
+	)
+
+	require.Len(t, scenario.Steps, 4)
+	require.Equal(t, FuncAdvanceEpoch, scenario.Steps[1].Function)
+	require.Equal(t, FuncAdvanceEpoch, scenario.Steps[2].Function)
+	require.Equal(t, FuncChecks, scenario.Steps[3].Function)
+}
+
+func TestParseBytes_DisableEndChecks(t *testing.T) {
+	input := `
+Name: Disable End Checks Test
+DisableEndChecks: true
+Scenario:
+  - advanceEpoch
+`
+	scenario, err := ParseBytes([]byte(input))
+	require.NoError(t, err)
+	require.Len(t, scenario.Steps, 1)
+}
+
+func TestParseBytes_UnknownFunction(t *testing.T) {
+	input := `
+Name: Bad Function
+Scenario:
+  - Do something weird: foo
+`
+	_, err := ParseBytes([]byte(input))
+	require.Error(t, err)
+}
+
+func TestCheck_EmptyName(t *testing.T) {
+	scenario := Scenario{
+		Name:        "",
+		Description: "A test scenario.",
+		Steps:       []Step{{Function: FuncAdvanceEpoch}},
+	}
+	err := scenario.Check()
+	require.Error(t, err)
+}
+
+func TestCheck_EmptyDescription(t *testing.T) {
+	scenario := Scenario{
+		Name:        "Test",
+		Description: "",
+		Steps:       []Step{{Function: FuncAdvanceEpoch}},
+	}
+	err := scenario.Check()
+	require.Error(t, err)
+}
+
+func TestCheck_InvalidNodeName(t *testing.T) {
+	scenario := Scenario{
+		Name:        "Test",
+		Description: "A test scenario.",
+		Steps: []Step{{
+			Function:   FuncStartNode,
+			Identifier: "invalid name with spaces",
+			NodeType:   "validator",
+		}},
+	}
+	err := scenario.Check()
+	require.Error(t, err)
+}
+
+func TestCheck_RunAppMissingRate(t *testing.T) {
+	scenario := Scenario{
+		Name:        "Test",
+		Description: "A test scenario.",
+		Steps: []Step{{
+			Function:   FuncRunApp,
+			Identifier: "load",
+			AppType:    "counter",
+		}},
+	}
+	err := scenario.Check()
+	require.Error(t, err)
+}
+
+func TestCheck_UpdateRulesEmpty(t *testing.T) {
+	scenario := Scenario{
+		Name:        "Test",
+		Description: "A test scenario.",
+		Steps: []Step{{
+			Function: FuncUpdateRules,
+			Rules:    genesis.NetworkRulesPatch{},
+		}},
+	}
+	err := scenario.Check()
+	require.Error(t, err)
+}
+
+func TestParseBytes_FullBlackoutScenario(t *testing.T) {
+	input := `
+Name: Single Proposer Blackout
+InitialNetworkRules:
+  Upgrades:
+    Sonic: true
+    Allegro: true
+    SingleProposerBlockFormation: true
+  Epochs:
+    MaxEpochDuration: 1000s
+Scenario:
+  - startNode: validator
+    type: validator
+    instances: 2
+  - runApp: load
+    type: counter
+    users: 50
+    rate:
+      constant: 50
+  - startNode: validator-before-1
+    type: validator
+  - advanceEpoch
+  - startNode: validator-before-2
+    type: validator
+  - advanceEpoch
+  - checks:
+      - blocksProduced
+  - stopNode: validator-before-1
+  - stopNode: validator-before-2
+  - checks:
+      - blocksHalted
+  - startNode: validator-before-1
+    type: validator
+  - startNode: validator-before-2
+    type: validator
+  - advanceEpoch
+  - checks:
+      - blocksProduced
+      - blockHeights
+      - blockHashes
+  - stopApp: load
+`
+	scenario, err := ParseBytes([]byte(input))
+	require.NoError(t, err)
+	require.Equal(t, "Single Proposer Blackout", scenario.Name)
+	require.Len(t, scenario.Steps, 18)
+
+	// Verify the rejoin pattern: validator-before-1 is started, stopped, then started again
+	starts := 0
+	for _, step := range scenario.Steps {
+		if step.Function == FuncStartNode && step.Identifier == "validator-before-1" {
+			starts++
+		}
+	}
+	require.Equal(t, 2, starts)
+}
+
+func TestParseFile_NonExistentFile(t *testing.T) {
+	_, err := ParseFile("/non/existent/path.yml")
+	require.Error(t, err)
+}
+
+func TestParseFile_InvalidContent(t *testing.T) {
+	// Write invalid YAML to a temp file.
+	path := t.TempDir() + "/bad.yml"
+	require.NoError(t, os.WriteFile(path, []byte(":::not valid yaml"), 0644))
+	_, err := ParseFile(path)
+	require.Error(t, err)
+}
+
+func TestParseBytes_InvalidParamForFunction(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+	}{
+		{
+			name: "users on startNode",
+			input: `
+Name: Test
+Scenario:
+  - startNode: node-A
+    type: validator
+    users: 10
+`,
+		},
+		{
+			name: "rate on startNode",
+			input: `
+Name: Test
+Scenario:
+  - startNode: node-A
+    type: validator
+    rate:
+      constant: 5
+`,
+		},
+		{
+			name: "instances on runApp",
+			input: `
+Name: Test
+Scenario:
+  - runApp: load
+    type: counter
+    instances: 3
+    rate:
+      constant: 5
+`,
+		},
+		{
+			name: "tolerance on stopNode",
+			input: `
+Name: Test
+Scenario:
+  - stopNode: node-A
+    tolerance: 5
+`,
+		},
+		{
+			name: "ceiling on checks blocks produced",
+			input: `
+Name: Test
+Scenario:
+  - checks:
+    blocksProduced:
+    ceiling: 100
+`,
+		},
+		{
+			name: "type on advanceEpoch",
+			input: `
+Name: Test
+Scenario:
+  - advanceEpoch:
+    type: validator
+`,
+		},
+		{
+			name: "imageName on runApp",
+			input: `
+Name: Test
+Scenario:
+  - runApp: load
+    type: counter
+    imageName: "sonic:v2.0.0"
+    rate:
+      constant: 5
+`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := ParseBytes([]byte(tt.input))
+			require.Error(t, err)
+			require.Contains(t, err.Error(), "is not valid for")
+		})
+	}
+}
+
+func TestParseBytes_NonScalarStringParam(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+	}{
+		{
+			name: "type as list",
+			input: `
+Name: Test
+Scenario:
+  - startNode: node-A
+    type: [validator, observer]
+`,
+		},
+		{
+			name: "imageName as mapping",
+			input: `
+Name: Test
+Scenario:
+  - startNode: node-A
+    type: validator
+    imageName:
+      name: sonic
+      version: v2.0.0
+`,
+		},
+		{
+			name: "dataVolume as list",
+			input: `
+Name: Test
+Scenario:
+  - startNode: node-A
+    type: validator
+    dataVolume: [vol-1, vol-2]
+`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := ParseBytes([]byte(tt.input))
+			require.Error(t, err)
+		})
+	}
+}
+
+func TestParseBytes_TypeOrderIndependent(t *testing.T) {
+	// type listed before the function key — should still be parsed correctly.
+	input := `
+Name: Order Test
+Scenario:
+  - type: counter
+    runApp: my-app
+    users: 5
+    rate:
+      constant: 10
+`
+	scenario, err := ParseBytes([]byte(input))
+	require.NoError(t, err)
+
+	step := scenario.Steps[0]
+	require.Equal(t, FuncRunApp, step.Function)
+	require.Equal(t, "counter", step.AppType)
+	require.Empty(t, step.NodeType)
+}
+
+func TestParseBytes_RulesPatchAllCategories(t *testing.T) {
+	input := `
+Name: Full Patch Test
+InitialNetworkRules:
+  Epochs:
+    MaxEpochGas: 5000000000
+    MaxEpochDuration: 30s
+  Blocks:
+    MaxBlockGas: 20500000000
+    MaxEmptyBlockSkipPeriod: 3s
+  Economy:
+    MinBaseFee: "1000000000"
+    MinGasPrice: "500000000"
+  Upgrades:
+    Sonic: true
+    Allegro: true
     Brio: false
 Scenario:
-  - startNode: validator
+  - startNode: validator
+    type: validator
+  - updateRules:
+      Blocks:
+        MaxBlockGas: 30000000000
+      Economy:
+        MinBaseFee: "2000000000"
+      Upgrades:
+        Brio: true
+`
+	scenario, err := ParseBytes([]byte(input))
+	require.NoError(t, err)
+
+	r := scenario.InitialRules
+
+	// Epochs
+	require.NotNil(t, r.Epochs)
+	if got, want := *r.Epochs.MaxEpochGas, uint64(5000000000); got != want {
+		require.Failf(t, "unexpected MaxEpochGas", "MaxEpochGas: got %d, want %d", got, want)
+	}
+	if got, want := int64(*r.Epochs.MaxEpochDuration), int64(30*time.Second); got != want {
+		require.Failf(t, "unexpected MaxEpochDuration", "MaxEpochDuration: got %d, want %d", got, want)
+	}
+
+	// Blocks
+	require.NotNil(t, r.Blocks)
+	if got, want := *r.Blocks.MaxBlockGas, uint64(20500000000); got != want {
+		require.Failf(t, "unexpected MaxBlockGas", "MaxBlockGas: got %d, want %d", got, want)
+	}
+	if got, want := int64(*r.Blocks.MaxEmptyBlockSkipPeriod), int64(3*time.Second); got != want {
+		require.Failf(t, "unexpected MaxEmptyBlockSkipPeriod", "MaxEmptyBlockSkipPeriod: got %d, want %d", got, want)
+	}
+
+	// Economy (BigIntValue fields)
+	require.NotNil(t, r.Economy)
+	require.NotNil(t, r.Economy.MinBaseFee)
+	require.NotNil(t, r.Economy.MinGasPrice)
+
+	// Upgrades
+	require.NotNil(t, r.Upgrades)
+	require.NotNil(t, r.Upgrades.Sonic)
+	require.True(t, *r.Upgrades.Sonic)
+	require.NotNil(t, r.Upgrades.Allegro)
+	require.True(t, *r.Upgrades.Allegro)
+	require.NotNil(t, r.Upgrades.Brio)
+	require.False(t, *r.Upgrades.Brio)
+
+	// Unset categories should be nil
+	require.Nil(t, r.Dag)
+	require.Nil(t, r.Emitter)
+
+	// Verify updateRules step
+	step := scenario.Steps[1]
+	require.Equal(t, FuncUpdateRules, step.Function)
+	require.NotNil(t, step.Rules.Blocks)
+	require.NotNil(t, step.Rules.Blocks.MaxBlockGas)
+	require.EqualValues(t, 30000000000, *step.Rules.Blocks.MaxBlockGas)
+	require.NotNil(t, step.Rules.Economy)
+	require.NotNil(t, step.Rules.Economy.MinBaseFee)
+	require.NotNil(t, step.Rules.Upgrades)
+	require.NotNil(t, step.Rules.Upgrades.Brio)
+	require.True(t, *step.Rules.Upgrades.Brio)
+	// Fields not in the update should remain nil
+	require.Nil(t, step.Rules.Epochs)
+}
+
+func TestCheck_EmptySubChecks(t *testing.T) {
+	scenario := Scenario{
+		Name:        "Test",
+		Description: "A test scenario.",
+		Steps: []Step{{
+			Function:  FuncChecks,
+			SubChecks: nil,
+		}},
+	}
+	err := scenario.Check()
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "at least one sub-check")
+}
+
+func TestCheck_ObservationWindowTooShort(t *testing.T) {
+	tooShort := MinObservationDuration - time.Millisecond
+	scenario := Scenario{
+		Name:        "Test",
+		Description: "A test scenario.",
+		Steps: []Step{{
+			Function: FuncChecks,
+			SubChecks: []CheckSpec{{
+				Function: FuncCheckBlocksProduced,
+				Duration: &tooShort,
+			}},
+		}},
+	}
+	err := scenario.Check()
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "duration must be at least")
+}
+
+func TestCheck_ObservationWindowAtTheMinimumIsAccepted(t *testing.T) {
+	minimum := MinObservationDuration
+	scenario := Scenario{
+		Name:        "Test",
+		Description: "A test scenario.",
+		Steps: []Step{{
+			Function: FuncChecks,
+			SubChecks: []CheckSpec{{
+				Function: FuncCheckBlocksProduced,
+				Duration: &minimum,
+			}},
+		}},
+	}
+	require.NoError(t, scenario.Check())
+}
+
+// checkScenario wraps a single sub-check into an otherwise valid scenario.
+func checkScenario(spec CheckSpec) *Scenario {
+	return &Scenario{
+		Name:        "Test",
+		Description: "A test scenario.",
+		Steps:       []Step{{Function: FuncChecks, SubChecks: []CheckSpec{spec}}},
+	}
+}
+
+func TestCheck_ToleranceTooShortForAnObservationWindow(t *testing.T) {
+	// tolerance is the window most scenarios actually use, so it must be
+	// rejected here rather than minutes into the run, just like duration.
+	for _, function := range []StepFunction{
+		FuncCheckBlocksProduced, FuncCheckBlocksHalted, FuncCheckBlockGasRate,
+	} {
+		t.Run(string(function), func(t *testing.T) {
+			for _, tolerance := range []int{0, 1} {
+				tolerance := tolerance
+				err := checkScenario(CheckSpec{
+					Function:  function,
+					Tolerance: &tolerance,
+				}).Check()
+				require.Error(t, err, "tolerance %d", tolerance)
+				require.Contains(t, err.Error(), "tolerance is an observation window")
+			}
+		})
+	}
+}
+
+func TestCheck_ToleranceAtTheMinimumIsAccepted(t *testing.T) {
+	minimum := MinObservationSamples
+	require.NoError(t, checkScenario(CheckSpec{
+		Function:  FuncCheckBlocksProduced,
+		Tolerance: &minimum,
+	}).Check())
+}
+
+func TestCheck_ShortToleranceIsAcceptedWhenDurationOverridesIt(t *testing.T) {
+	// duration wins over tolerance at run time, so a small tolerance next to a
+	// usable duration never reaches the checker.
+	tolerance := 1
+	duration := MinObservationDuration
+	require.NoError(t, checkScenario(CheckSpec{
+		Function:  FuncCheckBlocksProduced,
+		Tolerance: &tolerance,
+		Duration:  &duration,
+	}).Check())
+}
+
+func TestCheck_ZeroToleranceIsAcceptedForAHeightCheck(t *testing.T) {
+	// For blockHeights, tolerance is a slack in blocks; demanding exact
+	// agreement is legitimate.
+	zero := 0
+	require.NoError(t, checkScenario(CheckSpec{
+		Function:  FuncCheckBlockHeights,
+		Tolerance: &zero,
+	}).Check())
+}
+
+func TestCheck_ShortDurationIsAcceptedAsAConvergenceBudget(t *testing.T) {
+	// For blockHeights and networkRules, duration bounds how long the nodes are
+	// given to agree, not how long the network is observed. Nothing has to
+	// collect two monitoring samples, so a short budget - or none at all,
+	// meaning a single attempt - is a legitimate choice and must not be
+	// rejected by the observation-window floor.
+	for _, function := range []StepFunction{
+		FuncCheckBlockHeights, FuncCheckNetworkRules,
+	} {
+		t.Run(string(function), func(t *testing.T) {
+			for _, duration := range []time.Duration{
+				0, time.Millisecond, MinObservationDuration - time.Millisecond,
+			} {
+				require.NoError(t, checkScenario(CheckSpec{
+					Function: function,
+					Duration: &duration,
+				}).Check(), "duration %s", duration)
+			}
+		})
+	}
+}
+
+func TestCheck_ShortDurationIsRejectedForAnObservationWindow(t *testing.T) {
+	// The counterpart to the above: where duration really is the window, it
+	// still has to be long enough to hold two samples.
+	tooShort := MinObservationDuration - time.Millisecond
+	for _, function := range []StepFunction{
+		FuncCheckBlocksProduced, FuncCheckBlocksHalted, FuncCheckBlockGasRate,
+	} {
+		t.Run(string(function), func(t *testing.T) {
+			err := checkScenario(CheckSpec{
+				Function: function,
+				Duration: &tooShort,
+			}).Check()
+			require.Error(t, err)
+			require.Contains(t, err.Error(), "duration must be at least")
+		})
+	}
+}
+
+func TestParseBytes_UnknownCheckFunction(t *testing.T) {
+	input := `
+Name: Bad Check
+Scenario:
+  - checks:
+      - unknownCheck
+`
+	_, err := ParseBytes([]byte(input))
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "unknown check function")
+}
+
+func TestParseBytes_UpgradeNode(t *testing.T) {
+	input := `
+Name: Upgrade Test
+Description: t
+Scenario:
+  - upgradeNode: validator
+    imageName: sonic:v2.1.6
+    interval: 30s
+`
+	scenario, err := ParseBytes([]byte(input))
+	require.NoError(t, err)
+	require.NoError(t, scenario.Check())
+
+	step := scenario.Steps[0]
+	require.Equal(t, FuncUpgradeNode, step.Function)
+	require.Equal(t, "validator", step.Identifier)
+	require.Equal(t, "sonic:v2.1.6", step.ImageName)
+	require.Equal(t, 30*time.Second, step.Interval)
+}
+
+func TestStepCheck_UpgradeNode_ReportsSemanticErrors(t *testing.T) {
+	cases := map[string]struct {
+		step      Step
+		errSubstr string
+	}{
+		"no node": {
+			step:      Step{Function: FuncUpgradeNode, ImageName: "sonic:new"},
+			errSubstr: "node name must match",
+		},
+		"no image": {
+			step:      Step{Function: FuncUpgradeNode, Identifier: "a"},
+			errSubstr: "requires an imageName",
+		},
+		"negative interval": {
+			step:      Step{Function: FuncUpgradeNode, Identifier: "a", ImageName: "sonic:new", Interval: -time.Second},
+			errSubstr: "interval must not be negative",
+		},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			err := tc.step.Check()
+			require.Error(t, err)
+			require.Contains(t, err.Error(), tc.errSubstr)
+		})
+	}
+}
+
+func TestCheck_UpgradeNodeRequiresAnUndisturbedNode(t *testing.T) {
+	upgrade := Step{Function: FuncUpgradeNode, Identifier: "a", ImageName: "sonic:new"}
+
+	cases := map[string]struct {
+		steps     []Step
+		errSubstr string
+	}{
+		"partitioned": {
+			[]Step{{Function: FuncPartition, Groups: [][]string{{"a"}, {"b"}}}, upgrade},
+			"network is partitioned",
+		},
+		"throttled": {
+			[]Step{{Function: FuncThrottleNode, Identifier: "a", Egress: 1000}, upgrade},
+			"node a is throttled",
+		},
+		"paused": {
+			[]Step{{Function: FuncPauseNode, Identifier: "a"}, upgrade},
+			"node a is paused",
+		},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			scenario := Scenario{Name: "Test", Description: "t", Steps: tc.steps}
+			err := scenario.Check()
+			require.Error(t, err)
+			require.Contains(t, err.Error(), tc.errSubstr)
+		})
+	}
+
+	scenario := Scenario{Name: "Test", Description: "t", Steps: []Step{
+		{Function: FuncPauseNode, Identifier: "a"},
+		{Function: FuncResumeNode, Identifier: "a"},
+		{Function: FuncThrottleNode, Identifier: "b", Egress: 1000},
+		upgrade,
+	}}
+	require.NoError(t, scenario.Check())
+}
+
+func TestParseBytes_Snapshot(t *testing.T) {
+	input := `
+Name: Snapshot Test
+Description: t
+Scenario:
+  - snapshot: after-upgrade
+`
+	scenario, err := ParseBytes([]byte(input))
+	require.NoError(t, err)
+	require.NoError(t, scenario.Check())
+
+	step := scenario.Steps[0]
+	require.Equal(t, FuncSnapshot, step.Function)
+	require.Equal(t, "after-upgrade", step.Identifier)
+}
+
+func TestStepCheck_Snapshot_RequiresAName(t *testing.T) {
+	for _, name := range []string{"", "not/a/name"} {
+		step := Step{Function: FuncSnapshot, Identifier: name}
+		err := step.Check()
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "snapshot name must match")
+	}
+}
+
+func TestCheck_SnapshotRequiresAnUndisturbedNetwork(t *testing.T) {
+	snapshot := Step{Function: FuncSnapshot, Identifier: "s"}
+	runApp := Step{Function: FuncRunApp, Identifier: "load", AppType: "counter", Rate: &Rate{Constant: new(float32)}}
+
+	cases := map[string]struct {
+		steps     []Step
+		errSubstr string
+	}{
+		"partitioned": {
+			[]Step{{Function: FuncPartition, Groups: [][]string{{"a"}, {"b"}}}, snapshot},
+			"network is partitioned",
+		},
+		"throttled": {
+			[]Step{{Function: FuncThrottleNode, Identifier: "a", Egress: 1000}, snapshot},
+			"node a is throttled",
+		},
+		"paused": {
+			[]Step{{Function: FuncPauseNode, Identifier: "a"}, snapshot},
+			"node a is paused",
+		},
+		"app running": {
+			[]Step{runApp, snapshot},
+			"application load is running",
+		},
+		"taken twice": {
+			[]Step{snapshot, snapshot},
+			"snapshot s is taken more than once",
+		},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			scenario := Scenario{Name: "Test", Description: "t", Steps: tc.steps}
+			err := scenario.Check()
+			require.Error(t, err)
+			require.Contains(t, err.Error(), tc.errSubstr)
+		})
+	}
+
+	scenario := Scenario{Name: "Test", Description: "t", Steps: []Step{
+		runApp,
+		{Function: FuncStopApp, Identifier: "load"},
+		{Function: FuncPauseNode, Identifier: "a"},
+		{Function: FuncResumeNode, Identifier: "a"},
+		snapshot,
+	}}
+	require.NoError(t, scenario.Check())
+}
+
+func TestParseBytes_ClientPackingAndMemory(t *testing.T) {
+	input := `
+Name: Packing Test
+Description: t
+Scenario:
+  - startNode: obs
+    instances: 20
+    clientsPerContainer: 10
+    liveCache: 32mb
+    cache: 64mb
+    goMemLimit: 512mb
+`
+	scenario, err := ParseBytes([]byte(input))
+	require.NoError(t, err)
+	require.NoError(t, scenario.Check())
+
+	step := scenario.Steps[0]
+	require.Equal(t, 10, *step.ClientsPerContainer)
+	require.Equal(t, ByteSize(32<<20), step.LiveCache)
+	require.Equal(t, ByteSize(64<<20), step.Cache)
+	require.Equal(t, ByteSize(512<<20), step.GoMemLimit)
+}
+
+func TestStepCheck_ClientPackingAndMemory_ReportsSemanticErrors(t *testing.T) {
+	packing := 4
+	none := 0
+	crowd := 101
+	cases := map[string]struct {
+		step      Step
+		errSubstr string
+	}{
+		"no clients": {
+			step:      Step{Function: FuncStartNode, Identifier: "a", ClientsPerContainer: &none},
+			errSubstr: "clientsPerContainer must be >= 1",
+		},
+		"too many clients": {
+			step:      Step{Function: FuncStartNode, Identifier: "a", ClientsPerContainer: &crowd},
+			errSubstr: "clientsPerContainer must be <= 100",
+		},
+		"data volume": {
+			step:      Step{Function: FuncStartNode, Identifier: "a", ClientsPerContainer: &packing, DataVolume: "a-data"},
+			errSubstr: "packed clients cannot use a dataVolume",
+		},
+		"resource limits": {
+			step:      Step{Function: FuncStartNode, Identifier: "a", ClientsPerContainer: &packing, CPUs: 1},
+			errSubstr: "packed clients cannot have cpus, memory or blkioWeight limits",
+		},
+		"tiny live cache": {
+			step:      Step{Function: FuncStartNode, Identifier: "a", LiveCache: 1 << 10},
+			errSubstr: "liveCache must be at least",
+		},
+		"tiny cache": {
+			step:      Step{Function: FuncStartNode, Identifier: "a", Cache: 1 << 10},
+			errSubstr: "cache must be at least",
+		},
+		"tiny memory limit": {
+			step:      Step{Function: FuncStartNode, Identifier: "a", GoMemLimit: 1 << 20},
+			errSubstr: "goMemLimit must be at least",
+		},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			err := tc.step.Check()
+			require.Error(t, err)
+			require.Contains(t, err.Error(), tc.errSubstr)
+		})
+	}
+}
+
+func TestCheck_PackedClientsRejectStepsActingOnTheirContainer(t *testing.T) {
+	packing := 5
+	start := Step{Function: FuncStartNode, Identifier: "obs", Instances: &packing, ClientsPerContainer: &packing}
+
+	cases := map[string]struct {
+		step      Step
+		errSubstr string
+	}{
+		"throttle instance": {
+			Step{Function: FuncThrottleNode, Identifier: "obs-3", Egress: 1000},
+			"node obs-3 shares its container",
+		},
+		"pause node": {
+			Step{Function: FuncPauseNode, Identifier: "obs"},
+			"node obs shares its container",
+		},
+		"upgrade": {
+			Step{Function: FuncUpgradeNode, Identifier: "obs", ImageName: "sonic:new"},
+			"node obs shares its container",
+		},
+		"partition": {
+			Step{Function: FuncPartition, Groups: [][]string{{"obs-1"}, {"val"}}},
+			"packed clients cannot be partitioned",
+		},
+		"set link": {
+			Step{Function: FuncSetLink, Link: &Link{Between: []string{"obs", "val"}}},
+			"links cannot shape the traffic of packed clients",
+		},
+		"snapshot": {
+			Step{Function: FuncSnapshot, Identifier: "s"},
+			"not supported in scenarios packing clients",
+		},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			scenario := Scenario{Name: "Test", Description: "t", Steps: []Step{start, tc.step}}
+			err := scenario.Check()
+			require.Error(t, err)
+			require.Contains(t, err.Error(), tc.errSubstr)
+		})
+	}
+
+	scenario := Scenario{Name: "Test", Description: "t", Steps: []Step{
+		start,
+		{Function: FuncPauseNode, Identifier: "obsolete"},
+		{Function: FuncResumeNode, Identifier: "obsolete"},
+		{Function: FuncKillSonic, Identifier: "obs-2"},
+		{Function: FuncHealDb, Identifier: "obs-2"},
+	}}
+	require.NoError(t, scenario.Check())
+}
+
+func TestParseBytes_RepeatExpandsItsStepsPerIteration(t *testing.T) {
+	input := `
+Name: Repeat Test
+Description: t
+DisableEndChecks: true
+Scenario:
+  - startNode: base
     type: validator
-  - updateRules:
-      Blocks:
-        MaxBlockGas: 30000000000
-      Economy:
-        MinBaseFee: "2000000000"
-      Upgrades:
-        Brio: true
+  - repeat:
+    times: 3
+    steps:
+      - startNode: val-${i}
+        type: validator
+        stake: ${i}000000
+      - delegate:
+        - node: val-${i}
+          delegator: d${i}
+          stake: 1000
+      - stopNode: val-${i}
+  - advanceEpoch
 `
 	scenario, err := ParseBytes([]byte(input))
 	require.NoError(t, err)
+	require.NoError(t, scenario.Check())
 
-	r := scenario.InitialRules
+	require.Len(t, scenario.Steps, 1+3*3+1)
+	require.Nil(t, scenario.Steps[0].Iterations)
+	require.Nil(t, scenario.Steps[10].Iterations)
+	require.Equal(t, FuncAdvanceEpoch, scenario.Steps[10].Function)
+	for i := range 3 {
+		start := scenario.Steps[1+3*i]
+		require.Equal(t, FuncStartNode, start.Function)
+		require.Equal(t, fmt.Sprintf("val-%d", i), start.Identifier)
+		require.Equal(t, uint64(i*1_000_000), *start.Stake)
+		require.Equal(t, []int{i}, start.Iterations)
+
+		delegate := scenario.Steps[2+3*i]
+		require.Equal(t, fmt.Sprintf("val-%d", i), delegate.DelegateTargets[0].Node)
+		require.Equal(t, fmt.Sprintf("d%d", i), delegate.DelegateTargets[0].Delegator)
+
+		stop := scenario.Steps[3+3*i]
+		require.Equal(t, fmt.Sprintf("val-%d", i), stop.Identifier)
+	}
+}
 
-	// Epochs
-	require.NotNil(t, r.Epochs)
-	if got, want := *r.Epochs.MaxEpochGas, uint64(5000000000); got != want {
-		require.Failf(t, "unexpected MaxEpochGas", "MaxEpochGas: got %d, want %d", got, want)
+func TestParseBytes_NestedRepeatsReferToTheirOwnIterations(t *testing.T) {
+	input := `
+Name: Nested Repeat Test
+Description: t
+DisableEndChecks: true
+Scenario:
+  - repeat:
+    variable: round
+    times: 2
+    steps:
+      - repeat:
+        times: ${round}1
+        steps:
+          - startNode: val-${round}-${i}
+      - repeat:
+        times: 1
+        steps:
+          - repeat:
+            times: 2
+            steps:
+              - stopNode: obs-${i}
+`
+	scenario, err := ParseBytes([]byte(input))
+	require.NoError(t, err)
+	require.NoError(t, scenario.Check())
+
+	var names []string
+	var iterations [][]int
+	for _, step := range scenario.Steps {
+		names = append(names, step.Identifier)
+		iterations = append(iterations, step.Iterations)
 	}
-	if got, want := int64(*r.Epochs.MaxEpochDuration), int64(30*time.Second); got != want {
-		require.Failf(t, "unexpected MaxEpochDuration", "MaxEpochDuration: got %d, want %d", got, want)
+	// The first round repeats its start once, the second one 11 times.
+	require.Len(t, names, 1+2+11+2)
+	require.Equal(t, []string{"val-0-0", "obs-0", "obs-1", "val-1-0"}, names[:4])
+	require.Equal(t, "val-1-10", names[13])
+	require.Equal(t, [][]int{{0, 0}, {0, 0, 0}, {0, 0, 1}, {1, 0}}, iterations[:4])
+}
+
+func TestParseBytes_RepeatReportsInvalidRepetitions(t *testing.T) {
+	cases := map[string]struct {
+		repeat    string
+		errSubstr string
+	}{
+		"no times": {
+			repeat: `
+  - repeat:
+    steps:
+      - advanceEpoch`,
+			errSubstr: "repeat requires times",
+		},
+		"no iterations": {
+			repeat: `
+  - repeat:
+    times: 0
+    steps:
+      - advanceEpoch`,
+			errSubstr: "repeat times must be >= 1",
+		},
+		"no steps": {
+			repeat: `
+  - repeat:
+    times: 2`,
+			errSubstr: "repeat requires at least one step",
+		},
+		"steps not a list": {
+			repeat: `
+  - repeat:
+    times: 2
+    steps: advanceEpoch`,
+			errSubstr: "steps must be a list of steps",
+		},
+		"invalid variable": {
+			repeat: `
+  - repeat:
+    times: 2
+    variable: a-b
+    steps:
+      - advanceEpoch`,
+			errSubstr: "variable name must match",
+		},
+		"invalid repeated step": {
+			repeat: `
+  - repeat:
+    times: 2
+    steps:
+      - startNode: val-${i}
+        users: 3`,
+			errSubstr: `parameter "users" is not valid for startNode`,
+		},
+		"value given": {
+			repeat: `
+  - repeat: 3
+    steps:
+      - advanceEpoch`,
+			errSubstr: "repeat does not take a value",
+		},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			_, err := ParseBytes([]byte("Name: t\nDescription: t\nScenario:" + tc.repeat + "\n"))
+			require.ErrorContains(t, err, tc.errSubstr)
+		})
 	}
+}
 
-	// Blocks
-	require.NotNil(t, r.Blocks)
-	if got, want := *r.Blocks.MaxBlockGas, uint64(20500000000); got != want {
-		require.Failf(t, "unexpected MaxBlockGas", "MaxBlockGas: got %d, want %d", got, want)
+func TestCheck_RepeatedStepsAreCheckedAsExpanded(t *testing.T) {
+	input := `
+Name: Repeat Check Test
+Description: t
+Scenario:
+  - repeat:
+    times: 2
+    steps:
+      - startNode: val_${i}
+      - pauseNode: obs
+`
+	scenario, err := ParseBytes([]byte(input))
+	require.NoError(t, err)
+	err = scenario.Check()
+	require.ErrorContains(t, err, "step 1 (startNode): node name must match")
+	require.ErrorContains(t, err, "step 4 (pauseNode): node obs is already paused")
+}
+
+func TestParseBytes_ParallelRunsItsStepsAsBranches(t *testing.T) {
+	input := `
+Name: Parallel Test
+Description: t
+DisableEndChecks: true
+Scenario:
+  - startNode: A
+    type: validator
+  - parallel:
+    - stopNode: A
+    - startNode: B
+      type: validator
+    - checks:
+      - blockHeights
+    - repeat:
+      times: 2
+      steps:
+        - startNode: obs-${i}
+  - advanceEpoch
+`
+	scenario, err := ParseBytes([]byte(input))
+	require.NoError(t, err)
+	require.NoError(t, scenario.Check())
+
+	require.Len(t, scenario.Steps, 3)
+	parallel := scenario.Steps[1]
+	require.Equal(t, FuncParallel, parallel.Function)
+	var names []string
+	for _, branch := range parallel.Steps() {
+		names = append(names, string(branch.Function)+" "+branch.Identifier)
 	}
-	if got, want := int64(*r.Blocks.MaxEmptyBlockSkipPeriod), int64(3*time.Second); got != want {
-		require.Failf(t, "unexpected MaxEmptyBlockSkipPeriod", "MaxEmptyBlockSkipPeriod: got %d, want %d", got, want)
+	require.Equal(t, []string{"stopNode A", "startNode B", "checks ", "startNode obs-0", "startNode obs-1"}, names)
+	require.Equal(t, []int{1}, parallel.Branches[4].Iterations)
+	require.Equal(t, []Step{scenario.Steps[2]}, scenario.Steps[2].Steps())
+}
+
+func TestCheck_ParallelReportsInvalidBranches(t *testing.T) {
+	cases := map[string]struct {
+		parallel  string
+		errSubstr string
+	}{
+		"single branch": {
+			parallel: `
+  - parallel:
+    - advanceEpoch`,
+			errSubstr: "parallel requires at least two steps, got 1",
+		},
+		"not a list": {
+			parallel: `
+  - parallel: advanceEpoch`,
+			errSubstr: "parallel value must be a list of steps",
+		},
+		"network-wide step": {
+			parallel: `
+  - parallel:
+    - advanceEpoch
+    - partition:
+      - [A]
+      - [B]`,
+			errSubstr: "branch 2 (partition): cannot run in parallel with other steps",
+		},
+		"nested parallel": {
+			parallel: `
+  - parallel:
+    - advanceEpoch
+    - parallel:
+      - advanceEpoch
+      - waitForEpoch`,
+			errSubstr: "branch 2 (parallel): cannot run in parallel with other steps",
+		},
+		"invalid branch": {
+			parallel: `
+  - parallel:
+    - advanceEpoch
+    - startNode: val_1`,
+			errSubstr: "branch 2 (startNode): node name must match",
+		},
+		"same node": {
+			parallel: `
+  - parallel:
+    - stopNode: A
+    - pauseNode: A`,
+			errSubstr: "branch 2 (pauseNode): A is also acted on by branch 1",
+		},
+		"instance of a node": {
+			parallel: `
+  - parallel:
+    - stopNode: A
+    - delegate:
+      - node: A-1
+        delegator: d
+        stake: 1`,
+			errSubstr: "branch 2 (delegate): A-1 is also acted on by branch 1",
+		},
+		"same delegator": {
+			parallel: `
+  - parallel:
+    - delegate:
+      - node: A
+        delegator: d
+        stake: 1
+    - undelegate:
+      - node: B
+        delegator: d`,
+			errSubstr: "branch 2 (undelegate): d is also acted on by branch 1",
+		},
+	}
+	for name, c := range cases {
+		t.Run(name, func(t *testing.T) {
+			input := `
+Name: Parallel Test
+Description: t
+DisableEndChecks: true
+Scenario:
+  - startNode: A
+    type: validator
+  - startNode: B
+    type: validator` + c.parallel + "\n"
+			scenario, err := ParseBytes([]byte(input))
+			if err == nil {
+				err = scenario.Check()
+			}
+			require.ErrorContains(t, err, c.errSubstr)
+		})
 	}
+}
 
-	// Economy (BigIntValue fields)
-	require.NotNil(t, r.Economy)
-	require.NotNil(t, r.Economy.MinBaseFee)
-	require.NotNil(t, r.Economy.MinGasPrice)
+func TestStep_SubjectsOfNetworkWideSteps(t *testing.T) {
+	partition := Step{Function: FuncPartition, Groups: [][]string{{"A", "B"}, {"C"}}}
+	require.Equal(t, []string{"A", "B", "C"}, partition.subjects())
 
-	// Upgrades
-	require.NotNil(t, r.Upgrades)
-	require.NotNil(t, r.Upgrades.Sonic)
-	require.True(t, *r.Upgrades.Sonic)
-	require.NotNil(t, r.Upgrades.Allegro)
-	require.True(t, *r.Upgrades.Allegro)
-	require.NotNil(t, r.Upgrades.Brio)
-	require.False(t, *r.Upgrades.Brio)
+	chaos := Step{Function: FuncChaos, Targets: []string{"A", "B"}}
+	require.Equal(t, []string{"A", "B"}, chaos.subjects())
 
-	// Unset categories should be nil
-	require.Nil(t, r.Dag)
-	require.Nil(t, r.Emitter)
+	untargeted := Step{Function: FuncChaos}
+	require.Equal(t, []string{allNodes}, untargeted.subjects())
+	require.True(t, overlaps(allNodes, "A-1"))
+	require.True(t, overlaps("load", allNodes))
+}
 
-	// Verify updateRules step
-	step := scenario.Steps[1]
-	require.Equal(t, FuncUpdateRules, step.Function)
-	require.NotNil(t, step.Rules.Blocks)
-	require.NotNil(t, step.Rules.Blocks.MaxBlockGas)
-	require.EqualValues(t, 30000000000, *step.Rules.Blocks.MaxBlockGas)
-	require.NotNil(t, step.Rules.Economy)
-	require.NotNil(t, step.Rules.Economy.MinBaseFee)
-	require.NotNil(t, step.Rules.Upgrades)
-	require.NotNil(t, step.Rules.Upgrades.Brio)
-	require.True(t, *step.Rules.Upgrades.Brio)
-	// Fields not in the update should remain nil
-	require.Nil(t, step.Rules.Epochs)
+func TestCheck_ParallelBranchesAreTrackedLikeSteps(t *testing.T) {
+	input := `
+Name: Parallel Check Test
+Description: t
+DisableEndChecks: true
+Scenario:
+  - startNode: A
+    type: validator
+  - startNode: B
+    type: validator
+  - parallel:
+    - pauseNode: A
+    - pauseNode: B
+  - pauseNode: A
+`
+	scenario, err := ParseBytes([]byte(input))
+	require.NoError(t, err)
+	require.ErrorContains(t, scenario.Check(), "step 4 (pauseNode): node A is already paused")
+}
+
+func TestParseWithParameters_SubstitutesDefaultsAndGivenValues(t *testing.T) {
+	input := `
+Name: Parameters Test
+Description: t
+Parameters:
+  users: 100
+  rate: 20.5
+  validators: 3
+  image: sonic:v2.2.0
+  failing: false
+DisableEndChecks: true
+Scenario:
+  - startNode: val
+    type: validator
+    instances: ${validators}
+    imageName: ${image}
+    failing: ${failing}
+  - runApp: load-${users}
+    type: counter
+    users: ${users}
+    rate:
+      constant: ${rate}
+  - repeat:
+    times: ${validators}
+    steps:
+      - stopNode: val-${i}
+`
+	scenario, err := ParseWithParameters(strings.NewReader(input), map[string]string{
+		"users":      "200",
+		"validators": "2",
+		"failing":    "true",
+	})
+	require.NoError(t, err)
+	require.NoError(t, scenario.Check())
+
+	require.Equal(t, map[string]any{
+		"users":      200,
+		"rate":       20.5,
+		"validators": 2,
+		"image":      "sonic:v2.2.0",
+		"failing":    true,
+	}, scenario.Parameters)
+
+	require.Len(t, scenario.Steps, 4)
+	start := scenario.Steps[0]
+	require.Equal(t, 2, *start.Instances)
+	require.Equal(t, "sonic:v2.2.0", start.ImageName)
+	require.True(t, start.Failing)
+	app := scenario.Steps[1]
+	require.Equal(t, "load-200", app.Identifier)
+	require.Equal(t, 200, *app.Users)
+	require.Equal(t, float32(20.5), *app.Rate.Constant)
+	require.Equal(t, "val-1", scenario.Steps[3].Identifier)
+}
+
+func TestParseWithParameters_LeavesReferencesInCommentsAlone(t *testing.T) {
+	input := `
+Name: Parameters Test
+# Scenarios may mention ${users} or even ${undeclared} in comments.
+Description: >- # ${undeclared}
+  Runs ${users} users.
+Parameters:
+  users: 100
+DisableEndChecks: true
+Scenario:
+  - runApp: load # ${undeclared}, as ${users}
+    type: counter
+    users: ${users} # ${undeclared}
+    rate:
+      constant: ${users} # ${users}
+`
+	scenario, err := ParseWithParameters(strings.NewReader(input), map[string]string{"users": "200"})
+	require.NoError(t, err)
+	require.Equal(t, "Runs 200 users.", scenario.Description)
+	require.Equal(t, 200, *scenario.Steps[0].Users)
+	require.Equal(t, float32(200), *scenario.Steps[0].Rate.Constant)
 }
 
-func TestCheck_EmptySubChecks(t *testing.T) {
-	scenario := Scenario{
-		Name:        "Test",
-		Description: "A test scenario.",
-		Steps: []Step{{
-			Function:  FuncChecks,
-			SubChecks: nil,
-		}},
+func TestParseWithParameters_ReportsInvalidParameters(t *testing.T) {
+	cases := map[string]struct {
+		parameters string
+		scenario   string
+		values     map[string]string
+		errSubstr  string
+	}{
+		"not a mapping": {
+			parameters: "Parameters: [users]",
+			errSubstr:  "line 4: Parameters must map parameter names to their defaults",
+		},
+		"invalid name": {
+			parameters: "Parameters:\n  user-count: 1",
+			errSubstr:  `parameter name "user-count" must match`,
+		},
+		"no default": {
+			parameters: "Parameters:\n  users:",
+			errSubstr:  "parameter users must default to a number, boolean or string",
+		},
+		"unknown value": {
+			parameters: "Parameters:\n  users: 1",
+			values:     map[string]string{"rate": "1"},
+			errSubstr:  `scenario declares no parameter "rate"`,
+		},
+		"value of another type": {
+			parameters: "Parameters:\n  users: 1",
+			values:     map[string]string{"users": "many"},
+			errSubstr:  `parameter users: must be an integer, got "many"`,
+		},
+		"value changing the structure": {
+			parameters: "Parameters:\n  image: sonic",
+			values:     map[string]string{"image": "a, b"},
+			errSubstr:  `parameter image: value "a, b" cannot be written into a scenario`,
+		},
+		"undefined reference": {
+			parameters: "Parameters:\n  users: 1",
+			scenario:   "  - runApp: load\n    users: ${user}",
+			errSubstr:  `line 8: undefined parameter "user"`,
+		},
+		"repeat variable named like a parameter": {
+			parameters: "Parameters:\n  i: 1",
+			scenario:   "  - repeat:\n    times: 2\n    steps:\n      - advanceEpoch",
+			errSubstr:  "line 7: repeat variable i has the name of a parameter",
+		},
+	}
+	for name, c := range cases {
+		t.Run(name, func(t *testing.T) {
+			input := `
+Name: Parameters Test
+Description: t
+` + c.parameters + `
+Scenario:
+` + c.scenario + "\n"
+			_, err := ParseWithParameters(strings.NewReader(input), c.values)
+			require.ErrorContains(t, err, c.errSubstr)
+		})
 	}
-	err := scenario.Check()
-	require.Error(t, err)
-	require.Contains(t, err.Error(), "at least one sub-check")
 }
 
-func TestCheck_ObservationWindowTooShort(t *testing.T) {
-	tooShort := MinObservationDuration - time.Millisecond
-	scenario := Scenario{
-		Name:        "Test",
-		Description: "A test scenario.",
-		Steps: []Step{{
-			Function: FuncChecks,
-			SubChecks: []CheckSpec{{
-				Function: FuncCheckBlocksProduced,
-				Duration: &tooShort,
-			}},
-		}},
+// writeFiles writes the given files, by path relative to dir.
+func writeFiles(t *testing.T, dir string, files map[string]string) {
+	t.Helper()
+	for name, content := range files {
+		path := filepath.Join(dir, name)
+		require.NoError(t, os.MkdirAll(filepath.Dir(path), 0755))
+		require.NoError(t, os.WriteFile(path, []byte(content), 0644))
 	}
-	err := scenario.Check()
-	require.Error(t, err)
-	require.Contains(t, err.Error(), "duration must be at least")
 }
 
-func TestCheck_ObservationWindowAtTheMinimumIsAccepted(t *testing.T) {
-	minimum := MinObservationDuration
-	scenario := Scenario{
-		Name:        "Test",
-		Description: "A test scenario.",
-		Steps: []Step{{
-			Function: FuncChecks,
-			SubChecks: []CheckSpec{{
-				Function: FuncCheckBlocksProduced,
-				Duration: &minimum,
-			}},
-		}},
-	}
+func TestParseFile_IncludeExpandsFragmentsRelativeToTheIncludingFile(t *testing.T) {
+	dir := t.TempDir()
+	writeFiles(t, dir, map[string]string{
+		"scenarios/test.yml": `
+Name: Include Test
+Description: t
+DisableEndChecks: true
+Scenario:
+  - include: ../fragments/bootstrap.yml
+    parameters:
+      validators: 2
+  - repeat:
+    times: 2
+    steps:
+      - include: ../fragments/epoch.yml
+  - include: ../fragments/bootstrap.yml
+`,
+		"fragments/bootstrap.yml": `
+Description: Starts validators and checks them.
+Parameters:
+  validators: 3
+Steps:
+  - startNode: val-${validators}
+    type: validator
+    instances: ${validators}
+  - include: epoch.yml
+`,
+		"fragments/epoch.yml": `
+Steps:
+  - advanceEpoch
+  - checks:
+    - blockHeights
+`,
+	})
+
+	scenario, err := ParseFile(filepath.Join(dir, "scenarios", "test.yml"))
+	require.NoError(t, err)
 	require.NoError(t, scenario.Check())
-}
 
-// checkScenario wraps a single sub-check into an otherwise valid scenario.
-func checkScenario(spec CheckSpec) *Scenario {
-	return &Scenario{
-		Name:        "Test",
-		Description: "A test scenario.",
-		Steps:       []Step{{Function: FuncChecks, SubChecks: []CheckSpec{spec}}},
+	var functions []StepFunction
+	for _, step := range scenario.Steps {
+		functions = append(functions, step.Function)
 	}
+	require.Equal(t, []StepFunction{
+		FuncStartNode, FuncAdvanceEpoch, FuncChecks,
+		FuncAdvanceEpoch, FuncChecks, FuncAdvanceEpoch, FuncChecks,
+		FuncStartNode, FuncAdvanceEpoch, FuncChecks,
+	}, functions)
+	require.Equal(t, "val-2", scenario.Steps[0].Identifier)
+	require.Equal(t, 2, *scenario.Steps[0].Instances)
+	require.Equal(t, "val-3", scenario.Steps[7].Identifier)
+	require.Equal(t, []int{1}, scenario.Steps[5].Iterations)
 }
 
-func TestCheck_ToleranceTooShortForAnObservationWindow(t *testing.T) {
-	// tolerance is the window most scenarios actually use, so it must be
-	// rejected here rather than minutes into the run, just like duration.
-	for _, function := range []StepFunction{
-		FuncCheckBlocksProduced, FuncCheckBlocksHalted, FuncCheckBlockGasRate,
-	} {
-		t.Run(string(function), func(t *testing.T) {
-			for _, tolerance := range []int{0, 1} {
-				tolerance := tolerance
-				err := checkScenario(CheckSpec{
-					Function:  function,
-					Tolerance: &tolerance,
-				}).Check()
-				require.Error(t, err, "tolerance %d", tolerance)
-				require.Contains(t, err.Error(), "tolerance is an observation window")
-			}
+func TestParseFile_IncludeReportsTheIncludeChain(t *testing.T) {
+	cases := map[string]struct {
+		files      map[string]string
+		parameters string
+		errSubstr  string
+	}{
+		"missing fragment": {
+			files:     map[string]string{},
+			errSubstr: "line 6: include a.yml: open",
+		},
+		"error in fragment": {
+			files: map[string]string{
+				"a.yml": "Steps:\n  - include: b.yml\n",
+				"b.yml": "Steps:\n  - advanceEpoch\n  - waitFor: soon\n",
+			},
+			errSubstr: "line 6: include a.yml: line 2: include b.yml: line 3:",
+		},
+		"unknown parameter": {
+			files: map[string]string{
+				"a.yml": "Parameters:\n  users: 1\nSteps:\n  - advanceEpoch\n",
+			},
+			parameters: "    parameters:\n      rate: 1\n",
+			errSubstr:  `line 6: include a.yml: scenario declares no parameter "rate"`,
+		},
+		"no steps": {
+			files: map[string]string{
+				"a.yml": "Description: empty\nSteps: []\n",
+			},
+			errSubstr: "line 6: include a.yml: fragment has no steps",
+		},
+	}
+	for name, c := range cases {
+		t.Run(name, func(t *testing.T) {
+			dir := t.TempDir()
+			writeFiles(t, dir, c.files)
+			writeFiles(t, dir, map[string]string{"test.yml": `
+Name: Include Test
+Description: t
+DisableEndChecks: true
+Scenario:
+  - include: a.yml
+` + c.parameters})
+			_, err := ParseFile(filepath.Join(dir, "test.yml"))
+			require.ErrorContains(t, err, c.errSubstr)
 		})
 	}
 }
 
-func TestCheck_ToleranceAtTheMinimumIsAccepted(t *testing.T) {
-	minimum := MinObservationSamples
-	require.NoError(t, checkScenario(CheckSpec{
-		Function:  FuncCheckBlocksProduced,
-		Tolerance: &minimum,
-	}).Check())
+func TestParseFile_IncludeCycleListsTheChain(t *testing.T) {
+	dir := t.TempDir()
+	writeFiles(t, dir, map[string]string{
+		"test.yml": "Name: t\nDescription: t\nScenario:\n  - include: a.yml\n",
+		"a.yml":    "Steps:\n  - include: test.yml\n",
+	})
+	_, err := ParseFile(filepath.Join(dir, "test.yml"))
+	chain := strings.Join([]string{
+		filepath.Join(dir, "test.yml"), filepath.Join(dir, "a.yml"), filepath.Join(dir, "test.yml"),
+	}, " -> ")
+	require.ErrorContains(t, err, "line 4: include a.yml: line 2: include test.yml: include cycle "+chain)
 }
 
-func TestCheck_ShortToleranceIsAcceptedWhenDurationOverridesIt(t *testing.T) {
-	// duration wins over tolerance at run time, so a small tolerance next to a
-	// usable duration never reaches the checker.
-	tolerance := 1
-	duration := MinObservationDuration
-	require.NoError(t, checkScenario(CheckSpec{
-		Function:  FuncCheckBlocksProduced,
-		Tolerance: &tolerance,
-		Duration:  &duration,
-	}).Check())
+func TestParseBytes_TimeoutsOfScenarioAndSteps(t *testing.T) {
+	input := `
+Name: Timeout Test
+Description: t
+Timeout: 2h
+DisableEndChecks: true
+Scenario:
+  - startNode: val
+    type: validator
+    timeout: 5m
+  - checks:
+    - blockHeights
+    timeout: 90s
+  - waitFor: 1h
+`
+	scenario, err := ParseBytes([]byte(input))
+	require.NoError(t, err)
+	require.NoError(t, scenario.Check())
+	require.Equal(t, 2*time.Hour, scenario.Timeout)
+	require.Equal(t, 5*time.Minute, scenario.Steps[0].Timeout)
+	require.Equal(t, 90*time.Second, scenario.Steps[1].Timeout)
+	require.Equal(t, time.Duration(0), scenario.Steps[2].Timeout)
 }
 
-func TestCheck_ZeroToleranceIsAcceptedForAHeightCheck(t *testing.T) {
-	// For blockHeights, tolerance is a slack in blocks; demanding exact
-	// agreement is legitimate.
-	zero := 0
-	require.NoError(t, checkScenario(CheckSpec{
-		Function:  FuncCheckBlockHeights,
-		Tolerance: &zero,
-	}).Check())
+func TestParseBytes_ReportsInvalidTimeouts(t *testing.T) {
+	cases := map[string]struct {
+		input     string
+		errSubstr string
+	}{
+		"invalid scenario timeout": {
+			input:     "Timeout: soon\nScenario:\n  - advanceEpoch",
+			errSubstr: "line 3: cannot unmarshal !!str `soon` into time.Duration",
+		},
+		"negative scenario timeout": {
+			input:     "Timeout: -1m\nScenario:\n  - advanceEpoch",
+			errSubstr: "scenario timeout must be positive, got -1m0s",
+		},
+		"invalid step timeout": {
+			input:     "Scenario:\n  - advanceEpoch:\n    timeout: soon",
+			errSubstr: "invalid timeout value",
+		},
+		"zero step timeout": {
+			input:     "Scenario:\n  - advanceEpoch:\n    timeout: 0s",
+			errSubstr: "timeout must be positive, got 0s",
+		},
+		"waitFor outlasting its timeout": {
+			input:     "Scenario:\n  - waitFor: 2m\n    timeout: 1m",
+			errSubstr: "waitFor timeout 1m0s is shorter than its duration 2m0s",
+		},
+		"timeout of a repeat step": {
+			input:     "Scenario:\n  - repeat:\n    times: 2\n    timeout: 1m\n    steps:\n      - advanceEpoch",
+			errSubstr: `parameter "timeout" is not valid for repeat`,
+		},
+	}
+	for name, c := range cases {
+		t.Run(name, func(t *testing.T) {
+			scenario, err := ParseBytes([]byte("Name: t\nDescription: t\n" + c.input + "\n"))
+			if err == nil {
+				err = scenario.Check()
+			}
+			require.ErrorContains(t, err, c.errSubstr)
+		})
+	}
 }
 
-func TestCheck_ShortDurationIsAcceptedAsAConvergenceBudget(t *testing.T) {
-	// For blockHeights and networkRules, duration bounds how long the nodes are
-	// given to agree, not how long the network is observed. Nothing has to
-	// collect two monitoring samples, so a short budget - or none at all,
-	// meaning a single attempt - is a legitimate choice and must not be
-	// rejected by the observation-window floor.
-	for _, function := range []StepFunction{
-		FuncCheckBlockHeights, FuncCheckNetworkRules,
-	} {
-		t.Run(string(function), func(t *testing.T) {
-			for _, duration := range []time.Duration{
-				0, time.Millisecond, MinObservationDuration - time.Millisecond,
-			} {
-				require.NoError(t, checkScenario(CheckSpec{
-					Function: function,
-					Duration: &duration,
-				}).Check(), "duration %s", duration)
+func TestParseBytes_ExpectedErrorsOfSteps(t *testing.T) {
+	input := `
+Name: Expected Errors Test
+Description: t
+Scenario:
+  - startNode: val
+    type: validator
+  - updateRules:
+      Blocks:
+        MaxBlockGas: 1
+    expectError: true
+  - delegate:
+      - node: val
+        delegator: alice
+        stake: 1
+    expectError: insufficient self-stake
+  - undelegate: val
+    expectError: /stake .* too low/
+  - advanceEpoch:
+    expectError: false
+`
+	scenario, err := ParseBytes([]byte(input))
+	require.NoError(t, err)
+	require.NoError(t, scenario.Check())
+	require.Nil(t, scenario.Steps[0].ExpectError)
+	require.Equal(t, &ErrorExpectation{}, scenario.Steps[1].ExpectError)
+	require.Equal(t, &ErrorExpectation{Text: "insufficient self-stake"}, scenario.Steps[2].ExpectError)
+	require.Equal(t, "stake .* too low", scenario.Steps[3].ExpectError.Pattern.String())
+	require.Nil(t, scenario.Steps[4].ExpectError)
+}
+
+func TestParseBytes_ReportsInvalidExpectedErrors(t *testing.T) {
+	cases := map[string]struct {
+		input     string
+		errSubstr string
+	}{
+		"empty text": {
+			input:     "Scenario:\n  - advanceEpoch:\n    expectError: ''",
+			errSubstr: "expectError must be true, a substring of the error or a /regular expression/",
+		},
+		"list": {
+			input:     "Scenario:\n  - advanceEpoch:\n    expectError: [a, b]",
+			errSubstr: "expectError must be true, a substring of the error or a /regular expression/",
+		},
+		"invalid regular expression": {
+			input:     "Scenario:\n  - advanceEpoch:\n    expectError: /(/",
+			errSubstr: "invalid regular expression",
+		},
+		"combined with failing": {
+			input:     "Scenario:\n  - startNode: val\n    failing: true\n    expectError: true",
+			errSubstr: "failing and expectError must not be combined",
+		},
+		"expected error of an include step": {
+			input:     "Scenario:\n  - include: fragment.yml\n    expectError: true",
+			errSubstr: `parameter "expectError" is not valid for include`,
+		},
+	}
+	for name, c := range cases {
+		t.Run(name, func(t *testing.T) {
+			scenario, err := ParseBytes([]byte("Name: t\nDescription: t\n" + c.input + "\n"))
+			if err == nil {
+				err = scenario.Check()
 			}
+			require.ErrorContains(t, err, c.errSubstr)
 		})
 	}
 }
 
-func TestCheck_ShortDurationIsRejectedForAnObservationWindow(t *testing.T) {
-	// The counterpart to the above: where duration really is the window, it
-	// still has to be long enough to hold two samples.
-	tooShort := MinObservationDuration - time.Millisecond
-	for _, function := range []StepFunction{
-		FuncCheckBlocksProduced, FuncCheckBlocksHalted, FuncCheckBlockGasRate,
-	} {
-		t.Run(string(function), func(t *testing.T) {
-			err := checkScenario(CheckSpec{
-				Function: function,
-				Duration: &tooShort,
-			}).Check()
-			require.Error(t, err)
-			require.Contains(t, err.Error(), "duration must be at least")
+func TestErrorExpectation_MatchesExpectedErrorsOnly(t *testing.T) {
+	err := fmt.Errorf("failed to delegate: stake 5 too low")
+	cases := map[string]struct {
+		expectation ErrorExpectation
+		matches     bool
+		description string
+	}{
+		"any error": {
+			expectation: ErrorExpectation{},
+			matches:     true,
+			description: "an error",
+		},
+		"contained text": {
+			expectation: ErrorExpectation{Text: "too low"},
+			matches:     true,
+			description: `an error containing "too low"`,
+		},
+		"missing text": {
+			expectation: ErrorExpectation{Text: "too high"},
+			description: `an error containing "too high"`,
+		},
+		"matching pattern": {
+			expectation: ErrorExpectation{Pattern: regexp.MustCompile(`stake \d+ too low`)},
+			matches:     true,
+			description: `an error matching /stake \d+ too low/`,
+		},
+		"mismatching pattern": {
+			expectation: ErrorExpectation{Pattern: regexp.MustCompile(`^stake`)},
+			description: "an error matching /^stake/",
+		},
+	}
+	for name, c := range cases {
+		t.Run(name, func(t *testing.T) {
+			require.Equal(t, c.matches, c.expectation.Matches(err))
+			require.False(t, c.expectation.Matches(nil))
+			require.Equal(t, c.description, c.expectation.String())
 		})
 	}
 }
 
-func TestParseBytes_UnknownCheckFunction(t *testing.T) {
+func TestParseBytes_ScheduleStep(t *testing.T) {
 	input := `
-Name: Bad Check
+Name: Schedule Test
+Description: t
 Scenario:
-  - checks:
-      - unknownCheck
+  - startNode: val
+    type: validator
+    instances: 2
+  - runApp: load
+    type: counter
+    rate:
+      constant: 10
+  - schedule:
+    - stopNode: val-1
+      at: 37s
+    - checks:
+      - blockHeights
+      atBlock: 500
+    - advanceEpoch:
+      atEpoch: 3
+      timeout: 1m
+  - waitFor: 2m
+  - stopApp: load
 `
-	_, err := ParseBytes([]byte(input))
-	require.Error(t, err)
-	require.Contains(t, err.Error(), "unknown check function")
+	scenario, err := ParseBytes([]byte(input))
+	require.NoError(t, err)
+	require.NoError(t, scenario.Check())
+	require.Equal(t, 3, scenario.EndChecks)
+
+	step := scenario.Steps[2]
+	require.Equal(t, FuncSchedule, step.Function)
+	require.Len(t, step.Branches, 3)
+	require.Equal(t, "37s", step.Branches[0].Trigger.String())
+	require.Equal(t, "block 500", step.Branches[1].Trigger.String())
+	require.Equal(t, "epoch 3", step.Branches[2].Trigger.String())
+	require.Equal(t, time.Minute, step.Branches[2].Timeout)
+	require.Equal(t, step.Branches, step.Steps())
+}
+
+func TestParseBytes_ReportsInvalidScheduleSteps(t *testing.T) {
+	start := "Scenario:\n  - startNode: val\n    type: validator\n    instances: 2\n"
+	cases := map[string]struct {
+		input     string
+		errSubstr string
+	}{
+		"no steps": {
+			input:     start + "  - schedule: []",
+			errSubstr: "schedule requires at least one step",
+		},
+		"scheduled step without trigger": {
+			input:     start + "  - schedule:\n    - stopNode: val-1",
+			errSubstr: "scheduled step 1 (stopNode): requires one of at, atBlock or atEpoch",
+		},
+		"two triggers": {
+			input:     start + "  - schedule:\n    - stopNode: val-1\n      at: 1s\n      atBlock: 5",
+			errSubstr: "at, atBlock and atEpoch must not be combined",
+		},
+		"negative offset": {
+			input:     start + "  - schedule:\n    - stopNode: val-1\n      at: -1s",
+			errSubstr: "at must not be negative, got -1s",
+		},
+		"trigger outside a schedule": {
+			input:     start + "  - stopNode: val-1\n    at: 1s",
+			errSubstr: "at, atBlock and atEpoch are only valid in the steps of a schedule step",
+		},
+		"network-wide scheduled step": {
+			input:     start + "  - schedule:\n    - partition:\n      - [val-1]\n      - [val-2]\n      at: 1s",
+			errSubstr: "scheduled step 1 (partition): cannot run in the background of other steps",
+		},
+		"invalid scheduled step": {
+			input:     start + "  - schedule:\n    - waitFor: 1m\n      timeout: 1s\n      at: 1s",
+			errSubstr: "scheduled step 1 (waitFor): waitFor timeout 1s is shorter than its duration 1m0s",
+		},
+		"timeout of the schedule step": {
+			input:     start + "  - schedule:\n    - stopNode: val-1\n      at: 1s\n    timeout: 1m",
+			errSubstr: "schedule steps complete at once",
+		},
+		"later step acting on a scheduled node": {
+			input:     start + "  - schedule:\n    - stopNode: val-1\n      at: 1s\n  - stopNode: val",
+			errSubstr: "step 3 (stopNode): val is acted on by the steps scheduled by step 2",
+		},
+		"later partition cutting off a scheduled node": {
+			input:     start + "  - schedule:\n    - stopNode: val-1\n      at: 1s\n  - partition:\n    - [val-1]\n    - [val-2]",
+			errSubstr: "step 3 (partition): val-1 is acted on by the steps scheduled by step 2",
+		},
+		"later chaos targeting a scheduled node": {
+			input:     start + "  - schedule:\n    - stopNode: val-1\n      at: 1s\n  - chaos: 1m\n    targets: [val]",
+			errSubstr: "step 3 (chaos): val is acted on by the steps scheduled by step 2",
+		},
+		"snapshot after a schedule": {
+			input:     start + "  - schedule:\n    - checks:\n      - blockHeights\n      at: 1s\n  - snapshot: s",
+			errSubstr: "step 3 (snapshot): steps scheduled by step 2 may still be pending",
+		},
+	}
+	for name, c := range cases {
+		t.Run(name, func(t *testing.T) {
+			scenario, err := ParseBytes([]byte("Name: t\nDescription: t\n" + c.input + "\n"))
+			if err == nil {
+				err = scenario.Check()
+			}
+			require.ErrorContains(t, err, c.errSubstr)
+		})
+	}
 }
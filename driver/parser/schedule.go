@@ -0,0 +1,133 @@
+// Copyright 2024 Fantom Foundation
+// This file is part of Norma System Testing Infrastructure for Sonic.
+//
+// Norma is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// Norma is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with Norma. If not, see <http://www.gnu.org/licenses/>.
+
+package parser
+
+import (
+	"errors"
+	"fmt"
+	"time"
+)
+
+// Trigger is the point a scheduled step fires at: an offset from the time
+// its schedule step ran, or the first block or epoch of the given number.
+// Exactly one of them is set.
+type Trigger struct {
+	After *time.Duration
+	Block *uint64
+	Epoch *uint64
+}
+
+// String describes the trigger the way it is written, for naming the
+// scheduled step in the run output.
+func (t Trigger) String() string {
+	switch {
+	case t.Block != nil:
+		return fmt.Sprintf("block %d", *t.Block)
+	case t.Epoch != nil:
+		return fmt.Sprintf("epoch %d", *t.Epoch)
+	case t.After != nil:
+		return t.After.String()
+	default:
+		return "never"
+	}
+}
+
+// errTriggerOutsideSchedule is reported for a trigger on a step that is not
+// scheduled by a schedule step.
+var errTriggerOutsideSchedule = errors.New("at, atBlock and atEpoch are only valid in the steps of a schedule step")
+
+// setTrigger sets the trigger of a step, which may only have one.
+func (s *Step) setTrigger(trigger Trigger) error {
+	if s.Trigger != nil {
+		return fmt.Errorf("at, atBlock and atEpoch must not be combined")
+	}
+	s.Trigger = &trigger
+	return nil
+}
+
+// checkSchedule validates the steps of a schedule step. They run in the
+// background of the steps after the schedule step, one after another, so
+// the steps acting on the network as a whole cannot be among them, as the
+// steps running next to them would be caught halfway by them.
+func (s *Step) checkSchedule() error {
+	if len(s.Branches) == 0 {
+		return fmt.Errorf("schedule requires at least one step")
+	}
+	if s.Timeout > 0 || s.ExpectError != nil {
+		return fmt.Errorf("schedule steps complete at once; set timeout and expectError on the scheduled steps")
+	}
+
+	errs := []error{}
+	for i, scheduled := range s.Branches {
+		if notInParallel[scheduled.Function] {
+			errs = append(errs, fmt.Errorf("scheduled step %d (%s): cannot run in the background of other steps", i+1, scheduled.Function))
+			continue
+		}
+		if scheduled.Trigger == nil {
+			errs = append(errs, fmt.Errorf("scheduled step %d (%s): requires one of at, atBlock or atEpoch", i+1, scheduled.Function))
+			continue
+		}
+		unscheduled := scheduled
+		unscheduled.Trigger = nil
+		if err := unscheduled.Check(); err != nil {
+			errs = append(errs, fmt.Errorf("scheduled step %d (%s): %w", i+1, scheduled.Function, err))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// checkSchedules validates the interplay of the schedule steps of the
+// scenario with the other steps. The steps a schedule step schedules run
+// on their own until the end checks, so no step after the schedule step may
+// act on the nodes, applications or delegators they act on, and no snapshot
+// may be taken while they may still be pending.
+func (s *Scenario) checkSchedules() []error {
+	errs := []error{}
+	// scheduled lists the names scheduled steps act on, by schedule step.
+	type claim struct {
+		name string
+		step int
+	}
+	var scheduled []claim
+	firstSchedule := 0
+	for i, step := range s.Steps {
+		if step.Function == FuncSnapshot && firstSchedule > 0 {
+			errs = append(errs, fmt.Errorf("step %d (%s): steps scheduled by step %d may still be pending", i+1, step.Function, firstSchedule))
+		}
+		for _, step := range step.Steps() {
+			for _, name := range step.subjects() {
+				for _, other := range scheduled {
+					if overlaps(name, other.name) {
+						errs = append(errs, fmt.Errorf("step %d (%s): %v is acted on by the steps scheduled by step %d", i+1, step.Function, name, other.step))
+						break
+					}
+				}
+			}
+		}
+		if step.Function == FuncSchedule {
+			if firstSchedule == 0 {
+				firstSchedule = i + 1
+			}
+			for _, step := range step.Branches {
+				for _, name := range step.subjects() {
+					scheduled = append(scheduled, claim{name, i + 1})
+				}
+			}
+		}
+	}
+	return errs
+}
@@ -0,0 +1,100 @@
+// Copyright 2024 Fantom Foundation
+// This file is part of Norma System Testing Infrastructure for Sonic.
+//
+// Norma is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// Norma is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with Norma. If not, see <http://www.gnu.org/licenses/>.
+
+package parser
+
+import (
+	"errors"
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Topology kinds as written in a scenario.
+const (
+	TopologyFullMesh = "fullMesh"
+	TopologyRing     = "ring"
+	TopologyStar     = "star"
+	TopologyLine     = "line"
+	TopologyCustom   = "custom"
+)
+
+// Topology describes which nodes of a network are peered with each other.
+// In a scenario it is written either as the name of a shape - fullMesh,
+// ring, line or star:<hub> - or as an adjacency list mapping a node or
+// region to the nodes and regions it is peered with. Nodes are placed in a
+// ring or line in the order they start. The zero value is a full mesh.
+type Topology struct {
+	Kind      string
+	Hub       string
+	Adjacency map[string][]string
+}
+
+// UnmarshalYAML implements custom YAML unmarshalling for Topology.
+func (t *Topology) UnmarshalYAML(value *yaml.Node) error {
+	switch value.Kind {
+	case yaml.ScalarNode:
+		kind, hub, hasHub := strings.Cut(value.Value, ":")
+		switch {
+		case kind == TopologyStar && hasHub:
+			t.Kind, t.Hub = TopologyStar, hub
+		case !hasHub && slices.Contains([]string{TopologyFullMesh, TopologyRing, TopologyLine}, kind):
+			t.Kind = kind
+		default:
+			return fmt.Errorf("line %d: unknown topology %q, expected fullMesh, ring, line, star:<hub> or an adjacency list", value.Line, value.Value)
+		}
+	case yaml.MappingNode:
+		var adjacency map[string][]string
+		if err := value.Decode(&adjacency); err != nil {
+			return fmt.Errorf("line %d: invalid adjacency list: %w", value.Line, err)
+		}
+		t.Kind, t.Adjacency = TopologyCustom, adjacency
+	default:
+		return fmt.Errorf("line %d: topology must be a name or an adjacency list", value.Line)
+	}
+	return nil
+}
+
+// IsFullMesh reports whether t peers every node with every other node.
+func (t *Topology) IsFullMesh() bool {
+	return t.Kind == "" || t.Kind == TopologyFullMesh
+}
+
+// Check validates the names a topology refers to.
+func (t *Topology) Check() error {
+	errs := []error{}
+	switch t.Kind {
+	case TopologyStar:
+		if !NamePattern.Match([]byte(t.Hub)) {
+			errs = append(errs, fmt.Errorf("star hub must match %v, got %v", namePatternStr, t.Hub))
+		}
+	case TopologyCustom:
+		if len(t.Adjacency) == 0 {
+			errs = append(errs, fmt.Errorf("adjacency list must not be empty"))
+		}
+		for _, name := range slices.Sorted(maps.Keys(t.Adjacency)) {
+			for _, end := range append([]string{name}, t.Adjacency[name]...) {
+				if !NamePattern.Match([]byte(end)) {
+					errs = append(errs, fmt.Errorf("adjacency list: name must match %v, got %v", namePatternStr, end))
+				}
+			}
+		}
+	}
+	return errors.Join(errs...)
+}
@@ -0,0 +1,44 @@
+// Copyright 2024 Fantom Foundation
+// This file is part of Norma System Testing Infrastructure for Sonic.
+//
+// Norma is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// Norma is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with Norma. If not, see <http://www.gnu.org/licenses/>.
+
+package driver
+
+// Resources limits the host resources available to a node, making results
+// comparable across hosts and modelling under-provisioned machines. A zero
+// field leaves the respective resource unlimited.
+type Resources struct {
+	// CPUs is the number of CPUs the node may use; it may be fractional.
+	CPUs float64
+	// Memory is the amount of memory, in bytes, the node may use. The node
+	// cannot swap.
+	Memory uint64
+	// BlkioWeight is the node's share of the block IO bandwidth relative to
+	// other nodes, between 10 and 1000.
+	BlkioWeight uint16
+}
+
+// ClientMemory tunes the memory the client of a node uses, so that networks
+// of many light nodes fit on one host. A zero field keeps the client's
+// default.
+type ClientMemory struct {
+	// LiveCache is the size, in bytes, of the state database's node cache.
+	LiveCache uint64
+	// Cache is the memory, in bytes, the client allots to its other caches.
+	Cache uint64
+	// GoMemLimit is the soft memory limit, in bytes, of the client's Go
+	// runtime.
+	GoMemLimit uint64
+}
@@ -0,0 +1,33 @@
+// Copyright 2024 Fantom Foundation
+// This file is part of Norma System Testing Infrastructure for Sonic.
+//
+// Norma is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// Norma is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with Norma. If not, see <http://www.gnu.org/licenses/>.
+
+package driver
+
+// ThrottleConfig limits the bandwidth of a node's network interface, as on
+// a constrained uplink. A zero rate leaves the respective direction
+// unlimited.
+type ThrottleConfig struct {
+	// Egress is the maximum rate of the traffic the node sends, in bits per
+	// second.
+	Egress uint64
+	// Ingress is the maximum rate of the traffic the node receives, in bits
+	// per second.
+	Ingress uint64
+	// Burst is the amount of data, in bytes, that may be transferred at
+	// full speed before the rates take effect. Zero selects a default
+	// suited to the rates.
+	Burst uint64
+}
@@ -0,0 +1,131 @@
+// Copyright 2024 Fantom Foundation
+// This file is part of Norma System Testing Infrastructure for Sonic.
+//
+// Norma is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// Norma is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with Norma. If not, see <http://www.gnu.org/licenses/>.
+
+package driver
+
+// TopologyKind names the shape of the peer connections of a network.
+type TopologyKind string
+
+const (
+	// TopologyFullMesh peers every node with every other node. It is the
+	// only topology the nodes may extend on their own through peer discovery.
+	TopologyFullMesh TopologyKind = "fullMesh"
+	// TopologyRing peers every node with the nodes that joined right before
+	// and after it, and the last node with the first.
+	TopologyRing TopologyKind = "ring"
+	// TopologyStar peers the hub with every other node.
+	TopologyStar TopologyKind = "star"
+	// TopologyLine peers every node with the nodes that joined right before
+	// and after it.
+	TopologyLine TopologyKind = "line"
+	// TopologyCustom peers the nodes listed in an adjacency list.
+	TopologyCustom TopologyKind = "custom"
+)
+
+// Topology describes which nodes of a network are peered with each other.
+// Nodes are named as in LinkRule: by label, or by the base name of a node
+// started with several instances, which covers all of them. The zero value
+// is a full mesh.
+type Topology struct {
+	Kind TopologyKind
+	// Hub names the center of a star.
+	Hub string
+	// Adjacency lists, for a custom topology, the nodes each node is peered
+	// with. A peering listed for one of its ends need not be repeated for
+	// the other.
+	Adjacency map[string][]string
+}
+
+// Peering is an unordered pair of node labels whose nodes are peered.
+type Peering struct {
+	A, B string
+}
+
+// NewPeering returns the peering of the nodes with the given labels, in a
+// canonical order so that it does not depend on the order of its ends.
+func NewPeering(a, b string) Peering {
+	if b < a {
+		a, b = b, a
+	}
+	return Peering{A: a, B: b}
+}
+
+// Has reports whether the node with the given label is an end of p.
+func (p Peering) Has(label string) bool {
+	return p.A == label || p.B == label
+}
+
+// Other returns the end of p other than the node with the given label.
+func (p Peering) Other(label string) string {
+	if p.A == label {
+		return p.B
+	}
+	return p.A
+}
+
+// IsFullMesh reports whether t peers every node with every other node.
+func (t *Topology) IsFullMesh() bool {
+	return t.Kind == "" || t.Kind == TopologyFullMesh
+}
+
+// Peerings returns the peerings of t among the nodes with the given labels,
+// listed in the order the nodes joined the network.
+func (t *Topology) Peerings(labels []string) map[Peering]bool {
+	res := map[Peering]bool{}
+	add := func(a, b string) {
+		if a != b {
+			res[NewPeering(a, b)] = true
+		}
+	}
+	switch t.Kind {
+	case TopologyRing, TopologyLine:
+		for i := 1; i < len(labels); i++ {
+			add(labels[i-1], labels[i])
+		}
+		if t.Kind == TopologyRing && len(labels) > 2 {
+			add(labels[len(labels)-1], labels[0])
+		}
+	case TopologyStar:
+		for _, hub := range labels {
+			if !MatchesNodeName(hub, t.Hub) {
+				continue
+			}
+			for _, other := range labels {
+				add(hub, other)
+			}
+		}
+	case TopologyCustom:
+		for name, neighbors := range t.Adjacency {
+			for _, a := range labels {
+				if !MatchesNodeName(a, name) {
+					continue
+				}
+				for _, b := range labels {
+					if matchesAny(neighbors, b) {
+						add(a, b)
+					}
+				}
+			}
+		}
+	default:
+		for i, a := range labels {
+			for _, b := range labels[i+1:] {
+				add(a, b)
+			}
+		}
+	}
+	return res
+}
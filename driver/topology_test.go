@@ -0,0 +1,99 @@
+// Copyright 2024 Fantom Foundation
+// This file is part of Norma System Testing Infrastructure for Sonic.
+//
+// Norma is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// Norma is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with Norma. If not, see <http://www.gnu.org/licenses/>.
+
+package driver
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewPeering_IsIndependentOfTheOrderOfItsEnds(t *testing.T) {
+	require.Equal(t, NewPeering("a", "b"), NewPeering("b", "a"))
+	peering := NewPeering("b", "a")
+	require.True(t, peering.Has("a"))
+	require.False(t, peering.Has("c"))
+	require.Equal(t, "b", peering.Other("a"))
+	require.Equal(t, "a", peering.Other("b"))
+}
+
+func TestTopology_Peerings(t *testing.T) {
+	labels := []string{"val-0", "val-1", "val-2", "obs"}
+	tests := map[string]struct {
+		topology Topology
+		want     [][2]string
+	}{
+		"zero value is a full mesh": {
+			topology: Topology{},
+			want: [][2]string{
+				{"val-0", "val-1"}, {"val-0", "val-2"}, {"val-0", "obs"},
+				{"val-1", "val-2"}, {"val-1", "obs"}, {"val-2", "obs"},
+			},
+		},
+		"ring closes on the first node": {
+			topology: Topology{Kind: TopologyRing},
+			want: [][2]string{
+				{"val-0", "val-1"}, {"val-1", "val-2"}, {"val-2", "obs"}, {"obs", "val-0"},
+			},
+		},
+		"line": {
+			topology: Topology{Kind: TopologyLine},
+			want:     [][2]string{{"val-0", "val-1"}, {"val-1", "val-2"}, {"val-2", "obs"}},
+		},
+		"star around a single node": {
+			topology: Topology{Kind: TopologyStar, Hub: "obs"},
+			want:     [][2]string{{"obs", "val-0"}, {"obs", "val-1"}, {"obs", "val-2"}},
+		},
+		"star around the instances of a node": {
+			topology: Topology{Kind: TopologyStar, Hub: "val"},
+			want: [][2]string{
+				{"val-0", "val-1"}, {"val-0", "val-2"}, {"val-0", "obs"},
+				{"val-1", "val-2"}, {"val-1", "obs"}, {"val-2", "obs"},
+			},
+		},
+		"custom adjacency in either direction": {
+			topology: Topology{Kind: TopologyCustom, Adjacency: map[string][]string{
+				"val-0": {"val-1"},
+				"obs":   {"val"},
+			}},
+			want: [][2]string{{"val-0", "val-1"}, {"obs", "val-0"}, {"obs", "val-1"}, {"obs", "val-2"}},
+		},
+	}
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			want := map[Peering]bool{}
+			for _, pair := range test.want {
+				want[NewPeering(pair[0], pair[1])] = true
+			}
+			require.Equal(t, want, test.topology.Peerings(labels))
+		})
+	}
+}
+
+func TestTopology_RingOfTwoIsASinglePeering(t *testing.T) {
+	topology := Topology{Kind: TopologyRing}
+	require.Equal(t,
+		map[Peering]bool{NewPeering("a", "b"): true},
+		topology.Peerings([]string{"a", "b"}),
+	)
+}
+
+func TestTopology_IsFullMesh(t *testing.T) {
+	require.True(t, (&Topology{}).IsFullMesh())
+	require.True(t, (&Topology{Kind: TopologyFullMesh}).IsFullMesh())
+	require.False(t, (&Topology{Kind: TopologyLine}).IsFullMesh())
+}
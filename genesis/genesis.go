@@ -10,6 +10,7 @@ import (
 
 	gas_subsidies_registry "github.com/0xsoniclabs/sonic/gossip/blockproc/subsidies/registry"
 	"github.com/0xsoniclabs/sonic/integration/makefakegenesis"
+	"github.com/0xsoniclabs/sonic/inter/validatorpk"
 	"github.com/0xsoniclabs/sonic/opera"
 	"github.com/0xsoniclabs/sonic/opera/contracts/driver"
 	"github.com/0xsoniclabs/sonic/opera/contracts/driver/drivercall"
@@ -19,6 +20,7 @@ import (
 	"github.com/0xsoniclabs/sonic/opera/contracts/sfc"
 	"github.com/0xsoniclabs/sonic/utils"
 	"github.com/Fantom-foundation/lachesis-base/inter/idx"
+	"github.com/ethereum/go-ethereum/crypto"
 	"github.com/holiman/uint256"
 )
 
@@ -30,11 +32,15 @@ import (
 // on the client version - currently the subsidies registry - are chosen to suit
 // the oldest client of the network. The versions are the ones the clients report
 // for themselves; see docker.ClientVersions.
+//
+// If initialState is not nil, the accounts and contracts it describes are
+// added to the genesis as well.
 func GenerateJsonGenesis(
 	jsonFile string,
 	validatorStakes []uint64,
 	rules *opera.Rules,
 	clientVersions []string,
+	initialState *InitialState,
 ) error {
 	registryCode, err := subsidiesRegistryCode(clientVersions)
 	if err != nil {
@@ -87,10 +93,25 @@ func GenerateJsonGenesis(
 		},
 	}
 
-	// Create validator accounts and distribute initial supply.
-	const maxValidators = 100
+	// Create validator accounts and distribute initial supply. Validators
+	// beyond the fakenet ones share everything but their keys with the first.
+	accounts := ValidatorAccounts(validatorsCount)
 	totalSupply := utils.ToFtm(1_000_000_000_000_000)
-	validators := makefakegenesis.GetFakeValidators(idx.Validator(maxValidators))
+	validators := makefakegenesis.GetFakeValidators(idx.Validator(FakeNetValidators))
+	for id := FakeNetValidators + 1; id <= accounts; id++ {
+		key, err := ValidatorKey(id)
+		if err != nil {
+			return err
+		}
+		validator := validators[0]
+		validator.ID = idx.ValidatorID(id)
+		validator.Address = crypto.PubkeyToAddress(key.PublicKey)
+		validator.PubKey = validatorpk.PubKey{
+			Raw:  crypto.FromECDSAPub(&key.PublicKey),
+			Type: validatorpk.Types.Secp256k1,
+		}
+		validators = append(validators, validator)
+	}
 	supplyEach := new(big.Int).Div(totalSupply, big.NewInt(int64(len(validators))))
 	for _, validator := range validators {
 		jsonGenesis.Accounts = append(jsonGenesis.Accounts, makefakegenesis.Account{
@@ -100,6 +121,12 @@ func GenerateJsonGenesis(
 		})
 	}
 
+	initial, err := initialAccounts(initialState)
+	if err != nil {
+		return err
+	}
+	jsonGenesis.Accounts = append(jsonGenesis.Accounts, initial...)
+
 	// Configure genesis validators only for the configured number of validators.
 	validators = validators[0:validatorsCount]
 	delegations := make([]drivercall.Delegation, 0, validatorsCount)
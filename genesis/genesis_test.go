@@ -5,11 +5,14 @@ import (
 	"math/big"
 	"os"
 	"path/filepath"
+	"strings"
 	"testing"
 
 	gas_subsidies_registry "github.com/0xsoniclabs/sonic/gossip/blockproc/subsidies/registry"
 	"github.com/0xsoniclabs/sonic/integration/makefakegenesis"
 	"github.com/0xsoniclabs/sonic/opera"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
 	"github.com/stretchr/testify/require"
 )
 
@@ -24,7 +27,7 @@ func TestGenerateJsonGenesis_BalancesAreReadableByOlderClients(t *testing.T) {
 	path := filepath.Join(t.TempDir(), "genesis.json")
 	rules := opera.FakeNetRules(opera.GetSonicUpgrades())
 	require.NoError(GenerateJsonGenesis(
-		path, []uint64{5_000_000}, &rules, []string{"2.1.6"}))
+		path, []uint64{5_000_000}, &rules, []string{"2.1.6"}, nil))
 
 	content, err := os.ReadFile(path)
 	require.NoError(err)
@@ -61,7 +64,7 @@ func TestGenerateJsonGenesis_InstallsTheRegistryOfTheOldestClient(t *testing.T)
 			path := filepath.Join(t.TempDir(), "genesis.json")
 			rules := opera.FakeNetRules(opera.GetSonicUpgrades())
 			require.NoError(GenerateJsonGenesis(
-				path, []uint64{5_000_000}, &rules, clientVersions))
+				path, []uint64{5_000_000}, &rules, clientVersions, nil))
 
 			content, err := os.ReadFile(path)
 			require.NoError(err)
@@ -87,6 +90,44 @@ func TestGenerateJsonGenesis_InstallsTheRegistryOfTheOldestClient(t *testing.T)
 	}
 }
 
+// TestGenerateJsonGenesis_FundsAnAccountPerValidatorBeyondTheFakeNet checks
+// that networks with more validators than Sonic's fakenet get an account for
+// each of them, keyed like the nodes running them.
+func TestGenerateJsonGenesis_FundsAnAccountPerValidatorBeyondTheFakeNet(t *testing.T) {
+	require := require.New(t)
+
+	const validators = 150
+	stakes := make([]uint64, validators)
+	for i := range stakes {
+		stakes[i] = 5_000_000
+	}
+	path := filepath.Join(t.TempDir(), "genesis.json")
+	rules := opera.FakeNetRules(opera.GetSonicUpgrades())
+	require.NoError(GenerateJsonGenesis(path, stakes, &rules, []string{"2.2.0"}, nil))
+
+	content, err := os.ReadFile(path)
+	require.NoError(err)
+	var genesis struct {
+		Accounts []makefakegenesis.Account
+		Txs      []makefakegenesis.Transaction
+	}
+	require.NoError(json.Unmarshal(content, &genesis))
+
+	funded := map[common.Address]bool{}
+	for _, account := range genesis.Accounts {
+		if strings.HasPrefix(account.Name, "validator_") {
+			funded[account.Address] = account.Balance.Sign() > 0
+		}
+	}
+	require.Len(funded, validators)
+	for id := 1; id <= validators; id++ {
+		key, err := ValidatorKey(id)
+		require.NoError(err)
+		require.True(funded[crypto.PubkeyToAddress(key.PublicKey)], "validator %d has no funded account", id)
+	}
+	require.NotEmpty(genesis.Txs)
+}
+
 func TestEncodeBalancesAsNumbers_UnquotesBalancesOnly(t *testing.T) {
 	in := []byte(`{
   "Accounts": [
@@ -0,0 +1,218 @@
+package genesis
+
+import (
+	"errors"
+	"fmt"
+	"math/rand"
+	"slices"
+	"strings"
+
+	contract "github.com/0xsoniclabs/norma/load/contracts/abi"
+	"github.com/0xsoniclabs/sonic/integration/makefakegenesis"
+	"github.com/0xsoniclabs/sonic/utils"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/tracing"
+	"github.com/ethereum/go-ethereum/core/vm"
+	"github.com/ethereum/go-ethereum/core/vm/runtime"
+	"github.com/holiman/uint256"
+)
+
+// defaultInitialBalance is the balance, in S, of each generated account if
+// the initial state does not specify one.
+const defaultInitialBalance = 1_000
+
+// InitialState describes state a genesis is pre-populated with, on top of the
+// infrastructure contracts and validator accounts every network starts with.
+// It lets scenarios process blocks against a large state DB without warming it
+// up by hours of load first.
+//
+// All of it is derived from Seed, so every node generating a genesis for the
+// same scenario ends up with identical state.
+type InitialState struct {
+	// Seed makes the generated addresses and storage reproducible.
+	Seed int64 `yaml:"seed,omitempty"`
+	// Accounts is the number of funded externally owned accounts.
+	Accounts int `yaml:"accounts,omitempty"`
+	// Balance is the balance of each account in S; zero selects the default.
+	Balance uint64 `yaml:"balance,omitempty"`
+	// Contracts is the number of deployed contracts.
+	Contracts int `yaml:"contracts,omitempty"`
+	// ContractTypes lists the kinds of contracts deployed, in turn; all known
+	// kinds if empty.
+	ContractTypes []string `yaml:"contractTypes,omitempty"`
+	// StorageSlots is the number of storage slots filled with random values,
+	// spread evenly over the contracts.
+	StorageSlots int `yaml:"storageSlots,omitempty"`
+}
+
+// initialContracts lists the contracts an initial state may deploy, by the
+// name scenarios refer to them with. Their constructors take no arguments
+// but those given here.
+var initialContracts = map[string]func() ([]byte, error){
+	"counter": func() ([]byte, error) {
+		return common.FromHex(contract.CounterMetaData.Bin), nil
+	},
+	"store": func() ([]byte, error) {
+		return common.FromHex(contract.StoreMetaData.Bin), nil
+	},
+	"erc20": func() ([]byte, error) {
+		abi, err := contract.ERC20MetaData.GetAbi()
+		if err != nil {
+			return nil, err
+		}
+		args, err := abi.Pack("", "Genesis Token", "GEN")
+		if err != nil {
+			return nil, err
+		}
+		return append(common.FromHex(contract.ERC20MetaData.Bin), args...), nil
+	},
+}
+
+// InitialContractTypes returns the sorted names of the contracts an initial
+// state may deploy.
+func InitialContractTypes() []string {
+	names := make([]string, 0, len(initialContracts))
+	for name := range initialContracts {
+		names = append(names, name)
+	}
+	slices.Sort(names)
+	return names
+}
+
+// ValidateInitialState checks that the given initial state can be generated.
+func ValidateInitialState(state InitialState) error {
+	var errs []error
+	if state.Accounts < 0 {
+		errs = append(errs, fmt.Errorf("accounts must not be negative, got %d", state.Accounts))
+	}
+	if state.Contracts < 0 {
+		errs = append(errs, fmt.Errorf("contracts must not be negative, got %d", state.Contracts))
+	}
+	if state.StorageSlots < 0 {
+		errs = append(errs, fmt.Errorf("storageSlots must not be negative, got %d", state.StorageSlots))
+	}
+	if state.StorageSlots > 0 && state.Contracts <= 0 {
+		errs = append(errs, fmt.Errorf("storageSlots require at least one contract to hold them"))
+	}
+	for _, kind := range state.ContractTypes {
+		if _, found := initialContracts[kind]; !found {
+			errs = append(errs, fmt.Errorf("unknown contract type %q, must be one of %s",
+				kind, strings.Join(InitialContractTypes(), ", ")))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// initialAccounts generates the accounts of the given initial state. Accounts
+// get random addresses, contracts the code and storage their constructor
+// leaves behind, plus their share of the random storage slots. The slots are
+// not reachable through the functions of the contracts; they only populate
+// the state DB.
+func initialAccounts(state *InitialState) ([]makefakegenesis.Account, error) {
+	if state == nil {
+		return nil, nil
+	}
+	if err := ValidateInitialState(*state); err != nil {
+		return nil, fmt.Errorf("invalid initial state: %w", err)
+	}
+	rng := rand.New(rand.NewSource(state.Seed))
+	accounts := make([]makefakegenesis.Account, 0, state.Accounts+state.Contracts)
+
+	balance := state.Balance
+	if balance == 0 {
+		balance = defaultInitialBalance
+	}
+	for i := range state.Accounts {
+		accounts = append(accounts, makefakegenesis.Account{
+			Name:    fmt.Sprintf("account_%d", i),
+			Address: randomAddress(rng),
+			Balance: uint256.MustFromBig(utils.ToFtm(balance)),
+		})
+	}
+
+	kinds := state.ContractTypes
+	if len(kinds) == 0 {
+		kinds = InitialContractTypes()
+	}
+	deployed := map[string]deployedContract{}
+	for i := range state.Contracts {
+		kind := kinds[i%len(kinds)]
+		template, found := deployed[kind]
+		if !found {
+			var err error
+			if template, err = deployContract(kind); err != nil {
+				return nil, fmt.Errorf("failed to deploy %s contract: %w", kind, err)
+			}
+			deployed[kind] = template
+		}
+		storage := make(map[common.Hash]common.Hash, len(template.storage)+state.StorageSlots/state.Contracts+1)
+		for slot, value := range template.storage {
+			storage[slot] = value
+		}
+		accounts = append(accounts, makefakegenesis.Account{
+			Name:    fmt.Sprintf("%s_%d", kind, i),
+			Address: randomAddress(rng),
+			Code:    template.code,
+			Storage: storage,
+			Nonce:   1,
+		})
+	}
+
+	contracts := accounts[state.Accounts:]
+	for i := range state.StorageSlots {
+		value := randomHash(rng)
+		value[0] |= 1 // zero values would not be stored
+		contracts[i%len(contracts)].Storage[randomHash(rng)] = value
+	}
+	return accounts, nil
+}
+
+// deployedContract is a contract as its constructor left it.
+type deployedContract struct {
+	code    []byte
+	storage map[common.Hash]common.Hash
+}
+
+// deployContract runs the constructor of the given kind of contract in a
+// scratch EVM, recording the runtime code and storage it produces.
+func deployContract(kind string) (deployedContract, error) {
+	input, err := initialContracts[kind]()
+	if err != nil {
+		return deployedContract{}, err
+	}
+	// Collect the slots the constructor writes, then read what it left in
+	// them once it is done.
+	written := map[common.Hash]bool{}
+	config := &runtime.Config{EVMConfig: vm.Config{Tracer: &tracing.Hooks{
+		OnOpcode: func(_ uint64, op byte, _, _ uint64, scope tracing.OpContext, _ []byte, _ int, _ error) {
+			if vm.OpCode(op) != vm.SSTORE {
+				return
+			}
+			stack := scope.StackData()
+			written[common.Hash(stack[len(stack)-1].Bytes32())] = true
+		},
+	}}}
+	code, address, _, err := runtime.Create(input, config)
+	if err != nil {
+		return deployedContract{}, err
+	}
+	storage := make(map[common.Hash]common.Hash, len(written))
+	for slot := range written {
+		if value := config.State.GetState(address, slot); value != (common.Hash{}) {
+			storage[slot] = value
+		}
+	}
+	return deployedContract{code: code, storage: storage}, nil
+}
+
+func randomAddress(rng *rand.Rand) common.Address {
+	var address common.Address
+	rng.Read(address[:])
+	return address
+}
+
+func randomHash(rng *rand.Rand) common.Hash {
+	var hash common.Hash
+	rng.Read(hash[:])
+	return hash
+}
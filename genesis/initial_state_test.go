@@ -0,0 +1,117 @@
+package genesis
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/0xsoniclabs/sonic/integration/makefakegenesis"
+	"github.com/0xsoniclabs/sonic/opera"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/stretchr/testify/require"
+)
+
+func TestInitialAccounts_AreDerivedFromTheSeed(t *testing.T) {
+	require := require.New(t)
+
+	state := &InitialState{Seed: 42, Accounts: 10, Contracts: 3, StorageSlots: 30}
+	first, err := initialAccounts(state)
+	require.NoError(err)
+	second, err := initialAccounts(state)
+	require.NoError(err)
+	require.Equal(first, second)
+
+	state.Seed = 43
+	other, err := initialAccounts(state)
+	require.NoError(err)
+	require.NotEqual(first[0].Address, other[0].Address)
+}
+
+func TestInitialAccounts_DeployContractsWithTheirConstructorStorage(t *testing.T) {
+	require := require.New(t)
+
+	accounts, err := initialAccounts(&InitialState{
+		Accounts:      2,
+		Balance:       5,
+		Contracts:     4,
+		ContractTypes: []string{"counter", "store"},
+		StorageSlots:  10,
+	})
+	require.NoError(err)
+	require.Len(accounts, 6)
+
+	for _, account := range accounts[:2] {
+		require.Empty(account.Code)
+		require.Equal("5000000000000000000", account.Balance.Dec())
+	}
+
+	slots := 0
+	for i, account := range accounts[2:] {
+		require.NotEmpty(account.Code, "contract %d has no code", i)
+		slots += len(account.Storage)
+	}
+	require.Equal("counter_0", accounts[2].Name)
+	require.Equal("store_1", accounts[3].Name)
+	// The counter starts at 1, the store at 0, which is not stored.
+	require.Equal(common.BigToHash(common.Big1), accounts[2].Storage[common.Hash{}])
+	require.NotContains(accounts[3].Storage, common.Hash{})
+	require.Equal(2+10, slots)
+}
+
+func TestInitialAccounts_NoStateAddsNoAccounts(t *testing.T) {
+	accounts, err := initialAccounts(nil)
+	require.NoError(t, err)
+	require.Empty(t, accounts)
+}
+
+func TestValidateInitialState_ReportsUnusableStates(t *testing.T) {
+	tests := map[string]struct {
+		state     InitialState
+		errSubstr string
+	}{
+		"valid":              {InitialState{Accounts: 1, Contracts: 1, StorageSlots: 1}, ""},
+		"negative accounts":  {InitialState{Accounts: -1}, "accounts must not be negative"},
+		"negative contracts": {InitialState{Contracts: -1}, "contracts must not be negative"},
+		"negative slots":     {InitialState{Contracts: 1, StorageSlots: -1}, "storageSlots must not be negative"},
+		"slots without home": {InitialState{StorageSlots: 1}, "at least one contract"},
+		"unknown contract":   {InitialState{Contracts: 1, ContractTypes: []string{"uniswap"}}, `unknown contract type "uniswap"`},
+	}
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			err := ValidateInitialState(test.state)
+			if test.errSubstr == "" {
+				require.NoError(t, err)
+				return
+			}
+			require.ErrorContains(t, err, test.errSubstr)
+		})
+	}
+}
+
+func TestGenerateJsonGenesis_ContainsTheInitialState(t *testing.T) {
+	require := require.New(t)
+
+	path := filepath.Join(t.TempDir(), "genesis.json")
+	rules := opera.FakeNetRules(opera.GetSonicUpgrades())
+	state := &InitialState{Seed: 1, Accounts: 3, Contracts: 3, StorageSlots: 9}
+	require.NoError(GenerateJsonGenesis(
+		path, []uint64{5_000_000}, &rules, []string{"2.2.0"}, state))
+
+	content, err := os.ReadFile(path)
+	require.NoError(err)
+	var genesis struct {
+		Accounts []makefakegenesis.Account
+	}
+	require.NoError(json.Unmarshal(content, &genesis))
+
+	want, err := initialAccounts(state)
+	require.NoError(err)
+	found := map[common.Address]bool{}
+	for _, account := range genesis.Accounts {
+		found[account.Address] = true
+	}
+	for _, account := range want {
+		require.True(found[account.Address], "account %s is missing", account.Name)
+	}
+}
@@ -5,6 +5,7 @@ import (
 	"errors"
 	"fmt"
 	"path/filepath"
+	"strconv"
 
 	"github.com/0xsoniclabs/sonic/evmcore"
 	"github.com/0xsoniclabs/sonic/inter/validatorpk"
@@ -13,6 +14,16 @@ import (
 	"github.com/ethereum/go-ethereum/crypto"
 )
 
+// FakeNetValidators is the number of validators keyed like the validators of
+// Sonic's fakenet. A genesis funds the accounts of at least that many
+// validators, so that validators registered later can pay their stake.
+const FakeNetValidators = 100
+
+// validatorKeyPrefix is hashed with the ID of a validator beyond the fakenet
+// ones to derive its key, keeping it apart from other derived keys such as
+// those of delegators.
+const validatorKeyPrefix = "norma-validator:"
+
 func fakeKey(n uint32) (k *ecdsa.PrivateKey, retErr error) {
 	defer func() {
 		if err := recover(); err != nil {
@@ -23,9 +34,35 @@ func fakeKey(n uint32) (k *ecdsa.PrivateKey, retErr error) {
 	return k, nil
 }
 
+// ValidatorKey derives the private key of the validator with the given ID.
+// The first FakeNetValidators validators use the keys of Sonic's fakenet, the
+// first of which holds the treasury. Keys of validators beyond are derived
+// from keccak256("norma-validator:<id>"), so the key space has no limit and
+// stays reproducible across runs.
+func ValidatorKey(id int) (*ecdsa.PrivateKey, error) {
+	if id < 1 {
+		return nil, fmt.Errorf("invalid validator ID %d, IDs start at 1", id)
+	}
+	if id <= FakeNetValidators {
+		return fakeKey(uint32(id))
+	}
+	key, err := crypto.ToECDSA(crypto.Keccak256([]byte(validatorKeyPrefix + strconv.Itoa(id))))
+	if err != nil {
+		return nil, fmt.Errorf("failed to derive key of validator %d; %v", id, err)
+	}
+	return key, nil
+}
+
+// ValidatorAccounts returns the number of validator accounts a genesis with
+// the given number of validators funds: one per validator, but at least
+// FakeNetValidators.
+func ValidatorAccounts(validators int) int {
+	return max(FakeNetValidators, validators)
+}
+
 // DeriveValidatorKey derives a fake validator private key, pubkey and address from validator ID.
 func DeriveValidatorKey(id int) (privKeyHex string, pubKey string, address string, err error) {
-	privateKeyECDSA, err := fakeKey(uint32(id))
+	privateKeyECDSA, err := ValidatorKey(id)
 	if err != nil {
 		return "", "", "", err
 	}
@@ -4,6 +4,10 @@ import (
 	"os"
 	"path/filepath"
 	"testing"
+
+	"github.com/0xsoniclabs/sonic/evmcore"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
 )
 
 func TestDeriveValidatorKey_ReturnsData(t *testing.T) {
@@ -42,3 +46,47 @@ func TestWriteValidatorKeystore_CreatesFiles(t *testing.T) {
 		t.Fatalf("expected at least one keystore file in %s", keystoreDir)
 	}
 }
+
+func TestValidatorKey_KeepsFakeNetKeysAndScalesBeyond(t *testing.T) {
+	for _, id := range []int{1, FakeNetValidators} {
+		key, err := ValidatorKey(id)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if !key.Equal(evmcore.FakeKey(uint32(id))) {
+			t.Errorf("key of validator %d is not the fakenet key", id)
+		}
+	}
+
+	seen := map[common.Address]int{}
+	for id := 1; id <= 500; id++ {
+		key, err := ValidatorKey(id)
+		if err != nil {
+			t.Fatalf("failed to derive key of validator %d: %v", id, err)
+		}
+		address := crypto.PubkeyToAddress(key.PublicKey)
+		if other, found := seen[address]; found {
+			t.Fatalf("validators %d and %d share a key", other, id)
+		}
+		seen[address] = id
+	}
+
+	first, _ := ValidatorKey(FakeNetValidators + 1)
+	second, _ := ValidatorKey(FakeNetValidators + 1)
+	if !first.Equal(second) {
+		t.Errorf("keys beyond the fakenet ones are not reproducible")
+	}
+
+	if _, err := ValidatorKey(0); err == nil {
+		t.Errorf("expected an error for validator ID 0")
+	}
+}
+
+func TestValidatorAccounts_CoversAtLeastTheFakeNetValidators(t *testing.T) {
+	tests := map[int]int{0: FakeNetValidators, 4: FakeNetValidators, 100: 100, 250: 250}
+	for validators, want := range tests {
+		if got := ValidatorAccounts(validators); got != want {
+			t.Errorf("ValidatorAccounts(%d) = %d, want %d", validators, got, want)
+		}
+	}
+}
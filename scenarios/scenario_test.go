@@ -85,6 +85,9 @@ func listAll() ([]string, error) {
 				return err
 			}
 			if info.IsDir() {
+				if info.Name() == parser.FragmentsDir {
+					return filepath.SkipDir
+				}
 				return nil
 			}
 			if strings.HasSuffix(path, ".yml") || strings.HasSuffix(path, ".yaml") {